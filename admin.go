@@ -0,0 +1,60 @@
+package mcpwrapper
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// AdminHandler exposes read-only operational endpoints as JSON. It is meant
+// to be mounted on an internal listener, never on the public MCP endpoint:
+//
+//	GET /provenance             list of session IDs with recorded calls
+//	GET /provenance/{session}   provenance graph of one session
+//	GET /stats                  per-tool call statistics (see Stats)
+//	GET /catalog[?format=...]   tool catalog (see ExportManifest)
+func (w *Wrapper) AdminHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /provenance", w.handleProvenanceSessions)
+	mux.HandleFunc("GET /provenance/{session}", w.handleProvenanceGraph)
+	mux.HandleFunc("GET /stats", w.handleStats)
+	mux.HandleFunc("GET /catalog", w.handleCatalog)
+	return mux
+}
+
+func (w *Wrapper) handleProvenanceSessions(rw http.ResponseWriter, r *http.Request) {
+	if w.provenance == nil {
+		http.Error(rw, "provenance tracking is disabled", http.StatusNotFound)
+		return
+	}
+	writeJSON(rw, w.provenance.sessionIDs())
+}
+
+func (w *Wrapper) handleProvenanceGraph(rw http.ResponseWriter, r *http.Request) {
+	graph, ok := w.Provenance(r.PathValue("session"))
+	if !ok {
+		http.Error(rw, "session not found", http.StatusNotFound)
+		return
+	}
+	writeJSON(rw, graph)
+}
+
+func (w *Wrapper) handleStats(rw http.ResponseWriter, r *http.Request) {
+	writeJSON(rw, w.Stats())
+}
+
+func (w *Wrapper) handleCatalog(rw http.ResponseWriter, r *http.Request) {
+	catalog, err := w.ExportManifest(CatalogFormat(r.URL.Query().Get("format")))
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+	rw.Header().Set("Content-Type", "application/json")
+	rw.Write(catalog)
+}
+
+func writeJSON(rw http.ResponseWriter, v interface{}) {
+	rw.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(rw).Encode(v); err != nil {
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+	}
+}
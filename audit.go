@@ -0,0 +1,135 @@
+package mcpwrapper
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"sort"
+	"time"
+)
+
+type AuditEntry struct {
+	Time      time.Time     `json:"time"`
+	Tool      string        `json:"tool"`
+	SessionID string        `json:"session_id,omitempty"`
+	Args      interface{}   `json:"args"`
+	Changes   []FieldChange `json:"changes,omitempty"`
+	Duration  time.Duration `json:"duration"`
+	Error     string        `json:"error,omitempty"`
+	// StateError is set when the StateFetcher failed; the call still ran,
+	// but the entry has no Changes.
+	StateError string `json:"state_error,omitempty"`
+}
+
+// FieldChange is one argument field whose value differs from the entity's
+// state before the call. Sensitive fields report [REDACTED] on both sides.
+type FieldChange struct {
+	Field  string      `json:"field"`
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// AuditSink receives an entry after every tool call that passed validation.
+type AuditSink func(ctx context.Context, entry AuditEntry)
+
+// StateFetcher returns the current state of the entity a call is about to
+// modify. Its JSON fields are compared with the call's arguments. A failed
+// fetch is logged and recorded in the entry's StateError, and does not stop
+// the call.
+type StateFetcher func(ctx context.Context, args interface{}) (interface{}, error)
+
+// WithAudit sends an audit entry for every call to sink. Arguments are
+// redacted.
+func WithAudit(sink AuditSink) Option {
+	return func(w *Wrapper) {
+		w.audit = sink
+	}
+}
+
+// WithCurrentState declares how to fetch the entity an update-style tool
+// modifies, so its audit entries carry a before/after diff of the fields the
+// call sets instead of just the raw arguments.
+func WithCurrentState(fetch StateFetcher) ToolOption {
+	return func(o *toolOptions) {
+		o.currentState = fetch
+	}
+}
+
+func auditMiddleware(sink AuditSink, fetch StateFetcher, logger *slog.Logger) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, args interface{}) (interface{}, error) {
+			entry := AuditEntry{
+				Time:      time.Now(),
+				Tool:      ToolNameFromContext(ctx),
+				SessionID: SessionIDFromContext(ctx),
+				Args:      Redact(args),
+			}
+
+			var before interface{}
+			diff := false
+			if fetch != nil {
+				state, err := fetch(ctx, args)
+				if err != nil {
+					entry.StateError = redactString(fmt.Sprintf("fetch current state: %v", err), args)
+					logger.Warn("failed to fetch current state for audit", "tool", entry.Tool, "error", entry.StateError)
+				} else {
+					before, diff = state, true
+				}
+			}
+
+			result, err := next(ctx, args)
+			entry.Duration = time.Since(entry.Time)
+			if err != nil {
+				entry.Error = redactString(err.Error(), args)
+			}
+			if diff {
+				entry.Changes = diffArgs(before, args)
+			}
+
+			sink(ctx, entry)
+			return result, err
+		}
+	}
+}
+
+// diffArgs compares the top-level fields set in args (those that are not
+// null once marshalled) with the same fields of state. Fields that state does
+// not have, such as identifiers and flags, are not changes.
+func diffArgs(state, args interface{}) []FieldChange {
+	after := jsonFields(args)
+	before := jsonFields(state)
+	redacted, _ := Redact(args).(map[string]interface{})
+
+	var changes []FieldChange
+	for field, value := range after {
+		if value == nil {
+			continue
+		}
+		old, ok := before[field]
+		if !ok || reflect.DeepEqual(old, value) {
+			continue
+		}
+		change := FieldChange{Field: field, Before: old, After: value}
+		if redacted[field] == redactedValue {
+			change.Before, change.After = redactedValue, redactedValue
+		}
+		changes = append(changes, change)
+	}
+
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Field < changes[j].Field })
+	return changes
+}
+
+func jsonFields(v interface{}) map[string]interface{} {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil
+	}
+	return fields
+}
@@ -0,0 +1,119 @@
+package mcpwrapper
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+type UpdateUserArgs struct {
+	ID       string  `json:"id" validate:"required"`
+	Name     *string `json:"name,omitempty"`
+	Email    *string `json:"email,omitempty"`
+	Password *string `json:"password,omitempty" redact:"true"`
+}
+
+type User struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+func TestAuditRecordsDiff(t *testing.T) {
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+
+	var entries []AuditEntry
+	wrapper := New(mcpServer, WithAudit(func(ctx context.Context, entry AuditEntry) {
+		entries = append(entries, entry)
+	}))
+
+	users := map[string]User{"u1": {ID: "u1", Name: "Alice", Email: "alice@example.com", Password: "old"}}
+	fetch := func(ctx context.Context, args interface{}) (interface{}, error) {
+		id := args.(*UpdateUserArgs).ID
+		user, ok := users[id]
+		if !ok {
+			return nil, errors.New("no such user")
+		}
+		return user, nil
+	}
+	handler := func(ctx context.Context, args interface{}) (interface{}, error) {
+		return map[string]string{"status": "updated"}, nil
+	}
+
+	if err := wrapper.Register("update_user", "Update a user", UpdateUserArgs{}, handler, WithCurrentState(fetch)); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	call := func(args map[string]interface{}) *mcp.CallToolResult {
+		request := mcp.CallToolRequest{Params: mcp.CallToolParams{Name: "update_user", Arguments: args}}
+		result, err := mcpServer.GetTool("update_user").Handler(context.Background(), request)
+		if err != nil {
+			t.Fatalf("Handler invocation failed: %v", err)
+		}
+		return result
+	}
+
+	call(map[string]interface{}{"id": "u1", "name": "Alice", "email": "alice@new.example.com", "password": "new"})
+
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 audit entry, got %d", len(entries))
+	}
+	expected := []FieldChange{
+		{Field: "email", Before: "alice@example.com", After: "alice@new.example.com"},
+		{Field: "password", Before: redactedValue, After: redactedValue},
+	}
+	if !reflect.DeepEqual(entries[0].Changes, expected) {
+		t.Errorf("Expected changes %+v, got %+v", expected, entries[0].Changes)
+	}
+	if entries[0].Tool != "update_user" || entries[0].Error != "" {
+		t.Errorf("Unexpected entry: %+v", entries[0])
+	}
+	if args := entries[0].Args.(map[string]interface{}); args["password"] != redactedValue {
+		t.Errorf("Expected redacted password in args, got %v", args["password"])
+	}
+
+	result := call(map[string]interface{}{"id": "u2", "name": "Bob"})
+	if result.IsError {
+		t.Errorf("Expected the call to run when the current state cannot be fetched, got %v", result.Content)
+	}
+	if len(entries) != 2 || entries[1].Changes != nil || entries[1].Error != "" ||
+		entries[1].StateError != "fetch current state: no such user" {
+		t.Errorf("Expected an audit entry without a diff for the failed fetch, got %+v", entries)
+	}
+}
+
+func TestAuditWithoutStateFetcher(t *testing.T) {
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+
+	var entries []AuditEntry
+	wrapper := New(mcpServer, WithAudit(func(ctx context.Context, entry AuditEntry) {
+		entries = append(entries, entry)
+	}))
+
+	handler := func(ctx context.Context, args interface{}) (interface{}, error) {
+		return nil, errors.New("boom")
+	}
+
+	if err := wrapper.Register("test-tool", "Test tool", TestArgs{}, handler); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	request := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name:      "test-tool",
+			Arguments: map[string]interface{}{"name": "Alice", "age": 30, "category": "A"},
+		},
+	}
+	if _, err := mcpServer.GetTool("test-tool").Handler(context.Background(), request); err != nil {
+		t.Fatalf("Handler invocation failed: %v", err)
+	}
+
+	if len(entries) != 1 || entries[0].Changes != nil || entries[0].Error != "boom" {
+		t.Errorf("Unexpected audit entries: %+v", entries)
+	}
+}
@@ -0,0 +1,34 @@
+package mcpwrapper
+
+import "context"
+
+// Authorizer decides whether a call may proceed. It runs before argument
+// binding and validation and receives the raw arguments map. Returning an
+// error rejects the call.
+type Authorizer func(ctx context.Context, toolName string, args interface{}) error
+
+// WithAuthorizer installs an authorizer for every tool.
+func WithAuthorizer(a Authorizer) Option {
+	return func(w *Wrapper) {
+		w.authorizers = append(w.authorizers, a)
+	}
+}
+
+// WithToolAuthorizer installs an authorizer for a single tool. It runs after
+// the wrapper-level authorizers.
+func WithToolAuthorizer(a Authorizer) ToolOption {
+	return func(o *toolOptions) {
+		o.authorizers = append(o.authorizers, a)
+	}
+}
+
+func authorize(ctx context.Context, name string, args interface{}, authorizers ...[]Authorizer) error {
+	for _, list := range authorizers {
+		for _, a := range list {
+			if err := a(ctx, name, args); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
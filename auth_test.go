@@ -0,0 +1,72 @@
+package mcpwrapper
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func TestAuthorizer(t *testing.T) {
+	authorizer := func(ctx context.Context, toolName string, args interface{}) error {
+		if HeadersFromContext(ctx).Get("X-Tenant") != "acme" {
+			return fmt.Errorf("tenant may not call %s", toolName)
+		}
+		return nil
+	}
+
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	wrapper := New(mcpServer, WithAuthorizer(authorizer))
+
+	called := false
+	handler := func(ctx context.Context, args interface{}) (interface{}, error) {
+		called = true
+		return &TestResult{Message: "ok"}, nil
+	}
+
+	if err := wrapper.Register("test-tool", "Test tool", TestArgs{}, handler); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		tenant     string
+		authorized bool
+	}{
+		{"authorized tenant", "acme", true},
+		{"other tenant", "globex", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called = false
+
+			request := mcp.CallToolRequest{
+				Header: http.Header{"X-Tenant": []string{tt.tenant}},
+				Params: mcp.CallToolParams{
+					Name: "test-tool",
+					// Invalid arguments: authorization must run before validation.
+					Arguments: map[string]interface{}{"name": "A"},
+				},
+			}
+
+			result, err := mcpServer.GetTool("test-tool").Handler(context.Background(), request)
+			if err != nil {
+				t.Fatalf("Handler invocation failed: %v", err)
+			}
+
+			text := result.Content[0].(mcp.TextContent).Text
+			unauthorized := text == "unauthorized: tenant may not call test-tool"
+			if unauthorized == tt.authorized {
+				t.Errorf("Expected authorized=%v, got result %q", tt.authorized, text)
+			}
+
+			if called {
+				t.Error("Handler should not be called")
+			}
+		})
+	}
+}
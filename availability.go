@@ -0,0 +1,236 @@
+package mcpwrapper
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// AvailabilityConfig restricts when a tool may be called. When Windows is
+// empty the tool is available at any time outside of Blackouts.
+type AvailabilityConfig struct {
+	Timezone  string           `json:"timezone,omitempty" yaml:"timezone,omitempty"`
+	Windows   []TimeWindow     `json:"windows,omitempty" yaml:"windows,omitempty"`
+	Blackouts []BlackoutPeriod `json:"blackouts,omitempty" yaml:"blackouts,omitempty"`
+}
+
+// TimeWindow is a daily "HH:MM" range on the listed weekdays ("mon".."sun").
+// An empty Days list means every day. A window whose End is before its
+// Start wraps past midnight: "22:00" to "02:00" on "fri" runs from Friday
+// 22:00 to Saturday 02:00.
+type TimeWindow struct {
+	Days  []string `json:"days,omitempty" yaml:"days,omitempty"`
+	Start string   `json:"start" yaml:"start"`
+	End   string   `json:"end" yaml:"end"`
+}
+
+type BlackoutPeriod struct {
+	Start  time.Time `json:"start" yaml:"start"`
+	End    time.Time `json:"end" yaml:"end"`
+	Reason string    `json:"reason,omitempty" yaml:"reason,omitempty"`
+}
+
+type UnavailableError struct {
+	Tool   string
+	Until  time.Time
+	Reason string
+}
+
+func (e *UnavailableError) Error() string {
+	if e.Until.IsZero() {
+		return fmt.Sprintf("tool %s is unavailable (%s)", e.Tool, e.Reason)
+	}
+	return fmt.Sprintf("tool %s is unavailable until %s (%s)", e.Tool, e.Until.Format(time.RFC3339), e.Reason)
+}
+
+type schedule struct {
+	location  *time.Location
+	windows   []compiledWindow
+	blackouts []BlackoutPeriod
+	now       func() time.Time
+}
+
+type compiledWindow struct {
+	days       map[time.Weekday]bool
+	start, end time.Duration
+}
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+func (c *AvailabilityConfig) compile() (*schedule, error) {
+	s := &schedule{location: time.UTC, blackouts: c.Blackouts, now: time.Now}
+
+	if c.Timezone != "" {
+		loc, err := time.LoadLocation(c.Timezone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timezone %q: %w", c.Timezone, err)
+		}
+		s.location = loc
+	}
+
+	for i, window := range c.Windows {
+		compiled := compiledWindow{days: make(map[time.Weekday]bool)}
+		for _, day := range window.Days {
+			wd, ok := weekdays[strings.ToLower(day)[:min(3, len(day))]]
+			if !ok {
+				return nil, fmt.Errorf("window %d: invalid day %q", i, day)
+			}
+			compiled.days[wd] = true
+		}
+
+		var err error
+		if compiled.start, err = parseClock(window.Start); err != nil {
+			return nil, fmt.Errorf("window %d: %w", i, err)
+		}
+		if compiled.end, err = parseClock(window.End); err != nil {
+			return nil, fmt.Errorf("window %d: %w", i, err)
+		}
+		if compiled.end == compiled.start {
+			return nil, fmt.Errorf("window %d: end must differ from start", i)
+		}
+
+		s.windows = append(s.windows, compiled)
+	}
+
+	for i, blackout := range c.Blackouts {
+		if !blackout.End.After(blackout.Start) {
+			return nil, fmt.Errorf("blackout %d: end must be after start", i)
+		}
+	}
+
+	return s, nil
+}
+
+func parseClock(value string) (time.Duration, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM", value)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// check returns nil when the tool may run at the current time, otherwise an
+// *UnavailableError describing when it becomes available again.
+func (s *schedule) check(tool string) error {
+	now := s.now().In(s.location)
+
+	if blackout, ok := s.blackoutAt(now); ok {
+		reason := blackout.Reason
+		if reason == "" {
+			reason = "blackout period"
+		}
+		return &UnavailableError{Tool: tool, Until: s.nextAvailable(now), Reason: reason}
+	}
+
+	if len(s.windows) > 0 && !s.inWindow(now) {
+		return &UnavailableError{Tool: tool, Until: s.nextAvailable(now), Reason: "outside availability window"}
+	}
+
+	return nil
+}
+
+func (s *schedule) blackoutAt(t time.Time) (BlackoutPeriod, bool) {
+	for _, blackout := range s.blackouts {
+		if !t.Before(blackout.Start) && t.Before(blackout.End) {
+			return blackout, true
+		}
+	}
+	return BlackoutPeriod{}, false
+}
+
+func (s *schedule) inWindow(t time.Time) bool {
+	offset := clockOffset(t)
+	yesterday := (t.Weekday() + 6) % 7
+	for _, window := range s.windows {
+		if window.end < window.start {
+			// The part after midnight belongs to the previous day's window.
+			if (offset >= window.start && window.on(t.Weekday())) || (offset < window.end && window.on(yesterday)) {
+				return true
+			}
+			continue
+		}
+		if offset >= window.start && offset < window.end && window.on(t.Weekday()) {
+			return true
+		}
+	}
+	return false
+}
+
+func (w compiledWindow) on(day time.Weekday) bool {
+	return len(w.days) == 0 || w.days[day]
+}
+
+// nextAvailable alternates between skipping blackouts and jumping to the next
+// window start until both constraints are satisfied. It gives up (returning
+// the zero time) if no slot is found within a bounded number of steps.
+func (s *schedule) nextAvailable(t time.Time) time.Time {
+	for i := 0; i < 64; i++ {
+		if blackout, ok := s.blackoutAt(t); ok {
+			t = blackout.End.In(s.location)
+			continue
+		}
+		if len(s.windows) == 0 || s.inWindow(t) {
+			return t
+		}
+		next, ok := s.nextWindowStart(t)
+		if !ok {
+			return time.Time{}
+		}
+		t = next
+	}
+	return time.Time{}
+}
+
+func (s *schedule) nextWindowStart(t time.Time) (time.Time, bool) {
+	var best time.Time
+	for day := 0; day <= 7; day++ {
+		date := time.Date(t.Year(), t.Month(), t.Day()+day, 12, 0, 0, 0, t.Location())
+		for _, window := range s.windows {
+			if !window.on(date.Weekday()) {
+				continue
+			}
+			start := atClock(date, window.start)
+			if start.After(t) && (best.IsZero() || start.Before(best)) {
+				best = start
+			}
+		}
+		if !best.IsZero() {
+			return best, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// clockOffset returns the wall-clock time of day of t. Unlike the time
+// elapsed since midnight, it is not shifted by a DST change earlier that day.
+func clockOffset(t time.Time) time.Duration {
+	hour, min, sec := t.Clock()
+	return time.Duration(hour)*time.Hour + time.Duration(min)*time.Minute +
+		time.Duration(sec)*time.Second + time.Duration(t.Nanosecond())
+}
+
+// atClock returns the time at the wall-clock offset on date's day.
+func atClock(date time.Time, offset time.Duration) time.Time {
+	return time.Date(date.Year(), date.Month(), date.Day(),
+		int(offset/time.Hour), int(offset%time.Hour/time.Minute), 0, 0, date.Location())
+}
+
+func availabilityMiddleware(s *schedule) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, args interface{}) (interface{}, error) {
+			if err := s.check(ToolNameFromContext(ctx)); err != nil {
+				return nil, err
+			}
+			return next(ctx, args)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package mcpwrapper
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestScheduleWindows(t *testing.T) {
+	cfg := &AvailabilityConfig{
+		Timezone: "Europe/Warsaw",
+		Windows: []TimeWindow{
+			{Days: []string{"mon", "tue", "wed", "thu", "fri"}, Start: "09:00", End: "17:00"},
+		},
+	}
+
+	s, err := cfg.compile()
+	if err != nil {
+		t.Fatalf("compile failed: %v", err)
+	}
+
+	warsaw, _ := time.LoadLocation("Europe/Warsaw")
+
+	s.now = func() time.Time { return time.Date(2026, 10, 14, 10, 0, 0, 0, warsaw) }
+	if err := s.check("deploy"); err != nil {
+		t.Errorf("Expected tool to be available on Wednesday 10:00, got %v", err)
+	}
+
+	s.now = func() time.Time { return time.Date(2026, 10, 16, 18, 0, 0, 0, warsaw) }
+	err = s.check("deploy")
+
+	var unavailable *UnavailableError
+	if !errors.As(err, &unavailable) {
+		t.Fatalf("Expected UnavailableError on Friday 18:00, got %v", err)
+	}
+
+	expected := time.Date(2026, 10, 19, 9, 0, 0, 0, warsaw)
+	if !unavailable.Until.Equal(expected) {
+		t.Errorf("Expected available again at %v, got %v", expected, unavailable.Until)
+	}
+}
+
+func TestScheduleBlackout(t *testing.T) {
+	start := time.Date(2026, 12, 20, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2027, 1, 5, 0, 0, 0, 0, time.UTC)
+
+	cfg := &AvailabilityConfig{
+		Blackouts: []BlackoutPeriod{{Start: start, End: end, Reason: "change freeze"}},
+	}
+
+	s, err := cfg.compile()
+	if err != nil {
+		t.Fatalf("compile failed: %v", err)
+	}
+
+	s.now = func() time.Time { return start.Add(48 * time.Hour) }
+	err = s.check("deploy")
+
+	var unavailable *UnavailableError
+	if !errors.As(err, &unavailable) {
+		t.Fatalf("Expected UnavailableError during blackout, got %v", err)
+	}
+
+	if !unavailable.Until.Equal(end) || unavailable.Reason != "change freeze" {
+		t.Errorf("Unexpected error details: %v", unavailable)
+	}
+}
+
+func TestParseConfigAvailability(t *testing.T) {
+	data := []byte(`
+tools:
+  deploy:
+    availability:
+      timezone: UTC
+      windows:
+        - start: "17:00"
+          end: "17:00"
+`)
+
+	if _, err := ParseConfig(data); err == nil {
+		t.Error("Expected error for window ending when it starts")
+	}
+}
+
+func TestScheduleWindowWrapsMidnight(t *testing.T) {
+	cfg := &AvailabilityConfig{
+		Windows: []TimeWindow{{Days: []string{"fri"}, Start: "22:00", End: "02:00"}},
+	}
+	s, err := cfg.compile()
+	if err != nil {
+		t.Fatalf("compile failed: %v", err)
+	}
+
+	// 2026-10-16 is a Friday.
+	for _, tc := range []struct {
+		at        time.Time
+		available bool
+	}{
+		{time.Date(2026, 10, 16, 21, 59, 0, 0, time.UTC), false},
+		{time.Date(2026, 10, 16, 22, 0, 0, 0, time.UTC), true},
+		{time.Date(2026, 10, 16, 23, 30, 0, 0, time.UTC), true},
+		{time.Date(2026, 10, 17, 1, 59, 0, 0, time.UTC), true},
+		{time.Date(2026, 10, 17, 2, 0, 0, 0, time.UTC), false},
+		{time.Date(2026, 10, 17, 23, 0, 0, 0, time.UTC), false},
+		{time.Date(2026, 10, 16, 1, 0, 0, 0, time.UTC), false},
+	} {
+		s.now = func() time.Time { return tc.at }
+		if err := s.check("backup"); (err == nil) != tc.available {
+			t.Errorf("At %v: expected available=%v, got %v", tc.at, tc.available, err)
+		}
+	}
+
+	s.now = func() time.Time { return time.Date(2026, 10, 17, 3, 0, 0, 0, time.UTC) }
+	var unavailable *UnavailableError
+	if err := s.check("backup"); !errors.As(err, &unavailable) || !unavailable.Until.Equal(time.Date(2026, 10, 23, 22, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected the tool back on the next Friday at 22:00, got %v", err)
+	}
+}
+
+func TestScheduleWindowsOnDSTDays(t *testing.T) {
+	cfg := &AvailabilityConfig{
+		Timezone: "Europe/Warsaw",
+		Windows:  []TimeWindow{{Start: "09:00", End: "17:00"}},
+	}
+	s, err := cfg.compile()
+	if err != nil {
+		t.Fatalf("compile failed: %v", err)
+	}
+	warsaw, _ := time.LoadLocation("Europe/Warsaw")
+
+	// Clocks go forward on 2026-03-29 and back on 2026-10-25.
+	for _, now := range []time.Time{
+		time.Date(2026, 3, 29, 9, 30, 0, 0, warsaw),
+		time.Date(2026, 10, 25, 16, 30, 0, 0, warsaw),
+	} {
+		s.now = func() time.Time { return now }
+		if err := s.check("deploy"); err != nil {
+			t.Errorf("Expected tool to be available at %v, got %v", now, err)
+		}
+	}
+
+	s.now = func() time.Time { return time.Date(2026, 3, 29, 8, 30, 0, 0, warsaw) }
+	var unavailable *UnavailableError
+	if err := s.check("deploy"); !errors.As(err, &unavailable) {
+		t.Fatalf("Expected UnavailableError at 08:30, got %v", err)
+	}
+	if expected := time.Date(2026, 3, 29, 9, 0, 0, 0, warsaw); !unavailable.Until.Equal(expected) {
+		t.Errorf("Expected available again at %v, got %v", expected, unavailable.Until)
+	}
+}
@@ -0,0 +1,130 @@
+package mcpwrapper
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// BatchArgs are the arguments of the batch tool.
+type BatchArgs struct {
+	Calls       []BatchCall `json:"calls" jsonschema:"required,description=Tool calls to run" validate:"required,min=1,dive"`
+	Concurrent  bool        `json:"concurrent" jsonschema:"description=Run the calls concurrently instead of in order"`
+	StopOnError bool        `json:"stop_on_error" jsonschema:"description=Skip the remaining calls after a failed one (in-order runs only)"`
+}
+
+// BatchCall is one call of a batch.
+type BatchCall struct {
+	Tool      string                 `json:"tool" validate:"required"`
+	Arguments map[string]interface{} `json:"arguments"`
+}
+
+// BatchResult is the outcome of one call of a batch, in the order of the
+// calls. Result is the call's structured content, or its text (decoded if
+// it is JSON); Error is set instead when the call failed or was skipped.
+type BatchResult struct {
+	Tool   string      `json:"tool"`
+	Result interface{} `json:"result,omitempty"`
+	Error  string      `json:"error,omitempty"`
+}
+
+// BatchOutput is the result of the batch tool.
+type BatchOutput struct {
+	Results []BatchResult `json:"results"`
+}
+
+// RegisterBatch adds a meta-tool that runs several tool calls in one round
+// trip. Each call goes through the called tool's full pipeline, including
+// validation, middleware, authorization and feature gates, exactly as if
+// the client had made it. Concurrent batches run at most maxConcurrency
+// calls at a time; values below 1 mean one. A batch cannot call itself.
+func (w *Wrapper) RegisterBatch(name string, maxConcurrency int, opts ...ToolOption) error {
+	if maxConcurrency < 1 {
+		maxConcurrency = 1
+	}
+	description := "Run several tool calls in one request and get their results in order. " +
+		"Use it for many small independent operations."
+
+	return w.Register(name, description, BatchArgs{}, func(ctx context.Context, args interface{}) (interface{}, error) {
+		a := args.(*BatchArgs)
+		results := make([]BatchResult, len(a.Calls))
+
+		if !a.Concurrent {
+			failed := false
+			for i, call := range a.Calls {
+				if failed {
+					results[i] = BatchResult{Tool: call.Tool, Error: "skipped after a failed call"}
+					continue
+				}
+				results[i] = w.runBatchCall(ctx, name, call)
+				failed = a.StopOnError && results[i].Error != ""
+			}
+			return &BatchOutput{Results: results}, nil
+		}
+
+		sem := make(chan struct{}, maxConcurrency)
+		var wg sync.WaitGroup
+		for i, call := range a.Calls {
+			wg.Add(1)
+			sem <- struct{}{}
+			go func(i int, call BatchCall) {
+				defer func() {
+					<-sem
+					wg.Done()
+				}()
+				results[i] = w.runBatchCall(ctx, name, call)
+			}(i, call)
+		}
+		wg.Wait()
+		return &BatchOutput{Results: results}, nil
+	}, opts...)
+}
+
+func (w *Wrapper) runBatchCall(ctx context.Context, batchName string, call BatchCall) BatchResult {
+	outcome := BatchResult{Tool: call.Tool}
+	if call.Tool == batchName {
+		outcome.Error = "a batch cannot call itself"
+		return outcome
+	}
+	tool := w.server.GetTool(call.Tool)
+	if tool == nil {
+		outcome.Error = fmt.Sprintf("tool %s not found", call.Tool)
+		return outcome
+	}
+
+	result, err := tool.Handler(ctx, mcp.CallToolRequest{
+		Params: mcp.CallToolParams{Name: call.Tool, Arguments: call.Arguments},
+	})
+	if err != nil {
+		outcome.Error = err.Error()
+		return outcome
+	}
+
+	value := batchValue(result)
+	if result.IsError {
+		outcome.Error = fmt.Sprint(value)
+		return outcome
+	}
+	outcome.Result = value
+	return outcome
+}
+
+// batchValue reduces a call result to a single JSON value.
+func batchValue(result *mcp.CallToolResult) interface{} {
+	if result.StructuredContent != nil && !result.IsError {
+		return result.StructuredContent
+	}
+	if len(result.Content) == 1 {
+		if text, ok := mcp.AsTextContent(result.Content[0]); ok {
+			var decoded interface{}
+			if !result.IsError && json.Unmarshal([]byte(text.Text), &decoded) == nil {
+				return decoded
+			}
+			return text.Text
+		}
+	}
+	return result.Content
+}
@@ -0,0 +1,102 @@
+package mcpwrapper
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func TestRegisterBatch(t *testing.T) {
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	wrapper := New(mcpServer)
+
+	var running, peak int32
+	wrapper.Register("greet", "Greet a user", TestArgs{}, func(ctx context.Context, args interface{}) (interface{}, error) {
+		n := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		a := args.(*TestArgs)
+		if a.Name == "Mallory" {
+			return nil, errors.New("not allowed")
+		}
+		return &TestResult{Message: "hello " + a.Name}, nil
+	})
+	if err := wrapper.RegisterBatch("batch", 2); err != nil {
+		t.Fatalf("RegisterBatch failed: %v", err)
+	}
+
+	call := func(name string) map[string]interface{} {
+		return map[string]interface{}{"tool": "greet", "arguments": map[string]interface{}{"name": name, "age": 30, "category": "A"}}
+	}
+	run := func(args map[string]interface{}) []BatchResult {
+		t.Helper()
+		result := callTool(t, mcpServer, "batch", args)
+		if result.IsError {
+			t.Fatalf("Batch failed: %v", result.Content)
+		}
+		var output BatchOutput
+		if err := json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &output); err != nil {
+			t.Fatalf("Invalid batch result: %v", err)
+		}
+		return output.Results
+	}
+
+	results := run(map[string]interface{}{"calls": []interface{}{
+		call("Alice"),
+		call("Mallory"),
+		call("Al"),
+		map[string]interface{}{"tool": "missing"},
+		map[string]interface{}{"tool": "batch"},
+		call("Bob"),
+	}})
+	if len(results) != 6 {
+		t.Fatalf("Expected 6 results, got %v", results)
+	}
+	if results[0].Result.(map[string]interface{})["message"] != "hello Alice" || results[0].Error != "" {
+		t.Errorf("Unexpected first result: %+v", results[0])
+	}
+	if results[1].Error != "handler error: not allowed" {
+		t.Errorf("Unexpected handler failure: %+v", results[1])
+	}
+	if results[2].Error == "" {
+		t.Errorf("Expected a validation failure, got %+v", results[2])
+	}
+	if results[3].Error != "tool missing not found" || results[4].Error != "a batch cannot call itself" {
+		t.Errorf("Unexpected errors: %+v %+v", results[3], results[4])
+	}
+	if peak != 1 {
+		t.Errorf("Expected in-order calls, got %d at once", peak)
+	}
+
+	results = run(map[string]interface{}{"stop_on_error": true, "calls": []interface{}{call("Mallory"), call("Bob")}})
+	if results[1].Error != "skipped after a failed call" {
+		t.Errorf("Expected the second call to be skipped, got %+v", results[1])
+	}
+
+	atomic.StoreInt32(&peak, 0)
+	results = run(map[string]interface{}{"concurrent": true, "calls": []interface{}{call("A1a"), call("B2b"), call("C3c"), call("D4d")}})
+	if peak != 2 {
+		t.Errorf("Expected at most 2 concurrent calls, got %d", peak)
+	}
+	for i, want := range []string{"hello A1a", "hello B2b", "hello C3c", "hello D4d"} {
+		if results[i].Result.(map[string]interface{})["message"] != want {
+			t.Errorf("Result %d out of order: %+v", i, results[i])
+		}
+	}
+
+	if result := callTool(t, mcpServer, "batch", map[string]interface{}{"calls": []interface{}{}}); !result.IsError {
+		t.Error("Expected an empty batch to be rejected")
+	}
+}
@@ -0,0 +1,91 @@
+//go:build !nobbolt
+
+package mcpwrapper
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// boltJobBucket is the bucket a bbolt store keeps its jobs in.
+var boltJobBucket = []byte("mcpwrapper_jobs")
+
+// boltJobStore keeps jobs in a bbolt bucket, keyed by ID.
+type boltJobStore struct {
+	db        *bolt.DB
+	encryptor *Encryptor
+}
+
+// NewBoltJobStore returns a JobStore that keeps jobs in the mcpwrapper_jobs
+// bucket of db, so they survive restarts. The bucket is created if needed;
+// the caller opens db and closes it after Shutdown, and may keep its own
+// buckets in the same file. If encryptor is not nil, jobs are sealed with
+// it, since results and arguments may hold sensitive data.
+func NewBoltJobStore(db *bolt.DB, encryptor *Encryptor) (JobStore, error) {
+	err := db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(boltJobBucket)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create job store: %w", err)
+	}
+	return &boltJobStore{db: db, encryptor: encryptor}, nil
+}
+
+func (s *boltJobStore) Save(ctx context.Context, job *Job) error {
+	if job.ID == "" {
+		return fmt.Errorf("invalid job ID %q", job.ID)
+	}
+	data, err := encodeJob(job, s.encryptor)
+	if err != nil {
+		return err
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltJobBucket).Put([]byte(job.ID), data)
+	})
+}
+
+func (s *boltJobStore) Load(ctx context.Context, id string) (*Job, error) {
+	var job *Job
+	err := s.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(boltJobBucket).Get([]byte(id))
+		if data == nil {
+			return nil
+		}
+		var err error
+		job, err = decodeJob(data, s.encryptor)
+		return err
+	})
+	return job, err
+}
+
+func (s *boltJobStore) List(ctx context.Context) ([]*Job, error) {
+	var jobs []*Job
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltJobBucket).ForEach(func(id, data []byte) error {
+			job, err := decodeJob(data, s.encryptor)
+			if err != nil {
+				return fmt.Errorf("job %s: %w", id, err)
+			}
+			jobs = append(jobs, job)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].SubmittedAt.Before(jobs[j].SubmittedAt) })
+	return jobs, nil
+}
+
+func (s *boltJobStore) Delete(ctx context.Context, id string) error {
+	if id == "" {
+		return nil
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltJobBucket).Delete([]byte(id))
+	})
+}
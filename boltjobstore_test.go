@@ -0,0 +1,94 @@
+//go:build !nobbolt
+
+package mcpwrapper
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+func openTestBolt(t *testing.T, path string) *bolt.DB {
+	t.Helper()
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		t.Fatalf("bolt.Open failed: %v", err)
+	}
+	return db
+}
+
+func TestBoltJobStore(t *testing.T) {
+	encryptor := NewEncryptor(StaticSecrets("k1", map[string][]byte{"k1": bytes.Repeat([]byte{7}, 32)}))
+	for name, enc := range map[string]*Encryptor{"plain": nil, "encrypted": encryptor} {
+		t.Run(name, func(t *testing.T) {
+			db := openTestBolt(t, filepath.Join(t.TempDir(), "jobs.db"))
+			defer db.Close()
+			store, err := NewBoltJobStore(db, enc)
+			if err != nil {
+				t.Fatalf("NewBoltJobStore failed: %v", err)
+			}
+			testJobStore(t, store)
+		})
+	}
+}
+
+func TestBoltJobStoreEncryption(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "jobs.db")
+	db := openTestBolt(t, path)
+	encryptor := NewEncryptor(StaticSecrets("k1", map[string][]byte{"k1": bytes.Repeat([]byte{7}, 32)}))
+	store, err := NewBoltJobStore(db, encryptor)
+	if err != nil {
+		t.Fatalf("NewBoltJobStore failed: %v", err)
+	}
+	store.Save(context.Background(), &Job{ID: "a1", Tool: "login", Arguments: map[string]interface{}{"password": "hunter2"}})
+	db.Close()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+	if bytes.Contains(data, []byte("hunter2")) {
+		t.Error("Expected the job to be encrypted")
+	}
+}
+
+func TestBoltJobStoreRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "jobs.db")
+	newStore := func(db *bolt.DB) JobStore {
+		store, err := NewBoltJobStore(db, nil)
+		if err != nil {
+			t.Fatalf("NewBoltJobStore failed: %v", err)
+		}
+		return store
+	}
+
+	db := openTestBolt(t, path)
+	release := make(chan struct{}, 1)
+	before := newJobsServer(t, release, WithJobStore(newStore(db)))
+	var done, interrupted Job
+	decodeResult(t, callTool(t, before, "submit", map[string]interface{}{"tool": "slow", "arguments": map[string]interface{}{"name": "a"}}), &done)
+	release <- struct{}{}
+	decodeResult(t, callTool(t, before, "job_result", map[string]interface{}{"job_id": done.ID, "wait_ms": 5000}), &TestResult{})
+	decodeResult(t, callTool(t, before, "submit", map[string]interface{}{"tool": "slow", "arguments": map[string]interface{}{"name": "b"}}), &interrupted)
+	waitForJobState(t, before, interrupted.ID, JobRunning)
+	db.Close()
+
+	db = openTestBolt(t, path)
+	defer db.Close()
+	after := newJobsServer(t, make(chan struct{}), WithJobStore(newStore(db)))
+	var output TestResult
+	decodeResult(t, callTool(t, after, "job_result", map[string]interface{}{"job_id": done.ID}), &output)
+	if output.Message != "done a" {
+		t.Errorf("Expected the result to survive the restart, got %+v", output)
+	}
+	result := callTool(t, after, "job_result", map[string]interface{}{"job_id": interrupted.ID})
+	if !result.IsError || !strings.Contains(resultText(result), "server stopped") {
+		t.Errorf("Expected the interrupted job to have failed, got %v", result.Content)
+	}
+}
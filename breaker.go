@@ -0,0 +1,101 @@
+package mcpwrapper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+type CircuitBreakerConfig struct {
+	// Threshold is the number of consecutive failures that opens the circuit.
+	Threshold int `json:"threshold" yaml:"threshold"`
+	// Cooldown is how long the circuit stays open before a trial call is let through.
+	Cooldown time.Duration `json:"cooldown" yaml:"cooldown"`
+}
+
+func (c *CircuitBreakerConfig) validate() error {
+	if c.Threshold <= 0 {
+		return fmt.Errorf("threshold must be positive")
+	}
+	if c.Cooldown < 0 {
+		return fmt.Errorf("cooldown must not be negative")
+	}
+	return nil
+}
+
+type circuitBreaker struct {
+	mu       sync.Mutex
+	cfg      CircuitBreakerConfig
+	failures int
+	openedAt time.Time
+	trial    bool
+	now      func() time.Time
+}
+
+// errPanicked is recorded for calls that panic.
+var errPanicked = errors.New("call panicked")
+
+// CircuitBreaker returns a middleware that fails fast with an
+// *UnavailableError once a tool has failed Threshold times in a row. After
+// Cooldown a single trial call is allowed; its outcome closes or re-opens the
+// circuit. A call that panics counts as a failure. Each tool the middleware
+// wraps gets its own circuit.
+func CircuitBreaker(cfg CircuitBreakerConfig) Middleware {
+	return func(next Handler) Handler {
+		cb := &circuitBreaker{cfg: cfg, now: time.Now}
+		return func(ctx context.Context, args interface{}) (result interface{}, err error) {
+			if err := cb.allow(ToolNameFromContext(ctx)); err != nil {
+				return nil, err
+			}
+
+			// Recorded in a defer, so that a panicking trial call does not
+			// leave the circuit waiting for its outcome forever.
+			outcome := errPanicked
+			defer func() { cb.record(outcome) }()
+			result, err = next(ctx, args)
+			outcome = err
+			return result, err
+		}
+	}
+}
+
+func (cb *circuitBreaker) allow(tool string) error {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if cb.openedAt.IsZero() {
+		return nil
+	}
+
+	reopens := cb.openedAt.Add(cb.cfg.Cooldown)
+	if cb.trial || cb.now().Before(reopens) {
+		return &UnavailableError{
+			Tool:   tool,
+			Until:  reopens,
+			Reason: fmt.Sprintf("temporarily unavailable after %d consecutive failures", cb.failures),
+		}
+	}
+
+	cb.trial = true
+	return nil
+}
+
+func (cb *circuitBreaker) record(err error) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	cb.trial = false
+
+	if err == nil {
+		cb.failures = 0
+		cb.openedAt = time.Time{}
+		return
+	}
+
+	cb.failures++
+	if cb.cfg.Threshold > 0 && cb.failures >= cb.cfg.Threshold {
+		cb.openedAt = cb.now()
+	}
+}
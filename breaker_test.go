@@ -0,0 +1,98 @@
+package mcpwrapper
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	now := time.Now()
+	cb := &circuitBreaker{
+		cfg: CircuitBreakerConfig{Threshold: 3, Cooldown: time.Minute},
+		now: func() time.Time { return now },
+	}
+
+	failure := errors.New("downstream failed")
+	for i := 0; i < 3; i++ {
+		if err := cb.allow("fetch"); err != nil {
+			t.Fatalf("Expected call %d to be allowed, got %v", i+1, err)
+		}
+		cb.record(failure)
+	}
+
+	var unavailable *UnavailableError
+	if err := cb.allow("fetch"); !errors.As(err, &unavailable) {
+		t.Fatalf("Expected open circuit, got %v", err)
+	}
+
+	if !unavailable.Until.Equal(now.Add(time.Minute)) {
+		t.Errorf("Expected circuit to reopen at %v, got %v", now.Add(time.Minute), unavailable.Until)
+	}
+
+	now = now.Add(2 * time.Minute)
+	if err := cb.allow("fetch"); err != nil {
+		t.Fatalf("Expected trial call after cooldown, got %v", err)
+	}
+
+	if err := cb.allow("fetch"); err == nil {
+		t.Error("Expected concurrent calls to be rejected during trial")
+	}
+
+	cb.record(nil)
+	if err := cb.allow("fetch"); err != nil {
+		t.Errorf("Expected closed circuit after successful trial, got %v", err)
+	}
+}
+
+func TestCircuitBreakerTrialPanics(t *testing.T) {
+	calls := 0
+	handler := CircuitBreaker(CircuitBreakerConfig{Threshold: 1, Cooldown: 10 * time.Millisecond})(
+		func(ctx context.Context, args interface{}) (interface{}, error) {
+			calls++
+			if calls == 2 {
+				panic("trial blew up")
+			}
+			return nil, errors.New("downstream failed")
+		})
+	call := func() (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = errPanicked
+			}
+		}()
+		_, err = handler(context.Background(), nil)
+		return err
+	}
+
+	call()
+	time.Sleep(20 * time.Millisecond)
+	if err := call(); err != errPanicked {
+		t.Fatalf("Expected the trial call to panic, got %v", err)
+	}
+
+	var unavailable *UnavailableError
+	if err := call(); !errors.As(err, &unavailable) {
+		t.Fatalf("Expected the panic to re-open the circuit, got %v", err)
+	}
+	time.Sleep(20 * time.Millisecond)
+	if err := call(); errors.As(err, &unavailable) || calls != 3 {
+		t.Errorf("Expected another trial call after the cooldown, got %v after %d calls", err, calls)
+	}
+}
+
+func TestParseConfigCircuitBreakerErrors(t *testing.T) {
+	for _, breaker := range []string{
+		`{threshold: 0, cooldown: 1s}`,
+		`{threshold: -1, cooldown: 1s}`,
+		`{threshold: 3, cooldown: -1s}`,
+	} {
+		if _, err := ParseConfig([]byte("tools:\n  greet:\n    circuit_breaker: " + breaker + "\n")); err == nil {
+			t.Errorf("Expected %s to be rejected", breaker)
+		}
+	}
+	if _, err := ParseConfig([]byte("tools:\n  greet:\n    circuit_breaker: {threshold: 3, cooldown: 30s}\n")); err != nil {
+		t.Errorf("Expected a valid circuit breaker to load, got %v", err)
+	}
+}
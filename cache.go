@@ -0,0 +1,133 @@
+package mcpwrapper
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+)
+
+type CacheConfig struct {
+	TTL        time.Duration `json:"ttl" yaml:"ttl"`
+	MaxEntries int           `json:"max_entries" yaml:"max_entries"`
+}
+
+func (c *CacheConfig) validate() error {
+	if c.TTL < 0 {
+		return fmt.Errorf("ttl must not be negative")
+	}
+	if c.MaxEntries < 0 {
+		return fmt.Errorf("max_entries must not be negative")
+	}
+	return nil
+}
+
+type resultCache struct {
+	mu         sync.Mutex
+	ttl        time.Duration
+	maxEntries int
+	order      *list.List
+	entries    map[string]*list.Element
+	now        func() time.Time
+}
+
+type cacheEntry struct {
+	key     string
+	result  interface{}
+	expires time.Time
+}
+
+// Cache returns a middleware that memoizes successful results keyed by tool
+// name and canonical JSON of the validated arguments. Only use it for
+// idempotent tools. A zero TTL never expires; a zero MaxEntries is unbounded.
+func Cache(cfg CacheConfig) Middleware {
+	c := &resultCache{
+		ttl:        cfg.TTL,
+		maxEntries: cfg.MaxEntries,
+		order:      list.New(),
+		entries:    make(map[string]*list.Element),
+		now:        time.Now,
+	}
+	return c.middleware
+}
+
+func (c *resultCache) middleware(next Handler) Handler {
+	return func(ctx context.Context, args interface{}) (interface{}, error) {
+		key, err := cacheKey(ToolNameFromContext(ctx), args)
+		if err != nil {
+			return next(ctx, args)
+		}
+
+		if result, ok := c.get(key); ok {
+			recordCacheLookup(ctx, true)
+			return result, nil
+		}
+		recordCacheLookup(ctx, false)
+
+		result, err := next(ctx, args)
+		if err != nil {
+			return nil, err
+		}
+
+		c.put(key, result)
+		return result, nil
+	}
+}
+
+// cacheKey relies on encoding/json emitting struct fields in declaration
+// order and map keys sorted, which makes the encoding canonical.
+func cacheKey(name string, args interface{}) (string, error) {
+	data, err := json.Marshal(args)
+	if err != nil {
+		return "", err
+	}
+	return name + "\x00" + string(data), nil
+}
+
+func (c *resultCache) get(key string) (interface{}, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := elem.Value.(*cacheEntry)
+	if !entry.expires.IsZero() && c.now().After(entry.expires) {
+		c.order.Remove(elem)
+		delete(c.entries, key)
+		return nil, false
+	}
+
+	c.order.MoveToFront(elem)
+	return entry.result, true
+}
+
+func (c *resultCache) put(key string, result interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var expires time.Time
+	if c.ttl > 0 {
+		expires = c.now().Add(c.ttl)
+	}
+
+	if elem, ok := c.entries[key]; ok {
+		entry := elem.Value.(*cacheEntry)
+		entry.result = result
+		entry.expires = expires
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&cacheEntry{key: key, result: result, expires: expires})
+
+	if c.maxEntries > 0 && c.order.Len() > c.maxEntries {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+	}
+}
@@ -0,0 +1,98 @@
+package mcpwrapper
+
+import (
+	"container/list"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func TestCacheMiddleware(t *testing.T) {
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	wrapper := New(mcpServer)
+
+	calls := 0
+	handler := func(ctx context.Context, args interface{}) (interface{}, error) {
+		calls++
+		return &TestResult{Message: "cached"}, nil
+	}
+
+	err := wrapper.Register("test-tool", "Test tool", TestArgs{}, handler,
+		WithToolMiddleware(Cache(CacheConfig{TTL: time.Minute})))
+	if err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	call := func(name string) {
+		request := mcp.CallToolRequest{
+			Params: mcp.CallToolParams{
+				Name: "test-tool",
+				Arguments: map[string]interface{}{
+					"name":     name,
+					"age":      30,
+					"category": "A",
+				},
+			},
+		}
+		result, err := mcpServer.GetTool("test-tool").Handler(context.Background(), request)
+		if err != nil || result.IsError {
+			t.Fatalf("Handler invocation failed: %v %v", err, result)
+		}
+	}
+
+	call("Alice")
+	call("Alice")
+	if calls != 1 {
+		t.Errorf("Expected 1 handler call for identical args, got %d", calls)
+	}
+
+	call("Bob")
+	if calls != 2 {
+		t.Errorf("Expected 2 handler calls for different args, got %d", calls)
+	}
+}
+
+func TestCacheExpiryAndEviction(t *testing.T) {
+	now := time.Now()
+	c := &resultCache{
+		ttl:        time.Minute,
+		maxEntries: 2,
+		order:      list.New(),
+		entries:    make(map[string]*list.Element),
+		now:        func() time.Time { return now },
+	}
+
+	c.put("a", 1)
+	c.put("b", 2)
+	c.put("c", 3)
+
+	if _, ok := c.get("a"); ok {
+		t.Error("Expected oldest entry to be evicted")
+	}
+
+	if v, ok := c.get("b"); !ok || v != 2 {
+		t.Errorf("Expected entry b, got %v", v)
+	}
+
+	now = now.Add(2 * time.Minute)
+	if _, ok := c.get("c"); ok {
+		t.Error("Expected entry to expire after TTL")
+	}
+}
+
+func TestParseConfigCacheErrors(t *testing.T) {
+	for _, cache := range []string{
+		`{ttl: -1s}`,
+		`{ttl: 1m, max_entries: -1}`,
+	} {
+		if _, err := ParseConfig([]byte("tools:\n  greet:\n    cache: " + cache + "\n")); err == nil {
+			t.Errorf("Expected %s to be rejected", cache)
+		}
+	}
+	if _, err := ParseConfig([]byte("tools:\n  greet:\n    cache: {ttl: 0s, max_entries: 0}\n")); err != nil {
+		t.Errorf("Expected zero values to load, got %v", err)
+	}
+}
@@ -0,0 +1,119 @@
+package mcpwrapper
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const methodNotificationCancelled = "notifications/cancelled"
+
+// inflightCalls maps MCP request IDs to the cancel functions of running tool
+// calls. The request ID is not passed to tool handlers, so a BeforeCallTool
+// hook records it keyed by the request's _meta pointer, which is shared by
+// the copy of the request the handler receives. AfterCallTool and OnError
+// hooks drop it again for calls that never reach a wrapper handler, such as
+// calls to unknown tools.
+type inflightCalls struct {
+	mu      sync.Mutex
+	ids     map[*mcp.Meta]string
+	cancels map[string]context.CancelFunc
+}
+
+func newInflightCalls() *inflightCalls {
+	return &inflightCalls{
+		ids:     make(map[*mcp.Meta]string),
+		cancels: make(map[string]context.CancelFunc),
+	}
+}
+
+func inflightKey(sessionID string, requestID interface{}) string {
+	if id, ok := requestID.(mcp.RequestId); ok {
+		return sessionID + "|" + id.String()
+	}
+	return sessionID + "|" + mcp.NewRequestId(requestID).String()
+}
+
+func (c *inflightCalls) tag(ctx context.Context, id interface{}, request *mcp.CallToolRequest) {
+	if request.Params.Meta == nil {
+		request.Params.Meta = &mcp.Meta{}
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ids[request.Params.Meta] = inflightKey(SessionIDFromContext(ctx), id)
+}
+
+func (c *inflightCalls) untag(ctx context.Context, id interface{}, request *mcp.CallToolRequest, result *mcp.CallToolResult) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.ids, request.Params.Meta)
+}
+
+func (c *inflightCalls) untagFailed(ctx context.Context, id interface{}, method mcp.MCPMethod, message interface{}, err error) {
+	if request, ok := message.(*mcp.CallToolRequest); ok && method == mcp.MethodToolsCall {
+		c.untag(ctx, id, request, nil)
+	}
+}
+
+// start makes ctx cancellable by a notifications/cancelled for the request.
+// The returned function must be called when the call completes.
+func (c *inflightCalls) start(ctx context.Context, request mcp.CallToolRequest) (context.Context, func()) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	c.mu.Lock()
+	key, ok := c.ids[request.Params.Meta]
+	if ok {
+		delete(c.ids, request.Params.Meta)
+		c.cancels[key] = cancel
+	}
+	c.mu.Unlock()
+
+	return ctx, func() {
+		if ok {
+			c.mu.Lock()
+			delete(c.cancels, key)
+			c.mu.Unlock()
+		}
+		cancel()
+	}
+}
+
+func (c *inflightCalls) cancel(ctx context.Context, notification mcp.JSONRPCNotification) {
+	requestID, ok := notification.Params.AdditionalFields["requestId"]
+	if !ok {
+		return
+	}
+
+	c.mu.Lock()
+	cancel, ok := c.cancels[inflightKey(SessionIDFromContext(ctx), requestID)]
+	c.mu.Unlock()
+
+	if ok {
+		cancel()
+	}
+}
+
+func (w *Wrapper) installCancellation(hooks *server.Hooks) {
+	hooks.AddBeforeCallTool(w.inflight.tag)
+	hooks.AddAfterCallTool(w.inflight.untag)
+	hooks.AddOnError(w.inflight.untagFailed)
+}
+
+// CheckCancelled returns a non-nil error once the client has cancelled the
+// call (or its deadline has passed). Long-running handlers should call it
+// between units of work and return the error to stop promptly.
+func CheckCancelled(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("call cancelled: %w", err)
+	}
+	return nil
+}
+
+// WithCancellable advertises in the tool's _meta that the handler honours
+// cancellation, so clients know that cancelling a call stops the work.
+func WithCancellable() ToolOption {
+	return WithToolMeta("cancellable", true)
+}
@@ -0,0 +1,102 @@
+package mcpwrapper
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func TestCancellationNotification(t *testing.T) {
+	hooks := &server.Hooks{}
+	mcpServer := server.NewMCPServer("test", "1.0.0", server.WithHooks(hooks))
+	wrapper := New(mcpServer, WithHooks(hooks))
+
+	started := make(chan struct{})
+	handler := func(ctx context.Context, args interface{}) (interface{}, error) {
+		close(started)
+		select {
+		case <-ctx.Done():
+			return nil, CheckCancelled(ctx)
+		case <-time.After(5 * time.Second):
+			return &TestResult{Message: "finished"}, nil
+		}
+	}
+
+	if err := wrapper.Register("slow", "Slow tool", TestArgs{}, handler, WithCancellable()); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	if meta := mcpServer.GetTool("slow").Tool.Meta; meta == nil || meta.AdditionalFields["cancellable"] != true {
+		t.Error("Expected tool to advertise cancellable in _meta")
+	}
+
+	ctx := mcpServer.WithContext(context.Background(), &testSession{id: "session-1"})
+
+	response := make(chan mcp.JSONRPCMessage, 1)
+	go func() {
+		response <- mcpServer.HandleMessage(ctx, json.RawMessage(`{
+			"jsonrpc": "2.0", "id": 7, "method": "tools/call",
+			"params": {"name": "slow", "arguments": {"name": "Alice", "age": 30, "category": "A"}}
+		}`))
+	}()
+
+	<-started
+	mcpServer.HandleMessage(ctx, json.RawMessage(`{
+		"jsonrpc": "2.0", "method": "notifications/cancelled",
+		"params": {"requestId": 7, "reason": "user aborted"}
+	}`))
+
+	select {
+	case msg := <-response:
+		result := msg.(mcp.JSONRPCResponse).Result.(mcp.CallToolResult)
+		if !result.IsError {
+			t.Errorf("Expected cancelled call to return an error result, got %v", result.Content)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Handler was not cancelled")
+	}
+}
+
+func TestCheckCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	if err := CheckCancelled(ctx); err != nil {
+		t.Errorf("Expected nil before cancellation, got %v", err)
+	}
+
+	cancel()
+	if err := CheckCancelled(ctx); err == nil {
+		t.Error("Expected error after cancellation")
+	}
+}
+
+func TestCancellationForgetsUnknownTools(t *testing.T) {
+	hooks := &server.Hooks{}
+	mcpServer := server.NewMCPServer("test", "1.0.0", server.WithHooks(hooks))
+	wrapper := New(mcpServer, WithHooks(hooks))
+	wrapper.Register("greet", "Greet", TestArgs{}, func(ctx context.Context, args interface{}) (interface{}, error) {
+		return &TestResult{Message: "hello"}, nil
+	})
+	mcpServer.AddTool(mcp.NewTool("plain"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("plain"), nil
+	})
+
+	ctx := mcpServer.WithContext(context.Background(), &testSession{id: "session-1"})
+	for i, tool := range []string{"missing", "plain", "greet"} {
+		mcpServer.HandleMessage(ctx, json.RawMessage(fmt.Sprintf(
+			`{"jsonrpc": "2.0", "id": %d, "method": "tools/call", "params": {"name": %q, "arguments": {"name": "Alice", "age": 30, "category": "A"}}}`, i, tool)))
+	}
+	wrapper.Shutdown(context.Background())
+	mcpServer.HandleMessage(ctx, json.RawMessage(`{"jsonrpc": "2.0", "id": 9, "method": "tools/call", "params": {"name": "greet", "arguments": {}}}`))
+
+	wrapper.inflight.mu.Lock()
+	defer wrapper.inflight.mu.Unlock()
+	if len(wrapper.inflight.ids) != 0 || len(wrapper.inflight.cancels) != 0 {
+		t.Errorf("Expected no call to be left tracked, got %v and %v", wrapper.inflight.ids, wrapper.inflight.cancels)
+	}
+}
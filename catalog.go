@@ -0,0 +1,114 @@
+package mcpwrapper
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// CatalogFormat selects the layout of ExportManifest.
+type CatalogFormat string
+
+const (
+	// CatalogMCP is the tools/list result: {"tools": [...]}.
+	CatalogMCP CatalogFormat = "mcp"
+	// CatalogOpenAPI is an OpenAPI 3.1 document with one POST /tools/{name}
+	// operation per tool, for tooling that only understands OpenAPI.
+	CatalogOpenAPI CatalogFormat = "openapi"
+)
+
+// ExportManifest returns the catalog of listed tools (names, descriptions,
+// input and output schemas, annotations and _meta) as indented JSON. Tools
+// are sorted by name and object keys are sorted, so the output is stable
+// enough to diff in CI. Tools hidden by a FeatureGate or SetEnabled are
+// included, since the catalog does not depend on a session.
+func (w *Wrapper) ExportManifest(format CatalogFormat) ([]byte, error) {
+	tools := w.listedTools()
+
+	var catalog interface{}
+	switch format {
+	case CatalogMCP, "":
+		catalog = mcp.ListToolsResult{Tools: tools}
+	case CatalogOpenAPI:
+		catalog = openAPICatalog(tools)
+	default:
+		return nil, fmt.Errorf("unknown catalog format %q", format)
+	}
+
+	return json.MarshalIndent(catalog, "", "  ")
+}
+
+func (w *Wrapper) listedTools() []mcp.Tool {
+	listed := w.server.ListTools()
+	tools := make([]mcp.Tool, 0, len(listed))
+	for _, t := range listed {
+		tools = append(tools, t.Tool)
+	}
+	sort.Slice(tools, func(i, j int) bool { return tools[i].Name < tools[j].Name })
+	return tools
+}
+
+func openAPICatalog(tools []mcp.Tool) map[string]interface{} {
+	paths := make(map[string]interface{}, len(tools))
+	for _, tool := range tools {
+		var output interface{} = map[string]interface{}{}
+		if tool.OutputSchema.Type != "" {
+			output = schemaResource(tool.OutputSchema, "urn:mcp:tool:"+tool.Name+":output")
+		}
+
+		operation := map[string]interface{}{
+			"operationId": tool.Name,
+			"description": tool.Description,
+			"requestBody": map[string]interface{}{
+				"required": true,
+				"content": map[string]interface{}{
+					"application/json": map[string]interface{}{"schema": schemaResource(tool.InputSchema, "urn:mcp:tool:"+tool.Name+":input")},
+				},
+			},
+			"responses": map[string]interface{}{
+				"200": map[string]interface{}{
+					"description": "Tool result",
+					"content": map[string]interface{}{
+						"application/json": map[string]interface{}{"schema": output},
+					},
+				},
+			},
+			"x-mcp-annotations": tool.Annotations,
+		}
+		if tool.Annotations.Title != "" {
+			operation["summary"] = tool.Annotations.Title
+		}
+		if tool.Meta != nil && len(tool.Meta.AdditionalFields) > 0 {
+			operation["x-mcp-meta"] = tool.Meta.AdditionalFields
+		}
+
+		paths["/tools/"+tool.Name] = map[string]interface{}{"post": operation}
+	}
+
+	return map[string]interface{}{
+		"openapi": "3.1.0",
+		"info":    map[string]interface{}{"title": "MCP tools", "version": "1.0.0"},
+		"paths":   paths,
+	}
+}
+
+// schemaResource gives a schema with $defs its own $id, so its "#/$defs/..."
+// references resolve inside the schema instead of against the OpenAPI
+// document it is embedded in.
+func schemaResource(schema interface{}, id string) interface{} {
+	data, err := json.Marshal(schema)
+	if err != nil {
+		return schema
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return schema
+	}
+	if _, ok := m["$defs"]; !ok {
+		return schema
+	}
+	m["$id"] = id
+	return m
+}
@@ -0,0 +1,121 @@
+package mcpwrapper
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func TestExportManifest(t *testing.T) {
+	wrapper := New(server.NewMCPServer("test", "1.0.0"))
+	handler := func(ctx context.Context, args interface{}) (interface{}, error) {
+		return &TestResult{Message: "ok"}, nil
+	}
+
+	output := map[string]interface{}{
+		"type":       "object",
+		"properties": map[string]interface{}{"message": map[string]interface{}{"type": "string"}},
+	}
+	if err := wrapper.Register("lookup", "Look up a user", TestArgs{}, handler, WithReadOnly(), WithOutputSchema(output)); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	if err := wrapper.Register("delete_user", "Delete a user", TestArgs{}, handler, WithDeprecated("use remove_user", "remove_user")); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	data, err := wrapper.ExportManifest(CatalogMCP)
+	if err != nil {
+		t.Fatalf("ExportManifest failed: %v", err)
+	}
+	var list struct {
+		Tools []mcp.Tool `json:"tools"`
+	}
+	if err := json.Unmarshal(data, &list); err != nil {
+		t.Fatalf("Invalid MCP catalog: %v\n%s", err, data)
+	}
+	if len(list.Tools) != 2 || list.Tools[0].Name != "delete_user" || list.Tools[1].Name != "lookup" {
+		t.Fatalf("Expected tools sorted by name, got %+v", list.Tools)
+	}
+	if list.Tools[1].InputSchema.Properties["category"] == nil || list.Tools[1].OutputSchema.Type != "object" {
+		t.Errorf("Expected schemas in the catalog, got %+v", list.Tools[1])
+	}
+	if again, _ := wrapper.ExportManifest(CatalogMCP); string(again) != string(data) {
+		t.Error("Expected the catalog to be stable")
+	}
+
+	data, err = wrapper.ExportManifest(CatalogOpenAPI)
+	if err != nil {
+		t.Fatalf("ExportManifest failed: %v", err)
+	}
+	var doc struct {
+		OpenAPI string `json:"openapi"`
+		Paths   map[string]struct {
+			Post map[string]interface{} `json:"post"`
+		} `json:"paths"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("Invalid OpenAPI catalog: %v\n%s", err, data)
+	}
+	lookup := doc.Paths["/tools/lookup"].Post
+	if doc.OpenAPI != "3.1.0" || lookup["operationId"] != "lookup" {
+		t.Fatalf("Unexpected OpenAPI catalog: %s", data)
+	}
+	if lookup["x-mcp-annotations"].(map[string]interface{})["readOnlyHint"] != true {
+		t.Errorf("Expected annotations as an extension, got %v", lookup["x-mcp-annotations"])
+	}
+	if doc.Paths["/tools/delete_user"].Post["x-mcp-meta"] == nil {
+		t.Error("Expected _meta as an extension")
+	}
+
+	if _, err := wrapper.ExportManifest("xml"); err == nil {
+		t.Error("Expected an error for an unknown format")
+	}
+
+	rec := httptest.NewRecorder()
+	wrapper.AdminHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/catalog?format=openapi", nil))
+	if rec.Code != http.StatusOK || rec.Body.String() != string(data) {
+		t.Errorf("Unexpected /catalog response: %d %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestOpenAPICatalogDefs(t *testing.T) {
+	wrapper := New(server.NewMCPServer("test", "1.0.0"))
+	handler := func(ctx context.Context, args interface{}) (interface{}, error) {
+		return "ok", nil
+	}
+	wrapper.Register("ship", "Ship a parcel", ShipmentArgs{}, handler)
+	wrapper.Register("greet", "Greet a user", TestArgs{}, handler)
+
+	data, err := wrapper.ExportManifest(CatalogOpenAPI)
+	if err != nil {
+		t.Fatalf("ExportManifest failed: %v", err)
+	}
+	var doc struct {
+		Paths map[string]struct {
+			Post struct {
+				RequestBody struct {
+					Content map[string]struct {
+						Schema map[string]interface{} `json:"schema"`
+					} `json:"content"`
+				} `json:"requestBody"`
+			} `json:"post"`
+		} `json:"paths"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("Invalid OpenAPI catalog: %v", err)
+	}
+
+	ship := doc.Paths["/tools/ship"].Post.RequestBody.Content["application/json"].Schema
+	if ship["$id"] != "urn:mcp:tool:ship:input" || ship["$defs"] == nil {
+		t.Errorf("Expected a schema resource with $defs, got %v", ship)
+	}
+	greet := doc.Paths["/tools/greet"].Post.RequestBody.Content["application/json"].Schema
+	if _, ok := greet["$id"]; ok {
+		t.Errorf("Expected no $id without $defs, got %v", greet)
+	}
+}
@@ -0,0 +1,56 @@
+package mcpwrapper
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// argsProperty is the property holding positional arguments in schemas
+// built from a CLI's flags.
+const argsProperty = "args"
+
+// durationPattern matches the durations time.ParseDuration accepts.
+const durationPattern = `^[-+]?(0|([0-9]*(\.[0-9]*)?(ns|us|µs|μs|ms|s|m|h))+)$`
+
+// argumentString formats a JSON value the way it would be typed on the
+// command line: numbers without exponents, objects as JSON.
+func argumentString(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case map[string]interface{}:
+		data, _ := json.Marshal(v)
+		return string(data)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// jsonFlagValue converts a flag value string to the JSON type of its
+// schema, keeping it a string if it doesn't parse.
+func jsonFlagValue(typ, s string) interface{} {
+	switch typ {
+	case "boolean":
+		if b, err := strconv.ParseBool(s); err == nil {
+			return b
+		}
+	case "integer", "number":
+		if n, err := strconv.ParseFloat(s, 64); err == nil {
+			return n
+		}
+	}
+	return s
+}
+
+func stringValues(values []string) []interface{} {
+	list := make([]interface{}, len(values))
+	for i, v := range values {
+		list[i] = v
+	}
+	return list
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"text/template"
+)
+
+var fileTemplate = template.Must(template.New("file").Parse(`// Code generated by mcpwrapper-gen. DO NOT EDIT.
+
+package {{.Pkg.Name}}
+
+import (
+	"context"
+{{- if .Client}}
+	"encoding/json"
+	"fmt"
+{{- end}}
+
+	mcpwrapper "github.com/aleksadvaisly/mcp-go-wrapper"
+{{- if .Client}}
+	"github.com/mark3labs/mcp-go/mcp"
+{{- end}}
+)
+
+{{- if .HasFieldDocs}}
+
+func init() {
+{{- range .Pkg.Tools}}
+{{- if .FieldDocs}}
+	mcpwrapper.RegisterFieldDocs({{.Args}}{}, map[string]string{
+{{- range .FieldDocs}}
+		{{printf "%q" .Field}}: {{printf "%q" .Doc}},
+{{- end}}
+	})
+{{- end}}
+{{- end}}
+}
+{{- end}}
+
+// ToolHandlers implements the tools of package {{.Pkg.Name}}.
+type ToolHandlers interface {
+{{- range .Pkg.Tools}}
+	{{.Method}}(ctx context.Context, args *{{.Args}}) ({{if .Result}}*{{.Result}}{{else}}interface{}{{end}}, error)
+{{- end}}
+}
+
+// RegisterTools registers every tool of package {{.Pkg.Name}}. opts apply to
+// each of them.
+func RegisterTools(w *mcpwrapper.Wrapper, h ToolHandlers, opts ...mcpwrapper.ToolOption) error {
+{{- range .Pkg.Tools}}
+	if err := w.Register({{printf "%q" .Name}}, {{printf "%q" .Description}}, {{.Args}}{}, func(ctx context.Context, args interface{}) (interface{}, error) {
+		return h.{{.Method}}(ctx, args.(*{{.Args}}))
+	}, opts...); err != nil {
+		return err
+	}
+{{- end}}
+	return nil
+}
+{{- if .Client}}
+
+// ToolCaller is implemented by mcp-go clients.
+type ToolCaller interface {
+	CallTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error)
+}
+
+// ToolClient calls the tools of package {{.Pkg.Name}} with typed arguments and
+// results.
+type ToolClient struct {
+	Caller ToolCaller
+}
+{{- range .Pkg.Tools}}
+
+func (c *ToolClient) {{.Method}}(ctx context.Context, args *{{.Args}}) ({{if .Result}}*{{.Result}}{{else}}json.RawMessage{{end}}, error) {
+{{- if .Result}}
+	out := new({{.Result}})
+	if err := c.call(ctx, {{printf "%q" .Name}}, args, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+{{- else}}
+	var out json.RawMessage
+	if err := c.call(ctx, {{printf "%q" .Name}}, args, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+{{- end}}
+}
+{{- end}}
+
+func (c *ToolClient) call(ctx context.Context, name string, args, out interface{}) error {
+	data, err := json.Marshal(args)
+	if err != nil {
+		return fmt.Errorf("%s: failed to encode arguments: %w", name, err)
+	}
+	var arguments map[string]interface{}
+	if err := json.Unmarshal(data, &arguments); err != nil {
+		return fmt.Errorf("%s: failed to encode arguments: %w", name, err)
+	}
+
+	result, err := c.Caller.CallTool(ctx, mcp.CallToolRequest{
+		Params: mcp.CallToolParams{Name: name, Arguments: arguments},
+	})
+	if err != nil {
+		return err
+	}
+
+	var text string
+	for _, content := range result.Content {
+		if t, ok := mcp.AsTextContent(content); ok {
+			text = t.Text
+			break
+		}
+	}
+	if result.IsError {
+		return fmt.Errorf("%s: %s", name, text)
+	}
+	if err := json.Unmarshal([]byte(text), out); err != nil {
+		return fmt.Errorf("%s: failed to decode result: %w", name, err)
+	}
+	return nil
+}
+{{- end}}
+`))
+
+func generate(pkg *pkgInfo, client bool) ([]byte, error) {
+	var buf bytes.Buffer
+	hasFieldDocs := false
+	for _, tool := range pkg.Tools {
+		hasFieldDocs = hasFieldDocs || len(tool.FieldDocs) > 0
+	}
+
+	err := fileTemplate.Execute(&buf, struct {
+		Pkg          *pkgInfo
+		Client       bool
+		HasFieldDocs bool
+	}{pkg, client, hasFieldDocs})
+	if err != nil {
+		return nil, err
+	}
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("generated invalid code: %w\n%s", err, buf.Bytes())
+	}
+	return src, nil
+}
@@ -0,0 +1,55 @@
+// Command mcpwrapper-gen generates registration boilerplate and a typed
+// client stub for the tools of a package. Run it with go generate:
+//
+//	//go:generate go run github.com/aleksadvaisly/mcp-go-wrapper/cmd/mcpwrapper-gen
+//
+// Every struct carrying a //mcpwrapper:tool directive is a tool, as is every
+// struct whose name ends in Args and that has jsonschema tags:
+//
+//	// Search documents by keyword.
+//	//
+//	//mcpwrapper:tool search result=SearchResult
+//	type SearchArgs struct {
+//		Query string `json:"query" jsonschema:"required"`
+//	}
+//
+// The directive takes an optional tool name (default: the struct name
+// without Args, in snake_case; "-" skips the struct), an optional result
+// type, and an optional description (default: the doc comment).
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+func main() {
+	dir := flag.String("dir", ".", "package directory to scan")
+	output := flag.String("output", "mcpwrapper_tools_gen.go", "generated file, relative to -dir")
+	client := flag.Bool("client", true, "also generate the typed client stub")
+	flag.Parse()
+
+	if err := run(*dir, *output, *client); err != nil {
+		fmt.Fprintf(os.Stderr, "mcpwrapper-gen: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(dir, output string, client bool) error {
+	pkg, err := scan(dir, output)
+	if err != nil {
+		return err
+	}
+	if len(pkg.Tools) == 0 {
+		return fmt.Errorf("no tools found in %s", dir)
+	}
+
+	src, err := generate(pkg, client)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(dir, output), src, 0o644)
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestGolden regenerates testdata/tools and compares it with the checked-in
+// output. Run "go run . -dir testdata/tools" to update it.
+func TestGolden(t *testing.T) {
+	dir := t.TempDir()
+	src, err := os.ReadFile(filepath.Join("testdata", "tools", "tools.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "tools.go"), src, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := run(dir, "mcpwrapper_tools_gen.go", true); err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+
+	got, err := os.ReadFile(filepath.Join(dir, "mcpwrapper_tools_gen.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := os.ReadFile(filepath.Join("testdata", "tools", "mcpwrapper_tools_gen.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("Generated code differs from testdata/tools/mcpwrapper_tools_gen.go:\n%s", got)
+	}
+}
+
+func TestScan(t *testing.T) {
+	pkg, err := scan(filepath.Join("testdata", "tools"), "mcpwrapper_tools_gen.go")
+	if err != nil {
+		t.Fatalf("scan failed: %v", err)
+	}
+
+	var names []string
+	for _, tool := range pkg.Tools {
+		names = append(names, tool.Name+":"+tool.Method+":"+tool.Result)
+	}
+	want := "clone_repo:CloneRepo:,delete_file:Remove:,search:Search:SearchResult"
+	if strings.Join(names, ",") != want {
+		t.Errorf("Expected tools %s, got %s", want, strings.Join(names, ","))
+	}
+
+	docs := pkg.Tools[2].FieldDocs
+	if len(docs) != 2 || docs[0] != (fieldDoc{"Query", "Words to look for; all of them must match."}) || docs[1] != (fieldDoc{"Limit", "Maximum number of hits."}) {
+		t.Errorf("Expected doc and line comments of fields, got %+v", docs)
+	}
+}
+
+func TestScanErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		src     string
+		wantErr string
+	}{
+		{
+			name:    "no description",
+			src:     "package p\n\ntype FooArgs struct {\n\tA string `jsonschema:\"required\"`\n}\n",
+			wantErr: "tool foo has no description",
+		},
+		{
+			name:    "unknown option",
+			src:     "package p\n\n//mcpwrapper:tool foo output=X Foo.\ntype Foo struct{}\n",
+			wantErr: "unknown directive option output",
+		},
+		{
+			name:    "duplicate name",
+			src:     "package p\n\n//mcpwrapper:tool foo A.\ntype A struct{}\n\n//mcpwrapper:tool foo B.\ntype B struct{}\n",
+			wantErr: "tool foo is defined by both",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(dir, "p.go"), []byte(tt.src), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			_, err := scan(dir, "gen.go")
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestSnakeCase(t *testing.T) {
+	tests := map[string]string{
+		"Search":     "search",
+		"CloneRepo":  "clone_repo",
+		"HTTPGet":    "http_get",
+		"ListS3Keys": "list_s3_keys",
+	}
+	for in, want := range tests {
+		if got := snakeCase(in); got != want {
+			t.Errorf("snakeCase(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
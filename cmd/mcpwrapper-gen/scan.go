@@ -0,0 +1,210 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const directive = "//mcpwrapper:tool"
+
+type pkgInfo struct {
+	Name  string
+	Tools []toolInfo
+}
+
+type toolInfo struct {
+	Name        string
+	Description string
+	Args        string
+	Method      string
+	Result      string
+	// FieldDocs are the fields' doc comments, by Go field name.
+	FieldDocs []fieldDoc
+}
+
+type fieldDoc struct {
+	Field string
+	Doc   string
+}
+
+// scan parses the non-test Go files of dir, except the generated output.
+func scan(dir, output string) (*pkgInfo, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	fset := token.NewFileSet()
+	pkg := &pkgInfo{}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") || name == filepath.Base(output) {
+			continue
+		}
+
+		file, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		if pkg.Name == "" {
+			pkg.Name = file.Name.Name
+		} else if pkg.Name != file.Name.Name {
+			return nil, fmt.Errorf("%s: package %s, expected %s", name, file.Name.Name, pkg.Name)
+		}
+
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				ts := spec.(*ast.TypeSpec)
+				st, ok := ts.Type.(*ast.StructType)
+				if !ok {
+					continue
+				}
+				doc := ts.Doc
+				if doc == nil && len(gen.Specs) == 1 {
+					doc = gen.Doc
+				}
+				tool, ok, err := toolFromStruct(ts.Name.Name, st, doc)
+				if err != nil {
+					return nil, fmt.Errorf("%s: %w", fset.Position(ts.Pos()), err)
+				}
+				if ok {
+					pkg.Tools = append(pkg.Tools, tool)
+				}
+			}
+		}
+	}
+
+	sort.Slice(pkg.Tools, func(i, j int) bool { return pkg.Tools[i].Name < pkg.Tools[j].Name })
+	for i := 1; i < len(pkg.Tools); i++ {
+		if pkg.Tools[i].Name == pkg.Tools[i-1].Name {
+			return nil, fmt.Errorf("tool %s is defined by both %s and %s", pkg.Tools[i].Name, pkg.Tools[i-1].Args, pkg.Tools[i].Args)
+		}
+	}
+	return pkg, nil
+}
+
+func toolFromStruct(typeName string, st *ast.StructType, doc *ast.CommentGroup) (toolInfo, bool, error) {
+	base := strings.TrimSuffix(typeName, "Args")
+	tool := toolInfo{
+		Name:   snakeCase(base),
+		Args:   typeName,
+		Method: exported(base),
+	}
+
+	args, found := findDirective(doc)
+	if !found && (!strings.HasSuffix(typeName, "Args") || !hasJSONSchemaTags(st)) {
+		return tool, false, nil
+	}
+
+	if len(args) > 0 && !strings.Contains(args[0], "=") {
+		if args[0] == "-" {
+			return tool, false, nil
+		}
+		tool.Name = args[0]
+		args = args[1:]
+	}
+	for len(args) > 0 && strings.Contains(args[0], "=") {
+		key, value, _ := strings.Cut(args[0], "=")
+		switch key {
+		case "result":
+			tool.Result = value
+		default:
+			return tool, false, fmt.Errorf("unknown directive option %s", key)
+		}
+		args = args[1:]
+	}
+	tool.Description = strings.Join(args, " ")
+	tool.FieldDocs = fieldDocs(st)
+
+	if tool.Description == "" && doc != nil {
+		tool.Description = strings.Join(strings.Fields(doc.Text()), " ")
+	}
+	if tool.Description == "" {
+		return tool, false, fmt.Errorf("tool %s has no description: add a doc comment", tool.Name)
+	}
+	return tool, true, nil
+}
+
+// fieldDocs collects the doc comment, or else the trailing line comment, of
+// every named field.
+func fieldDocs(st *ast.StructType) []fieldDoc {
+	var docs []fieldDoc
+	for _, field := range st.Fields.List {
+		group := field.Doc
+		if group == nil {
+			group = field.Comment
+		}
+		if group == nil {
+			continue
+		}
+		text := strings.Join(strings.Fields(group.Text()), " ")
+		if text == "" {
+			continue
+		}
+		for _, name := range field.Names {
+			docs = append(docs, fieldDoc{Field: name.Name, Doc: text})
+		}
+	}
+	return docs
+}
+
+func findDirective(doc *ast.CommentGroup) ([]string, bool) {
+	if doc == nil {
+		return nil, false
+	}
+	for _, c := range doc.List {
+		if c.Text == directive || strings.HasPrefix(c.Text, directive+" ") {
+			return strings.Fields(strings.TrimPrefix(c.Text, directive)), true
+		}
+	}
+	return nil, false
+}
+
+func hasJSONSchemaTags(st *ast.StructType) bool {
+	for _, field := range st.Fields.List {
+		if field.Tag == nil {
+			continue
+		}
+		tag := reflect.StructTag(strings.Trim(field.Tag.Value, "`"))
+		if _, ok := tag.Lookup("jsonschema"); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func snakeCase(s string) string {
+	var b strings.Builder
+	runes := []rune(s)
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 && (unicode.IsLower(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func exported(s string) string {
+	if s == "" {
+		return s
+	}
+	runes := []rune(s)
+	runes[0] = unicode.ToUpper(runes[0])
+	return string(runes)
+}
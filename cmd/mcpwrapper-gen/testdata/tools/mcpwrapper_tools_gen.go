@@ -0,0 +1,115 @@
+// Code generated by mcpwrapper-gen. DO NOT EDIT.
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	mcpwrapper "github.com/aleksadvaisly/mcp-go-wrapper"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func init() {
+	mcpwrapper.RegisterFieldDocs(SearchArgs{}, map[string]string{
+		"Query": "Words to look for; all of them must match.",
+		"Limit": "Maximum number of hits.",
+	})
+}
+
+// ToolHandlers implements the tools of package tools.
+type ToolHandlers interface {
+	CloneRepo(ctx context.Context, args *CloneRepoArgs) (interface{}, error)
+	Remove(ctx context.Context, args *RemoveArgs) (interface{}, error)
+	Search(ctx context.Context, args *SearchArgs) (*SearchResult, error)
+}
+
+// RegisterTools registers every tool of package tools. opts apply to
+// each of them.
+func RegisterTools(w *mcpwrapper.Wrapper, h ToolHandlers, opts ...mcpwrapper.ToolOption) error {
+	if err := w.Register("clone_repo", "Clone a Git repository into the workspace.", CloneRepoArgs{}, func(ctx context.Context, args interface{}) (interface{}, error) {
+		return h.CloneRepo(ctx, args.(*CloneRepoArgs))
+	}, opts...); err != nil {
+		return err
+	}
+	if err := w.Register("delete_file", "Delete a file from the workspace.", RemoveArgs{}, func(ctx context.Context, args interface{}) (interface{}, error) {
+		return h.Remove(ctx, args.(*RemoveArgs))
+	}, opts...); err != nil {
+		return err
+	}
+	if err := w.Register("search", "Search documents by keyword.", SearchArgs{}, func(ctx context.Context, args interface{}) (interface{}, error) {
+		return h.Search(ctx, args.(*SearchArgs))
+	}, opts...); err != nil {
+		return err
+	}
+	return nil
+}
+
+// ToolCaller is implemented by mcp-go clients.
+type ToolCaller interface {
+	CallTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error)
+}
+
+// ToolClient calls the tools of package tools with typed arguments and
+// results.
+type ToolClient struct {
+	Caller ToolCaller
+}
+
+func (c *ToolClient) CloneRepo(ctx context.Context, args *CloneRepoArgs) (json.RawMessage, error) {
+	var out json.RawMessage
+	if err := c.call(ctx, "clone_repo", args, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ToolClient) Remove(ctx context.Context, args *RemoveArgs) (json.RawMessage, error) {
+	var out json.RawMessage
+	if err := c.call(ctx, "delete_file", args, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ToolClient) Search(ctx context.Context, args *SearchArgs) (*SearchResult, error) {
+	out := new(SearchResult)
+	if err := c.call(ctx, "search", args, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ToolClient) call(ctx context.Context, name string, args, out interface{}) error {
+	data, err := json.Marshal(args)
+	if err != nil {
+		return fmt.Errorf("%s: failed to encode arguments: %w", name, err)
+	}
+	var arguments map[string]interface{}
+	if err := json.Unmarshal(data, &arguments); err != nil {
+		return fmt.Errorf("%s: failed to encode arguments: %w", name, err)
+	}
+
+	result, err := c.Caller.CallTool(ctx, mcp.CallToolRequest{
+		Params: mcp.CallToolParams{Name: name, Arguments: arguments},
+	})
+	if err != nil {
+		return err
+	}
+
+	var text string
+	for _, content := range result.Content {
+		if t, ok := mcp.AsTextContent(content); ok {
+			text = t.Text
+			break
+		}
+	}
+	if result.IsError {
+		return fmt.Errorf("%s: %s", name, text)
+	}
+	if err := json.Unmarshal([]byte(text), out); err != nil {
+		return fmt.Errorf("%s: failed to decode result: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package tools
+
+// Search documents by keyword.
+//
+//mcpwrapper:tool search result=SearchResult
+type SearchArgs struct {
+	// Words to look for; all of them must match.
+	Query string `json:"query" jsonschema:"required" validate:"required"`
+	Limit int    `json:"limit,omitempty" jsonschema:"minimum=1"` // Maximum number of hits.
+}
+
+type SearchResult struct {
+	Hits []string `json:"hits"`
+}
+
+// Clone a Git repository into the workspace.
+type CloneRepoArgs struct {
+	URL string `json:"url" jsonschema:"required" validate:"required,url"`
+}
+
+//mcpwrapper:tool delete_file Delete a file from the workspace.
+type RemoveArgs struct {
+	Path string `json:"path" validate:"required"`
+}
+
+// Not a tool: no jsonschema tags.
+type ConfigArgs struct {
+	Verbose bool `json:"verbose"`
+}
+
+// Skipped explicitly.
+//
+//mcpwrapper:tool -
+type InternalArgs struct {
+	Token string `json:"token" jsonschema:"required"`
+}
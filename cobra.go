@@ -0,0 +1,401 @@
+//go:build !nocobra
+
+package mcpwrapper
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// cobraArgs is the positional argument layout of an argsType: the fields
+// tagged cobra:"arg=N", by position, and the []T field tagged cobra:"args"
+// that takes the remaining arguments.
+type cobraArgs struct {
+	positional []reflect.StructField
+	rest       *reflect.StructField
+}
+
+func parseCobraArgs(t reflect.Type) (*cobraArgs, error) {
+	layout := &cobraArgs{}
+	if t == nil {
+		return layout, nil
+	}
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return layout, nil
+	}
+
+	byIndex := make(map[int]reflect.StructField)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := strings.TrimSpace(field.Tag.Get("cobra"))
+		switch {
+		case tag == "":
+		case tag == "args":
+			if layout.rest != nil {
+				return nil, fmt.Errorf("fields %s and %s are both tagged cobra:\"args\"", layout.rest.Name, field.Name)
+			}
+			if field.Type.Kind() != reflect.Slice {
+				return nil, fmt.Errorf("field %s: cobra:\"args\" needs a slice", field.Name)
+			}
+			field := field
+			layout.rest = &field
+		case strings.HasPrefix(tag, "arg="):
+			n, err := strconv.Atoi(strings.TrimPrefix(tag, "arg="))
+			if err != nil || n < 0 {
+				return nil, fmt.Errorf("field %s: invalid cobra tag %q", field.Name, tag)
+			}
+			if other, ok := byIndex[n]; ok {
+				return nil, fmt.Errorf("fields %s and %s are both positional argument %d", other.Name, field.Name, n)
+			}
+			byIndex[n] = field
+		default:
+			return nil, fmt.Errorf("field %s: invalid cobra tag %q", field.Name, tag)
+		}
+	}
+
+	indexes := make([]int, 0, len(byIndex))
+	for n := range byIndex {
+		indexes = append(indexes, n)
+	}
+	sort.Ints(indexes)
+	for i, n := range indexes {
+		if i != n {
+			return nil, fmt.Errorf("positional argument %d has no field", i)
+		}
+		layout.positional = append(layout.positional, byIndex[n])
+	}
+	return layout, nil
+}
+
+// isPositional reports whether a field is bound to positional arguments
+// rather than a flag.
+func (a *cobraArgs) isPositional(name string) bool {
+	for _, field := range a.positional {
+		if field.Name == name {
+			return true
+		}
+	}
+	return a.rest != nil && a.rest.Name == name
+}
+
+// values returns the positional arguments of bound args. A zero field ends
+// the list; a set field after it is an error, since the command would see
+// it at the wrong position.
+func (a *cobraArgs) values(v reflect.Value) ([]string, error) {
+	var args []string
+	var missing string
+	for _, field := range a.positional {
+		fv := v.FieldByIndex(field.Index)
+		if isUnset(fv) {
+			if missing == "" {
+				missing = jsonFieldName(field)
+			}
+			continue
+		}
+		if missing != "" {
+			return nil, Errorf(CodeInvalidInput, "%s requires %s", jsonFieldName(field), missing)
+		}
+		args = append(args, flagString(fv))
+	}
+
+	if a.rest != nil {
+		fv := v.FieldByIndex(a.rest.Index)
+		if fv.Len() > 0 && missing != "" {
+			return nil, Errorf(CodeInvalidInput, "%s requires %s", jsonFieldName(*a.rest), missing)
+		}
+		for i := 0; i < fv.Len(); i++ {
+			args = append(args, flagString(fv.Index(i)))
+		}
+	}
+	return args, nil
+}
+
+func isUnset(v reflect.Value) bool {
+	if v.Kind() == reflect.Ptr {
+		return v.IsNil()
+	}
+	return v.IsZero()
+}
+
+func flagString(v reflect.Value) string {
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+// lookupFlag finds the flag for a JSON field name among the command's own
+// and inherited flags. snake_case names match kebab-case flags.
+func lookupFlag(cmd *cobra.Command, name string) *pflag.Flag {
+	for _, candidate := range []string{name, strings.ReplaceAll(name, "_", "-")} {
+		if flag := cmd.Flags().Lookup(candidate); flag != nil {
+			return flag
+		}
+		if flag := cmd.InheritedFlags().Lookup(candidate); flag != nil {
+			return flag
+		}
+	}
+	return nil
+}
+
+// setCobraFlags sets the command's flags from the bound args fields of the
+// same name, skipping zero values and positional fields. The returned
+// function restores the flags' previous values.
+func setCobraFlags(cmd *cobra.Command, v reflect.Value, layout *cobraArgs) (func(), error) {
+	var restores []func()
+	restore := func() {
+		for _, r := range restores {
+			r()
+		}
+	}
+
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := jsonFieldName(field)
+		if name == "" || !field.IsExported() || layout.isPositional(field.Name) {
+			continue
+		}
+		flag := lookupFlag(cmd, name)
+		fv := v.Field(i)
+		if flag == nil || isUnset(fv) {
+			continue
+		}
+
+		restores = append(restores, snapshotFlag(flag))
+		if err := setFlag(flag, fv); err != nil {
+			restore()
+			return nil, Errorf(CodeInvalidInput, "invalid value for --%s: %v", flag.Name, err)
+		}
+	}
+	return restore, nil
+}
+
+func setFlag(flag *pflag.Flag, v reflect.Value) error {
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	if v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8 {
+		if flag.Value.Type() == "bytesBase64" {
+			return setFlagValue(flag, base64.StdEncoding.EncodeToString(v.Bytes()))
+		}
+		return setFlagValue(flag, hex.EncodeToString(v.Bytes()))
+	}
+	if v.Kind() == reflect.Slice {
+		values := make([]string, v.Len())
+		for i := range values {
+			values[i] = flagString(v.Index(i))
+		}
+		return setFlagValues(flag, values)
+	}
+	return setFlagValue(flag, flagString(v))
+}
+
+func setFlagValue(flag *pflag.Flag, value string) error {
+	if err := flag.Value.Set(value); err != nil {
+		return err
+	}
+	flag.Changed = true
+	return nil
+}
+
+// setFlagValues sets a repeatable flag as if each value had been given
+// once, replacing its default.
+func setFlagValues(flag *pflag.Flag, values []string) error {
+	if slice, ok := flag.Value.(pflag.SliceValue); ok {
+		if err := slice.Replace(values); err != nil {
+			return err
+		}
+	} else {
+		for _, value := range values {
+			if err := flag.Value.Set(value); err != nil {
+				return err
+			}
+		}
+	}
+	flag.Changed = true
+	return nil
+}
+
+// snapshotFlag returns a function that restores the flag's current value.
+// Most pflag values are pointers to the variable itself, which is copied;
+// a value that prints as something it cannot parse, such as a nil IP
+// printed as "<nil>", would not survive a round trip through Set.
+func snapshotFlag(flag *pflag.Flag) func() {
+	changed := flag.Changed
+	if slice, ok := flag.Value.(pflag.SliceValue); ok {
+		saved := append([]string(nil), slice.GetSlice()...)
+		return func() {
+			slice.Replace(saved)
+			flag.Changed = changed
+		}
+	}
+	if rv := reflect.ValueOf(flag.Value); rv.Kind() == reflect.Ptr && !rv.IsNil() &&
+		rv.Elem().CanSet() && copyable(rv.Elem().Type()) {
+		saved := reflect.New(rv.Elem().Type()).Elem()
+		saved.Set(rv.Elem())
+		return func() {
+			rv.Elem().Set(saved)
+			flag.Changed = changed
+		}
+	}
+	value := flag.Value.String()
+	return func() {
+		flag.Value.Set(value)
+		flag.Changed = changed
+	}
+}
+
+// copyable reports whether a copy of a flag variable holds its value rather
+// than pointing at it, as a struct holding a pointer to the variable would.
+func copyable(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Func:
+		return false
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			if ft := t.Field(i).Type; ft.Kind() == reflect.Struct || !copyable(ft) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// cobraLocks serializes calls per command tree: subcommands share their
+// parents' persistent flags.
+var cobraLocks sync.Map // root *cobra.Command -> *sync.Mutex
+
+func lockCobra(cmd *cobra.Command) func() {
+	mu, _ := cobraLocks.LoadOrStore(cmd.Root(), &sync.Mutex{})
+	mu.(*sync.Mutex).Lock()
+	return mu.(*sync.Mutex).Unlock
+}
+
+// runCobra runs cmd with already set flags the way Execute would after
+// parsing them: the persistent and local pre-run hooks, the required flag
+// and flag group checks, Run, and the post-run hooks. What the command
+// writes to cmd.OutOrStdout and cmd.ErrOrStderr is returned as the
+// result's Stdout and Stderr instead, so it cannot corrupt a stdio
+// transport, and an error it returns is an ExitError. For the same reason
+// cmd.InOrStdin reads from prompts, which answers the prompts it
+// recognizes in the output; without any it is empty.
+func runCobra(ctx context.Context, cmd *cobra.Command, args []string, prompts *promptResponder) (*ExecResult, error) {
+	if err := cmd.ValidateArgs(args); err != nil {
+		return nil, Errorf(CodeInvalidInput, "%v", err)
+	}
+	if err := cmd.ValidateRequiredFlags(); err != nil {
+		return nil, Errorf(CodeInvalidInput, "%v", err)
+	}
+	if err := cmd.ValidateFlagGroups(); err != nil {
+		return nil, Errorf(CodeInvalidInput, "%v", err)
+	}
+
+	if prompts == nil {
+		prompts = newPromptResponder(ctx, nil, nil, nil, nil)
+	}
+	var stdoutBuf, stderrBuf bytes.Buffer
+	stdin, stdout, stderr := cmd.InOrStdin(), cmd.OutOrStdout(), cmd.ErrOrStderr()
+	cmd.SetIn(prompts)
+	cmd.SetOut(prompts.wrap(&stdoutBuf))
+	cmd.SetErr(prompts.wrap(&stderrBuf))
+	defer func() {
+		cmd.SetIn(stdin)
+		cmd.SetOut(stdout)
+		cmd.SetErr(stderr)
+	}()
+	cmd.SetContext(ctx)
+
+	start := time.Now()
+	err := runCobraHooks(cmd, args)
+	if prompts.Err() != nil {
+		return nil, prompts.Err()
+	}
+	result := &ExecResult{
+		Stdout:     stdoutBuf.String(),
+		Stderr:     stderrBuf.String(),
+		DurationMs: time.Since(start).Milliseconds(),
+	}
+	if err := commandExit(result, err); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func runCobraHooks(c *cobra.Command, args []string) error {
+	var parents []*cobra.Command
+	for p := c; p != nil; p = p.Parent() {
+		if cobra.EnableTraverseRunHooks {
+			parents = append([]*cobra.Command{p}, parents...)
+		} else {
+			parents = append(parents, p)
+		}
+	}
+	for _, p := range parents {
+		if p.PersistentPreRunE != nil {
+			if err := p.PersistentPreRunE(c, args); err != nil {
+				return err
+			}
+		} else if p.PersistentPreRun != nil {
+			p.PersistentPreRun(c, args)
+		} else {
+			continue
+		}
+		if !cobra.EnableTraverseRunHooks {
+			break
+		}
+	}
+
+	if c.PreRunE != nil {
+		if err := c.PreRunE(c, args); err != nil {
+			return err
+		}
+	} else if c.PreRun != nil {
+		c.PreRun(c, args)
+	}
+	if c.RunE != nil {
+		if err := c.RunE(c, args); err != nil {
+			return err
+		}
+	} else if c.Run != nil {
+		c.Run(c, args)
+	}
+	if c.PostRunE != nil {
+		if err := c.PostRunE(c, args); err != nil {
+			return err
+		}
+	} else if c.PostRun != nil {
+		c.PostRun(c, args)
+	}
+
+	for p := c; p != nil; p = p.Parent() {
+		if p.PersistentPostRunE != nil {
+			if err := p.PersistentPostRunE(c, args); err != nil {
+				return err
+			}
+		} else if p.PersistentPostRun != nil {
+			p.PersistentPostRun(c, args)
+		} else {
+			continue
+		}
+		if !cobra.EnableTraverseRunHooks {
+			break
+		}
+	}
+	return nil
+}
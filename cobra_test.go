@@ -0,0 +1,156 @@
+//go:build !nocobra
+
+package mcpwrapper
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/spf13/cobra"
+)
+
+type GitCloneArgs struct {
+	URL       string   `json:"url" cobra:"arg=0"`
+	Directory string   `json:"directory,omitempty" cobra:"arg=1"`
+	Depth     int      `json:"depth,omitempty"`
+	Branch    string   `json:"branch,omitempty"`
+	Config    []string `json:"config,omitempty"`
+}
+
+func TestRegisterCobraCommandPositionalArgs(t *testing.T) {
+	var gotArgs []string
+	var gotDepth int
+	var gotConfig []string
+	cmd := &cobra.Command{
+		Use:   "clone <url> [directory]",
+		Short: "Clone a repository",
+		Args:  cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			gotArgs = args
+			gotDepth, _ = cmd.Flags().GetInt("depth")
+			gotConfig, _ = cmd.Flags().GetStringSlice("config")
+			return nil
+		},
+	}
+	cmd.Flags().Int("depth", 0, "History depth")
+	cmd.Flags().String("branch", "main", "Branch to check out")
+	cmd.Flags().StringSlice("config", []string{"core.x=1"}, "Config values")
+
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	wrapper := New(mcpServer)
+	if err := wrapper.RegisterCobraCommand(cmd, GitCloneArgs{}); err != nil {
+		t.Fatalf("RegisterCobraCommand failed: %v", err)
+	}
+	if mcpServer.GetTool("clone") == nil {
+		t.Fatal("Expected the tool to be named after the first word of Use")
+	}
+
+	result := callTool(t, mcpServer, "clone", map[string]interface{}{
+		"url": "https://example.com/repo.git", "directory": "src", "depth": 1, "config": []interface{}{"a=1", "b=2"},
+	})
+	if result.IsError {
+		t.Fatalf("Unexpected error: %v", result.Content)
+	}
+	if want := []string{"https://example.com/repo.git", "src"}; !reflect.DeepEqual(gotArgs, want) {
+		t.Errorf("Expected positional args %v, got %v", want, gotArgs)
+	}
+	if gotDepth != 1 || !reflect.DeepEqual(gotConfig, []string{"a=1", "b=2"}) {
+		t.Errorf("Expected flags to be set, got depth=%d config=%v", gotDepth, gotConfig)
+	}
+
+	// Flags are reset between calls.
+	callTool(t, mcpServer, "clone", map[string]interface{}{"url": "u"})
+	if !reflect.DeepEqual(gotArgs, []string{"u"}) || gotDepth != 0 || !reflect.DeepEqual(gotConfig, []string{"core.x=1"}) {
+		t.Errorf("Expected defaults on the second call, got args=%v depth=%d config=%v", gotArgs, gotDepth, gotConfig)
+	}
+	if cmd.Flags().Lookup("depth").Changed {
+		t.Error("Expected the flag to be marked unchanged after the call")
+	}
+
+	for _, tt := range []struct {
+		args map[string]interface{}
+		want string
+	}{
+		{map[string]interface{}{}, "accepts between 1 and 2 arg(s), received 0"},
+		{map[string]interface{}{"directory": "src"}, "directory requires url"},
+	} {
+		result := callTool(t, mcpServer, "clone", tt.args)
+		if text := result.Content[0].(mcp.TextContent).Text; !result.IsError || !strings.Contains(text, tt.want) {
+			t.Errorf("%v: expected %q, got %q", tt.args, tt.want, text)
+		}
+	}
+}
+
+func TestRegisterCobraCommandRestArgs(t *testing.T) {
+	type RemoveArgs struct {
+		Files []string `json:"files" cobra:"args"`
+		Force bool     `json:"force,omitempty"`
+	}
+	var gotArgs []string
+	var gotForce bool
+	cmd := &cobra.Command{
+		Use:  "rm <file>...",
+		Args: cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			gotArgs = args
+			gotForce, _ = cmd.Flags().GetBool("force")
+		},
+	}
+	cmd.Flags().BoolP("force", "f", false, "Ignore missing files")
+
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	if err := New(mcpServer).RegisterCobraCommand(cmd, RemoveArgs{}); err != nil {
+		t.Fatalf("RegisterCobraCommand failed: %v", err)
+	}
+
+	callTool(t, mcpServer, "rm", map[string]interface{}{"files": []interface{}{"a", "b"}, "force": true})
+	if !reflect.DeepEqual(gotArgs, []string{"a", "b"}) || !gotForce {
+		t.Errorf("Unexpected invocation: args=%v force=%v", gotArgs, gotForce)
+	}
+	if result := callTool(t, mcpServer, "rm", map[string]interface{}{}); !result.IsError {
+		t.Error("Expected MinimumNArgs to reject a call without files")
+	}
+}
+
+func TestParseCobraArgsErrors(t *testing.T) {
+	type Gap struct {
+		A string `cobra:"arg=0"`
+		B string `cobra:"arg=2"`
+	}
+	type Duplicate struct {
+		A string `cobra:"arg=0"`
+		B string `cobra:"arg=0"`
+	}
+	type RestNotSlice struct {
+		A string `cobra:"args"`
+	}
+	type BadTag struct {
+		A string `cobra:"position=1"`
+	}
+	for _, v := range []interface{}{Gap{}, Duplicate{}, RestNotSlice{}, BadTag{}} {
+		if _, err := parseCobraArgs(reflect.TypeOf(v)); err == nil {
+			t.Errorf("Expected %T to be rejected", v)
+		}
+	}
+}
+
+func TestRegisterCobraCommandContext(t *testing.T) {
+	type key struct{}
+	var got interface{}
+	cmd := &cobra.Command{Use: "ctx", Run: func(cmd *cobra.Command, args []string) {
+		got = cmd.Context().Value(key{})
+	}}
+	wrapper := New(server.NewMCPServer("test", "1.0.0"))
+	if err := wrapper.RegisterCobraCommand(cmd, struct{}{}); err != nil {
+		t.Fatalf("RegisterCobraCommand failed: %v", err)
+	}
+	tool := wrapper.server.GetTool("ctx")
+	tool.Handler(context.WithValue(context.Background(), key{}, "v"), mcp.CallToolRequest{Params: mcp.CallToolParams{Name: "ctx"}})
+	if got != "v" {
+		t.Errorf("Expected the call context on the command, got %v", got)
+	}
+}
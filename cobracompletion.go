@@ -0,0 +1,132 @@
+//go:build !nocobra
+
+package mcpwrapper
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// WithCompletionEnums calls each flag completion function once at
+// registration and advertises its results as the flag's enum. Only
+// functions that return values with cobra.ShellCompDirectiveNoFileComp are
+// used: anything else means the values are not the complete set. Use it
+// for completions that list a fixed set, such as output formats; the
+// values are not refreshed afterwards.
+func WithCompletionEnums() CobraOption {
+	return func(o *cobraOptions) {
+		o.completionEnums = true
+	}
+}
+
+// validArgs returns cmd.ValidArgs without their tab-separated
+// descriptions.
+func validArgs(cmd *cobra.Command) []string {
+	values := make([]string, 0, len(cmd.ValidArgs))
+	for _, arg := range cmd.ValidArgs {
+		values = append(values, completionValue(arg))
+	}
+	return values
+}
+
+func completionValue(c cobra.Completion) string {
+	value, _, _ := strings.Cut(c, "\t")
+	return value
+}
+
+// registerCobraCompletions makes a command's completions available through
+// MCP completion: ValidArgs or ValidArgsFunction for the args property and
+// the flag completion functions for their properties.
+func (w *Wrapper) registerCobraCompletions(name string, cmd *cobra.Command, flags map[string]*pflag.Flag, hasArgs bool) error {
+	if hasArgs {
+		switch {
+		case len(cmd.ValidArgs) > 0:
+			values := validArgs(cmd)
+			if err := w.RegisterCompletion(name, argsProperty, func(ctx context.Context, partial string) ([]string, error) {
+				return filterPrefix(values, partial), nil
+			}); err != nil {
+				return err
+			}
+		case cmd.ValidArgsFunction != nil:
+			if err := w.RegisterCompletion(name, argsProperty, cobraCompletion(cmd, cmd.ValidArgsFunction)); err != nil {
+				return err
+			}
+		}
+	}
+
+	for prop, flag := range flags {
+		fn, ok := cmd.GetFlagCompletionFunc(flag.Name)
+		if !ok {
+			continue
+		}
+		if err := w.RegisterCompletion(name, prop, cobraCompletion(cmd, fn)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// cobraCompletion adapts a cobra completion function. Cobra leaves prefix
+// filtering to the shell, so values that don't start with the partial
+// input are dropped here.
+func cobraCompletion(cmd *cobra.Command, fn cobra.CompletionFunc) CompletionFunc {
+	return func(ctx context.Context, partial string) ([]string, error) {
+		defer lockCobra(cmd)()
+		cmd.SetContext(ctx)
+		completions, directive := fn(cmd, nil, partial)
+		if directive&cobra.ShellCompDirectiveError != 0 {
+			return nil, fmt.Errorf("completion for %s failed", cmd.Name())
+		}
+		values := make([]string, 0, len(completions))
+		for _, c := range completions {
+			values = append(values, completionValue(c))
+		}
+		return filterPrefix(values, partial), nil
+	}
+}
+
+func filterPrefix(values []string, prefix string) []string {
+	matches := make([]string, 0, len(values))
+	for _, v := range values {
+		if strings.HasPrefix(v, prefix) {
+			matches = append(matches, v)
+		}
+	}
+	return matches
+}
+
+// completionEnum returns the values of a flag's completion function when
+// they are the complete set of choices.
+func completionEnum(cmd *cobra.Command, flag *pflag.Flag) ([]interface{}, bool) {
+	fn, ok := cmd.GetFlagCompletionFunc(flag.Name)
+	if !ok {
+		return nil, false
+	}
+	completions, directive := fn(cmd, nil, "")
+	if len(completions) == 0 || directive&cobra.ShellCompDirectiveError != 0 ||
+		directive&cobra.ShellCompDirectiveNoFileComp == 0 {
+		return nil, false
+	}
+	values := make([]string, len(completions))
+	for i, c := range completions {
+		values[i] = completionValue(c)
+	}
+	return stringValues(values), true
+}
+
+// setEnum constrains a property, or the items of an array property, to
+// the given string values.
+func setEnum(prop map[string]interface{}, enum []interface{}) {
+	switch prop["type"] {
+	case "string":
+		prop["enum"] = enum
+	case "array":
+		if items, ok := prop["items"].(map[string]interface{}); ok && items["type"] == "string" {
+			items["enum"] = enum
+		}
+	}
+}
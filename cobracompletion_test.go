@@ -0,0 +1,104 @@
+//go:build !nocobra
+
+package mcpwrapper
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/spf13/cobra"
+)
+
+func completeTool(t *testing.T, wrapper *Wrapper, tool, field, partial string) []string {
+	t.Helper()
+	r := mcp.CompleteRequest{Params: mcp.CompleteParams{Ref: map[string]interface{}{"type": "ref/tool", "name": tool}}}
+	r.Params.Argument.Name = field
+	r.Params.Argument.Value = partial
+	result, err := wrapper.Complete(context.Background(), r)
+	if err != nil {
+		t.Fatalf("Complete failed: %v", err)
+	}
+	return result.Completion.Values
+}
+
+func TestRegisterCobraFlagsCompletion(t *testing.T) {
+	cmd := &cobra.Command{
+		Use:       "restart [service]",
+		ValidArgs: []string{"api\tThe API server", "worker", "web"},
+		Args:      cobra.OnlyValidArgs,
+		RunE:      func(cmd *cobra.Command, args []string) error { return nil },
+	}
+	cmd.Flags().String("region", "", "Region")
+	cmd.Flags().String("output", "text", "Output format")
+	cmd.Flags().StringSlice("zones", nil, "Zones")
+	cmd.RegisterFlagCompletionFunc("region", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"eu-west-1", "eu-central-1", "us-east-1"}, cobra.ShellCompDirectiveDefault
+	})
+	cmd.RegisterFlagCompletionFunc("output", cobra.FixedCompletions([]string{"text", "json\tJSON output"}, cobra.ShellCompDirectiveNoFileComp))
+	cmd.RegisterFlagCompletionFunc("zones", cobra.FixedCompletions([]string{"a", "b"}, cobra.ShellCompDirectiveNoFileComp))
+
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	wrapper := New(mcpServer)
+	if err := wrapper.RegisterCobraFlags(cmd, WithCompletionEnums()); err != nil {
+		t.Fatalf("RegisterCobraFlags failed: %v", err)
+	}
+	props := mcpServer.GetTool("restart").Tool.InputSchema.Properties
+
+	items := props["args"].(map[string]interface{})["items"].(map[string]interface{})
+	if want := []interface{}{"api", "worker", "web"}; !reflect.DeepEqual(items["enum"], want) {
+		t.Errorf("Expected ValidArgs %v as the args enum, got %v", want, items["enum"])
+	}
+	if want := []interface{}{"text", "json"}; !reflect.DeepEqual(props["output"].(map[string]interface{})["enum"], want) {
+		t.Errorf("Expected the output completions as enum, got %v", props["output"])
+	}
+	if _, ok := props["region"].(map[string]interface{})["enum"]; ok {
+		t.Error("Expected no enum for a completion that allows other values")
+	}
+	zoneItems := props["zones"].(map[string]interface{})["items"].(map[string]interface{})
+	if want := []interface{}{"a", "b"}; !reflect.DeepEqual(zoneItems["enum"], want) {
+		t.Errorf("Expected the zones completions as item enum, got %v", zoneItems)
+	}
+
+	if got := completeTool(t, wrapper, "restart", "region", "eu-"); !reflect.DeepEqual(got, []string{"eu-west-1", "eu-central-1"}) {
+		t.Errorf("Expected region completions filtered by prefix, got %v", got)
+	}
+	if got := completeTool(t, wrapper, "restart", "args", "w"); !reflect.DeepEqual(got, []string{"worker", "web"}) {
+		t.Errorf("Expected ValidArgs completions, got %v", got)
+	}
+
+	result := callTool(t, mcpServer, "restart", map[string]interface{}{"output": "yaml"})
+	if !result.IsError {
+		t.Error("Expected a value outside the completion enum to be rejected")
+	}
+}
+
+func TestRegisterCobraFlagsValidArgsFunction(t *testing.T) {
+	cmd := &cobra.Command{
+		Use: "open [file]",
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if toComplete == "broken" {
+				return nil, cobra.ShellCompDirectiveError
+			}
+			return []string{"main.go", "go.mod"}, cobra.ShellCompDirectiveNoFileComp
+		},
+		RunE: func(cmd *cobra.Command, args []string) error { return nil },
+	}
+
+	wrapper := New(server.NewMCPServer("test", "1.0.0"))
+	if err := wrapper.RegisterCobraFlags(cmd); err != nil {
+		t.Fatalf("RegisterCobraFlags failed: %v", err)
+	}
+	if got := completeTool(t, wrapper, "open", "args", "m"); !reflect.DeepEqual(got, []string{"main.go"}) {
+		t.Errorf("Expected ValidArgsFunction completions, got %v", got)
+	}
+
+	r := mcp.CompleteRequest{Params: mcp.CompleteParams{Ref: map[string]interface{}{"type": "ref/tool", "name": "open"}}}
+	r.Params.Argument.Name = "args"
+	r.Params.Argument.Value = "broken"
+	if _, err := wrapper.Complete(context.Background(), r); err == nil {
+		t.Error("Expected ShellCompDirectiveError to fail the completion")
+	}
+}
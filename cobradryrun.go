@@ -0,0 +1,115 @@
+//go:build !nocobra
+
+package mcpwrapper
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// dryRunProperty is the argument that asks for a dry run.
+const dryRunProperty = "dry_run"
+
+// WithDryRunFlag exposes a dry_run boolean argument, giving agents a safe
+// way to preview destructive commands. If the command has the named
+// boolean flag, such as "--dry-run", dry_run sets it and the command
+// decides what a preview does. Otherwise a call with dry_run set doesn't
+// run the command and returns the command line it would have run.
+func WithDryRunFlag(flag string) CobraOption {
+	return func(o *cobraOptions) {
+		name := strings.TrimLeft(flag, "-")
+		o.dryRunFlag = &name
+	}
+}
+
+// applyDryRun maps the dry_run property onto the command's dry-run flag.
+// It reports whether there is no such flag, so dry runs are previews.
+func (o *cobraOptions) applyDryRun(cmd *cobra.Command, flags map[string]*pflag.Flag) (bool, error) {
+	if o.dryRunFlag == nil {
+		return false, nil
+	}
+
+	flag := cmd.Flag(*o.dryRunFlag)
+	if flag != nil {
+		if flag.Value.Type() != "bool" {
+			return false, fmt.Errorf("dry-run flag --%s is not a boolean", flag.Name)
+		}
+		for prop, f := range flags {
+			if f == flag {
+				delete(flags, prop)
+			}
+		}
+	}
+	if other, taken := flags[dryRunProperty]; taken {
+		return false, fmt.Errorf("flag --%s collides with the %s argument", other.Name, dryRunProperty)
+	}
+	if flag == nil {
+		return true, nil
+	}
+	flags[dryRunProperty] = flag
+	return false, nil
+}
+
+// dryRun returns the command line a call would run, without running it.
+func dryRun(cmd *cobra.Command, flags map[string]*pflag.Flag, arguments Arguments, positional []string) (*ExecResult, error) {
+	if err := cmd.ValidateArgs(positional); err != nil {
+		return nil, Errorf(CodeInvalidInput, "%v", err)
+	}
+
+	line := strings.Fields(cmd.CommandPath())
+	props := make([]string, 0, len(flags))
+	for prop := range flags {
+		props = append(props, prop)
+	}
+	sort.Strings(props)
+	for _, prop := range props {
+		value, ok := arguments[prop]
+		if !ok || value == nil {
+			continue
+		}
+		name := "--" + flags[prop].Name
+		switch v := value.(type) {
+		case bool:
+			if v {
+				line = append(line, name)
+			} else {
+				line = append(line, name+"=false")
+			}
+		case []interface{}:
+			for _, item := range v {
+				line = append(line, name+"="+argumentString(item))
+			}
+		default:
+			line = append(line, name+"="+argumentString(v))
+		}
+	}
+	for i, arg := range positional {
+		if strings.HasPrefix(arg, "-") {
+			line = append(line, "--")
+			line = append(line, positional[i:]...)
+			break
+		}
+		line = append(line, arg)
+	}
+
+	quoted := make([]string, len(line))
+	for i, word := range line {
+		quoted[i] = shellQuote(word)
+	}
+	return &ExecResult{DryRun: true, Command: strings.Join(quoted, " ")}, nil
+}
+
+var shellSafe = regexp.MustCompile(`^[A-Za-z0-9_@%+=:,./-]+$`)
+
+// shellQuote quotes a word for a POSIX shell if it needs quoting.
+func shellQuote(word string) string {
+	if shellSafe.MatchString(word) {
+		return word
+	}
+	return "'" + strings.ReplaceAll(word, "'", `'\''`) + "'"
+}
@@ -0,0 +1,83 @@
+//go:build !nocobra
+
+package mcpwrapper
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/spf13/cobra"
+)
+
+func TestWithDryRunFlag(t *testing.T) {
+	var deleted []string
+	var simulated bool
+	root := &cobra.Command{Use: "app"}
+	del := &cobra.Command{Use: "delete <id>...", Args: cobra.MinimumNArgs(1), RunE: func(cmd *cobra.Command, args []string) error {
+		simulated, _ = cmd.Flags().GetBool("simulate")
+		if !simulated {
+			deleted = append(deleted, args...)
+		}
+		return nil
+	}}
+	del.Flags().Bool("simulate", false, "Only show what would be deleted")
+	purge := &cobra.Command{Use: "purge <id>...", RunE: func(cmd *cobra.Command, args []string) error {
+		deleted = append(deleted, args...)
+		return nil
+	}}
+	purge.Flags().Bool("force", false, "Skip confirmation")
+	purge.Flags().StringSlice("tag", nil, "Tags")
+	purge.Flags().String("reason", "", "Reason")
+	root.AddCommand(del, purge)
+
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	wrapper := New(mcpServer)
+	if err := wrapper.RegisterCobraFlags(del, WithDryRunFlag("--simulate")); err != nil {
+		t.Fatalf("RegisterCobraFlags failed: %v", err)
+	}
+	if err := wrapper.RegisterCobraFlags(purge, WithDryRunFlag("--dry-run")); err != nil {
+		t.Fatalf("RegisterCobraFlags failed: %v", err)
+	}
+
+	props := mcpServer.GetTool("delete").Tool.InputSchema.Properties
+	if _, ok := props["simulate"]; ok {
+		t.Error("Expected the dry-run flag to be exposed as dry_run only")
+	}
+	if p, _ := props["dry_run"].(map[string]interface{}); p["type"] != "boolean" {
+		t.Errorf("Expected a dry_run boolean, got %v", props["dry_run"])
+	}
+	callTool(t, mcpServer, "delete", map[string]interface{}{"dry_run": true, "args": []interface{}{"1"}})
+	if !simulated || len(deleted) != 0 {
+		t.Errorf("Expected dry_run to set --simulate, got simulated=%v deleted=%v", simulated, deleted)
+	}
+
+	if _, ok := mcpServer.GetTool("purge").Tool.InputSchema.Properties["dry_run"]; !ok {
+		t.Fatal("Expected a dry_run argument for a command without the flag")
+	}
+	result := callTool(t, mcpServer, "purge", map[string]interface{}{
+		"dry_run": true, "force": true, "tag": []interface{}{"a", "b"}, "reason": "it's old",
+		"args": []interface{}{"7", "-8"},
+	})
+	if result.IsError || len(deleted) != 0 {
+		t.Fatalf("Expected a preview without running the command, got %v deleted=%v", result.Content, deleted)
+	}
+	var preview ExecResult
+	json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &preview)
+	want := `app purge --force '--reason=it'\''s old' --tag=a --tag=b 7 -- -8`
+	if !preview.DryRun || preview.Command != want {
+		t.Errorf("Expected command %q, got %+v", want, preview)
+	}
+
+	callTool(t, mcpServer, "purge", map[string]interface{}{"dry_run": false, "args": []interface{}{"7"}})
+	if len(deleted) != 1 {
+		t.Errorf("Expected dry_run false to run the command, got %v", deleted)
+	}
+
+	reason := &cobra.Command{Use: "tidy", RunE: func(cmd *cobra.Command, args []string) error { return nil }}
+	reason.Flags().String("dry-run", "", "Not a boolean")
+	if err := New(server.NewMCPServer("test", "1.0.0")).RegisterCobraFlags(reason, WithDryRunFlag("dry-run")); err == nil {
+		t.Error("Expected an error for a non-boolean dry-run flag")
+	}
+}
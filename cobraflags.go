@@ -0,0 +1,386 @@
+//go:build !nocobra
+
+package mcpwrapper
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// CobraOption configures RegisterCobraFlags.
+type CobraOption func(*cobraOptions)
+
+type cobraOptions struct {
+	excluded          map[string]bool
+	toolOptions       []ToolOption
+	completionEnums   bool
+	hiddenFlags       bool
+	withoutDeprecated bool
+	dryRunFlag        *string
+	prompts           []Prompt
+}
+
+// WithExcludedFlags leaves flags out of the schema, by flag name without
+// dashes. Use it for inherited flags that make no sense to an agent, such
+// as --config.
+func WithExcludedFlags(names ...string) CobraOption {
+	return func(o *cobraOptions) {
+		for _, name := range names {
+			o.excluded[strings.TrimLeft(name, "-")] = true
+		}
+	}
+}
+
+// WithHiddenFlags includes hidden flags in the schema. They are left out
+// by default, like in the command's help, unless they are required.
+func WithHiddenFlags() CobraOption {
+	return func(o *cobraOptions) {
+		o.hiddenFlags = true
+	}
+}
+
+// WithoutDeprecatedFlags leaves deprecated flags out of the schema. By
+// default they are included and marked deprecated, so existing callers
+// keep working while clients are steered away from them.
+func WithoutDeprecatedFlags() CobraOption {
+	return func(o *cobraOptions) {
+		o.withoutDeprecated = true
+	}
+}
+
+// WithPrompts answers the command's interactive prompts, read from
+// cmd.InOrStdin, as ExecSpec.Prompts does for executed commands. Answer
+// templates see the call's arguments by property name.
+func WithPrompts(prompts ...Prompt) CobraOption {
+	return func(o *cobraOptions) {
+		o.prompts = append(o.prompts, prompts...)
+	}
+}
+
+// WithCobraToolOptions applies tool options to the registered command.
+func WithCobraToolOptions(opts ...ToolOption) CobraOption {
+	return func(o *cobraOptions) {
+		o.toolOptions = append(o.toolOptions, opts...)
+	}
+}
+
+// RegisterCobraFlags registers cmd as a tool whose input schema is built
+// from its flags, so no args struct is needed. The schema has one
+// property per flag, named in snake_case, including the persistent flags
+// inherited from parent commands, and an "args" array for positional
+// arguments unless cmd.Args rejects any, limited to cmd.ValidArgs if set.
+// Flags marked required are required. Hidden flags are left out and
+// deprecated flags marked deprecated; see WithHiddenFlags and
+// WithoutDeprecatedFlags. ValidArgs, ValidArgsFunction and
+// flag completion functions answer MCP completion requests for the tool.
+// A call sets the given flags, runs the command like RegisterCobraCommand
+// and restores them.
+//
+// The tool is named after the command's path below the root, joined with
+// underscores, e.g. "users_list"; a root command is named after itself.
+func (w *Wrapper) RegisterCobraFlags(cmd *cobra.Command, opts ...CobraOption) error {
+	options := &cobraOptions{excluded: map[string]bool{"help": true}}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	name := cobraToolName(cmd)
+	if name == "" {
+		return fmt.Errorf("cobra command must have a Use field")
+	}
+
+	prompts, err := compilePrompts(options.prompts)
+	if err != nil {
+		return fmt.Errorf("command %s: %w", name, err)
+	}
+	flags := cobraFlags(cmd, options)
+	preview, err := options.applyDryRun(cmd, flags)
+	if err != nil {
+		return fmt.Errorf("command %s: %w", name, err)
+	}
+	schema := mcp.ToolInputSchema{
+		Type:       "object",
+		Properties: make(map[string]interface{}, len(flags)),
+		Required:   make([]string, 0),
+	}
+	for prop, flag := range flags {
+		schema.Properties[prop] = flagSchema(flag)
+		if options.completionEnums {
+			if enum, ok := completionEnum(cmd, flag); ok {
+				setEnum(schema.Properties[prop].(map[string]interface{}), enum)
+			}
+		}
+		if isRequiredFlag(flag) {
+			schema.Required = append(schema.Required, prop)
+		}
+	}
+	sort.Strings(schema.Required)
+	if preview {
+		schema.Properties[dryRunProperty] = map[string]interface{}{
+			"type":        "boolean",
+			"description": "Return the command line instead of running it",
+		}
+	}
+
+	hasArgs := acceptsArgs(cmd)
+	if hasArgs {
+		if _, taken := flags[argsProperty]; taken {
+			return fmt.Errorf("command %s: flag --%s collides with the positional arguments", name, argsProperty)
+		}
+		items := map[string]interface{}{"type": "string"}
+		if len(cmd.ValidArgs) > 0 {
+			items["enum"] = stringValues(validArgs(cmd))
+		}
+		schema.Properties[argsProperty] = map[string]interface{}{
+			"type":        "array",
+			"items":       items,
+			"description": "Positional arguments: " + cmd.Use,
+		}
+		if cmd.ValidateArgs(nil) != nil {
+			schema.Required = append(schema.Required, argsProperty)
+		}
+	}
+
+	handler := func(ctx context.Context, args interface{}) (interface{}, error) {
+		arguments := args.(Arguments)
+		positional := []string{}
+		if values, ok := arguments[argsProperty].([]interface{}); ok && hasArgs {
+			for _, v := range values {
+				positional = append(positional, argumentString(v))
+			}
+		}
+		if preview && arguments[dryRunProperty] == true {
+			return dryRun(cmd, flags, arguments, positional)
+		}
+
+		defer lockCobra(cmd)()
+		var restores []func()
+		defer func() {
+			for _, restore := range restores {
+				restore()
+			}
+		}()
+		for prop, flag := range flags {
+			value, ok := arguments[prop]
+			if !ok || value == nil {
+				continue
+			}
+			restores = append(restores, snapshotFlag(flag))
+			if err := setFlagArgument(flag, value); err != nil {
+				return nil, Errorf(CodeInvalidInput, "invalid value for --%s: %v", flag.Name, err)
+			}
+		}
+		return runCobra(ctx, cmd, positional, newPromptResponder(ctx, prompts, arguments, nil, nil))
+	}
+
+	if err := w.register(name, cobraDescription(cmd), Arguments{}, &schema, handler, options.toolOptions); err != nil {
+		return err
+	}
+	return w.registerCobraCompletions(name, cmd, flags, hasArgs)
+}
+
+func cobraToolName(cmd *cobra.Command) string {
+	if cmd.Use == "" {
+		return ""
+	}
+	if !cmd.HasParent() {
+		return cmd.Name()
+	}
+	path := strings.Fields(cmd.CommandPath())
+	return strings.Join(path[1:], "_")
+}
+
+func cobraDescription(cmd *cobra.Command) string {
+	if cmd.Short != "" {
+		return cmd.Short
+	}
+	if cmd.Long != "" {
+		return cmd.Long
+	}
+	return fmt.Sprintf("Execute %s command", cmd.Name())
+}
+
+// cobraFlags returns the command's local and inherited flags by property
+// name. A local flag shadows an inherited one of the same name.
+func cobraFlags(cmd *cobra.Command, options *cobraOptions) map[string]*pflag.Flag {
+	flags := make(map[string]*pflag.Flag)
+	add := func(flag *pflag.Flag) {
+		if !options.includes(flag) {
+			return
+		}
+		prop := strings.ReplaceAll(flag.Name, "-", "_")
+		if _, ok := flags[prop]; !ok {
+			flags[prop] = flag
+		}
+	}
+	cmd.LocalFlags().VisitAll(add)
+	cmd.InheritedFlags().VisitAll(add)
+	return flags
+}
+
+// includes reports whether a flag belongs in the schema.
+func (o *cobraOptions) includes(flag *pflag.Flag) bool {
+	switch {
+	case o.excluded[flag.Name], unsupportedFlagTypes[flag.Value.Type()]:
+		return false
+	case flag.Deprecated != "":
+		// MarkDeprecated hides flags too; the deprecated policy decides.
+		return !o.withoutDeprecated
+	case flag.Hidden && !o.hiddenFlags:
+		return isRequiredFlag(flag)
+	}
+	return true
+}
+
+// flagSchema describes a flag's value.
+func flagSchema(flag *pflag.Flag) map[string]interface{} {
+	prop := flagTypeSchema(flag.Value.Type())
+	hint, _ := flagTypeSchemas[flag.Value.Type()]["description"].(string)
+	switch {
+	case flag.Usage != "" && hint != "":
+		prop["description"] = flag.Usage + " (" + hint + ")"
+	case flag.Usage != "":
+		prop["description"] = flag.Usage
+	case hint != "":
+		prop["description"] = hint
+	}
+	if def, ok := flagDefault(flag); ok {
+		prop["default"] = def
+	}
+	if flag.Deprecated != "" {
+		prop["deprecated"] = true
+		description, _ := prop["description"].(string)
+		prop["description"] = strings.TrimSpace(description + " (deprecated: " + flag.Deprecated + ")")
+	}
+	return prop
+}
+
+// flagTypeSchemas maps pflag value types whose JSON form is more specific
+// than their JSON type. A description is a hint appended to the usage.
+var flagTypeSchemas = map[string]map[string]interface{}{
+	"count": {"type": "integer", "minimum": 0},
+	"duration": {
+		"type":        "string",
+		"format":      "duration",
+		"pattern":     durationPattern,
+		"description": "e.g. 300ms, 1m30s or 2h",
+	},
+	"ip":          {"type": "string", "pattern": `^[0-9A-Fa-f.:]+$`},
+	"ipMask":      {"type": "string", "pattern": `^([0-9]{1,3}(\.[0-9]{1,3}){3}|[0-9A-Fa-f]{8})$`},
+	"ipNet":       {"type": "string", "pattern": `^[0-9A-Fa-f.:]+/[0-9]{1,3}$`},
+	"bytesHex":    {"type": "string", "pattern": `^([0-9A-Fa-f]{2})*$`},
+	"bytesBase64": {"type": "string", "contentEncoding": "base64"},
+}
+
+// unsupportedFlagTypes are left out of flag schemas: pflag merges map
+// values into the previous ones, so they cannot be reset between calls,
+// and func flags run their callback on every Set.
+var unsupportedFlagTypes = map[string]bool{
+	"stringToString": true,
+	"stringToInt":    true,
+	"stringToInt64":  true,
+	"func":           true,
+	"boolfunc":       true,
+}
+
+// flagTypeSchema returns the schema for a pflag value type. Slice types,
+// named like "intSlice", become arrays of their element type, and
+// "stringArray" an array of strings.
+func flagTypeSchema(typ string) map[string]interface{} {
+	if typ == "stringArray" {
+		typ = "stringSlice"
+	}
+	if elem, ok := strings.CutSuffix(typ, "Slice"); ok {
+		return map[string]interface{}{"type": "array", "items": flagTypeSchema(elem)}
+	}
+
+	prop := map[string]interface{}{"type": flagJSONType(typ)}
+	for k, v := range flagTypeSchemas[typ] {
+		if k != "description" {
+			prop[k] = v
+		}
+	}
+	return prop
+}
+
+func flagJSONType(typ string) string {
+	if s, ok := flagTypeSchemas[typ]; ok {
+		return s["type"].(string)
+	}
+	switch typ {
+	case "bool":
+		return "boolean"
+	case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64":
+		return "integer"
+	case "float32", "float64":
+		return "number"
+	default:
+		if strings.HasSuffix(typ, "Slice") || typ == "stringArray" {
+			return "array"
+		}
+		return "string"
+	}
+}
+
+// flagDefault returns a flag's default as a JSON value, leaving out the
+// zero values that would only add noise.
+func flagDefault(flag *pflag.Flag) (interface{}, bool) {
+	def := flag.DefValue
+	switch flagJSONType(flag.Value.Type()) {
+	case "boolean":
+		b, err := strconv.ParseBool(def)
+		return b, err == nil && b
+	case "integer", "number":
+		n, err := strconv.ParseFloat(def, 64)
+		return n, err == nil && n != 0
+	case "array":
+		slice, ok := flag.Value.(pflag.SliceValue)
+		if !ok || len(slice.GetSlice()) == 0 {
+			return nil, false
+		}
+		items := flagTypeSchema(flag.Value.Type())["items"].(map[string]interface{})
+		values := make([]interface{}, 0, len(slice.GetSlice()))
+		for _, s := range slice.GetSlice() {
+			values = append(values, jsonFlagValue(items["type"].(string), s))
+		}
+		return values, true
+	default:
+		return def, def != "" && def != "<nil>"
+	}
+}
+
+func isRequiredFlag(flag *pflag.Flag) bool {
+	values := flag.Annotations[cobra.BashCompOneRequiredFlag]
+	return len(values) > 0 && values[0] == "true"
+}
+
+// acceptsArgs reports whether cmd takes positional arguments at all.
+func acceptsArgs(cmd *cobra.Command) bool {
+	if cmd.Args == nil {
+		return !cmd.HasSubCommands()
+	}
+	probe := "x"
+	if values := validArgs(cmd); len(values) > 0 {
+		probe = values[0]
+	}
+	return cmd.ValidateArgs([]string{probe}) == nil || cmd.ValidateArgs(nil) != nil
+}
+
+// setFlagArgument sets a flag from a JSON argument value.
+func setFlagArgument(flag *pflag.Flag, value interface{}) error {
+	if values, ok := value.([]interface{}); ok {
+		strs := make([]string, len(values))
+		for i, v := range values {
+			strs[i] = argumentString(v)
+		}
+		return setFlagValues(flag, strs)
+	}
+	return setFlagValue(flag, argumentString(value))
+}
@@ -0,0 +1,320 @@
+//go:build !nocobra
+
+package mcpwrapper
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/spf13/cobra"
+)
+
+// newCLI builds "app users list" with persistent flags on the root.
+func newCLI(run func(cmd *cobra.Command, args []string) error) (*cobra.Command, *cobra.Command) {
+	root := &cobra.Command{Use: "app"}
+	root.PersistentFlags().String("config", "", "Config file")
+	root.PersistentFlags().BoolP("verbose", "v", false, "Verbose output")
+
+	users := &cobra.Command{Use: "users", Short: "Manage users"}
+	users.PersistentFlags().String("org", "", "Organization")
+
+	list := &cobra.Command{Use: "list [filter]", Short: "List users", Args: cobra.MaximumNArgs(1), RunE: run}
+	list.Flags().Int("page-size", 20, "Users per page")
+	list.MarkFlagRequired("page-size")
+
+	root.AddCommand(users)
+	users.AddCommand(list)
+	return root, list
+}
+
+func TestRegisterCobraFlags(t *testing.T) {
+	var gotOrg, gotConfig string
+	var gotVerbose bool
+	var gotPageSize int
+	var gotArgs []string
+	_, list := newCLI(func(cmd *cobra.Command, args []string) error {
+		gotOrg, _ = cmd.Flags().GetString("org")
+		gotConfig, _ = cmd.Flags().GetString("config")
+		gotVerbose, _ = cmd.Flags().GetBool("verbose")
+		gotPageSize, _ = cmd.Flags().GetInt("page-size")
+		gotArgs = args
+		cmd.Println("alice")
+		return nil
+	})
+
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	wrapper := New(mcpServer)
+	if err := wrapper.RegisterCobraFlags(list, WithExcludedFlags("--config")); err != nil {
+		t.Fatalf("RegisterCobraFlags failed: %v", err)
+	}
+
+	tool := mcpServer.GetTool("users_list")
+	if tool == nil {
+		t.Fatal("Expected a tool named after the command path")
+	}
+	props := tool.Tool.InputSchema.Properties
+	var names []string
+	for name := range props {
+		names = append(names, name)
+	}
+	if want := []string{"args", "org", "page_size", "verbose"}; !sameValues(names, want) {
+		t.Errorf("Expected properties %v, got %v", want, names)
+	}
+	if p := props["page_size"].(map[string]interface{}); p["type"] != "integer" || p["default"] != float64(20) {
+		t.Errorf("Unexpected page_size schema: %v", p)
+	}
+	if !reflect.DeepEqual(tool.Tool.InputSchema.Required, []string{"page_size"}) {
+		t.Errorf("Expected page_size to be required, got %v", tool.Tool.InputSchema.Required)
+	}
+
+	result := callTool(t, mcpServer, "users_list", map[string]interface{}{
+		"org": "acme", "verbose": true, "page_size": 5, "args": []interface{}{"a*"},
+	})
+	if result.IsError {
+		t.Fatalf("Unexpected error: %v", result.Content)
+	}
+	if gotOrg != "acme" || !gotVerbose || gotPageSize != 5 || gotConfig != "" || !reflect.DeepEqual(gotArgs, []string{"a*"}) {
+		t.Errorf("Unexpected invocation: org=%q verbose=%v page_size=%d config=%q args=%v", gotOrg, gotVerbose, gotPageSize, gotConfig, gotArgs)
+	}
+	var output ExecResult
+	json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &output)
+	if output.ExitCode != 0 || output.Stdout != "alice\n" {
+		t.Errorf("Expected the command output to be captured, got %+v", output)
+	}
+
+	callTool(t, mcpServer, "users_list", map[string]interface{}{"page_size": 1})
+	if gotOrg != "" || gotVerbose {
+		t.Errorf("Expected inherited flags to be reset, got org=%q verbose=%v", gotOrg, gotVerbose)
+	}
+
+	result = callTool(t, mcpServer, "users_list", map[string]interface{}{"page_size": 1, "args": []interface{}{"a", "b"}})
+	if !result.IsError || !strings.Contains(result.Content[0].(mcp.TextContent).Text, "accepts at most 1 arg(s)") {
+		t.Errorf("Expected cmd.Args to be enforced, got %v", result.Content)
+	}
+}
+
+func TestRegisterCobraFlagsHooks(t *testing.T) {
+	var calls []string
+	root, list := newCLI(func(cmd *cobra.Command, args []string) error {
+		calls = append(calls, "run")
+		return nil
+	})
+	root.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		calls = append(calls, "pre:"+cmd.Name())
+		return nil
+	}
+	list.Args = cobra.NoArgs
+
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	if err := New(mcpServer).RegisterCobraFlags(list); err != nil {
+		t.Fatalf("RegisterCobraFlags failed: %v", err)
+	}
+	if _, ok := mcpServer.GetTool("users_list").Tool.InputSchema.Properties["args"]; ok {
+		t.Error("Expected no args property for cobra.NoArgs")
+	}
+	if _, ok := mcpServer.GetTool("users_list").Tool.InputSchema.Properties["config"]; !ok {
+		t.Error("Expected inherited flags by default")
+	}
+
+	callTool(t, mcpServer, "users_list", map[string]interface{}{"page_size": 1})
+	if want := []string{"pre:list", "run"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("Expected %v, got %v", want, calls)
+	}
+}
+
+func TestRegisterCobraFlagsTypes(t *testing.T) {
+	var tags []string
+	var timeout time.Duration
+	var verbosity int
+	var subnet net.IPNet
+	var key []byte
+	cmd := &cobra.Command{Use: "sync", RunE: func(cmd *cobra.Command, args []string) error {
+		tags, _ = cmd.Flags().GetStringSlice("tags")
+		timeout, _ = cmd.Flags().GetDuration("timeout")
+		verbosity, _ = cmd.Flags().GetCount("verbosity")
+		subnet, _ = cmd.Flags().GetIPNet("subnet")
+		key, _ = cmd.Flags().GetBytesHex("key")
+		return nil
+	}}
+	cmd.Flags().StringSlice("tags", []string{"a"}, "Tags to sync")
+	cmd.Flags().IntSlice("ports", nil, "Ports")
+	cmd.Flags().Duration("timeout", time.Minute, "Sync timeout")
+	cmd.Flags().CountP("verbosity", "V", "Verbosity")
+	cmd.Flags().IPNet("subnet", net.IPNet{}, "Subnet")
+	cmd.Flags().BytesHex("key", nil, "Key")
+	cmd.Flags().StringToString("labels", nil, "Labels")
+
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	if err := New(mcpServer).RegisterCobraFlags(cmd); err != nil {
+		t.Fatalf("RegisterCobraFlags failed: %v", err)
+	}
+	props := mcpServer.GetTool("sync").Tool.InputSchema.Properties
+	prop := func(name string) map[string]interface{} {
+		p, _ := props[name].(map[string]interface{})
+		return p
+	}
+
+	if p := prop("tags"); p["type"] != "array" || !reflect.DeepEqual(p["items"], map[string]interface{}{"type": "string"}) ||
+		!reflect.DeepEqual(p["default"], []interface{}{"a"}) {
+		t.Errorf("Unexpected tags schema: %v", p)
+	}
+	if p := prop("ports"); p["type"] != "array" || !reflect.DeepEqual(p["items"], map[string]interface{}{"type": "integer"}) {
+		t.Errorf("Unexpected ports schema: %v", p)
+	}
+	if p := prop("timeout"); p["type"] != "string" || p["format"] != "duration" || p["default"] != "1m0s" ||
+		!strings.Contains(p["description"].(string), "1m30s") {
+		t.Errorf("Unexpected timeout schema: %v", p)
+	}
+	if p := prop("verbosity"); p["type"] != "integer" || p["minimum"] != 0 {
+		t.Errorf("Unexpected verbosity schema: %v", p)
+	}
+	if p := prop("subnet"); p["type"] != "string" || p["pattern"] == nil || p["default"] != nil {
+		t.Errorf("Unexpected subnet schema: %v", p)
+	}
+	if p := prop("key"); p["type"] != "string" || p["pattern"] == nil {
+		t.Errorf("Unexpected key schema: %v", p)
+	}
+	if _, ok := props["labels"]; ok {
+		t.Error("Expected map flags to be left out")
+	}
+
+	result := callTool(t, mcpServer, "sync", map[string]interface{}{
+		"tags": []interface{}{"x", "y"}, "timeout": "1m30s", "verbosity": 3,
+		"subnet": "10.0.0.0/8", "key": "cafe", "ports": []interface{}{80, 443},
+	})
+	if result.IsError {
+		t.Fatalf("Unexpected error: %v", result.Content)
+	}
+	if !reflect.DeepEqual(tags, []string{"x", "y"}) || timeout != 90*time.Second || verbosity != 3 ||
+		subnet.String() != "10.0.0.0/8" || !bytes.Equal(key, []byte{0xca, 0xfe}) {
+		t.Errorf("Unexpected values: tags=%v timeout=%v verbosity=%d subnet=%v key=%x", tags, timeout, verbosity, subnet.String(), key)
+	}
+
+	callTool(t, mcpServer, "sync", map[string]interface{}{})
+	if !reflect.DeepEqual(tags, []string{"a"}) || timeout != time.Minute || verbosity != 0 || subnet.IP != nil || len(key) != 0 {
+		t.Errorf("Expected flags to be reset, got tags=%v timeout=%v verbosity=%d subnet=%v key=%x", tags, timeout, verbosity, subnet.String(), key)
+	}
+
+	result = callTool(t, mcpServer, "sync", map[string]interface{}{"timeout": "soon"})
+	if !result.IsError || !strings.Contains(result.Content[0].(mcp.TextContent).Text, "invalid value for --timeout") {
+		t.Errorf("Expected an invalid duration to be rejected, got %v", result.Content)
+	}
+}
+
+func TestRegisterCobraFlagsHiddenDeprecated(t *testing.T) {
+	newCmd := func() *cobra.Command {
+		cmd := &cobra.Command{Use: "deploy", RunE: func(cmd *cobra.Command, args []string) error { return nil }}
+		cmd.Flags().String("zone", "", "Zone")
+		cmd.Flags().String("region", "", "Region")
+		cmd.Flags().MarkDeprecated("region", "use --zone instead")
+		cmd.Flags().Bool("debug-dump", false, "")
+		cmd.Flags().MarkHidden("debug-dump")
+		cmd.Flags().String("token", "", "Token")
+		cmd.Flags().MarkHidden("token")
+		cmd.MarkFlagRequired("token")
+		return cmd
+	}
+	properties := func(opts ...CobraOption) map[string]interface{} {
+		mcpServer := server.NewMCPServer("test", "1.0.0")
+		if err := New(mcpServer).RegisterCobraFlags(newCmd(), opts...); err != nil {
+			t.Fatalf("RegisterCobraFlags failed: %v", err)
+		}
+		return mcpServer.GetTool("deploy").Tool.InputSchema.Properties
+	}
+
+	props := properties()
+	if _, ok := props["debug_dump"]; ok {
+		t.Error("Expected hidden flags to be left out by default")
+	}
+	if _, ok := props["token"]; !ok {
+		t.Error("Expected required hidden flags to be kept")
+	}
+	region, _ := props["region"].(map[string]interface{})
+	if region["deprecated"] != true || region["description"] != "Region (deprecated: use --zone instead)" {
+		t.Errorf("Expected region to be marked deprecated, got %v", region)
+	}
+	if _, ok := props["zone"].(map[string]interface{})["deprecated"]; ok {
+		t.Error("Expected zone not to be marked deprecated")
+	}
+
+	props = properties(WithHiddenFlags(), WithoutDeprecatedFlags())
+	if _, ok := props["debug_dump"]; !ok {
+		t.Error("Expected WithHiddenFlags to include hidden flags")
+	}
+	if _, ok := props["region"]; ok {
+		t.Error("Expected WithoutDeprecatedFlags to leave out deprecated flags")
+	}
+}
+
+func TestRegisterCobraFlagsPrompts(t *testing.T) {
+	var answers []string
+	cmd := &cobra.Command{
+		Use: "purge",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.Print("Delete everything? [y/N] ")
+			line, _ := bufio.NewReader(cmd.InOrStdin()).ReadString('\n')
+			answers = append(answers, strings.TrimSpace(line))
+			return nil
+		},
+	}
+	cmd.Flags().Bool("yes", false, "Skip the confirmation")
+
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	err := New(mcpServer).RegisterCobraFlags(cmd, WithPrompts(Prompt{
+		Pattern: `Delete everything\?`,
+		Answer:  "{{if .yes}}y{{else}}n{{end}}",
+	}))
+	if err != nil {
+		t.Fatalf("RegisterCobraFlags failed: %v", err)
+	}
+	callTool(t, mcpServer, "purge", map[string]interface{}{"yes": true})
+	callTool(t, mcpServer, "purge", map[string]interface{}{})
+	if want := []string{"y", "n"}; !reflect.DeepEqual(answers, want) {
+		t.Errorf("Expected answers %v, got %v", want, answers)
+	}
+
+	// Without prompts, reading stdin must not consume the server's stdin.
+	answers = nil
+	plain := &cobra.Command{Use: "plain", RunE: cmd.RunE}
+	if err := New(mcpServer).RegisterCobraFlags(plain); err != nil {
+		t.Fatalf("RegisterCobraFlags failed: %v", err)
+	}
+	callTool(t, mcpServer, "plain", map[string]interface{}{})
+	if want := []string{""}; !reflect.DeepEqual(answers, want) {
+		t.Errorf("Expected an empty stdin, got %v", answers)
+	}
+}
+
+func TestRegisterCobraFlagsExitError(t *testing.T) {
+	cmd := &cobra.Command{
+		Use: "sync",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.Println("synced 2 of 3")
+			cmd.PrintErrln("warning: item 3 is locked")
+			return errors.New("sync incomplete")
+		},
+	}
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	if err := New(mcpServer).RegisterCobraFlags(cmd); err != nil {
+		t.Fatalf("RegisterCobraFlags failed: %v", err)
+	}
+
+	result := callTool(t, mcpServer, "sync", map[string]interface{}{})
+	text := result.Content[0].(mcp.TextContent).Text
+	if !result.IsError || !strings.Contains(text, "sync incomplete") || !strings.Contains(text, "stderr: warning: item 3 is locked") {
+		t.Errorf("Expected the error with stderr, got %q", text)
+	}
+	details := result.StructuredContent.(map[string]interface{})
+	if details["exit_code"] != 1 || details["stdout"] != "synced 2 of 3\n" {
+		t.Errorf("Unexpected exit details: %v", details)
+	}
+}
@@ -0,0 +1,174 @@
+//go:build !nocobra
+
+package mcpwrapper
+
+import (
+	"context"
+
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/spf13/cobra"
+)
+
+// CobraExcludeAnnotation set to "true" on a command keeps it and its
+// subcommands out of RegisterCobraTree.
+const CobraExcludeAnnotation = "mcpwrapper.exclude"
+
+// RegisterCobraTree registers every available runnable command below root,
+// and root itself if it is runnable, with RegisterCobraFlags. Hidden and
+// deprecated commands, the help and completion commands and commands
+// annotated with CobraExcludeAnnotation are skipped, with their
+// subcommands. It returns the registered tool names.
+func (w *Wrapper) RegisterCobraTree(root *cobra.Command, opts ...CobraOption) ([]string, error) {
+	var names []string
+	var walk func(cmd *cobra.Command) error
+	walk = func(cmd *cobra.Command) error {
+		if cmd.Hidden || cmd.Deprecated != "" || cmd.Annotations[CobraExcludeAnnotation] == "true" {
+			return nil
+		}
+		if cmd.Runnable() {
+			if err := w.RegisterCobraFlags(cmd, opts...); err != nil {
+				return err
+			}
+			names = append(names, cobraToolName(cmd))
+		}
+		for _, sub := range cmd.Commands() {
+			if isBuiltinCommand(sub) {
+				continue
+			}
+			if err := walk(sub); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := walk(root); err != nil {
+		return names, err
+	}
+	return names, nil
+}
+
+// isBuiltinCommand reports whether cmd is cobra's help or completion
+// command.
+func isBuiltinCommand(cmd *cobra.Command) bool {
+	if cmd.Parent() == nil || cmd.Parent().HasParent() {
+		return false
+	}
+	// IsAvailableCommand is false for the help command only, among
+	// runnable, visible commands.
+	if cmd.Runnable() && !cmd.Hidden && cmd.Deprecated == "" && !cmd.IsAvailableCommand() {
+		return true
+	}
+	// The completion command only groups one subcommand per shell.
+	return cmd.Name() == "completion" && !cmd.Runnable()
+}
+
+// ServeOption configures NewServeCommand.
+type ServeOption func(*serveOptions)
+
+type serveOptions struct {
+	name          string
+	version       string
+	options       []Option
+	serverOptions []server.ServerOption
+	cobraOptions  []CobraOption
+	httpOptions   []HTTPOption
+	setup         []func(*Wrapper) error
+}
+
+// WithServeName sets the command's name, "mcp" by default.
+func WithServeName(name string) ServeOption {
+	return func(o *serveOptions) {
+		o.name = name
+	}
+}
+
+// WithServeVersion sets the server version reported to clients, by
+// default the root command's Version.
+func WithServeVersion(version string) ServeOption {
+	return func(o *serveOptions) {
+		o.version = version
+	}
+}
+
+// WithServeOptions passes options to New.
+func WithServeOptions(opts ...Option) ServeOption {
+	return func(o *serveOptions) {
+		o.options = append(o.options, opts...)
+	}
+}
+
+// WithServeServerOptions passes options to server.NewMCPServer.
+func WithServeServerOptions(opts ...server.ServerOption) ServeOption {
+	return func(o *serveOptions) {
+		o.serverOptions = append(o.serverOptions, opts...)
+	}
+}
+
+// WithServeCobraOptions passes options to RegisterCobraTree.
+func WithServeCobraOptions(opts ...CobraOption) ServeOption {
+	return func(o *serveOptions) {
+		o.cobraOptions = append(o.cobraOptions, opts...)
+	}
+}
+
+// WithServeHTTPOptions passes options to ServeStreamableHTTP.
+func WithServeHTTPOptions(opts ...HTTPOption) ServeOption {
+	return func(o *serveOptions) {
+		o.httpOptions = append(o.httpOptions, opts...)
+	}
+}
+
+// WithServeSetup runs fn on the wrapper after the command tree is
+// registered and before serving, to add tools, middleware or hooks.
+func WithServeSetup(fn func(*Wrapper) error) ServeOption {
+	return func(o *serveOptions) {
+		o.setup = append(o.setup, fn)
+	}
+}
+
+// NewServeCommand returns a subcommand that serves root's command tree as
+// MCP tools, so an existing CLI becomes an MCP server with one line:
+//
+//	rootCmd.AddCommand(mcpwrapper.NewServeCommand(rootCmd))
+//
+// "app mcp" serves on stdin and stdout; "app mcp --http :8080" serves the
+// streamable HTTP transport instead. Both stop gracefully on SIGINT or
+// SIGTERM. The serve command excludes itself from the tree.
+func NewServeCommand(root *cobra.Command, opts ...ServeOption) *cobra.Command {
+	o := &serveOptions{name: "mcp", version: root.Version}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.version == "" {
+		o.version = "dev"
+	}
+
+	cmd := &cobra.Command{
+		Use:          o.name,
+		Short:        "Serve this CLI's commands as MCP tools",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		Annotations:  map[string]string{CobraExcludeAnnotation: "true"},
+	}
+	addr := cmd.Flags().String("http", "", "Serve streamable HTTP on this address instead of stdio")
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		w := New(server.NewMCPServer(root.Name(), o.version, o.serverOptions...), o.options...)
+		if _, err := w.RegisterCobraTree(root, o.cobraOptions...); err != nil {
+			return err
+		}
+		for _, setup := range o.setup {
+			if err := setup(w); err != nil {
+				return err
+			}
+		}
+
+		serve := w.ServeStdio
+		if *addr != "" {
+			serve = func(ctx context.Context) error {
+				return w.ServeStreamableHTTP(ctx, *addr, o.httpOptions...)
+			}
+		}
+		return w.RunWithSignals(serve)
+	}
+	return cmd
+}
@@ -0,0 +1,62 @@
+//go:build !nocobra
+
+package mcpwrapper
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/spf13/cobra"
+)
+
+func TestRegisterCobraTree(t *testing.T) {
+	root, _ := newCLI(func(cmd *cobra.Command, args []string) error { return nil })
+	users := root.Commands()[0]
+	run := func(cmd *cobra.Command, args []string) error { return nil }
+	users.AddCommand(
+		&cobra.Command{Use: "purge", Hidden: true, RunE: run},
+		&cobra.Command{Use: "rm", Deprecated: "use delete", RunE: run},
+		&cobra.Command{Use: "delete <id>", Args: cobra.ExactArgs(1), RunE: run},
+	)
+	internal := &cobra.Command{Use: "internal", Annotations: map[string]string{CobraExcludeAnnotation: "true"}}
+	internal.AddCommand(&cobra.Command{Use: "debug", RunE: run})
+	root.AddCommand(internal)
+	root.InitDefaultHelpCmd()
+	root.InitDefaultCompletionCmd()
+
+	wrapper := New(server.NewMCPServer("test", "1.0.0"))
+	names, err := wrapper.RegisterCobraTree(root)
+	if err != nil {
+		t.Fatalf("RegisterCobraTree failed: %v", err)
+	}
+	sort.Strings(names)
+	if want := []string{"users_delete", "users_list"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("Expected tools %v, got %v", want, names)
+	}
+	if want := []string{"users_delete", "users_list"}; !reflect.DeepEqual(wrapper.ToolNames(), want) {
+		t.Errorf("Expected registered tools %v, got %v", want, wrapper.ToolNames())
+	}
+}
+
+func TestNewServeCommand(t *testing.T) {
+	root, _ := newCLI(func(cmd *cobra.Command, args []string) error { return nil })
+	serve := NewServeCommand(root, WithServeName("serve-mcp"), WithServeSetup(func(w *Wrapper) error {
+		if want := []string{"users_list"}; !reflect.DeepEqual(w.ToolNames(), want) {
+			t.Errorf("Expected tools %v before setup, got %v", want, w.ToolNames())
+		}
+		return errors.New("setup failed")
+	}))
+	root.AddCommand(serve)
+
+	if serve.Name() != "serve-mcp" || serve.Flags().Lookup("http") == nil {
+		t.Errorf("Unexpected serve command %q", serve.Use)
+	}
+	root.SetArgs([]string{"serve-mcp"})
+	root.SilenceErrors = true
+	if err := root.Execute(); err == nil || err.Error() != "setup failed" {
+		t.Errorf("Expected the setup error before serving, got %v", err)
+	}
+}
@@ -0,0 +1,109 @@
+package mcpwrapper
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// maxCompletionValues is the protocol's limit on values per response.
+const maxCompletionValues = 100
+
+// CompletionFunc returns candidate values for an argument given what the user
+// has typed so far.
+type CompletionFunc func(ctx context.Context, partial string) ([]string, error)
+
+type completions struct {
+	mu    sync.RWMutex
+	funcs map[string]map[string]CompletionFunc
+}
+
+func newCompletions() *completions {
+	return &completions{funcs: make(map[string]map[string]CompletionFunc)}
+}
+
+func (c *completions) lookup(name, field string) (CompletionFunc, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	fn, ok := c.funcs[name][field]
+	return fn, ok
+}
+
+// RegisterCompletion provides autocompletion for one argument of a tool or
+// prompt (or for a resource template, keyed by its URI template).
+func (w *Wrapper) RegisterCompletion(name, field string, fn CompletionFunc) error {
+	if fn == nil {
+		return fmt.Errorf("completion for %s.%s must not be nil", name, field)
+	}
+
+	w.completions.mu.Lock()
+	defer w.completions.mu.Unlock()
+	if w.completions.funcs[name] == nil {
+		w.completions.funcs[name] = make(map[string]CompletionFunc)
+	}
+	w.completions.funcs[name][field] = fn
+	return nil
+}
+
+// Complete answers a completion/complete request. References without a
+// registered completion get an empty list, as the protocol expects.
+func (w *Wrapper) Complete(ctx context.Context, request mcp.CompleteRequest) (result *mcp.CompleteResult, err error) {
+	name := completionRefName(request.Params.Ref)
+	field := request.Params.Argument.Name
+
+	defer func() {
+		if r := recover(); r != nil {
+			panicErr := newPanicError(r)
+			w.logger.Error("completion panicked", "ref", name, "argument", field, "panic", panicErr.Value, "stack", string(panicErr.Stack))
+			result, err = nil, panicErr
+		}
+	}()
+
+	result = &mcp.CompleteResult{}
+	result.Completion.Values = []string{}
+
+	fn, ok := w.completions.lookup(name, field)
+	if !ok {
+		return result, nil
+	}
+
+	values, err := fn(ctx, request.Params.Argument.Value)
+	if err != nil {
+		return nil, fmt.Errorf("completion for %s.%s failed: %w", name, field, err)
+	}
+
+	if len(values) > maxCompletionValues {
+		result.Completion.Total = len(values)
+		result.Completion.HasMore = true
+		values = values[:maxCompletionValues]
+	}
+	if values != nil {
+		result.Completion.Values = values
+	}
+	return result, nil
+}
+
+// completionRefName reads the prompt name or resource URI of a reference,
+// which arrives either typed or as a decoded JSON object. A "ref/tool"
+// reference with a name is accepted for tool arguments.
+func completionRefName(ref interface{}) string {
+	switch r := ref.(type) {
+	case mcp.PromptReference:
+		return r.Name
+	case *mcp.PromptReference:
+		return r.Name
+	case mcp.ResourceReference:
+		return r.URI
+	case *mcp.ResourceReference:
+		return r.URI
+	case map[string]interface{}:
+		if name, ok := r["name"].(string); ok {
+			return name
+		}
+		uri, _ := r["uri"].(string)
+		return uri
+	}
+	return ""
+}
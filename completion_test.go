@@ -0,0 +1,100 @@
+package mcpwrapper
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func TestCompletion(t *testing.T) {
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	wrapper := New(mcpServer)
+
+	branches := []string{"main", "master", "feature/login"}
+	err := wrapper.RegisterCompletion("checkout", "branch", func(ctx context.Context, partial string) ([]string, error) {
+		var matches []string
+		for _, b := range branches {
+			if strings.HasPrefix(b, partial) {
+				matches = append(matches, b)
+			}
+		}
+		return matches, nil
+	})
+	if err != nil {
+		t.Fatalf("RegisterCompletion failed: %v", err)
+	}
+
+	response := wrapper.HandleMessage(context.Background(), json.RawMessage(`{
+		"jsonrpc": "2.0",
+		"id": 7,
+		"method": "completion/complete",
+		"params": {"ref": {"type": "ref/prompt", "name": "checkout"}, "argument": {"name": "branch", "value": "ma"}}
+	}`))
+
+	resp, ok := response.(mcp.JSONRPCResponse)
+	if !ok {
+		t.Fatalf("Expected a result response, got %#v", response)
+	}
+	result := resp.Result.(*mcp.CompleteResult)
+	if !reflect.DeepEqual(result.Completion.Values, []string{"main", "master"}) {
+		t.Errorf("Unexpected completions: %v", result.Completion.Values)
+	}
+
+	result, err = wrapper.Complete(context.Background(), mcp.CompleteRequest{
+		Params: mcp.CompleteParams{Ref: mcp.PromptReference{Type: "ref/prompt", Name: "checkout"}},
+	})
+	if err != nil || len(result.Completion.Values) != 0 {
+		t.Errorf("Expected no completions for an unknown argument, got %v %v", result, err)
+	}
+
+	if response := wrapper.HandleMessage(context.Background(), json.RawMessage(`{"jsonrpc": "2.0", "id": 8, "method": "ping"}`)); response == nil {
+		t.Error("Expected other methods to be handled by the server")
+	} else if _, ok := response.(mcp.JSONRPCResponse); !ok {
+		t.Errorf("Expected ping response, got %#v", response)
+	}
+}
+
+func TestCompletionLimitAndPanic(t *testing.T) {
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	wrapper := New(mcpServer)
+
+	wrapper.RegisterCompletion("files", "path", func(ctx context.Context, partial string) ([]string, error) {
+		values := make([]string, 150)
+		for i := range values {
+			values[i] = fmt.Sprintf("file-%d", i)
+		}
+		return values, nil
+	})
+	wrapper.RegisterCompletion("files", "owner", func(ctx context.Context, partial string) ([]string, error) {
+		panic("boom")
+	})
+
+	request := func(field string) mcp.CompleteRequest {
+		r := mcp.CompleteRequest{Params: mcp.CompleteParams{Ref: map[string]interface{}{"type": "ref/tool", "name": "files"}}}
+		r.Params.Argument.Name = field
+		return r
+	}
+
+	result, err := wrapper.Complete(context.Background(), request("path"))
+	if err != nil {
+		t.Fatalf("Complete failed: %v", err)
+	}
+	if len(result.Completion.Values) != maxCompletionValues || result.Completion.Total != 150 || !result.Completion.HasMore {
+		t.Errorf("Expected truncated completions, got %d values total=%d hasMore=%v",
+			len(result.Completion.Values), result.Completion.Total, result.Completion.HasMore)
+	}
+
+	if _, err := wrapper.Complete(context.Background(), request("owner")); err == nil {
+		t.Error("Expected a panicking completion to return an error")
+	}
+
+	if err := wrapper.RegisterCompletion("files", "path", nil); err == nil {
+		t.Error("Expected an error for a nil completion")
+	}
+}
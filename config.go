@@ -0,0 +1,87 @@
+package mcpwrapper
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+type Config struct {
+	Environment string                `json:"environment,omitempty" yaml:"environment,omitempty"`
+	Tools       map[string]ToolConfig `json:"tools" yaml:"tools"`
+}
+
+type ToolConfig struct {
+	Transform      *TransformConfig      `json:"transform,omitempty" yaml:"transform,omitempty"`
+	Cache          *CacheConfig          `json:"cache,omitempty" yaml:"cache,omitempty"`
+	Availability   *AvailabilityConfig   `json:"availability,omitempty" yaml:"availability,omitempty"`
+	CircuitBreaker *CircuitBreakerConfig `json:"circuit_breaker,omitempty" yaml:"circuit_breaker,omitempty"`
+	Webhooks       []Webhook             `json:"webhooks,omitempty" yaml:"webhooks,omitempty"`
+}
+
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config %s: %w", path, err)
+	}
+
+	cfg, err := ParseConfig(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse config %s: %w", path, err)
+	}
+
+	return cfg, nil
+}
+
+// ParseConfig accepts YAML or JSON (JSON is valid YAML).
+func ParseConfig(data []byte) (*Config, error) {
+	cfg := &Config{}
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, err
+	}
+
+	for name, toolCfg := range cfg.Tools {
+		if err := toolCfg.validate(); err != nil {
+			return nil, fmt.Errorf("tool %s: %w", name, err)
+		}
+	}
+
+	return cfg, nil
+}
+
+func (c ToolConfig) validate() error {
+	if c.Transform != nil {
+		if err := c.Transform.validate(); err != nil {
+			return err
+		}
+	}
+	if c.Cache != nil {
+		if err := c.Cache.validate(); err != nil {
+			return fmt.Errorf("cache: %w", err)
+		}
+	}
+	if c.Availability != nil {
+		if _, err := c.Availability.compile(); err != nil {
+			return fmt.Errorf("availability: %w", err)
+		}
+	}
+	if c.CircuitBreaker != nil {
+		if err := c.CircuitBreaker.validate(); err != nil {
+			return fmt.Errorf("circuit_breaker: %w", err)
+		}
+	}
+	for _, hook := range c.Webhooks {
+		if _, err := hook.compile(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Config) tool(name string) ToolConfig {
+	if c == nil || c.Tools == nil {
+		return ToolConfig{}
+	}
+	return c.Tools[name]
+}
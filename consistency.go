@@ -0,0 +1,261 @@
+package mcpwrapper
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ConsistencyIssue is a field whose advertised schema and validate tag
+// disagree.
+type ConsistencyIssue struct {
+	Tool string
+	// Field is the JSON path of the field, e.g. "target.cluster".
+	Field   string
+	Message string
+}
+
+func (i ConsistencyIssue) String() string {
+	return fmt.Sprintf("tool %s, field %s: %s", i.Tool, i.Field, i.Message)
+}
+
+// CheckConsistency compares the input schema of every struct-based tool
+// with the validate tags of its fields and reports where they drift apart:
+//
+//   - a property required in the schema without validate:"required", so
+//     omitting it is accepted;
+//   - enum values that differ from oneof, or oneof without an enum the model
+//     can see;
+//   - min, max, len, gte, lte, gt and lt that differ from minLength,
+//     maxLength, minimum, maximum, exclusiveMinimum and exclusiveMaximum, or
+//     that the schema doesn't advertise;
+//   - schema bounds nothing enforces, unless WithStrictSchema is set.
+//
+// Tools registered with RegisterSchema are checked against their schema
+// directly and are skipped.
+func (w *Wrapper) CheckConsistency() []ConsistencyIssue {
+	var issues []ConsistencyIssue
+	for _, name := range w.ToolNames() {
+		t, ok := w.lookupTool(name)
+		if !ok {
+			continue
+		}
+		typ := reflect.TypeOf(t.argsType)
+		if typ.Kind() == reflect.Ptr {
+			typ = typ.Elem()
+		}
+		if typ.Kind() != reflect.Struct {
+			continue
+		}
+		root, err := schemaMap(t.tool.InputSchema)
+		if err != nil {
+			continue
+		}
+
+		c := &consistencyChecker{
+			schemaResolver: newSchemaResolver(root),
+			tool:           name,
+			strict:         w.strictSchema,
+			seen:           make(map[reflect.Type]bool),
+		}
+		c.checkStruct(typ, root, "")
+		issues = append(issues, c.issues...)
+	}
+	return issues
+}
+
+type consistencyChecker struct {
+	schemaResolver
+	tool   string
+	strict bool
+	seen   map[reflect.Type]bool
+	issues []ConsistencyIssue
+}
+
+func (c *consistencyChecker) report(field, format string, args ...interface{}) {
+	c.issues = append(c.issues, ConsistencyIssue{Tool: c.tool, Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+func (c *consistencyChecker) checkStruct(t reflect.Type, schema map[string]interface{}, prefix string) {
+	if c.seen[t] {
+		return
+	}
+	c.seen[t] = true
+	defer delete(c.seen, t)
+
+	schema = c.resolve(schema)
+	if schema == nil {
+		return
+	}
+	props, _ := schema["properties"].(map[string]interface{})
+	required := make(map[string]bool)
+	for _, name := range listValues(schema["required"]) {
+		if s, ok := name.(string); ok {
+			required[s] = true
+		}
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if embedded, ok := embeddedStruct(field); ok {
+			c.checkStruct(embedded, schema, prefix)
+			continue
+		}
+		jsonTag := field.Tag.Get("json")
+		if jsonTag == "" || jsonTag == "-" {
+			continue
+		}
+		name := strings.Split(jsonTag, ",")[0]
+		prop, _ := props[name].(map[string]interface{})
+		if prop == nil {
+			continue
+		}
+		path := prefix + name
+		rules := parseValidateTag(field.Tag.Get("validate"))
+
+		if required[name] && !rules.has("required") {
+			c.report(path, `required in the schema but not validated; add validate:"required"`)
+		}
+
+		ft := field.Type
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		resolved := c.resolve(prop)
+		if resolved == nil {
+			continue
+		}
+		c.checkEnum(path, resolved, rules)
+		switch ft.Kind() {
+		case reflect.String:
+			c.checkBounds(path, resolved, rules, stringBounds)
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+			reflect.Float32, reflect.Float64:
+			c.checkBounds(path, resolved, rules, numberBounds)
+		case reflect.Struct:
+			if ft != timeType && !isUnmarshaler(ft) {
+				c.checkStruct(ft, prop, path+".")
+			}
+		}
+	}
+}
+
+func (c *consistencyChecker) checkEnum(path string, prop map[string]interface{}, rules validateRules) {
+	oneof, hasOneof := rules.value("oneof")
+	enum := listValues(prop["enum"])
+	if !hasOneof {
+		return
+	}
+	allowed := strings.Fields(oneof)
+	if len(enum) == 0 {
+		c.report(path, "validate oneof=%s is not in the schema; add enum values", oneof)
+		return
+	}
+
+	values := make([]string, len(enum))
+	for i, v := range enum {
+		values[i] = fmt.Sprint(v)
+	}
+	if !sameValues(values, allowed) {
+		c.report(path, "schema enum [%s] differs from validate oneof=%s", strings.Join(values, " "), oneof)
+	}
+}
+
+func sameValues(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	a = append([]string(nil), a...)
+	b = append([]string(nil), b...)
+	sort.Strings(a)
+	sort.Strings(b)
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// boundPair links a schema keyword to the validate rules that enforce the
+// same bound.
+type boundPair struct {
+	keyword string
+	rules   []string
+}
+
+var stringBounds = []boundPair{
+	{"minLength", []string{"min", "len"}},
+	{"maxLength", []string{"max", "len"}},
+}
+
+var numberBounds = []boundPair{
+	{"minimum", []string{"min", "gte"}},
+	{"maximum", []string{"max", "lte"}},
+	{"exclusiveMinimum", []string{"gt"}},
+	{"exclusiveMaximum", []string{"lt"}},
+}
+
+func (c *consistencyChecker) checkBounds(path string, prop map[string]interface{}, rules validateRules, pairs []boundPair) {
+	for _, pair := range pairs {
+		schemaValue, inSchema := prop[pair.keyword].(float64)
+
+		var rule string
+		var ruleValue float64
+		inRules := false
+		for _, name := range pair.rules {
+			if s, ok := rules.value(name); ok {
+				if n, err := strconv.ParseFloat(s, 64); err == nil {
+					rule, ruleValue, inRules = name, n, true
+					break
+				}
+			}
+		}
+
+		switch {
+		case inSchema && inRules && schemaValue != ruleValue:
+			c.report(path, "schema %s=%s differs from validate %s=%s",
+				pair.keyword, formatNumber(schemaValue), rule, formatNumber(ruleValue))
+		case inRules && !inSchema:
+			c.report(path, "validate %s=%s is not in the schema; add %s=%s",
+				rule, formatNumber(ruleValue), pair.keyword, formatNumber(ruleValue))
+		case inSchema && !inRules && !c.strict:
+			c.report(path, "schema %s=%s is not enforced; add validate %s=%s or use WithStrictSchema",
+				pair.keyword, formatNumber(schemaValue), pair.rules[0], formatNumber(schemaValue))
+		}
+	}
+}
+
+// validateRules holds the top-level rules of a validate tag. Rules after
+// dive apply to elements, and alternatives joined with | are ambiguous, so
+// both are left out.
+type validateRules map[string]string
+
+func parseValidateTag(tag string) validateRules {
+	rules := make(validateRules)
+	for _, part := range strings.Split(tag, ",") {
+		part = strings.TrimSpace(part)
+		if part == "dive" {
+			break
+		}
+		if part == "" || strings.Contains(part, "|") {
+			continue
+		}
+		name, value, _ := strings.Cut(part, "=")
+		rules[name] = value
+	}
+	return rules
+}
+
+func (r validateRules) has(name string) bool {
+	_, ok := r[name]
+	return ok
+}
+
+func (r validateRules) value(name string) (string, bool) {
+	v, ok := r[name]
+	return v, ok
+}
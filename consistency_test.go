@@ -0,0 +1,59 @@
+package mcpwrapper
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/server"
+)
+
+type DriftArgs struct {
+	Name     string      `json:"name" jsonschema:"required,minLength=3" validate:"required,min=2"`
+	Code     string      `json:"code" validate:"omitempty,len=6"`
+	Region   string      `json:"region" jsonschema:"required,enum=eu,enum=us" validate:"oneof=eu us ap"`
+	Size     string      `json:"size" validate:"omitempty,oneof=s m l"`
+	Replicas int         `json:"replicas" jsonschema:"minimum=1,maximum=10" validate:"gte=1"`
+	Tags     []string    `json:"tags" validate:"omitempty,dive,min=2"`
+	Target   DriftTarget `json:"target"`
+}
+
+type DriftTarget struct {
+	Port int    `json:"port" jsonschema:"exclusiveMaximum=65536" validate:"lt=65536"`
+	Zone string `json:"zone" jsonschema:"required"`
+}
+
+func TestCheckConsistency(t *testing.T) {
+	handler := func(ctx context.Context, args interface{}) (interface{}, error) { return "ok", nil }
+
+	wrapper := New(server.NewMCPServer("test", "1.0.0"))
+	wrapper.Register("drift", "Drifting tags", DriftArgs{}, handler)
+	wrapper.Register("clean", "Consistent tags", TestArgs{}, handler)
+
+	var got []string
+	for _, issue := range wrapper.CheckConsistency() {
+		got = append(got, issue.String())
+	}
+	want := []string{
+		"tool clean, field name: validate min=3 is not in the schema; add minLength=3",
+		"tool drift, field name: schema minLength=3 differs from validate min=2",
+		"tool drift, field code: validate len=6 is not in the schema; add minLength=6",
+		"tool drift, field code: validate len=6 is not in the schema; add maxLength=6",
+		`tool drift, field region: required in the schema but not validated; add validate:"required"`,
+		"tool drift, field region: schema enum [eu us] differs from validate oneof=eu us ap",
+		"tool drift, field size: validate oneof=s m l is not in the schema; add enum values",
+		"tool drift, field replicas: schema maximum=10 is not enforced; add validate max=10 or use WithStrictSchema",
+		`tool drift, field target.zone: required in the schema but not validated; add validate:"required"`,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Unexpected issues:\n got %q\nwant %q", got, want)
+	}
+
+	strict := New(server.NewMCPServer("test", "1.0.0"), WithStrictSchema())
+	strict.Register("drift", "Drifting tags", DriftArgs{}, handler)
+	for _, issue := range strict.CheckConsistency() {
+		if issue.Field == "replicas" {
+			t.Errorf("Expected schema-only bounds to pass with WithStrictSchema, got %s", issue)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package mcpwrapper
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+type contextKey int
+
+const (
+	toolNameKey contextKey = iota
+	callKey
+)
+
+// callState carries per-call data: the original request, and values that
+// middleware contributes to the final tool result.
+type callState struct {
+	wrapper *Wrapper
+	tool    *registeredTool
+	request mcp.CallToolRequest
+	args    interface{}
+
+	mu   sync.Mutex
+	meta map[string]interface{}
+	logs []string
+}
+
+func newCallContext(ctx context.Context, w *Wrapper, tool *registeredTool, request mcp.CallToolRequest) (context.Context, *callState) {
+	call := &callState{wrapper: w, tool: tool, request: request}
+	ctx = context.WithValue(ctx, toolNameKey, tool.name)
+	ctx = context.WithValue(ctx, callKey, call)
+	return ctx, call
+}
+
+func callFromContext(ctx context.Context) *callState {
+	call, _ := ctx.Value(callKey).(*callState)
+	return call
+}
+
+func ToolNameFromContext(ctx context.Context) string {
+	name, _ := ctx.Value(toolNameKey).(string)
+	return name
+}
+
+// ToolInfo describes the tool being called.
+type ToolInfo struct {
+	Name        string
+	Description string
+	ArgsType    reflect.Type
+}
+
+func ToolInfoFromContext(ctx context.Context) (ToolInfo, bool) {
+	call := callFromContext(ctx)
+	if call == nil {
+		return ToolInfo{}, false
+	}
+	return ToolInfo{
+		Name:        call.tool.name,
+		Description: call.tool.description,
+		ArgsType:    reflect.TypeOf(call.tool.argsType),
+	}, true
+}
+
+// ArgsAs converts the args a Handler or Middleware receives to the tool's
+// arguments struct. It accepts both *T and T and reports false for any other
+// type.
+func ArgsAs[T any](args interface{}) (*T, bool) {
+	switch a := args.(type) {
+	case *T:
+		return a, a != nil
+	case T:
+		return &a, true
+	}
+	return nil, false
+}
+
+// ArgsFromContext returns the bound and validated arguments of the current
+// call. It reports false before validation has completed or when the tool's
+// arguments are not a T.
+func ArgsFromContext[T any](ctx context.Context) (*T, bool) {
+	call := callFromContext(ctx)
+	if call == nil {
+		return nil, false
+	}
+	return ArgsAs[T](call.args)
+}
+
+// RequestFromContext returns the raw tools/call request as received from the
+// client, before config transforms are applied. The boolean is false outside
+// of a wrapped tool call.
+func RequestFromContext(ctx context.Context) (mcp.CallToolRequest, bool) {
+	call := callFromContext(ctx)
+	if call == nil {
+		return mcp.CallToolRequest{}, false
+	}
+	return call.request, true
+}
+
+// SessionIDFromContext returns the ID of the client session that made the
+// call, or "" when the transport has no sessions.
+func SessionIDFromContext(ctx context.Context) string {
+	if session := server.ClientSessionFromContext(ctx); session != nil {
+		return session.SessionID()
+	}
+	return ""
+}
+
+// ClientInfoFromContext returns the name and version the client reported
+// during initialization. The boolean is false if the session does not track
+// client info.
+func ClientInfoFromContext(ctx context.Context) (mcp.Implementation, bool) {
+	session, ok := server.ClientSessionFromContext(ctx).(server.SessionWithClientInfo)
+	if !ok {
+		return mcp.Implementation{}, false
+	}
+	return session.GetClientInfo(), true
+}
+
+// ProgressTokenFromContext returns the progress token the client attached to
+// the call, or nil if it did not request progress notifications.
+func ProgressTokenFromContext(ctx context.Context) mcp.ProgressToken {
+	call := callFromContext(ctx)
+	if call == nil || call.request.Params.Meta == nil {
+		return nil
+	}
+	return call.request.Params.Meta.ProgressToken
+}
+
+// HeadersFromContext returns the HTTP headers of the request that carried the
+// tool call, or nil for transports without headers (stdio).
+func HeadersFromContext(ctx context.Context) http.Header {
+	call := callFromContext(ctx)
+	if call == nil {
+		return nil
+	}
+	return call.request.Header
+}
+
+// SetResultMeta attaches a key to the _meta object of the tool result. It is a
+// no-op outside of a wrapped tool call.
+func SetResultMeta(ctx context.Context, key string, value interface{}) {
+	call := callFromContext(ctx)
+	if call == nil {
+		return
+	}
+	call.mu.Lock()
+	defer call.mu.Unlock()
+	if call.meta == nil {
+		call.meta = make(map[string]interface{})
+	}
+	call.meta[key] = value
+}
+
+func (c *callState) attachMeta(result *mcp.CallToolResult) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.meta) == 0 {
+		return
+	}
+	if result.Meta == nil {
+		result.Meta = &mcp.Meta{}
+	}
+	if result.Meta.AdditionalFields == nil {
+		result.Meta.AdditionalFields = make(map[string]any)
+	}
+	for k, v := range c.meta {
+		result.Meta.AdditionalFields[k] = v
+	}
+}
@@ -0,0 +1,60 @@
+package mcpwrapper
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func TestRequestMetadataFromContext(t *testing.T) {
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	wrapper := New(mcpServer)
+
+	var (
+		request       mcp.CallToolRequest
+		found         bool
+		sessionID     string
+		progressToken mcp.ProgressToken
+	)
+	handler := func(ctx context.Context, args interface{}) (interface{}, error) {
+		request, found = RequestFromContext(ctx)
+		sessionID = SessionIDFromContext(ctx)
+		progressToken = ProgressTokenFromContext(ctx)
+		return &TestResult{Message: "ok"}, nil
+	}
+
+	if err := wrapper.Register("test-tool", "Test tool", TestArgs{}, handler); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	ctx := mcpServer.WithContext(context.Background(), &testSession{id: "session-1"})
+	call := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name:      "test-tool",
+			Arguments: map[string]interface{}{"name": "Alice", "age": 30, "category": "A"},
+			Meta:      &mcp.Meta{ProgressToken: "progress-1"},
+		},
+	}
+
+	if _, err := mcpServer.GetTool("test-tool").Handler(ctx, call); err != nil {
+		t.Fatalf("Handler invocation failed: %v", err)
+	}
+
+	if !found || request.Params.Name != "test-tool" {
+		t.Errorf("Expected raw request in context, got %+v", request)
+	}
+
+	if sessionID != "session-1" {
+		t.Errorf("Expected session 'session-1', got '%s'", sessionID)
+	}
+
+	if progressToken != "progress-1" {
+		t.Errorf("Expected progress token 'progress-1', got '%v'", progressToken)
+	}
+
+	if _, ok := RequestFromContext(context.Background()); ok {
+		t.Error("Expected no request outside of a tool call")
+	}
+}
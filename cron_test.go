@@ -0,0 +1,46 @@
+package mcpwrapper
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCron(t *testing.T) {
+	from := time.Date(2025, time.January, 15, 10, 30, 0, 0, time.UTC) // a Wednesday
+	tests := []struct {
+		spec string
+		want time.Time
+	}{
+		{"0 2 * * *", time.Date(2025, time.January, 16, 2, 0, 0, 0, time.UTC)},
+		{"*/20 * * * *", time.Date(2025, time.January, 15, 10, 40, 0, 0, time.UTC)},
+		{"15,45 10-11 * * *", time.Date(2025, time.January, 15, 10, 45, 0, 0, time.UTC)},
+		{"0 9 * * mon-fri", time.Date(2025, time.January, 16, 9, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2025, time.January, 19, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1 mar *", time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 13 * fri", time.Date(2025, time.January, 17, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2028, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2025, time.January, 15, 11, 0, 0, 0, time.UTC)},
+		{"@monthly", time.Date(2025, time.February, 1, 0, 0, 0, 0, time.UTC)},
+		{"@every 90s", from.Add(90 * time.Second)},
+		{"0 0 30 2 *", time.Time{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			schedule, err := parseCron(tt.spec, time.UTC)
+			if err != nil {
+				t.Fatalf("parseCron failed: %v", err)
+			}
+			if got := schedule.next(from); !got.Equal(tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestParseCronErrors(t *testing.T) {
+	for _, spec := range []string{"", "* * * *", "60 * * * *", "* * 0 * *", "*/0 * * * *", "5-1 * * * *", "* * * foo *", "@every -1m", "@every soon"} {
+		if _, err := parseCron(spec, time.UTC); err == nil {
+			t.Errorf("Expected %q to be rejected", spec)
+		}
+	}
+}
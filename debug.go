@@ -0,0 +1,61 @@
+package mcpwrapper
+
+import (
+	"os"
+	"strconv"
+	"time"
+)
+
+// DebugEnv enables debug mode when set to a true value such as "1", without
+// a code change.
+const DebugEnv = "MCPWRAPPER_DEBUG"
+
+// WithDebug makes every tool result carry a "_debug" entry in its _meta:
+// the arguments as the handler received them, after input transformations,
+// coercion and binding (redact:"true" values masked), and how long binding,
+// validation, the handler and marshaling took. It helps diagnose why an
+// agent's call behaved unexpectedly. Setting MCPWRAPPER_DEBUG=1 has the
+// same effect.
+func WithDebug() Option {
+	return func(w *Wrapper) {
+		w.debug = true
+	}
+}
+
+func debugFromEnv() bool {
+	enabled, _ := strconv.ParseBool(os.Getenv(DebugEnv))
+	return enabled
+}
+
+type debugInfo struct {
+	Arguments interface{}       `json:"arguments,omitempty"`
+	Timings   map[string]string `json:"timings"`
+
+	last time.Time
+}
+
+// newDebug returns the debug record of a call, or nil when debug mode is
+// off. All debugInfo methods accept a nil receiver.
+func (w *Wrapper) newDebug() *debugInfo {
+	if !w.debug {
+		return nil
+	}
+	return &debugInfo{Timings: make(map[string]string), last: time.Now()}
+}
+
+// mark records the time since the previous mark as the duration of phase.
+func (d *debugInfo) mark(phase string) {
+	if d == nil {
+		return
+	}
+	now := time.Now()
+	d.Timings[phase] = now.Sub(d.last).String()
+	d.last = now
+}
+
+func (d *debugInfo) setArguments(args interface{}) {
+	if d == nil {
+		return
+	}
+	d.Arguments = Redact(args)
+}
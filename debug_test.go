@@ -0,0 +1,68 @@
+package mcpwrapper
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func TestWithDebug(t *testing.T) {
+	type LoginArgs struct {
+		User     string `json:"user"`
+		Password string `json:"password" redact:"true"`
+		Mode     int    `json:"mode" jsonschema:"enum=1,enum=2"`
+	}
+
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	wrapper := New(mcpServer, WithDebug())
+	wrapper.Register("login", "Log in", LoginArgs{}, func(ctx context.Context, args interface{}) (interface{}, error) {
+		return "ok", nil
+	})
+
+	result := callTool(t, mcpServer, "login", map[string]interface{}{"user": "alice", "password": "hunter2", "mode": "2"})
+	if result.IsError {
+		t.Fatalf("Call failed: %v", result.Content)
+	}
+	dbg, ok := result.Meta.AdditionalFields["_debug"].(*debugInfo)
+	if !ok {
+		t.Fatalf("Expected a _debug entry, got %v", result.Meta)
+	}
+	args := dbg.Arguments.(map[string]interface{})
+	if args["password"] != "[REDACTED]" || args["mode"] != 2 || args["user"] != "alice" {
+		t.Errorf("Unexpected debug arguments: %v", args)
+	}
+	for _, phase := range []string{"bind", "validate", "handle", "marshal"} {
+		if _, ok := dbg.Timings[phase]; !ok {
+			t.Errorf("Expected a %s timing, got %v", phase, dbg.Timings)
+		}
+	}
+
+	result = callTool(t, mcpServer, "login", map[string]interface{}{"user": "alice", "mode": 3})
+	dbg = result.Meta.AdditionalFields["_debug"].(*debugInfo)
+	if _, ok := dbg.Timings["handle"]; ok || dbg.Timings["validate"] == "" {
+		t.Errorf("Expected timings up to the failed validation, got %v", dbg.Timings)
+	}
+}
+
+func TestDebugFromEnv(t *testing.T) {
+	t.Setenv(DebugEnv, "1")
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	New(mcpServer).Register("greet", "Greet", TestArgs{}, func(ctx context.Context, args interface{}) (interface{}, error) {
+		return "ok", nil
+	})
+	result := callTool(t, mcpServer, "greet", map[string]interface{}{"name": "Alice", "age": 30, "category": "A"})
+	if result.Meta == nil || result.Meta.AdditionalFields["_debug"] == nil {
+		t.Error("Expected MCPWRAPPER_DEBUG to enable debug mode")
+	}
+
+	t.Setenv(DebugEnv, "")
+	mcpServer = server.NewMCPServer("test", "1.0.0")
+	New(mcpServer).Register("greet", "Greet", TestArgs{}, func(ctx context.Context, args interface{}) (interface{}, error) {
+		return "ok", nil
+	})
+	result = callTool(t, mcpServer, "greet", map[string]interface{}{"name": "Alice", "age": 30, "category": "A"})
+	if result.Meta != nil && result.Meta.AdditionalFields["_debug"] != nil {
+		t.Error("Expected no debug output by default")
+	}
+}
@@ -0,0 +1,277 @@
+package mcpwrapper
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Decoder parses the payload of a string argument whose field is tagged
+// decode:"<name>". v is a pointer to a new value of the field's type.
+type Decoder interface {
+	Decode(data string, v interface{}) error
+}
+
+// DecoderFunc adapts a function to a Decoder.
+type DecoderFunc func(data string, v interface{}) error
+
+func (f DecoderFunc) Decode(data string, v interface{}) error {
+	return f(data, v)
+}
+
+type registeredDecoder struct {
+	decoder Decoder
+	schema  map[string]interface{}
+}
+
+// decoders is global, like fieldDocs, because schemas are built from
+// struct tags alone.
+var (
+	decodersMu sync.RWMutex
+	decoders   = map[string]registeredDecoder{
+		"json":   {DecoderFunc(decodeJSON), map[string]interface{}{"contentMediaType": "application/json"}},
+		"yaml":   {DecoderFunc(decodeYAML), map[string]interface{}{"contentMediaType": "application/yaml"}},
+		"base64": {DecoderFunc(decodeBase64), map[string]interface{}{"contentEncoding": "base64"}},
+		"csv":    {DecoderFunc(decodeCSV), map[string]interface{}{"contentMediaType": "text/csv"}},
+	}
+)
+
+// RegisterDecoder makes d available to decode:"<name>" tags, replacing any
+// decoder of that name. schema holds keywords added to the argument's
+// string schema, such as contentMediaType; it may be nil. Register before
+// the tools that use it.
+func RegisterDecoder(name string, d Decoder, schema map[string]interface{}) {
+	decodersMu.Lock()
+	defer decodersMu.Unlock()
+	decoders[name] = registeredDecoder{decoder: d, schema: schema}
+}
+
+func lookupDecoder(name string) (registeredDecoder, bool) {
+	decodersMu.RLock()
+	defer decodersMu.RUnlock()
+	d, ok := decoders[name]
+	return d, ok
+}
+
+// decoderSchema returns the schema of a field tagged decode:"<name>".
+func decoderSchema(name string) (map[string]interface{}, error) {
+	d, ok := lookupDecoder(name)
+	if !ok {
+		return nil, fmt.Errorf("unknown decoder %q", name)
+	}
+	prop := map[string]interface{}{"type": "string"}
+	for k, v := range d.schema {
+		prop[k] = v
+	}
+	return prop, nil
+}
+
+func decodeJSON(data string, v interface{}) error {
+	return json.Unmarshal([]byte(data), v)
+}
+
+// decodeYAML goes through JSON so that the field's json tags apply.
+func decodeYAML(data string, v interface{}) error {
+	var raw interface{}
+	if err := yaml.Unmarshal([]byte(data), &raw); err != nil {
+		return err
+	}
+	encoded, err := json.Marshal(raw)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(encoded, v)
+}
+
+func decodeBase64(data string, v interface{}) error {
+	data = strings.TrimSpace(data)
+	decoded, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		if decoded, err = base64.RawStdEncoding.DecodeString(data); err != nil {
+			return err
+		}
+	}
+	switch p := v.(type) {
+	case *[]byte:
+		*p = decoded
+	case *string:
+		*p = string(decoded)
+	default:
+		return fmt.Errorf("base64 needs a string or []byte field, got %T", v)
+	}
+	return nil
+}
+
+// decodeCSV decodes into [][]string as is, and into anything else through
+// JSON, as a list of objects keyed by the header row.
+func decodeCSV(data string, v interface{}) error {
+	records, err := csv.NewReader(strings.NewReader(data)).ReadAll()
+	if err != nil {
+		return err
+	}
+	if p, ok := v.(*[][]string); ok {
+		*p = records
+		return nil
+	}
+	rows := make([]map[string]string, 0, len(records))
+	if len(records) > 0 {
+		header := records[0]
+		for _, record := range records[1:] {
+			row := make(map[string]string, len(header))
+			for i, name := range header {
+				if i < len(record) {
+					row[name] = record[i]
+				}
+			}
+			rows = append(rows, row)
+		}
+	}
+	encoded, err := json.Marshal(rows)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(encoded, v)
+}
+
+// decodedTypes caches whether an argsType has decode tags anywhere.
+var decodedTypes sync.Map // reflect.Type -> bool
+
+// decodeArguments replaces the payloads of fields tagged decode:"<name>"
+// with their decoded values, in JSON form, so that binding then fills the
+// fields as usual. args is copied before it is changed.
+func decodeArguments(argsType interface{}, args map[string]interface{}) (map[string]interface{}, error) {
+	t := derefType(reflect.TypeOf(argsType))
+	if t == nil || t.Kind() != reflect.Struct {
+		return args, nil
+	}
+	found, ok := decodedTypes.Load(t)
+	if !ok {
+		found = hasDecodeTags(t, map[reflect.Type]bool{})
+		decodedTypes.Store(t, found)
+	}
+	if !found.(bool) {
+		return args, nil
+	}
+	args = cloneMap(args)
+	if err := decodeFields(t, args, ""); err != nil {
+		return nil, err
+	}
+	return args, nil
+}
+
+func decodeFields(t reflect.Type, args map[string]interface{}, prefix string) error {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if embedded, ok := embeddedStruct(field); ok {
+			if err := decodeFields(embedded, args, prefix); err != nil {
+				return err
+			}
+			continue
+		}
+		name := jsonFieldName(field)
+		value, ok := args[name]
+		if name == "" || !field.IsExported() || !ok || value == nil {
+			continue
+		}
+		path := prefix + name
+
+		if decoder := field.Tag.Get("decode"); decoder != "" {
+			s, ok := value.(string)
+			if !ok {
+				return fmt.Errorf("%s: expected a %s string, got %T", path, decoder, value)
+			}
+			decoded, err := decodeValue(decoder, s, field.Type)
+			if err != nil {
+				return fmt.Errorf("%s: invalid %s: %w", path, decoder, err)
+			}
+			args[name] = decoded
+			continue
+		}
+
+		ft := derefType(field.Type)
+		switch {
+		case ft.Kind() == reflect.Struct:
+			if nested, ok := value.(map[string]interface{}); ok {
+				if err := decodeFields(ft, nested, path+"."); err != nil {
+					return err
+				}
+			}
+		case (ft.Kind() == reflect.Slice || ft.Kind() == reflect.Array) && derefType(ft.Elem()).Kind() == reflect.Struct:
+			items, ok := value.([]interface{})
+			if !ok {
+				continue
+			}
+			items = append([]interface{}(nil), items...)
+			for j, item := range items {
+				nested, ok := item.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				nested = cloneMap(nested)
+				if err := decodeFields(derefType(ft.Elem()), nested, fmt.Sprintf("%s[%d].", path, j)); err != nil {
+					return err
+				}
+				items[j] = nested
+			}
+			args[name] = items
+		}
+	}
+	return nil
+}
+
+func decodeValue(name, data string, t reflect.Type) (interface{}, error) {
+	d, ok := lookupDecoder(name)
+	if !ok {
+		return nil, fmt.Errorf("unknown decoder %q", name)
+	}
+	target := reflect.New(t)
+	if err := d.decoder.Decode(data, target.Interface()); err != nil {
+		return nil, err
+	}
+	encoded, err := json.Marshal(target.Elem().Interface())
+	if err != nil {
+		return nil, err
+	}
+	var value interface{}
+	decoder := json.NewDecoder(bytes.NewReader(encoded))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+func hasDecodeTags(t reflect.Type, seen map[reflect.Type]bool) bool {
+	if seen[t] {
+		return false
+	}
+	seen[t] = true
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Tag.Get("decode") != "" {
+			return true
+		}
+		ft := derefType(field.Type)
+		if ft.Kind() == reflect.Slice || ft.Kind() == reflect.Array {
+			ft = derefType(ft.Elem())
+		}
+		if ft.Kind() == reflect.Struct && hasDecodeTags(ft, seen) {
+			return true
+		}
+	}
+	return false
+}
+
+func derefType(t reflect.Type) reflect.Type {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
@@ -0,0 +1,115 @@
+package mcpwrapper
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/server"
+)
+
+type ImportManifest struct {
+	Name     string `json:"name"`
+	Replicas int    `json:"replicas"`
+}
+
+type ImportRow struct {
+	SKU      string `json:"sku"`
+	Quantity int    `json:"quantity,string"`
+}
+
+type ImportItem struct {
+	Labels map[string]string `json:"labels" decode:"json"`
+}
+
+type ImportArgs struct {
+	Manifest ImportManifest `json:"manifest" decode:"yaml" validate:"required"`
+	Key      []byte         `json:"key,omitempty" decode:"base64"`
+	Note     string         `json:"note,omitempty" decode:"base64"`
+	Rows     []ImportRow    `json:"rows,omitempty" decode:"csv"`
+	Table    [][]string     `json:"table,omitempty" decode:"csv"`
+	Items    []ImportItem   `json:"items,omitempty"`
+	Words    []string       `json:"words,omitempty" decode:"words"`
+}
+
+func TestDecodeArguments(t *testing.T) {
+	RegisterDecoder("words", DecoderFunc(func(data string, v interface{}) error {
+		*v.(*[]string) = strings.Fields(data)
+		return nil
+	}), map[string]interface{}{"description": "Space-separated words"})
+
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	var got *ImportArgs
+	err := New(mcpServer).Register("import", "Import data", ImportArgs{}, func(ctx context.Context, args interface{}) (interface{}, error) {
+		got = args.(*ImportArgs)
+		return &TestResult{Message: "ok"}, nil
+	})
+	if err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	props := mcpServer.GetTool("import").Tool.InputSchema.Properties
+	if p := props["manifest"].(map[string]interface{}); p["type"] != "string" || p["contentMediaType"] != "application/yaml" {
+		t.Errorf("Unexpected manifest schema: %v", p)
+	}
+	if p := props["key"].(map[string]interface{}); p["type"] != "string" || p["contentEncoding"] != "base64" {
+		t.Errorf("Unexpected key schema: %v", p)
+	}
+	if p := props["words"].(map[string]interface{}); p["description"] != "Space-separated words" {
+		t.Errorf("Unexpected words schema: %v", p)
+	}
+
+	args := map[string]interface{}{
+		"manifest": "name: api\nreplicas: 3\n",
+		"key":      "AAEC",
+		"note":     "aGVsbG8=",
+		"rows":     "sku,quantity\nA-1,2\nB-2,5\n",
+		"table":    "a,b\n1,2\n",
+		"items":    []interface{}{map[string]interface{}{"labels": `{"env":"prod"}`}},
+		"words":    "red green",
+	}
+	decodeResult(t, callTool(t, mcpServer, "import", args), &TestResult{})
+	if got.Manifest != (ImportManifest{Name: "api", Replicas: 3}) || !reflect.DeepEqual(got.Key, []byte{0, 1, 2}) || got.Note != "hello" {
+		t.Errorf("Unexpected decoded arguments: %+v", got)
+	}
+	if !reflect.DeepEqual(got.Rows, []ImportRow{{"A-1", 2}, {"B-2", 5}}) || !reflect.DeepEqual(got.Table, [][]string{{"a", "b"}, {"1", "2"}}) {
+		t.Errorf("Unexpected CSV arguments: %+v %+v", got.Rows, got.Table)
+	}
+	if got.Items[0].Labels["env"] != "prod" || !reflect.DeepEqual(got.Words, []string{"red", "green"}) {
+		t.Errorf("Unexpected nested arguments: %+v %+v", got.Items, got.Words)
+	}
+	if _, ok := args["manifest"].(string); !ok {
+		t.Error("Expected the request's arguments to be left alone")
+	}
+
+	tests := []struct {
+		name string
+		args map[string]interface{}
+		want string
+	}{
+		{"invalid yaml", map[string]interface{}{"manifest": "name: [api"}, "manifest: invalid yaml"},
+		{"not a string", map[string]interface{}{"manifest": map[string]interface{}{"name": "api"}}, "expected a yaml string"},
+		{"nested", map[string]interface{}{"manifest": "name: api", "items": []interface{}{map[string]interface{}{"labels": "{"}}}, "items[0].labels: invalid json"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := callTool(t, mcpServer, "import", tt.args)
+			if !result.IsError || !strings.Contains(resultText(result), tt.want) {
+				t.Errorf("Expected an error containing %q, got %v", tt.want, result.Content)
+			}
+		})
+	}
+}
+
+func TestDecodeUnknownDecoder(t *testing.T) {
+	type args struct {
+		Data string `json:"data" decode:"toml"`
+	}
+	err := New(server.NewMCPServer("test", "1.0.0")).Register("bad", "Bad", args{}, func(ctx context.Context, args interface{}) (interface{}, error) {
+		return nil, nil
+	})
+	if err == nil || !strings.Contains(err.Error(), `unknown decoder "toml"`) {
+		t.Errorf("Expected an unknown decoder error, got %v", err)
+	}
+}
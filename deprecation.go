@@ -0,0 +1,98 @@
+package mcpwrapper
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+type deprecation struct {
+	Reason      string `json:"reason,omitempty"`
+	Replacement string `json:"replacement,omitempty"`
+}
+
+// WithDeprecated marks a tool as deprecated. The description is prefixed
+// with a notice, _meta carries "deprecated": {reason, replacement}, and every
+// call logs a warning. replacement may be empty.
+func WithDeprecated(reason, replacement string) ToolOption {
+	return func(o *toolOptions) {
+		o.deprecated = &deprecation{Reason: reason, Replacement: replacement}
+		WithToolMeta("deprecated", *o.deprecated)(o)
+	}
+}
+
+func (d *deprecation) notice() string {
+	notice := "DEPRECATED"
+	if d.Reason != "" {
+		notice += ": " + d.Reason
+	}
+	if d.Replacement != "" {
+		notice += fmt.Sprintf(". Use %s instead", d.Replacement)
+	}
+	return "[" + notice + "]"
+}
+
+// Alias keeps a renamed tool working: oldName is listed as a deprecated tool
+// with newName's schema, and its calls are routed to newName's handler with
+// a warning log. newName must already be registered. The alias follows
+// UpdateSchema and UpdateDescription on newName.
+func (w *Wrapper) Alias(oldName, newName string) error {
+	w.updateMu.Lock()
+	defer w.updateMu.Unlock()
+
+	target, ok := w.lookupTool(newName)
+	if !ok {
+		return fmt.Errorf("cannot alias %s: tool %s is not registered", oldName, newName)
+	}
+	if _, exists := w.lookupTool(oldName); exists {
+		return fmt.Errorf("cannot alias %s: a tool with that name is registered", oldName)
+	}
+
+	w.toolsMu.Lock()
+	w.aliases[newName] = append(w.aliases[newName], oldName)
+	w.toolsMu.Unlock()
+	w.addAlias(oldName, target)
+	w.logger.Info("registered tool alias", "tool", oldName, "target", newName)
+	return nil
+}
+
+// addAlias lists oldName with the current definition of target. Calls look
+// target up by name, so they reach the tool as it is when they are made.
+func (w *Wrapper) addAlias(oldName string, target *registeredTool) {
+	newName := target.name
+	d := &deprecation{Reason: fmt.Sprintf("renamed to %s", newName), Replacement: newName}
+
+	tool := target.tool
+	tool.Name = oldName
+	tool.Description = d.notice() + " " + target.tool.Description
+	meta := map[string]interface{}{"deprecated": *d}
+	if target.tool.Meta != nil {
+		for k, v := range target.tool.Meta.AdditionalFields {
+			if k != "deprecated" {
+				meta[k] = v
+			}
+		}
+	}
+	tool.Meta = &mcp.Meta{AdditionalFields: meta}
+
+	w.server.AddTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		w.logger.Warn("deprecated tool alias called", "tool", oldName, "replacement", newName)
+		current, ok := w.lookupTool(newName)
+		if !ok {
+			return codedErrorResult(CodeNotFound, fmt.Sprintf("tool %s is not registered", newName)), nil
+		}
+		return w.createHandler(current)(ctx, request)
+	})
+}
+
+// warnDeprecatedArgs logs every argument of the call whose schema property is
+// marked deprecated.
+func (w *Wrapper) warnDeprecatedArgs(t *registeredTool, request mcp.CallToolRequest) {
+	for name := range request.GetArguments() {
+		prop, _ := t.tool.InputSchema.Properties[name].(map[string]interface{})
+		if deprecated, _ := prop["deprecated"].(bool); deprecated {
+			w.logger.Warn("deprecated argument used", "tool", t.name, "argument", name)
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package mcpwrapper
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func TestWithDeprecated(t *testing.T) {
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	var logs bytes.Buffer
+	wrapper := New(mcpServer, WithLogger(slog.New(slog.NewTextHandler(&logs, nil))))
+
+	handler := func(ctx context.Context, args interface{}) (interface{}, error) {
+		return &TestResult{Message: "ok"}, nil
+	}
+
+	err := wrapper.Register("get_user_v1", "Fetch a user", TestArgs{}, handler,
+		WithDeprecated("superseded by the v2 API", "get_user"))
+	if err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	tool := mcpServer.GetTool("get_user_v1").Tool
+	expected := "[DEPRECATED: superseded by the v2 API. Use get_user instead] Fetch a user"
+	if tool.Description != expected {
+		t.Errorf("Expected description %q, got %q", expected, tool.Description)
+	}
+	d := tool.Meta.AdditionalFields["deprecated"].(deprecation)
+	if d.Replacement != "get_user" {
+		t.Errorf("Unexpected deprecated meta: %+v", d)
+	}
+
+	request := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name:      "get_user_v1",
+			Arguments: map[string]interface{}{"name": "Alice", "age": 30, "category": "A"},
+		},
+	}
+	if result, err := mcpServer.GetTool("get_user_v1").Handler(context.Background(), request); err != nil || result.IsError {
+		t.Fatalf("Handler invocation failed: %v %v", err, result)
+	}
+	if !strings.Contains(logs.String(), "deprecated tool called") {
+		t.Errorf("Expected a deprecation warning, got %q", logs.String())
+	}
+}
+
+func TestAlias(t *testing.T) {
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	var logs bytes.Buffer
+	wrapper := New(mcpServer, WithLogger(slog.New(slog.NewTextHandler(&logs, nil))))
+
+	var calledAs string
+	handler := func(ctx context.Context, args interface{}) (interface{}, error) {
+		calledAs = ToolNameFromContext(ctx)
+		return &TestResult{Message: "hello " + args.(*TestArgs).Name}, nil
+	}
+
+	if err := wrapper.Register("greet_user", "Greet a user", TestArgs{}, handler, WithReadOnly()); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	if err := wrapper.Alias("greet", "greet_user"); err != nil {
+		t.Fatalf("Alias failed: %v", err)
+	}
+
+	alias := mcpServer.GetTool("greet")
+	if alias == nil {
+		t.Fatal("Expected alias to be listed")
+	}
+	if !strings.HasPrefix(alias.Tool.Description, "[DEPRECATED: renamed to greet_user. Use greet_user instead]") {
+		t.Errorf("Unexpected alias description: %q", alias.Tool.Description)
+	}
+	if alias.Tool.Annotations.ReadOnlyHint == nil || !*alias.Tool.Annotations.ReadOnlyHint {
+		t.Error("Expected alias to keep the target's annotations")
+	}
+	if _, ok := alias.Tool.InputSchema.Properties["category"]; !ok {
+		t.Error("Expected alias to keep the target's schema")
+	}
+
+	request := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name:      "greet",
+			Arguments: map[string]interface{}{"name": "Alice", "age": 30, "category": "A"},
+		},
+	}
+	result, err := alias.Handler(context.Background(), request)
+	if err != nil || result.IsError {
+		t.Fatalf("Handler invocation failed: %v %v", err, result)
+	}
+	if calledAs != "greet_user" {
+		t.Errorf("Expected call routed to greet_user, got %s", calledAs)
+	}
+	if !strings.Contains(logs.String(), "deprecated tool alias called") {
+		t.Errorf("Expected an alias warning, got %q", logs.String())
+	}
+
+	if err := wrapper.Alias("old", "missing"); err == nil {
+		t.Error("Expected an error when aliasing an unregistered tool")
+	}
+	if err := wrapper.Alias("greet_user", "greet_user"); err == nil {
+		t.Error("Expected an error when the alias name is taken")
+	}
+}
+
+func TestAliasDescription(t *testing.T) {
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	wrapper := New(mcpServer, WithDescriptionData(map[string]interface{}{"region": "eu-west-1"}))
+	handler := func(ctx context.Context, args interface{}) (interface{}, error) {
+		return &TestResult{Message: "ok"}, nil
+	}
+	err := wrapper.Register("deploy_service", "Deploy to {{.region}}", TestArgs{}, handler,
+		WithExamples(TestArgs{Name: "Alice", Age: 30, Category: "A"}))
+	if err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	if err := wrapper.Alias("deploy", "deploy_service"); err != nil {
+		t.Fatalf("Alias failed: %v", err)
+	}
+
+	description := mcpServer.GetTool("deploy").Tool.Description
+	if !strings.Contains(description, "Deploy to eu-west-1") || strings.Contains(description, "{{") ||
+		!strings.Contains(description, "Examples:") {
+		t.Errorf("Expected the alias to list the rendered description, got %q", description)
+	}
+
+	if err := wrapper.UpdateDescription("deploy_service", "Roll out to {{.region}}"); err != nil {
+		t.Fatalf("UpdateDescription failed: %v", err)
+	}
+	description = mcpServer.GetTool("deploy").Tool.Description
+	if !strings.HasPrefix(description, "[DEPRECATED") || !strings.Contains(description, "Roll out to eu-west-1") {
+		t.Errorf("Expected the alias to list the updated description, got %q", description)
+	}
+}
+
+func TestDeprecatedArgument(t *testing.T) {
+	type SearchArgs struct {
+		Query string `json:"query" jsonschema:"title=Search query"`
+		Limit int    `json:"limit" jsonschema:"title=Result limit,deprecated,description=Use page_size instead"`
+	}
+
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	var logs bytes.Buffer
+	wrapper := New(mcpServer, WithLogger(slog.New(slog.NewTextHandler(&logs, nil))))
+	handler := func(ctx context.Context, args interface{}) (interface{}, error) {
+		return "ok", nil
+	}
+	if err := wrapper.Register("search", "Search", SearchArgs{}, handler); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	props := mcpServer.GetTool("search").Tool.InputSchema.Properties
+	limit := props["limit"].(map[string]interface{})
+	if limit["title"] != "Result limit" || limit["deprecated"] != true {
+		t.Errorf("Unexpected limit schema: %v", limit)
+	}
+	if query := props["query"].(map[string]interface{}); query["title"] != "Search query" || query["deprecated"] != nil {
+		t.Errorf("Unexpected query schema: %v", query)
+	}
+
+	callTool(t, mcpServer, "search", map[string]interface{}{"query": "go"})
+	if strings.Contains(logs.String(), "deprecated argument used") {
+		t.Errorf("Expected no warning without deprecated arguments, got %q", logs.String())
+	}
+	callTool(t, mcpServer, "search", map[string]interface{}{"query": "go", "limit": 5})
+	if !strings.Contains(logs.String(), "argument=limit") {
+		t.Errorf("Expected a deprecated argument warning, got %q", logs.String())
+	}
+
+	var out bytes.Buffer
+	if err := wrapper.GenerateDocs(&out, DocsMarkdown); err != nil {
+		t.Fatalf("GenerateDocs failed: %v", err)
+	}
+	if !strings.Contains(out.String(), "**Deprecated.** Use page_size instead") || !strings.Contains(out.String(), "Search query") {
+		t.Errorf("Unexpected docs:\n%s", out.String())
+	}
+}
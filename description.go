@@ -0,0 +1,110 @@
+package mcpwrapper
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// WithDescriptionData renders tool and field descriptions as Go templates
+// against data, so that they can name runtime configuration such as the
+// regions a tool accepts. The environment from WithEnvironment is
+// available as .Environment unless data sets it. Only descriptions holding
+// "{{" are rendered; a key missing from data fails the registration.
+func WithDescriptionData(data map[string]interface{}) Option {
+	return func(w *Wrapper) {
+		w.descriptionData = data
+	}
+}
+
+// renderDescription renders one description, named for errors by where it
+// comes from.
+func (w *Wrapper) renderDescription(name, text string) (string, error) {
+	if w.descriptionData == nil || !strings.Contains(text, "{{") {
+		return text, nil
+	}
+	tmpl, err := parseTemplate(name, text)
+	if err != nil {
+		return "", err
+	}
+	data := make(map[string]interface{}, len(w.descriptionData)+1)
+	data["Environment"] = w.environment
+	for k, v := range w.descriptionData {
+		data[k] = v
+	}
+	return renderTemplate(tmpl.Option("missingkey=error"), data)
+}
+
+// renderToolDescriptions renders the description of tool and those of its
+// input and output schemas. Schemas may be shared between tools, so they
+// are copied before they are changed.
+func (w *Wrapper) renderToolDescriptions(tool *mcp.Tool) error {
+	if w.descriptionData == nil {
+		return nil
+	}
+	description, err := w.renderDescription("description of "+tool.Name, tool.Description)
+	if err != nil {
+		return err
+	}
+	tool.Description = description
+
+	render := func(schema map[string]any, path string) (map[string]any, error) {
+		if schema == nil {
+			return nil, nil
+		}
+		rendered, err := w.renderSchemaDescriptions(schema, path)
+		if err != nil {
+			return nil, err
+		}
+		return rendered.(map[string]any), nil
+	}
+	if tool.InputSchema.Properties, err = render(tool.InputSchema.Properties, "input"); err != nil {
+		return err
+	}
+	if tool.InputSchema.Defs, err = render(tool.InputSchema.Defs, "input $defs"); err != nil {
+		return err
+	}
+	if tool.OutputSchema.Properties, err = render(tool.OutputSchema.Properties, "output"); err != nil {
+		return err
+	}
+	tool.OutputSchema.Defs, err = render(tool.OutputSchema.Defs, "output $defs")
+	return err
+}
+
+// renderSchemaDescriptions returns a copy of v with its description
+// keywords rendered.
+func (w *Wrapper) renderSchemaDescriptions(v interface{}, path string) (interface{}, error) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for k, item := range v {
+			if s, ok := item.(string); ok && k == "description" {
+				rendered, err := w.renderDescription("description of "+path, s)
+				if err != nil {
+					return nil, err
+				}
+				out[k] = rendered
+				continue
+			}
+			rendered, err := w.renderSchemaDescriptions(item, strings.TrimPrefix(path+"."+k, "."))
+			if err != nil {
+				return nil, err
+			}
+			out[k] = rendered
+		}
+		return out, nil
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			rendered, err := w.renderSchemaDescriptions(item, fmt.Sprintf("%s[%d]", path, i))
+			if err != nil {
+				return nil, err
+			}
+			out[i] = rendered
+		}
+		return out, nil
+	default:
+		return v, nil
+	}
+}
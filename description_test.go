@@ -0,0 +1,75 @@
+package mcpwrapper
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/server"
+)
+
+type RolloutArgs struct {
+	Region  string          `json:"region" jsonschema:"description=One of {{.Regions}}"`
+	Targets []RolloutTarget `json:"targets,omitempty"`
+}
+
+type RolloutTarget struct {
+	Host string `json:"host" jsonschema:"description=Host in {{.Environment}}"`
+}
+
+func TestDescriptionTemplates(t *testing.T) {
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	wrapper := New(mcpServer, WithEnvironment("staging"), WithDescriptionData(map[string]interface{}{
+		"Regions": []string{"eu-west-1", "us-east-1"},
+	}))
+	handler := func(ctx context.Context, args interface{}) (interface{}, error) { return nil, nil }
+	if err := wrapper.Register("deploy", "Deploy to {{.Environment}}", RolloutArgs{}, handler); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	tool := mcpServer.GetTool("deploy").Tool
+	if tool.Description != "Deploy to staging" {
+		t.Errorf("Unexpected tool description: %q", tool.Description)
+	}
+	region := tool.InputSchema.Properties["region"].(map[string]interface{})
+	if region["description"] != "One of [eu-west-1 us-east-1]" {
+		t.Errorf("Unexpected field description: %v", region["description"])
+	}
+	if len(tool.InputSchema.Defs) == 0 {
+		t.Fatal("Expected RolloutTarget in $defs")
+	}
+	for _, def := range tool.InputSchema.Defs {
+		host := def.(map[string]interface{})["properties"].(map[string]interface{})["host"].(map[string]interface{})
+		if host["description"] != "Host in staging" {
+			t.Errorf("Unexpected nested field description: %v", host["description"])
+		}
+	}
+
+	// The cached schema is shared, so it must keep the template.
+	schema, err := wrapper.schema(RolloutArgs{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := schema.Properties["region"].(map[string]interface{})["description"]; d != "One of {{.Regions}}" {
+		t.Errorf("Expected the cached schema to be unchanged, got %v", d)
+	}
+
+	err = wrapper.Register("typo", "Deploy to {{.Enviroment}}", RolloutArgs{}, handler)
+	if err == nil || !strings.Contains(err.Error(), "invalid description for tool typo") {
+		t.Errorf("Expected a missing key to fail the registration, got %v", err)
+	}
+}
+
+func TestDescriptionTemplatesDisabled(t *testing.T) {
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	wrapper := New(mcpServer)
+	err := wrapper.Register("deploy", "Deploy to {{.Environment}}", RolloutArgs{}, func(ctx context.Context, args interface{}) (interface{}, error) {
+		return nil, nil
+	})
+	if err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	if d := mcpServer.GetTool("deploy").Tool.Description; d != "Deploy to {{.Environment}}" {
+		t.Errorf("Expected descriptions to stay as written without data, got %q", d)
+	}
+}
@@ -0,0 +1,320 @@
+package mcpwrapper
+
+import (
+	"encoding/json"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// DocFormat selects the output of GenerateDocs.
+type DocFormat int
+
+const (
+	DocsMarkdown DocFormat = iota
+	DocsHTML
+)
+
+type docTool struct {
+	Name        string
+	Description string
+	Hints       []string
+	Args        []docArg
+	Examples    []string
+	Output      string
+}
+
+type docArg struct {
+	Name        string
+	Type        string
+	Required    bool
+	Description string
+	Deprecated  bool
+	Constraints string
+	Default     string
+}
+
+// GenerateDocs writes a reference page for the listed tools: description,
+// behaviour hints, an argument table (name, type, required, constraints,
+// default), the examples given with WithExamples, or else one built from the
+// fields' example= values, or else the minimal call ExampleCall would build,
+// and the output schema. Tools are sorted by name.
+func (w *Wrapper) GenerateDocs(out io.Writer, format DocFormat) error {
+	tools := w.listedTools()
+	docs := make([]docTool, 0, len(tools))
+	for _, tool := range tools {
+		docs = append(docs, newDocTool(tool))
+	}
+
+	switch format {
+	case DocsMarkdown:
+		return markdownDocs.Execute(out, docs)
+	case DocsHTML:
+		return htmlDocs.Execute(out, docs)
+	default:
+		return fmt.Errorf("unknown doc format %d", format)
+	}
+}
+
+func newDocTool(tool mcp.Tool) docTool {
+	doc := docTool{
+		Name:        tool.Name,
+		Description: tool.Description,
+		Hints:       toolHints(tool.Annotations),
+	}
+
+	names := make([]string, 0, len(tool.InputSchema.Properties))
+	for name := range tool.InputSchema.Properties {
+		names = append(names, name)
+	}
+	// Required arguments first, then alphabetical.
+	sort.Slice(names, func(i, j int) bool {
+		ri, rj := contains(tool.InputSchema.Required, names[i]), contains(tool.InputSchema.Required, names[j])
+		if ri != rj {
+			return ri
+		}
+		return names[i] < names[j]
+	})
+
+	example := make(map[string]interface{})
+	for _, name := range names {
+		prop, _ := tool.InputSchema.Properties[name].(map[string]interface{})
+		arg := docArg{
+			Name:        name,
+			Type:        schemaTypeName(prop),
+			Required:    contains(tool.InputSchema.Required, name),
+			Constraints: schemaConstraints(prop),
+		}
+		arg.Description, _ = prop["description"].(string)
+		if arg.Description == "" {
+			arg.Description, _ = prop["title"].(string)
+		}
+		arg.Deprecated, _ = prop["deprecated"].(bool)
+		if def, ok := prop["default"]; ok {
+			arg.Default = docValue(def)
+		}
+		if examples := listValues(prop["examples"]); len(examples) > 0 {
+			example[name] = examples[0]
+		}
+		doc.Args = append(doc.Args, arg)
+	}
+
+	if examples, ok := toolExamples(tool); ok {
+		for _, example := range examples {
+			data, _ := json.MarshalIndent(example, "", "  ")
+			doc.Examples = append(doc.Examples, string(data))
+		}
+	} else if len(example) > 0 {
+		data, _ := json.MarshalIndent(example, "", "  ")
+		doc.Examples = []string{string(data)}
+	} else if example, err := exampleArguments(tool.InputSchema); err == nil && len(example) > 0 {
+		data, _ := json.MarshalIndent(example, "", "  ")
+		doc.Examples = []string{string(data)}
+	}
+	if tool.OutputSchema.Type != "" {
+		data, _ := json.MarshalIndent(tool.OutputSchema, "", "  ")
+		doc.Output = string(data)
+	}
+	return doc
+}
+
+// toolExamples returns the examples attached with WithExamples.
+func toolExamples(tool mcp.Tool) ([]map[string]interface{}, bool) {
+	if tool.Meta == nil {
+		return nil, false
+	}
+	examples, ok := tool.Meta.AdditionalFields["examples"].([]map[string]interface{})
+	return examples, ok
+}
+
+func toolHints(a mcp.ToolAnnotation) []string {
+	var hints []string
+	if a.ReadOnlyHint != nil && *a.ReadOnlyHint {
+		hints = append(hints, "read-only")
+	} else if a.DestructiveHint != nil && *a.DestructiveHint {
+		hints = append(hints, "destructive")
+	}
+	if a.IdempotentHint != nil && *a.IdempotentHint {
+		hints = append(hints, "idempotent")
+	}
+	if a.OpenWorldHint != nil && *a.OpenWorldHint {
+		hints = append(hints, "open world")
+	}
+	return hints
+}
+
+func schemaTypeName(prop map[string]interface{}) string {
+	if ref, ok := prop["$ref"].(string); ok {
+		if ref == "#" {
+			return "object"
+		}
+		return "object (" + strings.TrimPrefix(ref, "#/$defs/") + ")"
+	}
+	typ, _ := prop["type"].(string)
+	if typ == "array" {
+		if items, ok := prop["items"].(map[string]interface{}); ok {
+			if itemType := schemaTypeName(items); itemType != "" {
+				return "array of " + itemType
+			}
+		}
+	}
+	if format, ok := prop["format"].(string); ok && typ != "" {
+		return fmt.Sprintf("%s (%s)", typ, format)
+	}
+	return typ
+}
+
+// schemaConstraints summarises the validation keywords of a property.
+func schemaConstraints(prop map[string]interface{}) string {
+	var parts []string
+	for _, c := range []struct{ keyword, label string }{
+		{"minimum", "minimum"},
+		{"maximum", "maximum"},
+		{"exclusiveMinimum", "greater than"},
+		{"exclusiveMaximum", "less than"},
+		{"multipleOf", "multiple of"},
+		{"minLength", "min length"},
+		{"maxLength", "max length"},
+		{"minItems", "min items"},
+		{"maxItems", "max items"},
+		{"pattern", "pattern"},
+		{"format", "format"},
+		{"const", "always"},
+	} {
+		if v, ok := prop[c.keyword]; ok {
+			parts = append(parts, fmt.Sprintf("%s %s", c.label, docValue(v)))
+		}
+	}
+	if unique, _ := prop["uniqueItems"].(bool); unique {
+		parts = append(parts, "unique items")
+	}
+	if enum := listValues(prop["enum"]); len(enum) > 0 {
+		values := make([]string, len(enum))
+		for i, v := range enum {
+			values[i] = docValue(v)
+		}
+		parts = append(parts, "one of: "+strings.Join(values, ", "))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// listValues accepts the []string and []interface{} forms schema keywords
+// take in struct-built and JSON-parsed schemas.
+func listValues(v interface{}) []interface{} {
+	switch values := v.(type) {
+	case []interface{}:
+		return values
+	case []string:
+		out := make([]interface{}, len(values))
+		for i, s := range values {
+			out[i] = s
+		}
+		return out
+	}
+	return nil
+}
+
+func docValue(v interface{}) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
+
+func markdownCell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.ReplaceAll(s, "\n", "<br>")
+}
+
+var markdownDocs = template.Must(template.New("markdown").Funcs(template.FuncMap{"cell": markdownCell}).Parse(
+	`# Tools
+{{range .}}
+## {{.Name}}
+
+{{.Description}}
+{{- if .Hints}}
+
+_{{range $i, $h := .Hints}}{{if $i}}, {{end}}{{$h}}{{end}}_
+{{- end}}
+{{- if .Args}}
+
+| Argument | Type | Required | Description | Constraints | Default |
+|----------|------|----------|-------------|-------------|---------|
+{{- range .Args}}
+| ` + "`{{.Name}}`" + ` | {{cell .Type}} | {{if .Required}}yes{{else}}no{{end}} | {{if .Deprecated}}**Deprecated.** {{end}}{{cell .Description}} | {{cell .Constraints}} | {{cell .Default}} |
+{{- end}}
+{{- else}}
+
+No arguments.
+{{- end}}
+{{- if .Examples}}
+
+Example arguments:
+{{- range .Examples}}
+
+` + "```json" + `
+{{.}}
+` + "```" + `
+{{- end}}
+{{- end}}
+{{- if .Output}}
+
+Output schema:
+
+` + "```json" + `
+{{.Output}}
+` + "```" + `
+{{- end}}
+{{end}}`))
+
+var htmlDocs = htmltemplate.Must(htmltemplate.New("html").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Tools</title>
+</head>
+<body>
+<h1>Tools</h1>
+{{- range .}}
+<section id="{{.Name}}">
+<h2>{{.Name}}</h2>
+<p>{{.Description}}</p>
+{{- if .Hints}}
+<p><em>{{range $i, $h := .Hints}}{{if $i}}, {{end}}{{$h}}{{end}}</em></p>
+{{- end}}
+{{- if .Args}}
+<table>
+<thead><tr><th>Argument</th><th>Type</th><th>Required</th><th>Description</th><th>Constraints</th><th>Default</th></tr></thead>
+<tbody>
+{{- range .Args}}
+<tr><td><code>{{.Name}}</code></td><td>{{.Type}}</td><td>{{if .Required}}yes{{else}}no{{end}}</td><td>{{if .Deprecated}}<strong>Deprecated.</strong> {{end}}{{.Description}}</td><td>{{.Constraints}}</td><td>{{.Default}}</td></tr>
+{{- end}}
+</tbody>
+</table>
+{{- else}}
+<p>No arguments.</p>
+{{- end}}
+{{- if .Examples}}
+<p>Example arguments:</p>
+{{- range .Examples}}
+<pre><code>{{.}}</code></pre>
+{{- end}}
+{{- end}}
+{{- if .Output}}
+<p>Output schema:</p>
+<pre><code>{{.Output}}</code></pre>
+{{- end}}
+</section>
+{{- end}}
+</body>
+</html>
+`))
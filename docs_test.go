@@ -0,0 +1,83 @@
+package mcpwrapper
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/server"
+)
+
+type ScaleArgs struct {
+	Service  string `json:"service" jsonschema:"required,description=Service name|alias,example=web"`
+	Replicas int    `json:"replicas" jsonschema:"minimum=1,maximum=10,example=3"`
+	Tier     string `json:"tier" jsonschema:"enum=free,enum=pro"`
+}
+
+func docsWrapper(t *testing.T) *Wrapper {
+	t.Helper()
+	wrapper := New(server.NewMCPServer("test", "1.0.0"))
+	handler := func(ctx context.Context, args interface{}) (interface{}, error) {
+		return &TestResult{Message: "ok"}, nil
+	}
+	output := map[string]interface{}{
+		"type":       "object",
+		"properties": map[string]interface{}{"message": map[string]interface{}{"type": "string"}},
+	}
+	if err := wrapper.Register("scale", "Scale a service <fast>", ScaleArgs{}, handler, WithOutputSchema(output)); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	err := wrapper.RegisterSchema("ping", "Check the server", map[string]interface{}{"type": "object"}, handler, WithReadOnly())
+	if err != nil {
+		t.Fatalf("RegisterSchema failed: %v", err)
+	}
+	return wrapper
+}
+
+func TestGenerateDocsMarkdown(t *testing.T) {
+	var buf bytes.Buffer
+	if err := docsWrapper(t).GenerateDocs(&buf, DocsMarkdown); err != nil {
+		t.Fatalf("GenerateDocs failed: %v", err)
+	}
+	doc := buf.String()
+
+	for _, want := range []string{
+		"## ping\n\nCheck the server\n\n_read-only, open world_\n\nNo arguments.",
+		"## scale\n\nScale a service <fast>",
+		"| `service` | string | yes | Service name\\|alias (e.g. web) |  |  |",
+		"| `replicas` | integer | no | e.g. 3 | minimum 1; maximum 10 |  |",
+		"| `tier` | string | no |  | one of: free, pro |  |",
+		"Example arguments:\n\n```json\n{\n  \"replicas\": 3,\n  \"service\": \"web\"\n}\n```",
+		"Output schema:\n\n```json\n{\n  \"type\": \"object\"",
+	} {
+		if !strings.Contains(doc, want) {
+			t.Errorf("Expected docs to contain %q, got:\n%s", want, doc)
+		}
+	}
+	if strings.Index(doc, "## ping") > strings.Index(doc, "## scale") {
+		t.Error("Expected tools sorted by name")
+	}
+}
+
+func TestGenerateDocsHTML(t *testing.T) {
+	var buf bytes.Buffer
+	if err := docsWrapper(t).GenerateDocs(&buf, DocsHTML); err != nil {
+		t.Fatalf("GenerateDocs failed: %v", err)
+	}
+	doc := buf.String()
+
+	for _, want := range []string{
+		`<section id="scale">`,
+		"<p>Scale a service &lt;fast&gt;</p>",
+		"<tr><td><code>replicas</code></td><td>integer</td><td>no</td><td>e.g. 3</td><td>minimum 1; maximum 10</td><td></td></tr>",
+	} {
+		if !strings.Contains(doc, want) {
+			t.Errorf("Expected docs to contain %q, got:\n%s", want, doc)
+		}
+	}
+
+	if err := docsWrapper(t).GenerateDocs(&buf, DocFormat(42)); err == nil {
+		t.Error("Expected an error for an unknown format")
+	}
+}
@@ -0,0 +1,84 @@
+package mcpwrapper
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+type ElicitResult struct {
+	Action mcp.ElicitationResponseAction
+	// Value is a pointer to a new value of the schema type, filled with the
+	// user's validated answer. It is nil unless the user accepted.
+	Value interface{}
+}
+
+func (r *ElicitResult) Accepted() bool {
+	return r.Action == mcp.ElicitationResponseActionAccept
+}
+
+type confirmation struct {
+	Confirm bool `json:"confirm" jsonschema:"description=Confirm to proceed"`
+}
+
+// Elicit asks the user of the calling client for input. schema is a struct
+// value whose tags define the requested schema, like a tool's argsType. The
+// server must be created with server.WithElicitation() and the client must
+// support elicitation.
+func Elicit(ctx context.Context, schema interface{}, message string) (*ElicitResult, error) {
+	call := callFromContext(ctx)
+	if call == nil {
+		return nil, fmt.Errorf("elicit called outside of a tool call")
+	}
+
+	requested, err := call.wrapper.registry.schema(schema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build elicitation schema: %w", err)
+	}
+
+	response, err := call.wrapper.server.RequestElicitation(ctx, mcp.ElicitationRequest{
+		Request: mcp.Request{Method: string(mcp.MethodElicitationCreate)},
+		Params:  mcp.ElicitationParams{Message: message, RequestedSchema: requested},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("elicitation failed: %w", err)
+	}
+
+	result := &ElicitResult{Action: response.Action}
+	if !result.Accepted() {
+		return result, nil
+	}
+
+	t := reflect.TypeOf(schema)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	value := reflect.New(t).Interface()
+
+	data, err := json.Marshal(response.Content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read elicitation response: %w", err)
+	}
+	if err := json.Unmarshal(data, value); err != nil {
+		return nil, fmt.Errorf("failed to bind elicitation response: %w", err)
+	}
+	if err := call.wrapper.validate(value); err != nil {
+		return nil, err
+	}
+
+	result.Value = value
+	return result, nil
+}
+
+// Confirm asks the user a yes/no question before a destructive action. It
+// reports true only when the user accepted and ticked the confirmation.
+func Confirm(ctx context.Context, message string) (bool, error) {
+	result, err := Elicit(ctx, confirmation{}, message)
+	if err != nil || !result.Accepted() {
+		return false, err
+	}
+	return result.Value.(*confirmation).Confirm, nil
+}
@@ -0,0 +1,120 @@
+package mcpwrapper
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+type fakeElicitor struct {
+	request  mcp.ElicitationRequest
+	response mcp.ElicitationResponse
+}
+
+func (e *fakeElicitor) Elicit(ctx context.Context, request mcp.ElicitationRequest) (*mcp.ElicitationResult, error) {
+	e.request = request
+	return &mcp.ElicitationResult{ElicitationResponse: e.response}, nil
+}
+
+type ReasonInput struct {
+	Reason string `json:"reason" validate:"required,min=3" jsonschema:"description=Why the record is deleted"`
+}
+
+func elicitCall(t *testing.T, elicitor *fakeElicitor, handler Handler) *mcp.CallToolResult {
+	t.Helper()
+
+	mcpServer := server.NewMCPServer("test", "1.0.0", server.WithElicitation())
+	wrapper := New(mcpServer)
+
+	if err := wrapper.Register("delete", "Delete tool", TestArgs{}, handler); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	session := server.NewInProcessSessionWithHandlers("session-1", nil, elicitor, nil)
+	ctx := mcpServer.WithContext(context.Background(), session)
+	request := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name:      "delete",
+			Arguments: map[string]interface{}{"name": "Alice", "age": 30, "category": "A"},
+		},
+	}
+
+	result, err := mcpServer.GetTool("delete").Handler(ctx, request)
+	if err != nil {
+		t.Fatalf("Handler invocation failed: %v", err)
+	}
+	return result
+}
+
+func TestElicit(t *testing.T) {
+	elicitor := &fakeElicitor{response: mcp.ElicitationResponse{
+		Action:  mcp.ElicitationResponseActionAccept,
+		Content: map[string]interface{}{"reason": "duplicate"},
+	}}
+
+	var elicited *ElicitResult
+	result := elicitCall(t, elicitor, func(ctx context.Context, args interface{}) (interface{}, error) {
+		var err error
+		elicited, err = Elicit(ctx, ReasonInput{}, "Why?")
+		return &TestResult{Message: "ok"}, err
+	})
+	if result.IsError {
+		t.Fatalf("Unexpected error result: %v", result.Content)
+	}
+
+	if !elicited.Accepted() || elicited.Value.(*ReasonInput).Reason != "duplicate" {
+		t.Errorf("Unexpected elicitation result: %+v", elicited)
+	}
+
+	schema := elicitor.request.Params.RequestedSchema.(*mcp.ToolInputSchema)
+	if elicitor.request.Params.Message != "Why?" || !contains(schema.Required, "reason") {
+		t.Errorf("Unexpected elicitation request: %+v", elicitor.request)
+	}
+}
+
+func TestElicitValidatesAnswer(t *testing.T) {
+	elicitor := &fakeElicitor{response: mcp.ElicitationResponse{
+		Action:  mcp.ElicitationResponseActionAccept,
+		Content: map[string]interface{}{"reason": "x"},
+	}}
+
+	result := elicitCall(t, elicitor, func(ctx context.Context, args interface{}) (interface{}, error) {
+		_, err := Elicit(ctx, ReasonInput{}, "Why?")
+		return nil, err
+	})
+	if !result.IsError {
+		t.Error("Expected an error for an invalid answer")
+	}
+}
+
+func TestConfirm(t *testing.T) {
+	tests := []struct {
+		name     string
+		response mcp.ElicitationResponse
+		expected bool
+	}{
+		{"accepted", mcp.ElicitationResponse{Action: mcp.ElicitationResponseActionAccept, Content: map[string]interface{}{"confirm": true}}, true},
+		{"unticked", mcp.ElicitationResponse{Action: mcp.ElicitationResponseActionAccept, Content: map[string]interface{}{"confirm": false}}, false},
+		{"declined", mcp.ElicitationResponse{Action: mcp.ElicitationResponseActionDecline}, false},
+		{"cancelled", mcp.ElicitationResponse{Action: mcp.ElicitationResponseActionCancel}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var confirmed bool
+			result := elicitCall(t, &fakeElicitor{response: tt.response}, func(ctx context.Context, args interface{}) (interface{}, error) {
+				var err error
+				confirmed, err = Confirm(ctx, "Delete Alice?")
+				return &TestResult{Message: "ok"}, err
+			})
+			if result.IsError {
+				t.Fatalf("Unexpected error result: %v", result.Content)
+			}
+			if confirmed != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, confirmed)
+			}
+		})
+	}
+}
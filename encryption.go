@@ -0,0 +1,159 @@
+package mcpwrapper
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+)
+
+// SecretProvider supplies AES keys (16, 24 or 32 bytes) for at-rest
+// encryption. Keys are addressed by ID so that data sealed with a retired key
+// can still be opened after rotation.
+type SecretProvider interface {
+	// CurrentKey returns the key new data is sealed with.
+	CurrentKey() (id string, key []byte, err error)
+	// Key returns the key with the given ID.
+	Key(id string) ([]byte, error)
+}
+
+type staticSecrets struct {
+	current string
+	keys    map[string][]byte
+}
+
+// StaticSecrets is a SecretProvider backed by an in-memory key set. To rotate,
+// add the new key to keys and make it current; keep old keys until all data
+// sealed with them has been resealed.
+func StaticSecrets(current string, keys map[string][]byte) SecretProvider {
+	return &staticSecrets{current: current, keys: keys}
+}
+
+func (s *staticSecrets) CurrentKey() (string, []byte, error) {
+	key, err := s.Key(s.current)
+	return s.current, key, err
+}
+
+func (s *staticSecrets) Key(id string) ([]byte, error) {
+	key, ok := s.keys[id]
+	if !ok {
+		return nil, fmt.Errorf("unknown encryption key %q", id)
+	}
+	return key, nil
+}
+
+// Encryptor seals data with AES-GCM. The output embeds the key ID and nonce:
+//
+//	[len(id)][id][nonce][ciphertext+tag]
+type Encryptor struct {
+	secrets SecretProvider
+}
+
+var ErrCiphertextCorrupt = errors.New("ciphertext is corrupt")
+
+func NewEncryptor(secrets SecretProvider) *Encryptor {
+	return &Encryptor{secrets: secrets}
+}
+
+func (e *Encryptor) Seal(plaintext []byte) ([]byte, error) {
+	id, key, err := e.secrets.CurrentKey()
+	if err != nil {
+		return nil, err
+	}
+	if len(id) > 255 {
+		return nil, fmt.Errorf("encryption key id %q is too long", id)
+	}
+
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	out := make([]byte, 0, 1+len(id)+len(nonce)+len(plaintext)+aead.Overhead())
+	out = append(out, byte(len(id)))
+	out = append(out, id...)
+	out = append(out, nonce...)
+	// The key ID is authenticated so it cannot be swapped undetected.
+	return aead.Seal(out, nonce, plaintext, []byte(id)), nil
+}
+
+func (e *Encryptor) Open(sealed []byte) ([]byte, error) {
+	id, nonce, ciphertext, aead, err := e.split(sealed)
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext, err := aead.Open(nil, nonce, ciphertext, []byte(id))
+	if err != nil {
+		return nil, ErrCiphertextCorrupt
+	}
+	return plaintext, nil
+}
+
+// Reseal re-encrypts data with the current key if it was sealed with an older
+// one, and returns it unchanged otherwise.
+func (e *Encryptor) Reseal(sealed []byte) ([]byte, error) {
+	id, err := sealedKeyID(sealed)
+	if err != nil {
+		return nil, err
+	}
+
+	current, _, err := e.secrets.CurrentKey()
+	if err != nil {
+		return nil, err
+	}
+	if id == current {
+		return sealed, nil
+	}
+
+	plaintext, err := e.Open(sealed)
+	if err != nil {
+		return nil, err
+	}
+	return e.Seal(plaintext)
+}
+
+func (e *Encryptor) split(sealed []byte) (string, []byte, []byte, cipher.AEAD, error) {
+	id, err := sealedKeyID(sealed)
+	if err != nil {
+		return "", nil, nil, nil, err
+	}
+
+	key, err := e.secrets.Key(id)
+	if err != nil {
+		return "", nil, nil, nil, err
+	}
+
+	aead, err := newGCM(key)
+	if err != nil {
+		return "", nil, nil, nil, err
+	}
+
+	rest := sealed[1+len(id):]
+	if len(rest) < aead.NonceSize()+aead.Overhead() {
+		return "", nil, nil, nil, ErrCiphertextCorrupt
+	}
+
+	return id, rest[:aead.NonceSize()], rest[aead.NonceSize():], aead, nil
+}
+
+func sealedKeyID(sealed []byte) (string, error) {
+	if len(sealed) == 0 || len(sealed) < 1+int(sealed[0]) {
+		return "", ErrCiphertextCorrupt
+	}
+	return string(sealed[1 : 1+int(sealed[0])]), nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid encryption key: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
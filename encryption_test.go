@@ -0,0 +1,48 @@
+package mcpwrapper
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestEncryptorRotation(t *testing.T) {
+	oldKey := bytes.Repeat([]byte{1}, 32)
+	newKey := bytes.Repeat([]byte{2}, 32)
+
+	enc := NewEncryptor(StaticSecrets("2025", map[string][]byte{"2025": oldKey}))
+
+	plaintext := []byte(`{"api_key":"secret"}`)
+	sealed, err := enc.Seal(plaintext)
+	if err != nil {
+		t.Fatalf("Seal failed: %v", err)
+	}
+
+	if bytes.Contains(sealed, []byte("secret")) {
+		t.Fatal("Sealed data contains plaintext")
+	}
+
+	rotated := NewEncryptor(StaticSecrets("2026", map[string][]byte{"2025": oldKey, "2026": newKey}))
+
+	opened, err := rotated.Open(sealed)
+	if err != nil {
+		t.Fatalf("Open with rotated key set failed: %v", err)
+	}
+	if !bytes.Equal(opened, plaintext) {
+		t.Errorf("Expected %q, got %q", plaintext, opened)
+	}
+
+	resealed, err := rotated.Reseal(sealed)
+	if err != nil {
+		t.Fatalf("Reseal failed: %v", err)
+	}
+
+	if id, _ := sealedKeyID(resealed); id != "2026" {
+		t.Errorf("Expected resealed data to use key 2026, got %q", id)
+	}
+
+	sealed[len(sealed)-1] ^= 0xff
+	if _, err := rotated.Open(sealed); !errors.Is(err, ErrCiphertextCorrupt) {
+		t.Errorf("Expected ErrCiphertextCorrupt for tampered data, got %v", err)
+	}
+}
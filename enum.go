@@ -0,0 +1,308 @@
+package mcpwrapper
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// tagValues returns the raw values of a repeatable key=value segment of a
+// jsonschema tag, such as enum= or example=.
+func tagValues(tag, key string) []string {
+	var values []string
+	for _, part := range strings.Split(tag, ",") {
+		part = strings.TrimSpace(part)
+		if strings.HasPrefix(part, key+"=") {
+			values = append(values, strings.TrimPrefix(part, key+"="))
+		}
+	}
+	return values
+}
+
+// typedValues converts tag values (enum=, example=) to the field's type so
+// numeric and boolean values are emitted as JSON numbers and booleans. String
+// values stay []string.
+func typedValues(values []string, t reflect.Type) (interface{}, error) {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() == reflect.String {
+		return values, nil
+	}
+
+	typed := make([]interface{}, 0, len(values))
+	for _, s := range values {
+		v, err := parseTagValue(s, t.Kind())
+		if err != nil {
+			return nil, err
+		}
+		typed = append(typed, v)
+	}
+	return typed, nil
+}
+
+func parseTagValue(s string, kind reflect.Kind) (interface{}, error) {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not an integer", s)
+		}
+		return v, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v, err := strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not an unsigned integer", s)
+		}
+		return v, nil
+	case reflect.Float32, reflect.Float64:
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a number", s)
+		}
+		return v, nil
+	case reflect.Bool:
+		v, err := strconv.ParseBool(s)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a boolean", s)
+		}
+		return v, nil
+	default:
+		return s, nil
+	}
+}
+
+// coerceEnumStrings accepts numeric and boolean enum values sent as JSON
+// strings ("443" for an integer port) by converting them before binding. The
+// args map is copied if anything changes.
+func coerceEnumStrings(argsType interface{}, args map[string]interface{}) map[string]interface{} {
+	t := reflect.TypeOf(argsType)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return args
+	}
+
+	copied := false
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		values := tagValues(field.Tag.Get("jsonschema"), "enum")
+		if len(values) == 0 {
+			continue
+		}
+		ft := field.Type
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if ft.Kind() == reflect.String {
+			continue
+		}
+
+		name := jsonFieldName(field)
+		s, ok := args[name].(string)
+		if !ok {
+			continue
+		}
+		v, err := parseTagValue(strings.TrimSpace(s), ft.Kind())
+		if err != nil {
+			continue
+		}
+		if !copied {
+			args = cloneMap(args)
+			copied = true
+		}
+		args[name] = v
+	}
+	return args
+}
+
+// validateEnums checks every field with enum values in its jsonschema tag.
+// Zero values of non-pointer fields are skipped, since an omitted optional
+// field cannot be told apart from one set to zero; use validate:"required" to
+// reject them.
+func validateEnums(v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr {
+		rv = rv.Elem()
+	}
+	t := rv.Type()
+
+	var errs ValidationErrors
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		values := tagValues(field.Tag.Get("jsonschema"), "enum")
+		if len(values) == 0 {
+			continue
+		}
+
+		fv := rv.Field(i)
+		if fv.Kind() == reflect.Ptr {
+			if fv.IsNil() {
+				continue
+			}
+			fv = fv.Elem()
+		} else if fv.IsZero() {
+			continue
+		}
+
+		if !enumContains(values, fv) {
+			errs = append(errs, ValidationError{
+				Field:   field.Name,
+				Message: fmt.Sprintf("must be one of: %s", strings.Join(values, " ")),
+			})
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func enumContains(values []string, v reflect.Value) bool {
+	for _, s := range values {
+		allowed, err := parseTagValue(s, v.Kind())
+		if err != nil {
+			continue
+		}
+		switch a := allowed.(type) {
+		case int64:
+			if v.Int() == a {
+				return true
+			}
+		case uint64:
+			if v.Uint() == a {
+				return true
+			}
+		case float64:
+			if v.Float() == a {
+				return true
+			}
+		case bool:
+			if v.Bool() == a {
+				return true
+			}
+		case string:
+			if v.Kind() == reflect.String && v.String() == a {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// RegisterEnum declares the allowed values of a named type, typically its
+// constants:
+//
+//	registry.RegisterEnum(LevelLow, LevelMedium, LevelHigh)
+//
+// Fields of that type, or a pointer to it, get an enum of the values in
+// their JSON form, and values outside it fail validation. All values must
+// have the same string, integer, float or boolean type. Like MapType, it
+// clears the schema cache; call it before registering tools.
+func (r *Registry) RegisterEnum(values ...interface{}) error {
+	if len(values) == 0 {
+		return fmt.Errorf("RegisterEnum needs at least one value")
+	}
+	t := reflect.TypeOf(values[0])
+	switch t.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+	default:
+		return fmt.Errorf("enum type %s must have a string, number or boolean kind", t)
+	}
+
+	enum := make([]interface{}, len(values))
+	for i, v := range values {
+		if reflect.TypeOf(v) != t {
+			return fmt.Errorf("enum value %v is a %T, not a %s", v, v, t)
+		}
+		enum[i] = jsonScalar(reflect.ValueOf(v))
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.types[t] = map[string]interface{}{"type": inferType(t), "enum": enum}
+	r.enums[t] = enum
+	r.schemas = make(map[reflect.Type]*mcp.ToolInputSchema)
+	return nil
+}
+
+// validateEnumTypes checks fields whose type was registered with
+// RegisterEnum. Zero values of non-pointer fields are skipped, as in
+// validateEnums.
+func (r *Registry) validateEnumTypes(v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil
+	}
+	t := rv.Type()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if len(r.enums) == 0 {
+		return nil
+	}
+
+	var errs ValidationErrors
+	for i := 0; i < t.NumField(); i++ {
+		fv := rv.Field(i)
+		if fv.Kind() == reflect.Ptr {
+			if fv.IsNil() {
+				continue
+			}
+			fv = fv.Elem()
+		} else if fv.IsZero() {
+			continue
+		}
+
+		enum, ok := r.enums[fv.Type()]
+		if !ok {
+			continue
+		}
+		value := jsonScalar(fv)
+		allowed := false
+		values := make([]string, len(enum))
+		for j, e := range enum {
+			allowed = allowed || e == value
+			values[j] = fmt.Sprint(e)
+		}
+		if !allowed {
+			errs = append(errs, ValidationError{
+				Field:   t.Field(i).Name,
+				Message: fmt.Sprintf("must be one of: %s", strings.Join(values, " ")),
+			})
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// jsonScalar converts a value of a named scalar type to the plain Go value
+// it is encoded as, so a fmt.Stringer constant is emitted as its number.
+func jsonScalar(v reflect.Value) interface{} {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return v.Uint()
+	case reflect.Float32, reflect.Float64:
+		return v.Float()
+	case reflect.Bool:
+		return v.Bool()
+	default:
+		return v.String()
+	}
+}
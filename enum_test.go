@@ -0,0 +1,176 @@
+package mcpwrapper
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+type ListenArgs struct {
+	Port    int      `json:"port" jsonschema:"enum=80,enum=443,enum=8080" validate:"required"`
+	Version *float64 `json:"version,omitempty" jsonschema:"enum=1.0,enum=1.1"`
+	TLS     bool     `json:"tls,omitempty" jsonschema:"enum=true"`
+	Mode    string   `json:"mode,omitempty" jsonschema:"enum=fast,enum=safe"`
+}
+
+func TestTypedEnumSchema(t *testing.T) {
+	schema, err := buildSchema(ListenArgs{})
+	if err != nil {
+		t.Fatalf("buildSchema failed: %v", err)
+	}
+
+	tests := []struct {
+		field    string
+		expected interface{}
+	}{
+		{"port", []interface{}{int64(80), int64(443), int64(8080)}},
+		{"version", []interface{}{1.0, 1.1}},
+		{"tls", []interface{}{true}},
+		{"mode", []string{"fast", "safe"}},
+	}
+
+	for _, tt := range tests {
+		prop := schema.Properties[tt.field].(map[string]interface{})
+		if !reflect.DeepEqual(prop["enum"], tt.expected) {
+			t.Errorf("%s: expected enum %#v, got %#v", tt.field, tt.expected, prop["enum"])
+		}
+	}
+}
+
+func TestInvalidEnumForType(t *testing.T) {
+	type BadArgs struct {
+		Port int `json:"port" jsonschema:"enum=http"`
+	}
+	if _, err := buildSchema(BadArgs{}); err == nil {
+		t.Error("Expected an error for a non-integer enum on an integer field")
+	}
+}
+
+func TestTypedEnumValidation(t *testing.T) {
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	wrapper := New(mcpServer)
+
+	var got *ListenArgs
+	handler := func(ctx context.Context, args interface{}) (interface{}, error) {
+		got = args.(*ListenArgs)
+		return map[string]string{"status": "listening"}, nil
+	}
+
+	if err := wrapper.Register("listen", "Listen", ListenArgs{}, handler); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		args    map[string]interface{}
+		wantErr string
+	}{
+		{"valid", map[string]interface{}{"port": 443, "version": 1.1}, ""},
+		{"number as string", map[string]interface{}{"port": "8080"}, ""},
+		{"port not in enum", map[string]interface{}{"port": 22}, "must be one of: 80 443 8080"},
+		{"float not in enum", map[string]interface{}{"port": 80, "version": 2.0}, "Version: must be one of"},
+		{"string not in enum", map[string]interface{}{"port": 80, "mode": "slow"}, "Mode: must be one of"},
+		{"unparsable string", map[string]interface{}{"port": "https"}, "failed to bind arguments"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := mcp.CallToolRequest{Params: mcp.CallToolParams{Name: "listen", Arguments: tt.args}}
+			result, err := mcpServer.GetTool("listen").Handler(context.Background(), request)
+			if err != nil {
+				t.Fatalf("Handler invocation failed: %v", err)
+			}
+
+			if tt.wantErr == "" {
+				if result.IsError {
+					t.Fatalf("Unexpected error: %v", result.Content)
+				}
+				return
+			}
+			if !result.IsError {
+				t.Fatal("Expected an error result")
+			}
+			if text := result.Content[0].(mcp.TextContent).Text; !strings.Contains(text, tt.wantErr) {
+				t.Errorf("Expected %q in %q", tt.wantErr, text)
+			}
+		})
+	}
+
+	request := mcp.CallToolRequest{Params: mcp.CallToolParams{Name: "listen", Arguments: map[string]interface{}{"port": "443"}}}
+	if _, err := mcpServer.GetTool("listen").Handler(context.Background(), request); err != nil {
+		t.Fatalf("Handler invocation failed: %v", err)
+	}
+	if got.Port != 443 {
+		t.Errorf("Expected port 443 from string, got %d", got.Port)
+	}
+	if request.GetArguments()["port"] != "443" {
+		t.Error("Expected caller's arguments to be left unchanged")
+	}
+}
+
+type Priority int
+
+const (
+	PriorityLow Priority = iota + 1
+	PriorityHigh
+)
+
+func (p Priority) String() string {
+	return [...]string{"", "low", "high"}[p]
+}
+
+type TicketArgs struct {
+	Title    string    `json:"title"`
+	Priority Priority  `json:"priority"`
+	Escalate *Priority `json:"escalate,omitempty" jsonschema:"description=Escalation priority"`
+}
+
+func TestRegisterEnum(t *testing.T) {
+	registry := NewRegistry()
+	if err := registry.RegisterEnum(PriorityLow, PriorityHigh); err != nil {
+		t.Fatalf("RegisterEnum failed: %v", err)
+	}
+
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	wrapper := New(mcpServer, WithRegistry(registry))
+	var got TicketArgs
+	handler := func(ctx context.Context, args interface{}) (interface{}, error) {
+		got = *args.(*TicketArgs)
+		return "ok", nil
+	}
+	if err := wrapper.Register("open_ticket", "Open a ticket", TicketArgs{}, handler); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	props := mcpServer.GetTool("open_ticket").Tool.InputSchema.Properties
+	priority := props["priority"].(map[string]interface{})
+	if priority["type"] != "integer" || !reflect.DeepEqual(priority["enum"], []interface{}{int64(1), int64(2)}) {
+		t.Errorf("Unexpected priority schema: %v", priority)
+	}
+	escalate := props["escalate"].(map[string]interface{})
+	if escalate["enum"] == nil || escalate["description"] != "Escalation priority" {
+		t.Errorf("Unexpected escalate schema: %v", escalate)
+	}
+
+	if result := callTool(t, mcpServer, "open_ticket", map[string]interface{}{"title": "x", "priority": 2}); result.IsError || got.Priority != PriorityHigh {
+		t.Errorf("Expected a valid priority to bind, got %v %+v", result.Content, got)
+	}
+	result := callTool(t, mcpServer, "open_ticket", map[string]interface{}{"title": "x", "escalate": 7})
+	if !result.IsError || !strings.Contains(result.Content[0].(mcp.TextContent).Text, "Escalate: must be one of: 1 2") {
+		t.Errorf("Expected an enum validation error, got %v", result.Content)
+	}
+
+	if err := registry.RegisterEnum(PriorityLow, 3); err == nil {
+		t.Error("Expected an error for mixed value types")
+	}
+	if err := registry.RegisterEnum(); err == nil {
+		t.Error("Expected an error without values")
+	}
+	if err := registry.RegisterEnum(struct{}{}); err == nil {
+		t.Error("Expected an error for a non-scalar type")
+	}
+}
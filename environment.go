@@ -0,0 +1,29 @@
+package mcpwrapper
+
+// WithEnvironment sets the deployment environment (e.g. "prod") the wrapper
+// runs in. It overrides the environment from the config file.
+func WithEnvironment(env string) Option {
+	return func(w *Wrapper) {
+		w.environment = env
+	}
+}
+
+// WithEnvironments limits a tool to the listed environments. In any other
+// environment, including when none is configured, Register skips the tool so
+// it is never exposed to clients.
+func WithEnvironments(envs ...string) ToolOption {
+	return func(o *toolOptions) {
+		o.environments = append(o.environments, envs...)
+	}
+}
+
+func (w *Wrapper) Environment() string {
+	return w.environment
+}
+
+func (w *Wrapper) environmentAllowed(envs []string) bool {
+	if len(envs) == 0 {
+		return true
+	}
+	return contains(envs, w.environment)
+}
@@ -0,0 +1,48 @@
+package mcpwrapper
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func TestWithEnvironments(t *testing.T) {
+	handler := func(ctx context.Context, args interface{}) (interface{}, error) {
+		return &TestResult{Message: "dropped"}, nil
+	}
+
+	tests := []struct {
+		name        string
+		opts        []Option
+		registered  bool
+		environment string
+	}{
+		{"allowed environment", []Option{WithEnvironment("dev")}, true, "dev"},
+		{"disallowed environment", []Option{WithEnvironment("prod")}, false, "prod"},
+		{"unknown environment", nil, false, ""},
+		{"environment from config", []Option{WithConfig(&Config{Environment: "staging"})}, true, "staging"},
+		{"option overrides config", []Option{WithEnvironment("prod"), WithConfig(&Config{Environment: "dev"})}, false, "prod"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mcpServer := server.NewMCPServer("test", "1.0.0")
+			wrapper := New(mcpServer, tt.opts...)
+
+			err := wrapper.Register("drop-db", "Drop database", TestArgs{}, handler, WithEnvironments("dev", "staging"))
+			if err != nil {
+				t.Fatalf("Register failed: %v", err)
+			}
+
+			if wrapper.Environment() != tt.environment {
+				t.Errorf("Expected environment '%s', got '%s'", tt.environment, wrapper.Environment())
+			}
+
+			registered := mcpServer.GetTool("drop-db") != nil
+			if registered != tt.registered {
+				t.Errorf("Expected registered=%v, got %v", tt.registered, registered)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package mcpwrapper
+
+import (
+	"context"
+	"os"
+	"sort"
+)
+
+type envKey struct{}
+
+// WithEnv sets environment variables for the tool's calls. Commands run by
+// RegisterExec get them on top of the server's environment; handlers and
+// in-process commands, such as those of RegisterCobraFlags, read them with
+// Getenv from the call's context. The server's own environment is never
+// changed, so values such as credentials don't leak to other tools.
+func WithEnv(env map[string]string) ToolOption {
+	return WithToolMiddleware(envMiddleware(func(interface{}) map[string]string { return env }))
+}
+
+// WithEnvFromArgs sets environment variables from the call's arguments, as
+// WithEnv does. mapping maps argument names, as in the schema, to variable
+// names. Arguments the client omitted are not set; strings are passed as
+// they are and other values as they would be typed on the command line.
+func WithEnvFromArgs(mapping map[string]string) ToolOption {
+	return WithToolMiddleware(envMiddleware(func(args interface{}) map[string]string {
+		fields := jsonFields(args)
+		env := make(map[string]string, len(mapping))
+		for arg, name := range mapping {
+			if value, ok := fields[arg]; ok && value != nil {
+				env[name] = argumentString(value)
+			}
+		}
+		return env
+	}))
+}
+
+func envMiddleware(vars func(args interface{}) map[string]string) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, args interface{}) (interface{}, error) {
+			env := make(map[string]string)
+			for k, v := range envFromContext(ctx) {
+				env[k] = v
+			}
+			for k, v := range vars(args) {
+				env[k] = v
+			}
+			return next(context.WithValue(ctx, envKey{}, env), args)
+		}
+	}
+}
+
+func envFromContext(ctx context.Context) map[string]string {
+	env, _ := ctx.Value(envKey{}).(map[string]string)
+	return env
+}
+
+// LookupEnv returns a variable set for the call by WithEnv or
+// WithEnvFromArgs, or else from the server's environment.
+func LookupEnv(ctx context.Context, key string) (string, bool) {
+	if value, ok := envFromContext(ctx)[key]; ok {
+		return value, true
+	}
+	return os.LookupEnv(key)
+}
+
+// Getenv is like LookupEnv but returns "" for an unset variable.
+func Getenv(ctx context.Context, key string) string {
+	value, _ := LookupEnv(ctx, key)
+	return value
+}
+
+// callEnviron returns the variables set for the call as NAME=value
+// entries, sorted by name.
+func callEnviron(ctx context.Context) []string {
+	env := envFromContext(ctx)
+	entries := make([]string, 0, len(env))
+	for k, v := range env {
+		entries = append(entries, k+"="+v)
+	}
+	sort.Strings(entries)
+	return entries
+}
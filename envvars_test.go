@@ -0,0 +1,77 @@
+package mcpwrapper
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+type EnvArgs struct {
+	Token   string `json:"token,omitempty"`
+	Region  string `json:"region,omitempty"`
+	Retries int    `json:"retries,omitempty"`
+}
+
+func TestExecEnv(t *testing.T) {
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	wrapper := New(mcpServer)
+	err := wrapper.RegisterExec("env", "Print variables", EnvArgs{}, ExecSpec{
+		Command: "sh",
+		Args:    []string{"-c", `printf '%s|%s|%s|%s' "$API_URL" "$API_TOKEN" "$REGION" "$RETRIES"`},
+	},
+		WithEnv(map[string]string{"API_URL": "https://example.com", "REGION": "default"}),
+		WithEnvFromArgs(map[string]string{"token": "API_TOKEN", "region": "REGION", "retries": "RETRIES"}),
+	)
+	if err != nil {
+		t.Fatalf("RegisterExec failed: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		args map[string]interface{}
+		want string
+	}{
+		{"static only", map[string]interface{}{}, "https://example.com||default|"},
+		{"from arguments", map[string]interface{}{"token": "t0k", "region": "eu", "retries": 3}, "https://example.com|t0k|eu|3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var result ExecResult
+			text := callTool(t, mcpServer, "env", tt.args).Content[0].(mcp.TextContent).Text
+			if err := json.Unmarshal([]byte(text), &result); err != nil {
+				t.Fatalf("Failed to decode result %q: %v", text, err)
+			}
+			if result.Stdout != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, result.Stdout)
+			}
+		})
+	}
+	if _, ok := os.LookupEnv("API_TOKEN"); ok {
+		t.Error("Expected the server's environment to be left alone")
+	}
+}
+
+func TestGetenv(t *testing.T) {
+	t.Setenv("MCPWRAPPER_TEST_HOME", "/home/server")
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	var token, home string
+	var set bool
+	err := New(mcpServer).Register("env", "Read variables", EnvArgs{}, func(ctx context.Context, args interface{}) (interface{}, error) {
+		token = Getenv(ctx, "API_TOKEN")
+		home = Getenv(ctx, "MCPWRAPPER_TEST_HOME")
+		_, set = LookupEnv(ctx, "REGION")
+		return &TestResult{Message: "ok"}, nil
+	}, WithEnvFromArgs(map[string]string{"token": "API_TOKEN", "region": "REGION"}))
+	if err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	callTool(t, mcpServer, "env", map[string]interface{}{"token": "t0k"})
+	if token != "t0k" || home != "/home/server" || set {
+		t.Errorf("Unexpected variables: token=%q home=%q region set=%v", token, home, set)
+	}
+}
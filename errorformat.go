@@ -0,0 +1,34 @@
+package mcpwrapper
+
+import (
+	"context"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// ErrorFormatter builds the result reported for a failed call. err is the
+// handler error as returned, or, for arguments that fail to bind or
+// validate, a CodedError with CodeInvalidInput wrapping the cause (a
+// ValidationErrors for validation failures). Returning nil keeps the
+// default result.
+type ErrorFormatter func(ctx context.Context, toolName string, err error) *mcp.CallToolResult
+
+// WithErrorFormatter controls how handler errors, binding errors and
+// validation failures are presented, for example to add remediation hints
+// for models or to hide internals in production. The default result
+// redacts redact:"true" argument values from the message; a formatter that
+// echoes err is responsible for that itself.
+func WithErrorFormatter(f ErrorFormatter) Option {
+	return func(w *Wrapper) {
+		w.errorFormatter = f
+	}
+}
+
+// formatError returns the ErrorFormatter's result for err, or nil if there
+// is no formatter or it keeps the default.
+func (w *Wrapper) formatError(ctx context.Context, t *registeredTool, err error) *mcp.CallToolResult {
+	if w.errorFormatter == nil {
+		return nil
+	}
+	return w.errorFormatter(ctx, t.name, err)
+}
@@ -0,0 +1,62 @@
+package mcpwrapper
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func TestWithErrorFormatter(t *testing.T) {
+	var seen []error
+	formatter := func(ctx context.Context, toolName string, err error) *mcp.CallToolResult {
+		seen = append(seen, err)
+		if code, ok := CodeFromError(err); ok && code == CodeNotFound {
+			return nil // keep the default
+		}
+		var validation ValidationErrors
+		if errors.As(err, &validation) {
+			return mcp.NewToolResultError(toolName + ": fix " + validation[0].Field)
+		}
+		return mcp.NewToolResultError(toolName + ": something went wrong")
+	}
+
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	wrapper := New(mcpServer, WithErrorFormatter(formatter))
+	var handlerErr error
+	wrapper.Register("get_user", "Get a user", TestArgs{}, func(ctx context.Context, args interface{}) (interface{}, error) {
+		return nil, handlerErr
+	})
+	valid := map[string]interface{}{"name": "Alice", "age": 30, "category": "A"}
+
+	text := func(result *mcp.CallToolResult) string {
+		return result.Content[0].(mcp.TextContent).Text
+	}
+
+	handlerErr = errors.New("connection refused to 10.0.0.3:5432")
+	if got := text(callTool(t, mcpServer, "get_user", valid)); got != "get_user: something went wrong" {
+		t.Errorf("Unexpected handler error text %q", got)
+	}
+	if seen[0] != handlerErr {
+		t.Errorf("Expected the handler error as returned, got %v", seen[0])
+	}
+
+	handlerErr = NotFound("no such user")
+	if got := text(callTool(t, mcpServer, "get_user", valid)); got != "not_found: no such user" {
+		t.Errorf("Expected the default result, got %q", got)
+	}
+
+	if got := text(callTool(t, mcpServer, "get_user", map[string]interface{}{"name": "Al", "age": 30, "category": "A"})); got != "get_user: fix Name" {
+		t.Errorf("Unexpected validation text %q", got)
+	}
+
+	result := callTool(t, mcpServer, "get_user", map[string]interface{}{"name": "Alice", "age": "old", "category": "A"})
+	if got := text(result); got != "get_user: something went wrong" {
+		t.Errorf("Unexpected bind error text %q", got)
+	}
+	if code, _ := CodeFromError(seen[len(seen)-1]); code != CodeInvalidInput {
+		t.Errorf("Expected bind errors to be invalid_input, got %v", seen[len(seen)-1])
+	}
+}
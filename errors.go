@@ -0,0 +1,90 @@
+package mcpwrapper
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// ErrorCode classifies a failed call. It is reported in the "code" field of
+// the error result's structured content, so clients and agents can react
+// without parsing the message.
+type ErrorCode string
+
+const (
+	CodeNotFound     ErrorCode = "not_found"
+	CodeInvalidInput ErrorCode = "invalid_input"
+	CodeUnauthorized ErrorCode = "unauthorized"
+	CodeUnavailable  ErrorCode = "unavailable"
+	CodeTooLarge     ErrorCode = "too_large"
+	CodeInternal     ErrorCode = "internal"
+)
+
+// CodedError is an error with an ErrorCode.
+type CodedError struct {
+	Code ErrorCode
+	Err  error
+}
+
+func (e *CodedError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *CodedError) Unwrap() error {
+	return e.Err
+}
+
+// Errorf formats an error like fmt.Errorf, including %w wrapping, and
+// classifies it with code.
+func Errorf(code ErrorCode, format string, args ...interface{}) error {
+	return &CodedError{Code: code, Err: fmt.Errorf(format, args...)}
+}
+
+// NotFound reports that something the call refers to does not exist.
+func NotFound(format string, args ...interface{}) error {
+	return Errorf(CodeNotFound, format, args...)
+}
+
+// InvalidInput reports arguments the handler rejects beyond what the
+// validate tags catch.
+func InvalidInput(format string, args ...interface{}) error {
+	return Errorf(CodeInvalidInput, format, args...)
+}
+
+// Unauthorized reports that the caller may not perform the call.
+func Unauthorized(format string, args ...interface{}) error {
+	return Errorf(CodeUnauthorized, format, args...)
+}
+
+// Unavailable reports a transient failure, such as a backend being down;
+// the call may succeed later.
+func Unavailable(format string, args ...interface{}) error {
+	return Errorf(CodeUnavailable, format, args...)
+}
+
+// CodeFromError returns the code of the outermost CodedError in err's
+// chain. An *UnavailableError from availability windows or the circuit
+// breaker is CodeUnavailable.
+func CodeFromError(err error) (ErrorCode, bool) {
+	var coded *CodedError
+	if errors.As(err, &coded) {
+		return coded.Code, true
+	}
+	var unavailable *UnavailableError
+	if errors.As(err, &unavailable) {
+		return CodeUnavailable, true
+	}
+	return "", false
+}
+
+// codedErrorResult builds an error result for a failure the wrapper itself
+// classified, such as a validation error.
+func codedErrorResult(code ErrorCode, msg string) *mcp.CallToolResult {
+	result := mcp.NewToolResultError(msg)
+	result.StructuredContent = map[string]interface{}{
+		"error": msg,
+		"code":  code,
+	}
+	return result
+}
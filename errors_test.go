@@ -0,0 +1,85 @@
+package mcpwrapper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func TestCodedErrors(t *testing.T) {
+	err := NotFound("user %d does not exist", 42)
+	if code, ok := CodeFromError(err); !ok || code != CodeNotFound {
+		t.Errorf("Unexpected code %q", code)
+	}
+	if err.Error() != "user 42 does not exist" {
+		t.Errorf("Unexpected message %q", err.Error())
+	}
+
+	wrapped := fmt.Errorf("lookup: %w", Unavailable("backend down: %w", io.ErrUnexpectedEOF))
+	if code, _ := CodeFromError(wrapped); code != CodeUnavailable {
+		t.Errorf("Expected the code of a wrapped error, got %q", code)
+	}
+	if !errors.Is(wrapped, io.ErrUnexpectedEOF) {
+		t.Error("Expected Errorf to keep the wrapped error")
+	}
+
+	if code, _ := CodeFromError(&UnavailableError{Tool: "deploy", Reason: "circuit open"}); code != CodeUnavailable {
+		t.Errorf("Expected an UnavailableError to be unavailable, got %q", code)
+	}
+	if _, ok := CodeFromError(errors.New("plain")); ok {
+		t.Error("Expected no code for a plain error")
+	}
+}
+
+func TestCodedErrorResults(t *testing.T) {
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	wrapper := New(mcpServer)
+
+	var handlerErr error
+	wrapper.Register("get_user", "Get a user", TestArgs{}, func(ctx context.Context, args interface{}) (interface{}, error) {
+		return nil, handlerErr
+	})
+	valid := map[string]interface{}{"name": "Alice", "age": 30, "category": "A"}
+
+	tests := []struct {
+		name     string
+		err      error
+		args     map[string]interface{}
+		wantText string
+		wantCode interface{}
+	}{
+		{"not_found", NotFound("user Alice does not exist"), valid, "not_found: user Alice does not exist", CodeNotFound},
+		{"unauthorized", Unauthorized("not your user"), valid, "unauthorized: not your user", CodeUnauthorized},
+		{"hinted", ErrorWithHint(InvalidInput("age mismatch"), "check the age"), valid, "invalid_input: age mismatch\nhint: check the age", CodeInvalidInput},
+		{"plain", errors.New("boom"), valid, "handler error: boom", nil},
+		{"validation", nil, map[string]interface{}{"name": "Al", "age": 30, "category": "A"}, "", CodeInvalidInput},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handlerErr = tt.err
+			result := callTool(t, mcpServer, "get_user", tt.args)
+			if !result.IsError {
+				t.Fatal("Expected an error result")
+			}
+			if text := result.Content[0].(mcp.TextContent).Text; tt.wantText != "" && text != tt.wantText {
+				t.Errorf("Unexpected text %q", text)
+			}
+
+			structured, _ := result.StructuredContent.(map[string]interface{})
+			if tt.wantCode == nil {
+				if structured != nil {
+					t.Errorf("Expected no structured content, got %v", structured)
+				}
+				return
+			}
+			if structured["code"] != tt.wantCode {
+				t.Errorf("Expected code %v, got %v", tt.wantCode, structured)
+			}
+		})
+	}
+}
@@ -0,0 +1,389 @@
+package mcpwrapper
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"regexp/syntax"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// ExampleCall synthesizes a minimal valid arguments object for a tool from
+// its input schema: only required properties, each set to its default,
+// first example, const or first enum value, or else the value closest to
+// zero that the type, format, pattern, length and range keywords allow.
+// Required properties avoid Go zero values, which validate:"required"
+// rejects: 1 rather than 0, true rather than false. The result is
+// run through the tool's binding and validation, so an error means the
+// validate tags ask for more than the schema advertises.
+//
+// GenerateDocs shows it for tools without explicit examples, and smoke
+// tests can call every tool with it.
+func (w *Wrapper) ExampleCall(name string) (map[string]interface{}, error) {
+	t, ok := w.lookupTool(name)
+	if !ok {
+		return nil, fmt.Errorf("tool %s is not registered", name)
+	}
+
+	args, err := exampleArguments(t.tool.InputSchema)
+	if err != nil {
+		return nil, fmt.Errorf("tool %s: %w", name, err)
+	}
+
+	data, err := json.Marshal(args)
+	if err != nil {
+		return nil, fmt.Errorf("tool %s: %w", name, err)
+	}
+	bound := reflect.New(reflect.TypeOf(t.argsType)).Interface()
+	if err := json.Unmarshal(data, bound); err != nil {
+		return nil, fmt.Errorf("tool %s: example does not bind: %w", name, err)
+	}
+	if err := w.validateArgs(t, bound); err != nil {
+		return nil, fmt.Errorf("tool %s: example does not validate: %w", name, err)
+	}
+	return args, nil
+}
+
+// exampleArguments builds the minimal example object for an input schema.
+func exampleArguments(schema mcp.ToolInputSchema) (map[string]interface{}, error) {
+	root, err := schemaMap(schema)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &exampleBuilder{newSchemaResolver(root)}
+	value, err := b.value(root, 0, false)
+	if err != nil {
+		return nil, err
+	}
+	args, ok := value.(map[string]interface{})
+	if !ok {
+		return map[string]interface{}{}, nil
+	}
+	return args, nil
+}
+
+// schemaMap returns an input schema in its JSON form, with float64 numbers
+// and []interface{} lists whichever backend built it.
+func schemaMap(schema mcp.ToolInputSchema) (map[string]interface{}, error) {
+	data, err := json.Marshal(schema)
+	if err != nil {
+		return nil, err
+	}
+	var root map[string]interface{}
+	if err := json.Unmarshal(data, &root); err != nil {
+		return nil, err
+	}
+	return root, nil
+}
+
+// schemaResolver follows local $refs in a schema returned by schemaMap.
+type schemaResolver struct {
+	root map[string]interface{}
+	defs map[string]interface{}
+}
+
+func newSchemaResolver(root map[string]interface{}) schemaResolver {
+	defs, _ := root["$defs"].(map[string]interface{})
+	return schemaResolver{root: root, defs: defs}
+}
+
+func (r schemaResolver) resolve(schema map[string]interface{}) map[string]interface{} {
+	ref, ok := schema["$ref"].(string)
+	if !ok {
+		return schema
+	}
+	if ref == "#" {
+		return r.root
+	}
+	def, _ := r.defs[strings.TrimPrefix(ref, "#/$defs/")].(map[string]interface{})
+	return def
+}
+
+// maxExampleDepth stops recursive schemas whose required properties refer
+// back to themselves.
+const maxExampleDepth = 8
+
+type exampleBuilder struct {
+	schemaResolver
+}
+
+// value builds an example for schema. nonZero asks for a value that isn't
+// the Go zero value of its type.
+func (b *exampleBuilder) value(schema map[string]interface{}, depth int, nonZero bool) (interface{}, error) {
+	if depth > maxExampleDepth {
+		return nil, fmt.Errorf("schema nests required properties deeper than %d levels", maxExampleDepth)
+	}
+	schema = b.resolve(schema)
+	if schema == nil {
+		return nil, fmt.Errorf("unresolved $ref")
+	}
+
+	if v, ok := schema["default"]; ok {
+		return v, nil
+	}
+	if examples := listValues(schema["examples"]); len(examples) > 0 {
+		return examples[0], nil
+	}
+	if v, ok := schema["const"]; ok {
+		return v, nil
+	}
+	if enum := listValues(schema["enum"]); len(enum) > 0 {
+		return enum[0], nil
+	}
+
+	typ, _ := schema["type"].(string)
+	if typ == "" {
+		if _, ok := schema["properties"]; ok {
+			typ = "object"
+		}
+	}
+	switch typ {
+	case "string":
+		return exampleString(schema)
+	case "integer":
+		return exampleNumber(schema, true, nonZero)
+	case "number":
+		return exampleNumber(schema, false, nonZero)
+	case "boolean":
+		return nonZero, nil
+	case "array":
+		return b.array(schema, depth, nonZero)
+	case "object":
+		return b.object(schema, depth)
+	case "null":
+		return nil, nil
+	}
+	return "example", nil
+}
+
+func (b *exampleBuilder) object(schema map[string]interface{}, depth int) (interface{}, error) {
+	props, _ := schema["properties"].(map[string]interface{})
+	obj := make(map[string]interface{})
+	for _, name := range listValues(schema["required"]) {
+		key, _ := name.(string)
+		prop, _ := props[key].(map[string]interface{})
+		if prop == nil {
+			prop = map[string]interface{}{}
+		}
+		v, err := b.value(prop, depth+1, true)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", key, err)
+		}
+		obj[key] = v
+	}
+	return obj, nil
+}
+
+func (b *exampleBuilder) array(schema map[string]interface{}, depth int, nonZero bool) (interface{}, error) {
+	n, _ := schemaNumber(schema, "minItems")
+	if nonZero && n < 1 {
+		if max, ok := schemaNumber(schema, "maxItems"); !ok || max >= 1 {
+			n = 1
+		}
+	}
+	items, _ := schema["items"].(map[string]interface{})
+	if items == nil {
+		items = map[string]interface{}{}
+	}
+	values := make([]interface{}, 0, int(n))
+	for i := 0; i < int(n); i++ {
+		v, err := b.value(items, depth+1, false)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+	}
+	if unique, _ := schema["uniqueItems"].(bool); unique && len(values) > 1 {
+		return nil, fmt.Errorf("cannot build %d unique items", len(values))
+	}
+	return values, nil
+}
+
+func schemaNumber(schema map[string]interface{}, keyword string) (float64, bool) {
+	v, ok := schema[keyword].(float64)
+	return v, ok
+}
+
+var exampleFormats = map[string]string{
+	"date-time": "2024-01-01T00:00:00Z",
+	"date":      "2024-01-01",
+	"time":      "00:00:00Z",
+	"email":     "user@example.com",
+	"uri":       "https://example.com",
+	"uuid":      "00000000-0000-0000-0000-000000000000",
+	"ipv4":      "192.0.2.1",
+	"ipv6":      "2001:db8::1",
+	"hostname":  "example.com",
+}
+
+func exampleString(schema map[string]interface{}) (interface{}, error) {
+	if format, ok := schema["format"].(string); ok {
+		if s, ok := exampleFormats[format]; ok {
+			return s, nil
+		}
+	}
+	if pattern, ok := schema["pattern"].(string); ok {
+		s, err := patternExample(pattern)
+		if err != nil {
+			return nil, err
+		}
+		return s, nil
+	}
+
+	s := "example"
+	if min, ok := schemaNumber(schema, "minLength"); ok && utf8.RuneCountInString(s) < int(min) {
+		s += strings.Repeat("x", int(min)-utf8.RuneCountInString(s))
+	}
+	if max, ok := schemaNumber(schema, "maxLength"); ok && utf8.RuneCountInString(s) > int(max) {
+		s = string([]rune(s)[:int(max)])
+	}
+	return s, nil
+}
+
+// exampleNumber returns 0 (or 1 with nonZero) when allowed, and otherwise
+// the allowed value closest to 0, rounded away from 0 to multipleOf.
+func exampleNumber(schema map[string]interface{}, integer, nonZero bool) (interface{}, error) {
+	lo, hi := math.Inf(-1), math.Inf(1)
+	loOpen, hiOpen := false, false
+	if v, ok := schemaNumber(schema, "minimum"); ok {
+		lo = v
+	}
+	if v, ok := schemaNumber(schema, "exclusiveMinimum"); ok && v >= lo {
+		lo, loOpen = v, true
+	}
+	if v, ok := schemaNumber(schema, "maximum"); ok {
+		hi = v
+	}
+	if v, ok := schemaNumber(schema, "exclusiveMaximum"); ok && v <= hi {
+		hi, hiOpen = v, true
+	}
+	within := func(v float64) bool {
+		return (v > lo || (!loOpen && v == lo)) && (v < hi || (!hiOpen && v == hi)) &&
+			(!integer || v == math.Trunc(v))
+	}
+
+	// step moves off an exclusive bound: to the next integer, or halfway
+	// to the other bound.
+	step := func(from, to float64) float64 {
+		if integer {
+			if to > from {
+				return math.Floor(from) + 1
+			}
+			return math.Ceil(from) - 1
+		}
+		if math.IsInf(to, 0) {
+			return from + math.Copysign(1, to)
+		}
+		return from + (to-from)/2
+	}
+
+	var v float64
+	switch {
+	case within(0) && !nonZero:
+		v = 0
+	case within(0):
+		switch {
+		case within(1):
+			v = 1
+		case within(-1):
+			v = -1
+		case hi > 0:
+			v = hi / 2
+		default:
+			v = lo / 2
+		}
+	case lo >= 0:
+		v = lo
+		if loOpen || (integer && v != math.Trunc(v)) {
+			v = step(lo, hi)
+		}
+	default:
+		v = hi
+		if hiOpen || (integer && v != math.Trunc(v)) {
+			v = step(hi, lo)
+		}
+	}
+	if m, ok := schemaNumber(schema, "multipleOf"); ok && m > 0 && !isMultipleOf(v, m) {
+		if v >= 0 {
+			v = math.Ceil(v/m) * m
+		} else {
+			v = math.Floor(v/m) * m
+		}
+	}
+	if !within(v) {
+		return nil, fmt.Errorf("no number satisfies the range keywords")
+	}
+	return v, nil
+}
+
+// patternExample returns a short string matching a regular expression:
+// the first branch of each alternation, the minimum repetitions, and a
+// letter or digit from each character class where possible.
+func patternExample(pattern string) (string, error) {
+	re, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	if !writePatternExample(&b, re.Simplify()) {
+		return "", fmt.Errorf("cannot build an example for pattern %s", pattern)
+	}
+	s := b.String()
+	if !regexp.MustCompile(pattern).MatchString(s) {
+		return "", fmt.Errorf("cannot build an example for pattern %s", pattern)
+	}
+	return s, nil
+}
+
+func writePatternExample(b *strings.Builder, re *syntax.Regexp) bool {
+	switch re.Op {
+	case syntax.OpLiteral:
+		b.WriteString(string(re.Rune))
+	case syntax.OpCharClass:
+		if len(re.Rune) == 0 {
+			return false
+		}
+		b.WriteRune(classExample(re.Rune))
+	case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		b.WriteRune('a')
+	case syntax.OpCapture, syntax.OpPlus:
+		return writePatternExample(b, re.Sub[0])
+	case syntax.OpRepeat:
+		for i := 0; i < re.Min; i++ {
+			if !writePatternExample(b, re.Sub[0]) {
+				return false
+			}
+		}
+	case syntax.OpConcat:
+		for _, sub := range re.Sub {
+			if !writePatternExample(b, sub) {
+				return false
+			}
+		}
+	case syntax.OpAlternate:
+		return writePatternExample(b, re.Sub[0])
+	case syntax.OpNoMatch:
+		return false
+	}
+	// Empty matches, anchors, word boundaries, star and quest add nothing.
+	return true
+}
+
+// classExample prefers a readable rune from a character class given as
+// ranges.
+func classExample(ranges []rune) rune {
+	for _, r := range []rune{'a', 'A', '0', '-', '_'} {
+		for i := 0; i+1 < len(ranges); i += 2 {
+			if ranges[i] <= r && r <= ranges[i+1] {
+				return r
+			}
+		}
+	}
+	return ranges[0]
+}
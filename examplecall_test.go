@@ -0,0 +1,123 @@
+package mcpwrapper
+
+import (
+	"context"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/server"
+)
+
+type DeployArgs struct {
+	Service  string       `json:"service" jsonschema:"required,minLength=10" validate:"required,min=10"`
+	Tag      string       `json:"tag" jsonschema:"required,pattern=^v[0-9]{1,3}\\.[0-9]+(-rc[0-9])?$"`
+	Owner    string       `json:"owner" jsonschema:"required,format=email"`
+	Replicas int          `json:"replicas" jsonschema:"required,multipleOf=3,exclusiveMinimum=4"`
+	Ratio    float64      `json:"ratio" jsonschema:"required,exclusiveMaximum=0"`
+	Canary   bool         `json:"canary" jsonschema:"required" validate:"required"`
+	Region   string       `json:"region" jsonschema:"required,enum=eu,enum=us"`
+	Target   DeployTarget `json:"target" jsonschema:"required"`
+	Note     string       `json:"note,omitempty"`
+}
+
+type DeployTarget struct {
+	Cluster string   `json:"cluster" jsonschema:"required,example=prod-1"`
+	Zones   []string `json:"zones" jsonschema:"required"`
+}
+
+func TestExampleCall(t *testing.T) {
+	wrapper := New(server.NewMCPServer("test", "1.0.0"), WithStrictSchema())
+	handler := func(ctx context.Context, args interface{}) (interface{}, error) { return "ok", nil }
+	wrapper.Register("deploy", "Deploy a service", DeployArgs{}, handler)
+
+	args, err := wrapper.ExampleCall("deploy")
+	if err != nil {
+		t.Fatalf("ExampleCall failed: %v", err)
+	}
+	want := map[string]interface{}{
+		"service":  "examplexxx",
+		"tag":      "v0.0",
+		"owner":    "user@example.com",
+		"replicas": float64(6),
+		"ratio":    float64(-1),
+		"canary":   true,
+		"region":   "eu",
+		"target": map[string]interface{}{
+			"cluster": "prod-1",
+			"zones":   []interface{}{"example"},
+		},
+	}
+	if !reflect.DeepEqual(args, want) {
+		t.Errorf("Unexpected example:\n got %v\nwant %v", args, want)
+	}
+	if result := callTool(t, wrapper.server, "deploy", args); result.IsError {
+		t.Errorf("Expected the example to pass, got %v", result.Content)
+	}
+
+	var docs strings.Builder
+	if err := wrapper.GenerateDocs(&docs, DocsMarkdown); err != nil {
+		t.Fatalf("GenerateDocs failed: %v", err)
+	}
+	if !strings.Contains(docs.String(), `"region": "eu"`) {
+		t.Errorf("Expected the example call in the docs, got:\n%s", docs.String())
+	}
+
+	if _, err := wrapper.ExampleCall("missing"); err == nil {
+		t.Error("Expected an error for an unknown tool")
+	}
+}
+
+func TestExampleCallDefaults(t *testing.T) {
+	wrapper := New(server.NewMCPServer("test", "1.0.0"))
+	handler := func(ctx context.Context, args interface{}) (interface{}, error) { return "ok", nil }
+	wrapper.RegisterSchema("search", "Search", map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"query": map[string]interface{}{"type": "string", "default": "*"},
+			"limit": map[string]interface{}{"type": "integer", "minimum": 10},
+		},
+		"required": []interface{}{"query", "limit"},
+	}, handler)
+
+	args, err := wrapper.ExampleCall("search")
+	if err != nil {
+		t.Fatalf("ExampleCall failed: %v", err)
+	}
+	if args["query"] != "*" || args["limit"] != float64(10) {
+		t.Errorf("Unexpected example: %v", args)
+	}
+}
+
+func TestExampleCallValidationMismatch(t *testing.T) {
+	type StrictArgs struct {
+		Code string `json:"code" jsonschema:"required" validate:"required,len=6"`
+	}
+	wrapper := New(server.NewMCPServer("test", "1.0.0"))
+	wrapper.Register("redeem", "Redeem a code", StrictArgs{}, func(ctx context.Context, args interface{}) (interface{}, error) {
+		return "ok", nil
+	})
+	if _, err := wrapper.ExampleCall("redeem"); err == nil || !strings.Contains(err.Error(), "does not validate") {
+		t.Errorf("Expected a validation error, got %v", err)
+	}
+}
+
+func TestPatternExample(t *testing.T) {
+	for _, pattern := range []string{
+		`^[a-z]+-[0-9]{2,4}$`,
+		`^(foo|bar)baz?$`,
+		`^\d{3}-\w+$`,
+		`[^0-9]x`,
+		`^$`,
+	} {
+		s, err := patternExample(pattern)
+		if err != nil {
+			t.Errorf("%s: %v", pattern, err)
+			continue
+		}
+		if !regexp.MustCompile(pattern).MatchString(s) {
+			t.Errorf("%s: %q does not match", pattern, s)
+		}
+	}
+}
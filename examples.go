@@ -0,0 +1,109 @@
+package mcpwrapper
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// fieldExamples returns the example= values and the |-separated examples=
+// values declared on a field, in tag order.
+func fieldExamples(field reflect.StructField) []string {
+	var values []string
+	for _, part := range strings.Split(field.Tag.Get("jsonschema"), ",") {
+		part = strings.TrimSpace(part)
+		switch {
+		case strings.HasPrefix(part, "example="):
+			values = append(values, strings.TrimPrefix(part, "example="))
+		case strings.HasPrefix(part, "examples="):
+			values = append(values, strings.Split(strings.TrimPrefix(part, "examples="), "|")...)
+		}
+	}
+	return values
+}
+
+// addExamples emits a field's examples into its schema property, both as the
+// "examples" keyword and in the description, which is what most clients show
+// to the model.
+func addExamples(prop map[string]interface{}, field reflect.StructField) error {
+	values := fieldExamples(field)
+	if len(values) == 0 {
+		return nil
+	}
+
+	examples, err := typedValues(values, field.Type)
+	if err != nil {
+		return err
+	}
+	prop["examples"] = examples
+
+	desc, _ := prop["description"].(string)
+	prop["description"] = withExampleText(desc, values)
+	return nil
+}
+
+// withExampleText appends "(e.g. a, b)" to a description or usage string.
+// The same text is used in schemas, docs and Cobra flag usage.
+func withExampleText(text string, examples []string) string {
+	suffix := fmt.Sprintf("e.g. %s", strings.Join(examples, ", "))
+	if text == "" {
+		return suffix
+	}
+	if strings.Contains(text, suffix) {
+		return text
+	}
+	return fmt.Sprintf("%s (%s)", text, suffix)
+}
+
+// WithExamples attaches example call payloads to the tool: structs of the
+// tool's argsType or maps keyed by JSON name. They are appended to the
+// description as JSON, which materially improves how accurately models call
+// complex tools, and listed in _meta as "examples". Each example must pass
+// the tool's validation, so examples cannot drift from the schema unnoticed.
+func WithExamples(examples ...interface{}) ToolOption {
+	return func(o *toolOptions) {
+		o.examples = append(o.examples, examples...)
+	}
+}
+
+// encodeExamples validates the examples against the tool and returns them as
+// JSON objects.
+func (w *Wrapper) encodeExamples(t *registeredTool, examples []interface{}) ([]map[string]interface{}, error) {
+	encoded := make([]map[string]interface{}, 0, len(examples))
+	for i, example := range examples {
+		data, err := json.Marshal(example)
+		if err != nil {
+			return nil, fmt.Errorf("example %d: %w", i+1, err)
+		}
+
+		args := reflect.New(reflect.TypeOf(t.argsType)).Interface()
+		if err := json.Unmarshal(data, args); err != nil {
+			return nil, fmt.Errorf("example %d: %w", i+1, err)
+		}
+		if err := w.validateArgs(t, args); err != nil {
+			return nil, fmt.Errorf("example %d: %w", i+1, err)
+		}
+
+		var fields map[string]interface{}
+		if err := json.Unmarshal(data, &fields); err != nil {
+			return nil, fmt.Errorf("example %d: must be an object: %w", i+1, err)
+		}
+		encoded = append(encoded, fields)
+	}
+	return encoded, nil
+}
+
+// withExamplesText appends the examples to a tool description, one JSON
+// object per line.
+func withExamplesText(description string, examples []map[string]interface{}) string {
+	var b strings.Builder
+	b.WriteString(description)
+	b.WriteString("\n\nExamples:")
+	for _, example := range examples {
+		data, _ := json.Marshal(example)
+		b.WriteString("\n")
+		b.Write(data)
+	}
+	return b.String()
+}
@@ -0,0 +1,93 @@
+package mcpwrapper
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func TestFieldExamplesInSchema(t *testing.T) {
+	type DeployArgs struct {
+		Image    string `json:"image" jsonschema:"description=Container image,example=nginx:1.27"`
+		Replicas int    `json:"replicas" jsonschema:"example=1,example=3"`
+		Region   string `json:"region"`
+		Zone     string `json:"zone" jsonschema:"examples=eu-west-1a|us-east-1b"`
+	}
+
+	schema, err := buildSchema(DeployArgs{})
+	if err != nil {
+		t.Fatalf("buildSchema failed: %v", err)
+	}
+
+	image := schema.Properties["image"].(map[string]interface{})
+	if !reflect.DeepEqual(image["examples"], []string{"nginx:1.27"}) {
+		t.Errorf("Unexpected image examples: %#v", image["examples"])
+	}
+	if image["description"] != "Container image (e.g. nginx:1.27)" {
+		t.Errorf("Unexpected image description: %q", image["description"])
+	}
+
+	replicas := schema.Properties["replicas"].(map[string]interface{})
+	if !reflect.DeepEqual(replicas["examples"], []interface{}{int64(1), int64(3)}) {
+		t.Errorf("Unexpected replicas examples: %#v", replicas["examples"])
+	}
+	if replicas["description"] != "e.g. 1, 3" {
+		t.Errorf("Unexpected replicas description: %q", replicas["description"])
+	}
+
+	region := schema.Properties["region"].(map[string]interface{})
+	if _, ok := region["examples"]; ok {
+		t.Error("Expected no examples on a field without any")
+	}
+
+	zone := schema.Properties["zone"].(map[string]interface{})
+	if !reflect.DeepEqual(zone["examples"], []string{"eu-west-1a", "us-east-1b"}) {
+		t.Errorf("Unexpected zone examples: %#v", zone["examples"])
+	}
+}
+
+func TestWithExamples(t *testing.T) {
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	wrapper := New(mcpServer)
+	handler := func(ctx context.Context, args interface{}) (interface{}, error) {
+		return &TestResult{Message: "ok"}, nil
+	}
+
+	err := wrapper.Register("greet", "Greet a user", TestArgs{}, handler, WithExamples(
+		TestArgs{Name: "Alice", Age: 30, Category: "A"},
+		map[string]interface{}{"name": "Bob", "age": 41, "category": "B", "email": "bob@example.com"},
+	))
+	if err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	tool := mcpServer.GetTool("greet").Tool
+	want := "Greet a user\n\nExamples:\n" +
+		`{"age":30,"category":"A","email":"","name":"Alice"}` + "\n" +
+		`{"age":41,"category":"B","email":"bob@example.com","name":"Bob"}`
+	if tool.Description != want {
+		t.Errorf("Unexpected description:\n%s", tool.Description)
+	}
+	if examples := tool.Meta.AdditionalFields["examples"].([]map[string]interface{}); len(examples) != 2 {
+		t.Errorf("Expected examples in _meta, got %v", tool.Meta.AdditionalFields)
+	}
+
+	err = wrapper.Register("stale", "Stale examples", TestArgs{}, handler, WithExamples(
+		map[string]interface{}{"name": "Al", "age": 30, "category": "D"},
+	))
+	if err == nil || !strings.Contains(err.Error(), "example 1") {
+		t.Errorf("Expected an invalid example error, got %v", err)
+	}
+}
+
+func TestInvalidExampleForType(t *testing.T) {
+	type BadArgs struct {
+		Replicas int `json:"replicas" jsonschema:"example=many"`
+	}
+	if _, err := buildSchema(BadArgs{}); err == nil {
+		t.Error("Expected an error for a non-integer example on an integer field")
+	}
+}
@@ -0,0 +1,290 @@
+package mcpwrapper
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// ExecSpec describes a command run once per call. Args and Env entries are
+// text/template templates rendered with the call's arguments, keyed by their
+// JSON names; an argument the client omitted renders as "". An Args entry
+// that renders to "" is dropped, so optional flags can be written as
+// "{{if .force}}--force{{end}}". Each entry is passed to the command as one
+// argument, never through a shell. Prompts answer the command's interactive
+// prompts; without them its stdin is empty.
+type ExecSpec struct {
+	Command string        `json:"command" yaml:"command"`
+	Args    []string      `json:"args,omitempty" yaml:"args,omitempty"`
+	Env     []string      `json:"env,omitempty" yaml:"env,omitempty"`
+	Timeout time.Duration `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+	Prompts []Prompt      `json:"prompts,omitempty" yaml:"prompts,omitempty"`
+	// LongRunning registers companion tools to start a command in the
+	// background and manage it, instead of one tool that waits for it.
+	LongRunning bool `json:"long_running,omitempty" yaml:"long_running,omitempty"`
+}
+
+// ExecResult is the result of a command tool: RegisterExec, and commands
+// registered from Cobra, urfave/cli or kong. A dry run sets DryRun and the
+// Command line it would have run instead.
+type ExecResult struct {
+	ExitCode   int    `json:"exit_code"`
+	Stdout     string `json:"stdout"`
+	Stderr     string `json:"stderr"`
+	DurationMs int64  `json:"duration_ms"`
+	DryRun     bool   `json:"dry_run,omitempty"`
+	Command    string `json:"command,omitempty"`
+}
+
+// ExitError reports a command that exited with a nonzero code. The tool's
+// error result carries Result in its structured content, so the client sees
+// the output of the failed command, and the message includes its stderr.
+type ExitError struct {
+	Result *ExecResult
+	// Err is the error an in-process command returned.
+	Err error
+}
+
+func (e *ExitError) Error() string {
+	msg := fmt.Sprintf("exit code %d", e.Result.ExitCode)
+	if e.Err != nil {
+		msg = e.Err.Error()
+	}
+	if stderr := strings.TrimSpace(e.Result.Stderr); stderr != "" {
+		msg += "\nstderr: " + stderr
+	}
+	return msg
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+// commandExit reports the error of an in-process command as an ExitError
+// with result. An error with an ExitCode method, such as those of cli.Exit,
+// sets the exit code; any other exits with 1.
+func commandExit(result *ExecResult, err error) error {
+	if err == nil {
+		return nil
+	}
+	result.ExitCode = 1
+	var coder interface{ ExitCode() int }
+	if errors.As(err, &coder) && coder.ExitCode() != 0 {
+		result.ExitCode = coder.ExitCode()
+	}
+	return &ExitError{Result: result, Err: err}
+}
+
+// RegisterExec registers a tool that runs a command, wrapping a CLI without
+// handler code. Arguments are bound to argsType and validated as for
+// Register, then rendered into spec's templates by JSON name. The result is
+// an ExecResult; a nonzero exit code is an ExitError.
+func (w *Wrapper) RegisterExec(name, description string, argsType interface{}, spec ExecSpec, opts ...ToolOption) error {
+	schema, err := w.schema(argsType)
+	if err != nil {
+		return fmt.Errorf("failed to build schema for tool %s: %w", name, err)
+	}
+
+	templates, err := parseExecSpec(spec, schemaProperties(schema))
+	if err != nil {
+		return fmt.Errorf("invalid exec spec for tool %s: %w", name, err)
+	}
+	if spec.LongRunning {
+		return w.registerProcess(name, description, argsType, schema, templates, opts)
+	}
+
+	handler, err := execHandler(templates)
+	if err != nil {
+		return fmt.Errorf("invalid exec spec for tool %s: %w", name, err)
+	}
+	return w.register(name, description, argsType, schema, handler, opts)
+}
+
+// execTemplates is an ExecSpec with its templates parsed. properties are
+// the argument names templates may refer to.
+type execTemplates struct {
+	spec       ExecSpec
+	args       []*template.Template
+	env        []*template.Template
+	properties []string
+}
+
+func parseExecSpec(spec ExecSpec, properties []string) (*execTemplates, error) {
+	if spec.Command == "" {
+		return nil, fmt.Errorf("command is required")
+	}
+	args, err := parseTemplates("args", spec.Args)
+	if err != nil {
+		return nil, err
+	}
+	env, err := parseTemplates("env", spec.Env)
+	if err != nil {
+		return nil, err
+	}
+	return &execTemplates{spec: spec, args: args, env: env, properties: properties}, nil
+}
+
+// command renders the templates with a call's arguments into a command
+// bound to ctx, with the call's environment and working directory. It also
+// returns the template data.
+func (t *execTemplates) command(ctx context.Context, a interface{}) (*exec.Cmd, map[string]interface{}, error) {
+	data := templateData(a, t.properties)
+	argv, err := renderTemplates(t.args, data, true)
+	if err != nil {
+		return nil, nil, err
+	}
+	extraEnv, err := renderTemplates(t.env, data, false)
+	if err != nil {
+		return nil, nil, err
+	}
+	cmd := exec.CommandContext(ctx, t.spec.Command, argv...)
+	cmd.Env = append(append(os.Environ(), callEnviron(ctx)...), extraEnv...)
+	cmd.Dir = WorkDir(ctx)
+	return cmd, data, nil
+}
+
+// execHandler runs a command for every call.
+func execHandler(t *execTemplates) (Handler, error) {
+	spec := t.spec
+	prompts, err := compilePrompts(spec.Prompts)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(ctx context.Context, a interface{}) (interface{}, error) {
+		if spec.Timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, spec.Timeout)
+			defer cancel()
+		}
+
+		// An unanswerable prompt kills the command.
+		ctx, abort := context.WithCancel(ctx)
+		defer abort()
+
+		cmd, data, err := t.command(ctx, a)
+		if err != nil {
+			return nil, err
+		}
+		var stdout, stderr bytes.Buffer
+		var responder *promptResponder
+		if len(prompts) > 0 {
+			stdin, err := cmd.StdinPipe()
+			if err != nil {
+				return nil, fmt.Errorf("failed to run %s: %w", spec.Command, err)
+			}
+			responder = newPromptResponder(ctx, prompts, data, stdin, abort)
+			cmd.Stdout = responder.wrap(&stdout)
+			cmd.Stderr = responder.wrap(&stderr)
+		} else {
+			cmd.Stdout = &stdout
+			cmd.Stderr = &stderr
+		}
+
+		start := time.Now()
+		err = cmd.Run()
+		if responder != nil && responder.Err() != nil {
+			return nil, responder.Err()
+		}
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("%s timed out after %s", spec.Command, spec.Timeout)
+		}
+		var exitErr *exec.ExitError
+		if err != nil && !errors.As(err, &exitErr) {
+			return nil, fmt.Errorf("failed to run %s: %w", spec.Command, err)
+		}
+
+		result := &ExecResult{
+			ExitCode:   cmd.ProcessState.ExitCode(),
+			Stdout:     stdout.String(),
+			Stderr:     stderr.String(),
+			DurationMs: time.Since(start).Milliseconds(),
+		}
+		if result.ExitCode != 0 {
+			return nil, &ExitError{Result: result}
+		}
+		return result, nil
+	}, nil
+}
+
+var templateFuncs = template.FuncMap{
+	"json": func(v interface{}) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+}
+
+func parseTemplate(name, text string) (*template.Template, error) {
+	tmpl, err := template.New(name).Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid template %s: %w", name, err)
+	}
+	return tmpl, nil
+}
+
+func parseTemplates(name string, texts []string) ([]*template.Template, error) {
+	templates := make([]*template.Template, 0, len(texts))
+	for i, text := range texts {
+		tmpl, err := parseTemplate(fmt.Sprintf("%s[%d]", name, i), text)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, tmpl)
+	}
+	return templates, nil
+}
+
+func renderTemplate(tmpl *template.Template, data map[string]interface{}) (string, error) {
+	var buf strings.Builder
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to render %s: %w", tmpl.Name(), err)
+	}
+	return buf.String(), nil
+}
+
+func renderTemplates(templates []*template.Template, data map[string]interface{}, dropEmpty bool) ([]string, error) {
+	out := make([]string, 0, len(templates))
+	for _, tmpl := range templates {
+		s, err := renderTemplate(tmpl, data)
+		if err != nil {
+			return nil, err
+		}
+		if s == "" && dropEmpty {
+			continue
+		}
+		out = append(out, s)
+	}
+	return out, nil
+}
+
+// templateData keys the arguments by JSON name. Omitted properties are set
+// to "" so templates never render "<no value>".
+func templateData(args interface{}, properties []string) map[string]interface{} {
+	data := jsonFields(args)
+	if data == nil {
+		data = make(map[string]interface{})
+	}
+	for _, name := range properties {
+		if data[name] == nil {
+			data[name] = ""
+		}
+	}
+	return data
+}
+
+func schemaProperties(schema *mcp.ToolInputSchema) []string {
+	names := make([]string, 0, len(schema.Properties))
+	for name := range schema.Properties {
+		names = append(names, name)
+	}
+	return names
+}
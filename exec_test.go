@@ -0,0 +1,118 @@
+package mcpwrapper
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+type GrepArgs struct {
+	Pattern    string `json:"pattern" jsonschema:"required" validate:"required"`
+	IgnoreCase bool   `json:"ignore_case,omitempty"`
+	Context    int    `json:"context,omitempty" validate:"gte=0"`
+}
+
+func TestRegisterExec(t *testing.T) {
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	wrapper := New(mcpServer)
+
+	err := wrapper.RegisterExec("echo_args", "Print the rendered arguments", GrepArgs{}, ExecSpec{
+		Command: "sh",
+		Args: []string{
+			"-c", `printf '%s|' "$@"; printf "$MODE"`, "sh",
+			"{{if .ignore_case}}-i{{end}}",
+			"{{if .context}}--context={{.context}}{{end}}",
+			"{{.pattern}}",
+		},
+		Env: []string{"MODE={{if .ignore_case}}loose{{else}}strict{{end}}"},
+	})
+	if err != nil {
+		t.Fatalf("RegisterExec failed: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		args map[string]interface{}
+		want string
+	}{
+		{"required only", map[string]interface{}{"pattern": "a b"}, "a b|strict"},
+		{"optional flags", map[string]interface{}{"pattern": "x", "ignore_case": true, "context": 2}, "-i|--context=2|x|loose"},
+		{"shell metacharacters stay one argument", map[string]interface{}{"pattern": "$(rm -rf /); echo"}, "$(rm -rf /); echo|strict"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var result ExecResult
+			text := callTool(t, mcpServer, "echo_args", tt.args).Content[0].(mcp.TextContent).Text
+			if err := json.Unmarshal([]byte(text), &result); err != nil {
+				t.Fatalf("Unexpected result %s: %v", text, err)
+			}
+			if result.Stdout != tt.want || result.ExitCode != 0 {
+				t.Errorf("Expected stdout %q, got %+v", tt.want, result)
+			}
+		})
+	}
+
+	result := callTool(t, mcpServer, "echo_args", map[string]interface{}{"pattern": "x", "context": -1})
+	if !result.IsError {
+		t.Error("Expected validate tags to apply before the command runs")
+	}
+}
+
+func TestRegisterExecTimeout(t *testing.T) {
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	wrapper := New(mcpServer)
+
+	err := wrapper.RegisterExec("slow", "Sleep", GrepArgs{}, ExecSpec{
+		Command: "sleep",
+		Args:    []string{"5"},
+		Timeout: 50 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("RegisterExec failed: %v", err)
+	}
+
+	result := callTool(t, mcpServer, "slow", map[string]interface{}{"pattern": "x"})
+	if !result.IsError || !strings.Contains(result.Content[0].(mcp.TextContent).Text, "sleep timed out after 50ms") {
+		t.Errorf("Expected a timeout error, got %+v", result)
+	}
+}
+
+func TestRegisterExecExitCode(t *testing.T) {
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	wrapper := New(mcpServer)
+
+	err := wrapper.RegisterExec("fail", "Fail", GrepArgs{}, ExecSpec{
+		Command: "sh",
+		Args:    []string{"-c", `echo partial; echo "no such pattern: $1" >&2; exit 2`, "sh", "{{.pattern}}"},
+	})
+	if err != nil {
+		t.Fatalf("RegisterExec failed: %v", err)
+	}
+
+	result := callTool(t, mcpServer, "fail", map[string]interface{}{"pattern": "x"})
+	if !result.IsError || !strings.Contains(result.Content[0].(mcp.TextContent).Text, "stderr: no such pattern: x") {
+		t.Errorf("Expected an error result with stderr, got %+v", result)
+	}
+	details := result.StructuredContent.(map[string]interface{})
+	if details["exit_code"] != 2 || details["stdout"] != "partial\n" || details["stderr"] != "no such pattern: x\n" {
+		t.Errorf("Unexpected exit details: %v", details)
+	}
+	if _, ok := details["duration_ms"].(int64); !ok {
+		t.Errorf("Expected duration_ms, got %v", details)
+	}
+}
+
+func TestRegisterExecInvalidSpec(t *testing.T) {
+	wrapper := New(server.NewMCPServer("test", "1.0.0"))
+
+	if err := wrapper.RegisterExec("a", "", GrepArgs{}, ExecSpec{}); err == nil {
+		t.Error("Expected an error without a command")
+	}
+	if err := wrapper.RegisterExec("b", "", GrepArgs{}, ExecSpec{Command: "ls", Args: []string{"{{.pattern"}}); err == nil {
+		t.Error("Expected an error for an invalid template")
+	}
+}
@@ -0,0 +1,114 @@
+package mcpwrapper
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// FeatureGate decides whether a tool is enabled, for example by asking a
+// feature flag service. It is consulted whenever tools are listed and on
+// every call, with the tool name clients see.
+type FeatureGate interface {
+	Enabled(ctx context.Context, tool string) bool
+}
+
+// FeatureGateFunc adapts a function to a FeatureGate.
+type FeatureGateFunc func(ctx context.Context, tool string) bool
+
+func (f FeatureGateFunc) Enabled(ctx context.Context, tool string) bool {
+	return f(ctx, tool)
+}
+
+func WithFeatureGate(gate FeatureGate) Option {
+	return func(w *Wrapper) {
+		w.features.gate = gate
+	}
+}
+
+// features holds the tools disabled with SetEnabled and the optional gate.
+// A tool is enabled when it is not disabled and the gate, if any, agrees.
+type features struct {
+	gate FeatureGate
+
+	mu       sync.RWMutex
+	disabled map[string]bool
+}
+
+func newFeatures() *features {
+	return &features{disabled: make(map[string]bool)}
+}
+
+func (f *features) enabled(ctx context.Context, name string) bool {
+	f.mu.RLock()
+	disabled := f.disabled[name]
+	f.mu.RUnlock()
+	if disabled {
+		return false
+	}
+	return f.gate == nil || f.gate.Enabled(ctx, name)
+}
+
+func (f *features) filter(ctx context.Context, tools []mcp.Tool) []mcp.Tool {
+	enabled := make([]mcp.Tool, 0, len(tools))
+	for _, tool := range tools {
+		if f.enabled(ctx, tool.Name) {
+			enabled = append(enabled, tool)
+		}
+	}
+	return enabled
+}
+
+// installFeatures adds the features filter to the server's tool filters.
+// The server applies every filter in turn, so filters given with
+// server.WithToolFilter keep working alongside SetEnabled.
+func (w *Wrapper) installFeatures() {
+	server.WithToolFilter(w.features.filter)(w.server)
+}
+
+// SetEnabled turns a tool off or back on while the server is running. A
+// disabled tool is not listed and its calls are rejected. Enabling a tool
+// does not override a FeatureGate that disables it. Clients are sent
+// notifications/tools/list_changed when the setting changes.
+func (w *Wrapper) SetEnabled(name string, enabled bool) {
+	w.features.mu.Lock()
+	changed := w.features.disabled[name] == enabled
+	if enabled {
+		delete(w.features.disabled, name)
+	} else {
+		w.features.disabled[name] = true
+	}
+	w.features.mu.Unlock()
+
+	if changed {
+		w.logger.Info("tool availability changed", "tool", name, "enabled", enabled)
+		w.NotifyToolsChanged()
+	}
+}
+
+// Enabled reports whether a tool is currently enabled.
+func (w *Wrapper) Enabled(ctx context.Context, name string) bool {
+	return w.features.enabled(ctx, name)
+}
+
+// NotifyToolsChanged sends notifications/tools/list_changed to all clients,
+// so they list the tools again. Call it when a FeatureGate's answers change.
+func (w *Wrapper) NotifyToolsChanged() {
+	w.server.SendNotificationToAllClients(mcp.MethodNotificationToolsListChanged, nil)
+}
+
+// checkEnabled checks the name the client called, which is the name the
+// tool was listed under: a version's base name or an alias.
+func (w *Wrapper) checkEnabled(ctx context.Context, t *registeredTool, request mcp.CallToolRequest) error {
+	name := request.Params.Name
+	if name == "" {
+		name = t.name
+	}
+	if !w.features.enabled(ctx, name) {
+		return fmt.Errorf("tool %s is disabled", name)
+	}
+	return nil
+}
@@ -0,0 +1,157 @@
+package mcpwrapper
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func listedTools(t *testing.T, wrapper *Wrapper, ctx context.Context) []string {
+	t.Helper()
+	response := wrapper.HandleMessage(ctx, json.RawMessage(`{"jsonrpc": "2.0", "id": 1, "method": "tools/list"}`))
+	var names []string
+	for _, tool := range response.(mcp.JSONRPCResponse).Result.(mcp.ListToolsResult).Tools {
+		names = append(names, tool.Name)
+	}
+	return names
+}
+
+func TestSetEnabled(t *testing.T) {
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	wrapper := New(mcpServer)
+
+	handler := func(ctx context.Context, args interface{}) (interface{}, error) {
+		return &TestResult{Message: "ok"}, nil
+	}
+	for _, name := range []string{"drop_table", "list_tables"} {
+		if err := wrapper.Register(name, "Test tool", TestArgs{}, handler); err != nil {
+			t.Fatalf("Register failed: %v", err)
+		}
+	}
+
+	session := &notifyingSession{id: "session-1", notifications: make(chan mcp.JSONRPCNotification, 10)}
+	ctx := mcpServer.WithContext(context.Background(), session)
+	if err := mcpServer.RegisterSession(ctx, session); err != nil {
+		t.Fatalf("RegisterSession failed: %v", err)
+	}
+
+	wrapper.SetEnabled("drop_table", false)
+	select {
+	case n := <-session.notifications:
+		if n.Method != mcp.MethodNotificationToolsListChanged {
+			t.Errorf("Unexpected notification %s", n.Method)
+		}
+	default:
+		t.Error("Expected a list_changed notification")
+	}
+
+	wrapper.SetEnabled("drop_table", false)
+	if len(session.notifications) != 0 {
+		t.Error("Expected no notification when nothing changed")
+	}
+
+	if names := listedTools(t, wrapper, ctx); len(names) != 1 || names[0] != "list_tables" {
+		t.Errorf("Expected only list_tables to be listed, got %v", names)
+	}
+
+	request := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name:      "drop_table",
+			Arguments: map[string]interface{}{"name": "Alice", "age": 30, "category": "A"},
+		},
+	}
+	result, err := mcpServer.GetTool("drop_table").Handler(ctx, request)
+	if err != nil {
+		t.Fatalf("Handler returned error: %v", err)
+	}
+	if !result.IsError || result.Content[0].(mcp.TextContent).Text != "tool drop_table is disabled" {
+		t.Errorf("Expected the call to be rejected, got %+v", result)
+	}
+
+	wrapper.SetEnabled("drop_table", true)
+	if len(session.notifications) != 1 {
+		t.Error("Expected a list_changed notification on re-enable")
+	}
+	if result, err := mcpServer.GetTool("drop_table").Handler(ctx, request); err != nil || result.IsError {
+		t.Fatalf("Expected the re-enabled tool to run: %v %v", err, result)
+	}
+}
+
+func TestSetEnabledAlias(t *testing.T) {
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	wrapper := New(mcpServer)
+	wrapper.Register("greet_user", "Greet a user", TestArgs{}, func(ctx context.Context, args interface{}) (interface{}, error) {
+		return &TestResult{Message: "ok"}, nil
+	})
+	if err := wrapper.Alias("greet", "greet_user"); err != nil {
+		t.Fatalf("Alias failed: %v", err)
+	}
+
+	wrapper.SetEnabled("greet", false)
+	args := map[string]interface{}{"name": "Alice", "age": 30, "category": "A"}
+	if result := callTool(t, mcpServer, "greet", args); !result.IsError || resultText(result) != "tool greet is disabled" {
+		t.Errorf("Expected calls to the disabled alias to be rejected, got %v", result.Content)
+	}
+	if result := callTool(t, mcpServer, "greet_user", args); result.IsError {
+		t.Errorf("Expected the target to stay enabled, got %v", result.Content)
+	}
+}
+
+func TestSetEnabledKeepsServerFilters(t *testing.T) {
+	mcpServer := server.NewMCPServer("test", "1.0.0", server.WithToolFilter(func(ctx context.Context, tools []mcp.Tool) []mcp.Tool {
+		var visible []mcp.Tool
+		for _, tool := range tools {
+			if tool.Name != "internal" {
+				visible = append(visible, tool)
+			}
+		}
+		return visible
+	}))
+	wrapper := New(mcpServer)
+	for _, name := range []string{"internal", "search", "beta"} {
+		wrapper.Register(name, "Test tool", TestArgs{}, func(ctx context.Context, args interface{}) (interface{}, error) {
+			return &TestResult{Message: "ok"}, nil
+		})
+	}
+
+	wrapper.SetEnabled("beta", false)
+	if names := listedTools(t, wrapper, context.Background()); len(names) != 1 || names[0] != "search" {
+		t.Errorf("Expected both filters to apply, got %v", names)
+	}
+}
+
+func TestWithFeatureGate(t *testing.T) {
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	flags := map[string]bool{"beta_search": false}
+	wrapper := New(mcpServer, WithFeatureGate(FeatureGateFunc(func(ctx context.Context, tool string) bool {
+		enabled, ok := flags[tool]
+		return !ok || enabled
+	})))
+
+	handler := func(ctx context.Context, args interface{}) (interface{}, error) {
+		return &TestResult{Message: "ok"}, nil
+	}
+	for _, name := range []string{"beta_search", "search"} {
+		if err := wrapper.Register(name, "Test tool", TestArgs{}, handler); err != nil {
+			t.Fatalf("Register failed: %v", err)
+		}
+	}
+
+	ctx := context.Background()
+	if names := listedTools(t, wrapper, ctx); len(names) != 1 || names[0] != "search" {
+		t.Errorf("Expected the gate to hide beta_search, got %v", names)
+	}
+
+	wrapper.SetEnabled("beta_search", true)
+	if wrapper.Enabled(ctx, "beta_search") {
+		t.Error("Expected SetEnabled not to override the gate")
+	}
+
+	flags["beta_search"] = true
+	if names := listedTools(t, wrapper, ctx); len(names) != 2 {
+		t.Errorf("Expected both tools once the flag is on, got %v", names)
+	}
+}
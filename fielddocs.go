@@ -0,0 +1,32 @@
+package mcpwrapper
+
+import (
+	"reflect"
+	"sync"
+)
+
+// fieldDocs holds field descriptions taken from Go doc comments, keyed by
+// struct type and then field name. It is global because generated code
+// registers it from init functions, before any Wrapper exists.
+var fieldDocs sync.Map // reflect.Type -> map[string]string
+
+// RegisterFieldDocs sets descriptions for the fields of argsType, keyed by Go
+// field name. A field's description= tag takes precedence. mcpwrapper-gen
+// calls it with the fields' doc comments, so they don't have to be repeated
+// in tags; register before the tools that use argsType.
+func RegisterFieldDocs(argsType interface{}, docs map[string]string) {
+	t := reflect.TypeOf(argsType)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	fieldDocs.Store(t, docs)
+}
+
+func fieldDoc(t reflect.Type, field string) (string, bool) {
+	docs, ok := fieldDocs.Load(t)
+	if !ok {
+		return "", false
+	}
+	doc, ok := docs.(map[string]string)[field]
+	return doc, ok && doc != ""
+}
@@ -0,0 +1,34 @@
+package mcpwrapper
+
+import "testing"
+
+type RepoArgs struct {
+	// Name of the repository to clone.
+	Name   string `json:"name"`
+	Branch string `json:"branch" jsonschema:"description=Branch to check out,example=main"`
+	Depth  int    `json:"depth" jsonschema:"example=1"`
+}
+
+func TestRegisterFieldDocs(t *testing.T) {
+	RegisterFieldDocs(&RepoArgs{}, map[string]string{
+		"Name":   "Name of the repository to clone",
+		"Branch": "Ignored: the tag wins",
+		"Depth":  "History depth",
+	})
+
+	schema, err := buildSchema(RepoArgs{})
+	if err != nil {
+		t.Fatalf("buildSchema failed: %v", err)
+	}
+
+	tests := map[string]string{
+		"name":   "Name of the repository to clone",
+		"branch": "Branch to check out (e.g. main)",
+		"depth":  "History depth (e.g. 1)",
+	}
+	for field, want := range tests {
+		if got := schema.Properties[field].(map[string]interface{})["description"]; got != want {
+			t.Errorf("%s: expected description %q, got %v", field, want, got)
+		}
+	}
+}
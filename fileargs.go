@@ -0,0 +1,239 @@
+package mcpwrapper
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+)
+
+// DefaultMaxFileSize bounds the files FileArg arguments may hold by
+// default.
+const DefaultMaxFileSize = 10 << 20
+
+// FileArg is an argument holding a file: either the path of a file on the
+// server, or its base64 content with a filename. Before the handler runs,
+// content is written to a temporary file, removed when the call returns,
+// so the handler always reads the file at Path. Paths must be inside the
+// allowed directories, as set with WithAllowedPaths.
+type FileArg struct {
+	Path     string `json:"path,omitempty"`
+	Content  string `json:"content,omitempty"`
+	Filename string `json:"filename,omitempty"`
+
+	// Set before the handler runs.
+	MIMEType string `json:"-"`
+	Size     int64  `json:"-"`
+}
+
+var fileArgType = reflect.TypeOf(FileArg{})
+
+// fileArgSchema is the schema of a FileArg argument.
+func fileArgSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type":        "object",
+		"description": "A file: the path of a file on the server, or its base64 content and a filename",
+		"properties": map[string]interface{}{
+			"path":     map[string]interface{}{"type": "string", "description": "Path of a file on the server"},
+			"content":  map[string]interface{}{"type": "string", "contentEncoding": "base64", "description": "Content of the file, base64-encoded"},
+			"filename": map[string]interface{}{"type": "string", "description": "Name of the file sent as content"},
+		},
+		"oneOf": []interface{}{
+			map[string]interface{}{"required": []interface{}{"path"}},
+			map[string]interface{}{"required": []interface{}{"content"}},
+		},
+	}
+}
+
+// WithMaxFileSize bounds the size of the files the tool's FileArg
+// arguments may hold. The default is DefaultMaxFileSize.
+func WithMaxFileSize(bytes int64) ToolOption {
+	return func(o *toolOptions) {
+		o.ensureWorkspace().maxFileSize = bytes
+	}
+}
+
+// hasFileArgs reports whether t has FileArg fields anywhere.
+func hasFileArgs(t reflect.Type, seen map[reflect.Type]bool) bool {
+	t = derefType(t)
+	if t == nil {
+		return false
+	}
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array:
+		return hasFileArgs(t.Elem(), seen)
+	case reflect.Struct:
+		if t == fileArgType {
+			return true
+		}
+		if seen[t] {
+			return false
+		}
+		seen[t] = true
+		for i := 0; i < t.NumField(); i++ {
+			if t.Field(i).IsExported() && hasFileArgs(t.Field(i).Type, seen) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// materializeFiles prepares every FileArg in args for the handler. The
+// returned function removes the files written for content.
+func (ws *workspace) materializeFiles(args interface{}, base string, roots []string) (func(), error) {
+	limit := ws.maxFileSize
+	if limit <= 0 {
+		limit = DefaultMaxFileSize
+	}
+	var tmpDir string
+	cleanup := func() {
+		if tmpDir != "" {
+			os.RemoveAll(tmpDir)
+		}
+	}
+
+	err := visitFileArgs(reflect.ValueOf(args), "", func(name string, f *FileArg) error {
+		switch {
+		case f.Path == "" && f.Content == "":
+			return nil
+		case f.Path != "" && f.Content != "":
+			return Errorf(CodeInvalidInput, "%s: give either path or content, not both", name)
+		case f.Path != "":
+			return f.open(name, base, roots, limit)
+		}
+		if tmpDir == "" {
+			dir, err := os.MkdirTemp("", "mcpwrapper-files-")
+			if err != nil {
+				return fmt.Errorf("failed to create directory for files: %w", err)
+			}
+			tmpDir = dir
+		}
+		return f.materialize(name, tmpDir, limit)
+	})
+	if err != nil {
+		cleanup()
+		return nil, err
+	}
+	return cleanup, nil
+}
+
+func (f *FileArg) open(name, base string, roots []string, limit int64) error {
+	path := resolvePath(base, f.Path)
+	if !insideRoots(path, base, roots) {
+		return Errorf(CodeInvalidInput, "%s: path %q is outside the allowed directories", name, f.Path)
+	}
+	info, err := os.Stat(path)
+	if err != nil || !info.Mode().IsRegular() {
+		return Errorf(CodeInvalidInput, "%s: %q is not a readable file", name, f.Path)
+	}
+	if info.Size() > limit {
+		return Errorf(CodeTooLarge, "%s: file is %d bytes, over the limit of %d bytes", name, info.Size(), limit)
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return Errorf(CodeInvalidInput, "%s: %q is not a readable file", name, f.Path)
+	}
+	defer file.Close()
+	head := make([]byte, 512)
+	n, _ := io.ReadFull(file, head)
+
+	if f.Filename == "" {
+		f.Filename = filepath.Base(path)
+	}
+	f.Path = path
+	f.Size = info.Size()
+	f.MIMEType = sniffMIMEType(head[:n], f.Filename)
+	return nil
+}
+
+func (f *FileArg) materialize(name, dir string, limit int64) error {
+	if int64(base64.StdEncoding.DecodedLen(len(f.Content))) > limit+2 {
+		return Errorf(CodeTooLarge, "%s: file is over the limit of %d bytes", name, limit)
+	}
+	data, err := base64.StdEncoding.DecodeString(strings.TrimSpace(f.Content))
+	if err != nil {
+		return Errorf(CodeInvalidInput, "%s: content is not valid base64: %v", name, err)
+	}
+	if int64(len(data)) > limit {
+		return Errorf(CodeTooLarge, "%s: file is %d bytes, over the limit of %d bytes", name, len(data), limit)
+	}
+
+	filename := filepath.Base(filepath.Clean("/" + f.Filename))
+	if filename == "/" || filename == "." {
+		filename = "file"
+	}
+	// Each file gets its own directory so that equal names don't clash.
+	fileDir, err := os.MkdirTemp(dir, "")
+	if err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", name, err)
+	}
+	path := filepath.Join(fileDir, filename)
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+
+	f.Path = path
+	f.Content = ""
+	f.Filename = filename
+	f.Size = int64(len(data))
+	f.MIMEType = sniffMIMEType(data, filename)
+	return nil
+}
+
+// sniffMIMEType detects the type from the content, falling back to the
+// extension when the content only tells that it is text or binary.
+func sniffMIMEType(head []byte, filename string) string {
+	detected := http.DetectContentType(head)
+	if detected == "application/octet-stream" || strings.HasPrefix(detected, "text/plain") {
+		if byExt := mime.TypeByExtension(filepath.Ext(filename)); byExt != "" {
+			return byExt
+		}
+	}
+	return detected
+}
+
+// visitFileArgs calls visit with every FileArg reachable from v through
+// struct fields, pointers and slices, named by its argument path.
+func visitFileArgs(v reflect.Value, name string, visit func(name string, f *FileArg) error) error {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return visitFileArgs(v.Elem(), name, visit)
+	case reflect.Struct:
+		if v.Type() == fileArgType {
+			if !v.CanAddr() {
+				return nil
+			}
+			return visit(name, v.Addr().Interface().(*FileArg))
+		}
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			fieldName := name
+			if !field.Anonymous || field.Tag.Get("json") != "" {
+				fieldName = strings.TrimPrefix(name+"."+jsonFieldName(field), ".")
+			}
+			if err := visitFileArgs(v.Field(i), fieldName, visit); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if err := visitFileArgs(v.Index(i), fmt.Sprintf("%s[%d]", name, i), visit); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
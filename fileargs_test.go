@@ -0,0 +1,143 @@
+package mcpwrapper
+
+import (
+	"context"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/server"
+)
+
+type UploadArgs struct {
+	Document FileArg   `json:"document"`
+	Extra    []FileArg `json:"extra,omitempty"`
+}
+
+type UploadResult struct {
+	Path     string `json:"path"`
+	MIMEType string `json:"mime_type"`
+	Size     int64  `json:"size"`
+	Content  string `json:"content"`
+}
+
+func newUploadServer(t *testing.T, dir string, seen *[]string) *server.MCPServer {
+	t.Helper()
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	wrapper := New(mcpServer)
+	err := wrapper.Register("upload", "Read a file", UploadArgs{}, func(ctx context.Context, args interface{}) (interface{}, error) {
+		doc := args.(*UploadArgs).Document
+		data, err := os.ReadFile(doc.Path)
+		if err != nil {
+			return nil, err
+		}
+		*seen = append(*seen, doc.Path)
+		return UploadResult{Path: doc.Path, MIMEType: doc.MIMEType, Size: doc.Size, Content: string(data)}, nil
+	}, WithWorkDir(dir), WithMaxFileSize(64))
+	if err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	return mcpServer
+}
+
+func TestFileArgSchema(t *testing.T) {
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	wrapper := New(mcpServer)
+	wrapper.Register("upload", "Read a file", UploadArgs{}, func(ctx context.Context, args interface{}) (interface{}, error) {
+		return nil, nil
+	})
+
+	props := mcpServer.GetTool("upload").Tool.InputSchema.Properties
+	doc, _ := props["document"].(map[string]interface{})
+	fields, _ := doc["properties"].(map[string]interface{})
+	content, _ := fields["content"].(map[string]interface{})
+	if doc["type"] != "object" || fields["path"] == nil || content["contentEncoding"] != "base64" || doc["oneOf"] == nil {
+		t.Errorf("Unexpected FileArg schema: %v", doc)
+	}
+	extra, _ := props["extra"].(map[string]interface{})
+	if items, _ := extra["items"].(map[string]interface{}); items["oneOf"] == nil {
+		t.Errorf("Expected a list of FileArg schemas, got %v", extra)
+	}
+}
+
+func TestFileArgContent(t *testing.T) {
+	var seen []string
+	mcpServer := newUploadServer(t, t.TempDir(), &seen)
+
+	var out UploadResult
+	decodeResult(t, callTool(t, mcpServer, "upload", map[string]interface{}{
+		"document": map[string]interface{}{
+			"content":  base64.StdEncoding.EncodeToString([]byte("a,b\n1,2\n")),
+			"filename": "../../report.csv",
+		},
+	}), &out)
+	if out.Content != "a,b\n1,2\n" || out.Size != 8 || filepath.Base(out.Path) != "report.csv" {
+		t.Errorf("Unexpected file: %+v", out)
+	}
+	if !strings.HasPrefix(out.MIMEType, "text/csv") {
+		t.Errorf("Expected text/csv from the extension, got %q", out.MIMEType)
+	}
+	if _, err := os.Stat(seen[0]); !os.IsNotExist(err) {
+		t.Errorf("Expected the temporary file to be removed after the call, got %v", err)
+	}
+
+	png := []byte("\x89PNG\r\n\x1a\n0000")
+	decodeResult(t, callTool(t, mcpServer, "upload", map[string]interface{}{
+		"document": map[string]interface{}{"content": base64.StdEncoding.EncodeToString(png)},
+	}), &out)
+	if out.MIMEType != "image/png" || filepath.Base(out.Path) != "file" {
+		t.Errorf("Expected a sniffed image/png named file, got %+v", out)
+	}
+}
+
+func TestFileArgPath(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("hello"), 0o644)
+	os.WriteFile(filepath.Join(dir, "big.txt"), []byte(strings.Repeat("x", 65)), 0o644)
+	os.Mkdir(filepath.Join(dir, "sub"), 0o755)
+	var seen []string
+	mcpServer := newUploadServer(t, dir, &seen)
+
+	var out UploadResult
+	decodeResult(t, callTool(t, mcpServer, "upload", map[string]interface{}{
+		"document": map[string]interface{}{"path": "notes.txt"},
+	}), &out)
+	if out.Content != "hello" || !filepath.IsAbs(out.Path) || out.Size != 5 || !strings.HasPrefix(out.MIMEType, "text/plain") {
+		t.Errorf("Unexpected file: %+v", out)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "notes.txt")); err != nil {
+		t.Errorf("Expected files given by path to be kept, got %v", err)
+	}
+
+	tests := []struct {
+		name string
+		doc  map[string]interface{}
+		want string
+	}{
+		{"outside", map[string]interface{}{"path": "/etc/passwd"}, "outside the allowed directories"},
+		{"directory", map[string]interface{}{"path": "sub"}, "not a readable file"},
+		{"missing", map[string]interface{}{"path": "missing.txt"}, "not a readable file"},
+		{"too large", map[string]interface{}{"path": "big.txt"}, "over the limit"},
+		{"content too large", map[string]interface{}{"content": base64.StdEncoding.EncodeToString(make([]byte, 65))}, "over the limit"},
+		{"invalid base64", map[string]interface{}{"content": "not base64!"}, "not valid base64"},
+		{"both", map[string]interface{}{"path": "notes.txt", "content": "aGk="}, "either path or content"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := callTool(t, mcpServer, "upload", map[string]interface{}{"document": tt.doc})
+			if !result.IsError || !strings.Contains(resultText(result), tt.want) {
+				t.Errorf("Expected an error containing %q, got %v", tt.want, result.Content)
+			}
+		})
+	}
+
+	result := callTool(t, mcpServer, "upload", map[string]interface{}{
+		"document": map[string]interface{}{"path": "notes.txt"},
+		"extra":    []interface{}{map[string]interface{}{"path": "../escape"}},
+	})
+	if !strings.Contains(resultText(result), "extra[0]: path") {
+		t.Errorf("Expected the error to name the list item, got %v", result.Content)
+	}
+}
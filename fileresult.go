@@ -0,0 +1,204 @@
+package mcpwrapper
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	fileScheme = "file-result://"
+	// DefaultFileResultTTL is how long hosted file results stay readable.
+	DefaultFileResultTTL = 15 * time.Minute
+	// DefaultMaxInlineFileSize bounds the file results returned inline.
+	DefaultMaxInlineFileSize = 1 << 20
+)
+
+// FileResult is a handler result holding a file, read from Path or given
+// as Bytes. Over HTTP it is hosted as a resource for a while and the call
+// returns a link to it; over stdio it is returned inline as a base64 blob.
+// MIMEType and Filename default to what Path tells.
+type FileResult struct {
+	Path     string
+	Bytes    []byte
+	MIMEType string
+	Filename string
+}
+
+// WithFileResults sets how long hosted file results stay readable, and the
+// size of the largest file result returned inline. A file over maxInline
+// is rejected as too large. Zero keeps a default.
+func WithFileResults(ttl time.Duration, maxInline int) Option {
+	return func(w *Wrapper) {
+		if ttl > 0 {
+			w.files.ttl = ttl
+		}
+		if maxInline > 0 {
+			w.files.maxInline = maxInline
+		}
+	}
+}
+
+// fileHost keeps the file results hosted as file-result://<id> resources.
+// IDs are random, so a link is only known to the client it was sent to.
+type fileHost struct {
+	ttl       time.Duration
+	maxInline int
+
+	once    sync.Once
+	mu      sync.Mutex
+	entries map[string]*hostedFile
+}
+
+type hostedFile struct {
+	data     []byte
+	mimeType string
+	expires  time.Time
+}
+
+func newFileHost() *fileHost {
+	return &fileHost{
+		ttl:       DefaultFileResultTTL,
+		maxInline: DefaultMaxInlineFileSize,
+		entries:   make(map[string]*hostedFile),
+	}
+}
+
+func (h *fileHost) put(data []byte, mimeType string, now time.Time) (string, time.Time) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for id, f := range h.entries {
+		if !now.Before(f.expires) {
+			delete(h.entries, id)
+		}
+	}
+	id := newJobID()
+	expires := now.Add(h.ttl)
+	h.entries[id] = &hostedFile{data: data, mimeType: mimeType, expires: expires}
+	return id, expires
+}
+
+func (h *fileHost) get(id string) (*hostedFile, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	f, ok := h.entries[id]
+	if !ok {
+		return nil, false
+	}
+	if !time.Now().Before(f.expires) {
+		delete(h.entries, id)
+		return nil, false
+	}
+	return f, true
+}
+
+// fileResultOf returns the FileResult of a handler result, if it is one.
+func fileResultOf(result interface{}) (*FileResult, bool) {
+	switch r := result.(type) {
+	case FileResult:
+		return &r, true
+	case *FileResult:
+		return r, r != nil
+	}
+	return nil, false
+}
+
+// fileResult turns a FileResult into the call's result.
+func (w *Wrapper) fileResult(ctx context.Context, t *registeredTool, file *FileResult) *mcp.CallToolResult {
+	data := file.Bytes
+	if file.Path != "" {
+		var err error
+		if data, err = os.ReadFile(file.Path); err != nil {
+			w.logger.Error("failed to read file result", "tool", t.name, "path", file.Path, "error", err)
+			return codedErrorResult(CodeInternal, "internal error: failed to read file result")
+		}
+	}
+	filename := file.Filename
+	if filename == "" && file.Path != "" {
+		filename = filepath.Base(file.Path)
+	}
+	mimeType := file.MIMEType
+	if mimeType == "" {
+		mimeType = sniffMIMEType(data, filename)
+	}
+
+	// Calls over stdio carry no headers.
+	if len(HeadersFromContext(ctx)) == 0 {
+		if len(data) > w.files.maxInline {
+			return codedErrorResult(CodeTooLarge, fmt.Sprintf(
+				"file result is %d bytes, over the limit of %d bytes for inline files", len(data), w.files.maxInline))
+		}
+		uri := fileScheme + escapeFilename(filename)
+		return &mcp.CallToolResult{Content: []mcp.Content{mcp.NewEmbeddedResource(mcp.BlobResourceContents{
+			Meta:     map[string]any{"filename": filename, "size": len(data)},
+			URI:      uri,
+			MIMEType: mimeType,
+			Blob:     base64.StdEncoding.EncodeToString(data),
+		})}}
+	}
+
+	w.files.once.Do(func() {
+		w.server.AddResourceTemplate(
+			mcp.NewResourceTemplate(fileScheme+"{id}/{filename}", "File result",
+				mcp.WithTemplateDescription("Files returned by tool calls, readable until they expire"),
+			),
+			w.readFileResult,
+		)
+	})
+	id, expires := w.files.put(data, mimeType, time.Now())
+	uri := fileScheme + id + "/" + escapeFilename(filename)
+	w.logger.Info("hosted file result", "tool", t.name, "uri", uri, "bytes", len(data))
+
+	info := map[string]interface{}{
+		"uri":        uri,
+		"filename":   filename,
+		"mime_type":  mimeType,
+		"size":       len(data),
+		"expires_at": expires.UTC().Format(time.RFC3339),
+	}
+	text, _ := json.Marshal(info)
+	result := mcp.NewToolResultText(string(text))
+	result.Content = append(result.Content, mcp.NewResourceLink(uri, filename, t.name+" file result", mimeType))
+	result.Meta = mcp.NewMetaFromMap(map[string]interface{}{"file": info})
+	return result
+}
+
+// escapeFilename escapes a filename for a resource URI, defaulting it to
+// "file".
+func escapeFilename(filename string) string {
+	if filename == "" {
+		return "file"
+	}
+	return url.PathEscape(filename)
+}
+
+func (w *Wrapper) readFileResult(ctx context.Context, request mcp.ReadResourceRequest) (contents []mcp.ResourceContents, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			panicErr := newPanicError(r)
+			w.logger.Error("file result read panicked", "uri", request.Params.URI, "panic", panicErr.Value, "stack", string(panicErr.Stack))
+			contents, err = nil, panicErr
+		}
+	}()
+
+	uri := request.Params.URI
+	id, _, _ := strings.Cut(strings.TrimPrefix(uri, fileScheme), "/")
+	file, ok := w.files.get(id)
+	if !ok {
+		return nil, fmt.Errorf("%s has expired or does not exist", uri)
+	}
+	return []mcp.ResourceContents{mcp.BlobResourceContents{
+		URI:      uri,
+		MIMEType: file.mimeType,
+		Blob:     base64.StdEncoding.EncodeToString(file.data),
+	}}, nil
+}
@@ -0,0 +1,124 @@
+package mcpwrapper
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+type ExportArgs struct {
+	Format string `json:"format"`
+}
+
+func newExportServer(t *testing.T, opts ...Option) (*server.MCPServer, *Wrapper) {
+	t.Helper()
+	dir := t.TempDir()
+	report := filepath.Join(dir, "report.csv")
+	os.WriteFile(report, []byte("a,b\n1,2\n"), 0o644)
+
+	mcpServer := server.NewMCPServer("test", "1.0.0", server.WithResourceCapabilities(false, false))
+	wrapper := New(mcpServer, opts...)
+	err := wrapper.Register("export", "Export a report", ExportArgs{}, func(ctx context.Context, args interface{}) (interface{}, error) {
+		if args.(*ExportArgs).Format == "png" {
+			return &FileResult{Bytes: []byte("\x89PNG\r\n\x1a\n0000"), Filename: "chart.png"}, nil
+		}
+		return FileResult{Path: report}, nil
+	})
+	if err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	return mcpServer, wrapper
+}
+
+func callExport(t *testing.T, mcpServer *server.MCPServer, format string, header http.Header) *mcp.CallToolResult {
+	t.Helper()
+	result, err := mcpServer.GetTool("export").Handler(context.Background(), mcp.CallToolRequest{
+		Header: header,
+		Params: mcp.CallToolParams{Name: "export", Arguments: map[string]interface{}{"format": format}},
+	})
+	if err != nil {
+		t.Fatalf("Handler returned error: %v", err)
+	}
+	return result
+}
+
+func TestFileResultInline(t *testing.T) {
+	mcpServer, _ := newExportServer(t)
+
+	result := callExport(t, mcpServer, "csv", http.Header{})
+	if result.IsError || len(result.Content) != 1 {
+		t.Fatalf("Unexpected result: %v", result.Content)
+	}
+	blob := result.Content[0].(mcp.EmbeddedResource).Resource.(mcp.BlobResourceContents)
+	data, _ := base64.StdEncoding.DecodeString(blob.Blob)
+	if string(data) != "a,b\n1,2\n" || !strings.HasPrefix(blob.MIMEType, "text/csv") || blob.URI != "file-result://report.csv" {
+		t.Errorf("Unexpected blob: %+v", blob)
+	}
+
+	mcpServer, _ = newExportServer(t, WithFileResults(0, 4))
+	result = callExport(t, mcpServer, "csv", nil)
+	if !result.IsError || !strings.Contains(resultText(result), "over the limit of 4 bytes") {
+		t.Errorf("Expected a too large error, got %v", result.Content)
+	}
+}
+
+func TestFileResultHosted(t *testing.T) {
+	mcpServer, wrapper := newExportServer(t, WithFileResults(time.Minute, 4))
+	header := http.Header{"Content-Type": []string{"application/json"}}
+
+	result := callExport(t, mcpServer, "png", header)
+	if result.IsError || len(result.Content) != 2 {
+		t.Fatalf("Unexpected result: %v", result.Content)
+	}
+	var info struct {
+		URI       string    `json:"uri"`
+		Filename  string    `json:"filename"`
+		MIMEType  string    `json:"mime_type"`
+		Size      int       `json:"size"`
+		ExpiresAt time.Time `json:"expires_at"`
+	}
+	decodeResult(t, result, &info)
+	link := result.Content[1].(mcp.ResourceLink)
+	if link.URI != info.URI || !strings.HasSuffix(info.URI, "/chart.png") || info.MIMEType != "image/png" || info.Size != 12 {
+		t.Errorf("Unexpected file info: %+v %+v", info, link)
+	}
+	if info.ExpiresAt.Before(time.Now()) || info.ExpiresAt.After(time.Now().Add(time.Minute)) {
+		t.Errorf("Unexpected expiry: %v", info.ExpiresAt)
+	}
+
+	read := func(uri string) (mcp.BlobResourceContents, bool) {
+		response := mcpServer.HandleMessage(context.Background(), json.RawMessage(fmt.Sprintf(
+			`{"jsonrpc": "2.0", "id": 1, "method": "resources/read", "params": {"uri": %q}}`, uri)))
+		resp, ok := response.(mcp.JSONRPCResponse)
+		if !ok {
+			return mcp.BlobResourceContents{}, false
+		}
+		return resp.Result.(mcp.ReadResourceResult).Contents[0].(mcp.BlobResourceContents), true
+	}
+	blob, ok := read(info.URI)
+	data, _ := base64.StdEncoding.DecodeString(blob.Blob)
+	if !ok || string(data) != "\x89PNG\r\n\x1a\n0000" || blob.MIMEType != "image/png" {
+		t.Errorf("Unexpected hosted file: %+v %v", blob, ok)
+	}
+	if _, ok := read("file-result://0000000000000000/chart.png"); ok {
+		t.Error("Expected an unknown file to be unreadable")
+	}
+
+	id, _, _ := strings.Cut(strings.TrimPrefix(info.URI, fileScheme), "/")
+	wrapper.files.mu.Lock()
+	wrapper.files.entries[id].expires = time.Now().Add(-time.Second)
+	wrapper.files.mu.Unlock()
+	if _, ok := read(info.URI); ok {
+		t.Error("Expected an expired file to be unreadable")
+	}
+}
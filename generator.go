@@ -1,17 +1,22 @@
+//go:build !nocobra
+
 package mcpwrapper
 
 import (
 	"context"
 	"fmt"
+	"reflect"
+	"strings"
 
 	"github.com/spf13/cobra"
 )
 
-func (w *Wrapper) RegisterCobra(cmd *cobra.Command, argsType interface{}, handler Handler) error {
-	name := cmd.Use
-	if name == "" {
+func (w *Wrapper) RegisterCobra(cmd *cobra.Command, argsType interface{}, handler Handler, opts ...ToolOption) error {
+	if cmd.Use == "" {
 		return fmt.Errorf("cobra command must have a Use field")
 	}
+	// Use may list positional arguments after the name, as in "clone <url>".
+	name := cmd.Name()
 
 	description := cmd.Short
 	if description == "" {
@@ -21,31 +26,73 @@ func (w *Wrapper) RegisterCobra(cmd *cobra.Command, argsType interface{}, handle
 		description = fmt.Sprintf("Execute %s command", name)
 	}
 
-	return w.Register(name, description, argsType, handler)
+	annotateFlagExamples(cmd, argsType)
+
+	return w.Register(name, description, argsType, handler, opts...)
 }
 
-func (w *Wrapper) RegisterCobraCommand(cmd *cobra.Command, argsType interface{}) error {
-	handler := func(ctx context.Context, args interface{}) (interface{}, error) {
-		output := &struct {
-			Success bool   `json:"success"`
-			Message string `json:"message"`
-		}{
-			Success: true,
-			Message: fmt.Sprintf("Command %s executed successfully", cmd.Use),
+// annotateFlagExamples appends the examples declared on argument fields to
+// the usage of the command's flag of the same name (snake_case fields match
+// kebab-case flags), so --help shows what MCP clients see.
+func annotateFlagExamples(cmd *cobra.Command, argsType interface{}) {
+	t := reflect.TypeOf(argsType)
+	if t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		examples := fieldExamples(field)
+		if len(examples) == 0 {
+			continue
 		}
 
-		if cmd.RunE != nil {
-			if err := cmd.RunE(cmd, []string{}); err != nil {
-				output.Success = false
-				output.Message = err.Error()
-				return output, err
-			}
-		} else if cmd.Run != nil {
-			cmd.Run(cmd, []string{})
+		name := jsonFieldName(field)
+		flag := cmd.Flags().Lookup(name)
+		if flag == nil {
+			flag = cmd.Flags().Lookup(strings.ReplaceAll(name, "_", "-"))
 		}
+		if flag != nil {
+			flag.Usage = withExampleText(flag.Usage, examples)
+		}
+	}
+}
+
+// RegisterCobraCommand registers cmd with a handler that runs it, hooks
+// included. Bound args fields set the command's flags of the same name
+// (snake_case fields match kebab-case flags); fields tagged cobra:"arg=N"
+// are passed as the Nth positional argument and a slice tagged
+// cobra:"args" as the remaining ones, checked against cmd.Args. Calls to
+// commands of one tree run one at a time, and the flags are reset after
+// each.
+func (w *Wrapper) RegisterCobraCommand(cmd *cobra.Command, argsType interface{}, opts ...ToolOption) error {
+	layout, err := parseCobraArgs(reflect.TypeOf(argsType))
+	if err != nil {
+		return fmt.Errorf("invalid args type for command %s: %w", cmd.Name(), err)
+	}
+
+	handler := func(ctx context.Context, args interface{}) (interface{}, error) {
+		defer lockCobra(cmd)()
 
-		return output, nil
+		positional := []string{}
+		if v := reflect.Indirect(reflect.ValueOf(args)); v.Kind() == reflect.Struct {
+			values, err := layout.values(v)
+			if err != nil {
+				return nil, err
+			}
+			positional = append(positional, values...)
+
+			reset, err := setCobraFlags(cmd, v, layout)
+			if err != nil {
+				return nil, err
+			}
+			defer reset()
+		}
+		return runCobra(ctx, cmd, positional, nil)
 	}
 
-	return w.RegisterCobra(cmd, argsType, handler)
+	return w.RegisterCobra(cmd, argsType, handler, opts...)
 }
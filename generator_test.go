@@ -0,0 +1,94 @@
+//go:build !nocobra
+
+package mcpwrapper
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/spf13/cobra"
+)
+
+func TestRegisterCobra(t *testing.T) {
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	wrapper := New(mcpServer)
+
+	cmd := &cobra.Command{
+		Use:   "test-cmd",
+		Short: "Test command description",
+	}
+
+	handler := func(ctx context.Context, args interface{}) (interface{}, error) {
+		return &TestResult{Message: "Success"}, nil
+	}
+
+	err := wrapper.RegisterCobra(cmd, TestArgs{}, handler)
+	if err != nil {
+		t.Fatalf("RegisterCobra failed: %v", err)
+	}
+
+	tools := mcpServer.ListTools()
+	if len(tools) != 1 {
+		t.Errorf("Expected 1 tool handler, got %d", len(tools))
+	}
+}
+
+func TestRegisterCobraNoUse(t *testing.T) {
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	wrapper := New(mcpServer)
+
+	cmd := &cobra.Command{
+		Short: "Missing Use field",
+	}
+
+	handler := func(ctx context.Context, args interface{}) (interface{}, error) {
+		return &TestResult{Message: "Success"}, nil
+	}
+
+	err := wrapper.RegisterCobra(cmd, TestArgs{}, handler)
+	if err == nil {
+		t.Error("Expected error for command without Use field")
+	}
+}
+
+type CloneArgs struct {
+	Repo      string `json:"repo" jsonschema:"description=Repository to clone,example=octocat/hello-world"`
+	Depth     int    `json:"clone_depth,omitempty" jsonschema:"example=1,example=10"`
+	Directory string `json:"directory,omitempty"`
+}
+
+func TestRegisterCobraFlagExamples(t *testing.T) {
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	wrapper := New(mcpServer)
+
+	cmd := &cobra.Command{Use: "clone", Short: "Clone a repository"}
+	cmd.Flags().String("repo", "", "Repository to clone")
+	cmd.Flags().Int("clone-depth", 0, "History depth")
+	cmd.Flags().String("directory", "", "Target directory")
+
+	handler := func(ctx context.Context, args interface{}) (interface{}, error) {
+		return &TestResult{Message: "Success"}, nil
+	}
+
+	if err := wrapper.RegisterCobra(cmd, CloneArgs{}, handler); err != nil {
+		t.Fatalf("RegisterCobra failed: %v", err)
+	}
+
+	expected := map[string]string{
+		"repo":        "Repository to clone (e.g. octocat/hello-world)",
+		"clone-depth": "History depth (e.g. 1, 10)",
+		"directory":   "Target directory",
+	}
+	for name, usage := range expected {
+		if got := cmd.Flags().Lookup(name).Usage; got != usage {
+			t.Errorf("Flag %s: expected usage %q, got %q", name, usage, got)
+		}
+	}
+
+	props := mcpServer.GetTool("clone").Tool.InputSchema.Properties
+	repo := props["repo"].(map[string]interface{})
+	if repo["description"] != expected["repo"] {
+		t.Errorf("Expected schema description to match flag usage, got %q", repo["description"])
+	}
+}
@@ -0,0 +1,147 @@
+package mcpwrapper
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultHealthCheckTimeout bounds each readiness check.
+const DefaultHealthCheckTimeout = 5 * time.Second
+
+// HealthCheck reports whether a dependency, such as a database or an
+// upstream API, is reachable.
+type HealthCheck func(ctx context.Context) error
+
+type health struct {
+	registered atomic.Bool
+	stopping   atomic.Bool
+
+	mu     sync.RWMutex
+	checks map[string]HealthCheck
+}
+
+func newHealth() *health {
+	return &health{checks: make(map[string]HealthCheck)}
+}
+
+// WithReadinessCheck adds a check that must pass for /readyz to report the
+// server ready. Checks run concurrently on every probe, each bounded by
+// DefaultHealthCheckTimeout.
+func WithReadinessCheck(name string, check HealthCheck) Option {
+	return func(w *Wrapper) {
+		w.health.mu.Lock()
+		defer w.health.mu.Unlock()
+		w.health.checks[name] = check
+	}
+}
+
+// MarkReady records that tool registration is complete. /readyz fails until
+// it is called. ServeStreamableHTTP calls it once it is listening, so it is
+// only needed when the handlers are mounted on another server.
+func (w *Wrapper) MarkReady() {
+	w.health.registered.Store(true)
+}
+
+// HealthReport is the body of /healthz and /readyz.
+type HealthReport struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks,omitempty"`
+}
+
+// Readiness runs the readiness checks and reports whether the server should
+// receive traffic.
+func (w *Wrapper) Readiness(ctx context.Context) (HealthReport, bool) {
+	report := HealthReport{Status: "ready", Checks: make(map[string]string)}
+	ready := true
+	fail := func(name, reason string) {
+		report.Checks[name] = reason
+		ready = false
+	}
+
+	switch {
+	case w.health.stopping.Load():
+		fail("lifecycle", "shutting down")
+	case !w.health.registered.Load():
+		fail("registration", "pending")
+	default:
+		report.Checks["registration"] = "ok"
+	}
+
+	w.health.mu.RLock()
+	names := make([]string, 0, len(w.health.checks))
+	for name := range w.health.checks {
+		names = append(names, name)
+	}
+	checks := w.health.checks
+	w.health.mu.RUnlock()
+	sort.Strings(names)
+
+	errs := make([]error, len(names))
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		go func(i int, check HealthCheck) {
+			defer wg.Done()
+			errs[i] = runHealthCheck(ctx, check)
+		}(i, checks[name])
+	}
+	wg.Wait()
+
+	for i, name := range names {
+		if errs[i] != nil {
+			w.logger.Warn("readiness check failed", "check", name, "error", errs[i])
+			fail(name, errs[i].Error())
+		} else {
+			report.Checks[name] = "ok"
+		}
+	}
+
+	if !ready {
+		report.Status = "not ready"
+	}
+	return report, ready
+}
+
+func runHealthCheck(ctx context.Context, check HealthCheck) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, DefaultHealthCheckTimeout)
+	defer cancel()
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("check panicked: %v", r)
+		}
+	}()
+	return check(ctx)
+}
+
+// HealthHandler serves the probe endpoints for HTTP transports:
+//
+//	GET /healthz   200 while the process is serving
+//	GET /readyz    200 once registration is complete and every readiness
+//	               check passes, 503 otherwise
+//
+// StreamableHTTPHandler and ServeStreamableHTTP mount it next to the MCP
+// endpoint; mount it yourself next to other transports, such as SSE.
+func (w *Wrapper) HealthHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", w.handleHealthz)
+	mux.HandleFunc("GET /readyz", w.handleReadyz)
+	return mux
+}
+
+func (w *Wrapper) handleHealthz(rw http.ResponseWriter, r *http.Request) {
+	writeJSON(rw, HealthReport{Status: "ok"})
+}
+
+func (w *Wrapper) handleReadyz(rw http.ResponseWriter, r *http.Request) {
+	report, ready := w.Readiness(r.Context())
+	if !ready {
+		rw.Header().Set("Content-Type", "application/json")
+		rw.WriteHeader(http.StatusServiceUnavailable)
+	}
+	writeJSON(rw, report)
+}
@@ -0,0 +1,72 @@
+package mcpwrapper
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func TestHealthEndpoints(t *testing.T) {
+	var dbDown atomic.Bool
+	wrapper := New(server.NewMCPServer("test", "1.0.0"),
+		WithReadinessCheck("database", func(ctx context.Context) error {
+			if dbDown.Load() {
+				return errors.New("connection refused")
+			}
+			return nil
+		}),
+	)
+	ts := httptest.NewServer(wrapper.StreamableHTTPHandler(WithHTTPAuth(func(r *http.Request) (context.Context, error) {
+		return nil, errors.New("probes must not be authenticated")
+	})))
+	defer ts.Close()
+
+	probe := func(path string) (int, HealthReport) {
+		t.Helper()
+		resp, err := http.Get(ts.URL + path)
+		if err != nil {
+			t.Fatalf("GET %s failed: %v", path, err)
+		}
+		defer resp.Body.Close()
+		var report HealthReport
+		json.NewDecoder(resp.Body).Decode(&report)
+		return resp.StatusCode, report
+	}
+
+	if code, report := probe("/healthz"); code != http.StatusOK || report.Status != "ok" {
+		t.Errorf("Unexpected /healthz: %d %+v", code, report)
+	}
+
+	code, report := probe("/readyz")
+	if code != http.StatusServiceUnavailable || report.Checks["registration"] != "pending" || report.Checks["database"] != "ok" {
+		t.Errorf("Expected not ready before MarkReady, got %d %+v", code, report)
+	}
+
+	wrapper.MarkReady()
+	if code, report := probe("/readyz"); code != http.StatusOK || report.Status != "ready" {
+		t.Errorf("Expected ready, got %d %+v", code, report)
+	}
+
+	dbDown.Store(true)
+	code, report = probe("/readyz")
+	if code != http.StatusServiceUnavailable || report.Checks["database"] != "connection refused" {
+		t.Errorf("Expected the failing check to be reported, got %d %+v", code, report)
+	}
+}
+
+func TestReadinessCheckPanic(t *testing.T) {
+	wrapper := New(server.NewMCPServer("test", "1.0.0"),
+		WithReadinessCheck("cache", func(ctx context.Context) error { panic("boom") }),
+	)
+	wrapper.MarkReady()
+	report, ready := wrapper.Readiness(context.Background())
+	if ready || report.Checks["cache"] != "check panicked: boom" {
+		t.Errorf("Expected a panicking check to fail readiness, got %+v", report)
+	}
+}
@@ -0,0 +1,72 @@
+package mcpwrapper
+
+import (
+	"errors"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// HintError pairs an error with a remediation hint the model can act on,
+// such as which argument to change.
+type HintError struct {
+	Err  error
+	Hint string
+}
+
+func (e *HintError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *HintError) Unwrap() error {
+	return e.Err
+}
+
+// ErrorWithHint annotates err with a remediation hint. Handlers return it like
+// any other error; the hint is reported separately from the error message.
+func ErrorWithHint(err error, hint string) error {
+	if err == nil {
+		return nil
+	}
+	return &HintError{Err: err, Hint: hint}
+}
+
+// HintFromError returns the hint of the outermost HintError in err's chain.
+func HintFromError(err error) (string, bool) {
+	var hintErr *HintError
+	if !errors.As(err, &hintErr) {
+		return "", false
+	}
+	return hintErr.Hint, true
+}
+
+// newErrorResult builds an error result for a handler error. When err
+// carries a code or a hint, they are returned as separate "code" and "hint"
+// fields in the structured content, and the hint is also appended to the
+// text for the model.
+func newErrorResult(msg string, err error) *mcp.CallToolResult {
+	code, hasCode := CodeFromError(err)
+	hint, hasHint := HintFromError(err)
+	var exitErr *ExitError
+	hasExit := errors.As(err, &exitErr)
+	if !hasCode && !hasHint && !hasExit {
+		return mcp.NewToolResultError(msg)
+	}
+
+	structured := map[string]interface{}{"error": msg}
+	if hasExit {
+		structured["exit_code"] = exitErr.Result.ExitCode
+		structured["stdout"] = exitErr.Result.Stdout
+		structured["stderr"] = exitErr.Result.Stderr
+		structured["duration_ms"] = exitErr.Result.DurationMs
+	}
+	if hasCode {
+		structured["code"] = code
+	}
+	if hasHint {
+		msg += "\nhint: " + hint
+		structured["hint"] = hint
+	}
+	result := mcp.NewToolResultError(msg)
+	result.StructuredContent = structured
+	return result
+}
@@ -0,0 +1,73 @@
+package mcpwrapper
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func TestErrorWithHint(t *testing.T) {
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	wrapper := New(mcpServer)
+
+	handler := func(ctx context.Context, args interface{}) (interface{}, error) {
+		return nil, ErrorWithHint(errors.New("category A is full"), "try passing category=B")
+	}
+
+	if err := wrapper.Register("test-tool", "Test tool", TestArgs{}, handler); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	request := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name:      "test-tool",
+			Arguments: map[string]interface{}{"name": "Alice", "age": 30, "category": "A"},
+		},
+	}
+
+	result, err := mcpServer.GetTool("test-tool").Handler(context.Background(), request)
+	if err != nil {
+		t.Fatalf("Handler invocation failed: %v", err)
+	}
+
+	if !result.IsError {
+		t.Fatal("Expected error result")
+	}
+
+	structured, ok := result.StructuredContent.(map[string]interface{})
+	if !ok {
+		t.Fatal("Expected structured content with hint")
+	}
+
+	if structured["hint"] != "try passing category=B" {
+		t.Errorf("Expected hint 'try passing category=B', got '%v'", structured["hint"])
+	}
+
+	if structured["error"] != "handler error: category A is full" {
+		t.Errorf("Unexpected error field: '%v'", structured["error"])
+	}
+
+	text := result.Content[0].(mcp.TextContent).Text
+	if text != "handler error: category A is full\nhint: try passing category=B" {
+		t.Errorf("Unexpected text content: %q", text)
+	}
+}
+
+func TestHintFromError(t *testing.T) {
+	wrapped := errors.Join(errors.New("outer"), ErrorWithHint(errors.New("inner"), "retry later"))
+
+	if hint, ok := HintFromError(wrapped); !ok || hint != "retry later" {
+		t.Errorf("Expected hint 'retry later', got '%s'", hint)
+	}
+
+	if _, ok := HintFromError(errors.New("plain")); ok {
+		t.Error("Expected no hint for plain error")
+	}
+
+	if ErrorWithHint(nil, "unused") != nil {
+		t.Error("Expected nil error to stay nil")
+	}
+}
@@ -0,0 +1,146 @@
+package mcpwrapper
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const historyScheme = "history://"
+
+// resultHistory keeps the last N successful results of each tool per
+// session, addressable as history://<tool>/<n> where n counts the tool's
+// calls in the session from 1.
+type resultHistory struct {
+	mu       sync.Mutex
+	limit    int
+	sessions map[string]map[string]*toolHistory
+}
+
+type toolHistory struct {
+	next    int
+	entries []historyEntry
+}
+
+type historyEntry struct {
+	n    int
+	text string
+}
+
+// WithResultHistory retains the last n results per tool per session as
+// resources. Each result's _meta carries its "history_uri", so agents can
+// re-read an earlier result instead of calling the tool again.
+func WithResultHistory(n int) Option {
+	return func(w *Wrapper) {
+		if n <= 0 {
+			return
+		}
+		w.history = &resultHistory{limit: n, sessions: make(map[string]map[string]*toolHistory)}
+		w.server.AddResourceTemplate(
+			mcp.NewResourceTemplate(historyScheme+"{tool}/{n}", "Tool result history",
+				mcp.WithTemplateDescription(fmt.Sprintf("The last %d results of each tool in this session", n)),
+				mcp.WithTemplateMIMEType("application/json"),
+			),
+			w.readHistory,
+		)
+	}
+}
+
+func (h *resultHistory) record(sessionID, tool, text string) string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	tools, ok := h.sessions[sessionID]
+	if !ok {
+		tools = make(map[string]*toolHistory)
+		h.sessions[sessionID] = tools
+	}
+	th, ok := tools[tool]
+	if !ok {
+		th = &toolHistory{}
+		tools[tool] = th
+	}
+
+	th.next++
+	th.entries = append(th.entries, historyEntry{n: th.next, text: text})
+	if len(th.entries) > h.limit {
+		th.entries = th.entries[len(th.entries)-h.limit:]
+	}
+	return fmt.Sprintf("%s%s/%d", historyScheme, tool, th.next)
+}
+
+func (h *resultHistory) lookup(sessionID, tool string, n int) (string, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	th, ok := h.sessions[sessionID][tool]
+	if !ok {
+		return "", false
+	}
+	for _, e := range th.entries {
+		if e.n == n {
+			return e.text, true
+		}
+	}
+	return "", false
+}
+
+func (h *resultHistory) end(sessionID string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.sessions, sessionID)
+}
+
+// recordHistory stores a successful result and references it in its _meta.
+func (w *Wrapper) recordHistory(ctx context.Context, call *callState, result *mcp.CallToolResult) {
+	if w.history == nil || result.IsError || len(result.Content) == 0 {
+		return
+	}
+	text, ok := result.Content[0].(mcp.TextContent)
+	if !ok {
+		return
+	}
+	uri := w.history.record(SessionIDFromContext(ctx), call.tool.name, text.Text)
+	SetResultMeta(ctx, "history_uri", uri)
+}
+
+func (w *Wrapper) readHistory(ctx context.Context, request mcp.ReadResourceRequest) (contents []mcp.ResourceContents, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			panicErr := newPanicError(r)
+			w.logger.Error("history read panicked", "uri", request.Params.URI, "panic", panicErr.Value, "stack", string(panicErr.Stack))
+			contents, err = nil, panicErr
+		}
+	}()
+
+	uri := request.Params.URI
+	path := strings.TrimPrefix(uri, historyScheme)
+	i := strings.LastIndex(path, "/")
+	if i < 0 {
+		return nil, fmt.Errorf("invalid history URI %s", uri)
+	}
+	n, err := strconv.Atoi(path[i+1:])
+	if err != nil {
+		return nil, fmt.Errorf("invalid history URI %s", uri)
+	}
+
+	text, ok := w.history.lookup(SessionIDFromContext(ctx), path[:i], n)
+	if !ok {
+		return nil, fmt.Errorf("%s is not in this session's history", uri)
+	}
+
+	mimeType := "text/plain"
+	if json.Valid([]byte(text)) {
+		mimeType = "application/json"
+	}
+	return []mcp.ResourceContents{mcp.TextResourceContents{
+		URI:      uri,
+		MIMEType: mimeType,
+		Text:     text,
+	}}, nil
+}
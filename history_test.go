@@ -0,0 +1,72 @@
+package mcpwrapper
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func TestResultHistory(t *testing.T) {
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	wrapper := New(mcpServer, WithResultHistory(2))
+
+	handler := func(ctx context.Context, args interface{}) (interface{}, error) {
+		return &TestResult{Message: "hello " + args.(*TestArgs).Name}, nil
+	}
+
+	if err := wrapper.Register("greet", "Greet tool", TestArgs{}, handler); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	ctx := mcpServer.WithContext(context.Background(), &testSession{id: "session-1"})
+	call := func(name string) string {
+		request := mcp.CallToolRequest{
+			Params: mcp.CallToolParams{
+				Name:      "greet",
+				Arguments: map[string]interface{}{"name": name, "age": 30, "category": "A"},
+			},
+		}
+		result, err := mcpServer.GetTool("greet").Handler(ctx, request)
+		if err != nil || result.IsError {
+			t.Fatalf("Handler invocation failed: %v %v", err, result)
+		}
+		return result.Meta.AdditionalFields["history_uri"].(string)
+	}
+
+	read := func(ctx context.Context, uri string) (string, bool) {
+		response := mcpServer.HandleMessage(ctx, json.RawMessage(fmt.Sprintf(
+			`{"jsonrpc": "2.0", "id": 1, "method": "resources/read", "params": {"uri": %q}}`, uri)))
+		resp, ok := response.(mcp.JSONRPCResponse)
+		if !ok {
+			return "", false
+		}
+		contents := resp.Result.(mcp.ReadResourceResult).Contents
+		return contents[0].(mcp.TextResourceContents).Text, true
+	}
+
+	uris := []string{call("Alice"), call("Bob"), call("Carol")}
+	if uris[0] != "history://greet/1" || uris[2] != "history://greet/3" {
+		t.Errorf("Unexpected history URIs: %v", uris)
+	}
+
+	if text, ok := read(ctx, "history://greet/2"); !ok || text != `{"message":"hello Bob"}` {
+		t.Errorf("Unexpected history content: %q %v", text, ok)
+	}
+	if _, ok := read(ctx, "history://greet/1"); ok {
+		t.Error("Expected the oldest result to be evicted")
+	}
+
+	other := mcpServer.WithContext(context.Background(), &testSession{id: "session-2"})
+	if _, ok := read(other, "history://greet/3"); ok {
+		t.Error("Expected history to be scoped to the session")
+	}
+
+	wrapper.EndSession("session-1")
+	if _, ok := read(ctx, "history://greet/3"); ok {
+		t.Error("Expected history to be dropped when the session ends")
+	}
+}
@@ -0,0 +1,181 @@
+package mcpwrapper
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// HTTPSpec describes an HTTP request sent once per call. URLTemplate,
+// Headers values and BodyTemplate are text/template templates rendered with
+// the call's arguments, keyed by their JSON names; use urlquery to escape
+// values placed in the URL and json to embed them in a JSON body. Without a
+// BodyTemplate, POST, PUT and PATCH requests send the arguments as a JSON
+// object. Client defaults to http.DefaultClient; set it to add
+// authentication or TLS configuration through its Transport.
+type HTTPSpec struct {
+	Method       string            `json:"method,omitempty" yaml:"method,omitempty"`
+	URLTemplate  string            `json:"url" yaml:"url"`
+	Headers      map[string]string `json:"headers,omitempty" yaml:"headers,omitempty"`
+	BodyTemplate string            `json:"body,omitempty" yaml:"body,omitempty"`
+	Timeout      time.Duration     `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+	Client       *http.Client      `json:"-" yaml:"-"`
+}
+
+// HTTPResult is the response of an HTTP-backed tool. Body holds the decoded
+// JSON for JSON responses and the raw text otherwise. Truncated is set when
+// the body was cut off at 10 MiB; Body is then the raw text read.
+type HTTPResult struct {
+	Status    int               `json:"status"`
+	Headers   map[string]string `json:"headers"`
+	Body      interface{}       `json:"body"`
+	Truncated bool              `json:"truncated,omitempty"`
+}
+
+// RegisterHTTP registers a tool that calls an HTTP endpoint, turning a REST
+// API into a tool without handler code. Arguments are bound to argsType and
+// validated as for Register, then rendered into spec's templates by JSON
+// name. The result is an HTTPResult; error statuses are reported in it, not
+// as errors. At most 10 MiB of the response body is read; a longer body is
+// cut off and the result is marked "truncated", in it and in its _meta.
+func (w *Wrapper) RegisterHTTP(name, description string, argsType interface{}, spec HTTPSpec, opts ...ToolOption) error {
+	schema, err := w.schema(argsType)
+	if err != nil {
+		return fmt.Errorf("failed to build schema for tool %s: %w", name, err)
+	}
+
+	handler, err := httpHandler(spec, schemaProperties(schema))
+	if err != nil {
+		return fmt.Errorf("invalid HTTP spec for tool %s: %w", name, err)
+	}
+
+	return w.register(name, description, argsType, schema, handler, opts)
+}
+
+// maxHTTPResponseBytes bounds how much of a response body is read.
+const maxHTTPResponseBytes = 10 << 20
+
+func httpHandler(spec HTTPSpec, properties []string) (Handler, error) {
+	if spec.URLTemplate == "" {
+		return nil, fmt.Errorf("url is required")
+	}
+	method := strings.ToUpper(spec.Method)
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	url, err := parseTemplate("url", spec.URLTemplate)
+	if err != nil {
+		return nil, err
+	}
+	headers := make(map[string]*template.Template, len(spec.Headers))
+	for name, value := range spec.Headers {
+		headers[name], err = parseTemplate("header "+name, value)
+		if err != nil {
+			return nil, err
+		}
+	}
+	client := spec.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	var body *template.Template
+	if spec.BodyTemplate != "" {
+		if body, err = parseTemplate("body", spec.BodyTemplate); err != nil {
+			return nil, err
+		}
+	}
+
+	return func(ctx context.Context, a interface{}) (interface{}, error) {
+		data := templateData(a, properties)
+
+		target, err := renderTemplate(url, data)
+		if err != nil {
+			return nil, err
+		}
+
+		var reqBody io.Reader
+		jsonBody := false
+		switch {
+		case body != nil:
+			s, err := renderTemplate(body, data)
+			if err != nil {
+				return nil, err
+			}
+			reqBody = strings.NewReader(s)
+		case method == http.MethodPost || method == http.MethodPut || method == http.MethodPatch:
+			encoded, err := json.Marshal(jsonFields(a))
+			if err != nil {
+				return nil, fmt.Errorf("failed to encode body: %w", err)
+			}
+			reqBody = bytes.NewReader(encoded)
+			jsonBody = true
+		}
+
+		if spec.Timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, spec.Timeout)
+			defer cancel()
+		}
+
+		req, err := http.NewRequestWithContext(ctx, method, target, reqBody)
+		if err != nil {
+			return nil, fmt.Errorf("invalid request: %w", err)
+		}
+		if jsonBody {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		for name, tmpl := range headers {
+			value, err := renderTemplate(tmpl, data)
+			if err != nil {
+				return nil, err
+			}
+			req.Header.Set(name, value)
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("%s %s failed: %w", method, req.URL.Redacted(), err)
+		}
+		defer resp.Body.Close()
+
+		respBody, err := io.ReadAll(io.LimitReader(resp.Body, maxHTTPResponseBytes+1))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read response: %w", err)
+		}
+
+		result := &HTTPResult{Status: resp.StatusCode, Headers: flattenHeaders(resp.Header)}
+		if len(respBody) > maxHTTPResponseBytes {
+			result.Body = string(respBody[:maxHTTPResponseBytes])
+			result.Truncated = true
+			SetResultMeta(ctx, "truncated", true)
+		} else {
+			result.Body = decodeHTTPBody(resp.Header.Get("Content-Type"), respBody)
+		}
+		return result, nil
+	}, nil
+}
+
+func flattenHeaders(h http.Header) map[string]string {
+	flat := make(map[string]string, len(h))
+	for name, values := range h {
+		flat[name] = strings.Join(values, ", ")
+	}
+	return flat
+}
+
+func decodeHTTPBody(contentType string, body []byte) interface{} {
+	if strings.Contains(contentType, "json") {
+		var decoded interface{}
+		if err := json.Unmarshal(body, &decoded); err == nil {
+			return decoded
+		}
+	}
+	return string(body)
+}
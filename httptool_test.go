@@ -0,0 +1,149 @@
+package mcpwrapper
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+type CreateIssueArgs struct {
+	Project string   `json:"project" jsonschema:"required" validate:"required"`
+	Title   string   `json:"title" jsonschema:"required" validate:"required"`
+	Labels  []string `json:"labels,omitempty"`
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
+
+func TestRegisterHTTP(t *testing.T) {
+	var gotMethod, gotPath, gotBody, gotContentType, gotAuth string
+	api := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		gotMethod, gotPath, gotBody = r.Method, r.URL.EscapedPath(), string(body)
+		gotContentType, gotAuth = r.Header.Get("Content-Type"), r.Header.Get("Authorization")
+		rw.Header().Set("Content-Type", "application/json")
+		rw.Header().Set("X-Request-Id", "42")
+		rw.WriteHeader(http.StatusCreated)
+		io.WriteString(rw, `{"id": 7}`)
+	}))
+	defer api.Close()
+
+	client := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		r.Header.Set("Authorization", "Bearer secret")
+		return http.DefaultTransport.RoundTrip(r)
+	})}
+
+	tests := []struct {
+		name     string
+		spec     HTTPSpec
+		wantBody string
+	}{
+		{
+			name:     "arguments as JSON body",
+			spec:     HTTPSpec{Method: "post", URLTemplate: api.URL + "/projects/{{urlquery .project}}/issues"},
+			wantBody: `{"labels":["bug"],"project":"web app","title":"Crash"}`,
+		},
+		{
+			name: "body template",
+			spec: HTTPSpec{
+				Method:       http.MethodPost,
+				URLTemplate:  api.URL + "/projects/{{urlquery .project}}/issues",
+				BodyTemplate: `{"summary": {{json .title}}}`,
+				Headers:      map[string]string{"Content-Type": "application/json"},
+			},
+			wantBody: `{"summary": "Crash"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mcpServer := server.NewMCPServer("test", "1.0.0")
+			wrapper := New(mcpServer)
+			tt.spec.Client = client
+			if err := wrapper.RegisterHTTP("create_issue", "Create an issue", CreateIssueArgs{}, tt.spec); err != nil {
+				t.Fatalf("RegisterHTTP failed: %v", err)
+			}
+
+			result := callTool(t, mcpServer, "create_issue", map[string]interface{}{
+				"project": "web app", "title": "Crash", "labels": []interface{}{"bug"},
+			})
+			var got HTTPResult
+			if err := json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &got); err != nil {
+				t.Fatalf("Unexpected result %+v: %v", result, err)
+			}
+
+			if gotMethod != http.MethodPost || gotPath != "/projects/web+app/issues" {
+				t.Errorf("Unexpected request %s %s", gotMethod, gotPath)
+			}
+			if gotBody != tt.wantBody || gotContentType != "application/json" {
+				t.Errorf("Expected body %s, got %s (%s)", tt.wantBody, gotBody, gotContentType)
+			}
+			if gotAuth != "Bearer secret" {
+				t.Errorf("Expected the custom client to be used, got %q", gotAuth)
+			}
+			if got.Status != http.StatusCreated || got.Headers["X-Request-Id"] != "42" || got.Body.(map[string]interface{})["id"] != float64(7) {
+				t.Errorf("Unexpected HTTP result: %+v", got)
+			}
+		})
+	}
+}
+
+func TestRegisterHTTPValidatesBeforeSending(t *testing.T) {
+	called := false
+	api := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer api.Close()
+
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	wrapper := New(mcpServer)
+	if err := wrapper.RegisterHTTP("create_issue", "Create an issue", CreateIssueArgs{}, HTTPSpec{Method: "POST", URLTemplate: api.URL}); err != nil {
+		t.Fatalf("RegisterHTTP failed: %v", err)
+	}
+
+	if result := callTool(t, mcpServer, "create_issue", map[string]interface{}{"project": "web"}); !result.IsError {
+		t.Error("Expected a validation error")
+	}
+	if called {
+		t.Error("Expected no request for invalid arguments")
+	}
+
+	if err := wrapper.RegisterHTTP("broken", "", CreateIssueArgs{}, HTTPSpec{}); err == nil {
+		t.Error("Expected an error without a URL")
+	}
+}
+
+func TestRegisterHTTPTruncatesLargeBodies(t *testing.T) {
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	wrapper := New(mcpServer)
+	client := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": {"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(`["` + strings.Repeat("x", maxHTTPResponseBytes) + `"]`)),
+		}, nil
+	})}
+	spec := HTTPSpec{URLTemplate: "https://api.example.com/export", Client: client}
+	if err := wrapper.RegisterHTTP("export", "Export everything", struct{}{}, spec); err != nil {
+		t.Fatalf("RegisterHTTP failed: %v", err)
+	}
+
+	result := callTool(t, mcpServer, "export", map[string]interface{}{})
+	var got HTTPResult
+	if err := json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &got); err != nil {
+		t.Fatalf("Unexpected result: %v", err)
+	}
+	body, _ := got.Body.(string)
+	if !got.Truncated || len(body) != maxHTTPResponseBytes {
+		t.Errorf("Expected the body to be truncated to %d bytes, got %d (truncated %v)", maxHTTPResponseBytes, len(body), got.Truncated)
+	}
+	if result.Meta == nil || result.Meta.AdditionalFields["truncated"] != true {
+		t.Errorf("Expected the result to be marked truncated, got %+v", result.Meta)
+	}
+}
@@ -0,0 +1,70 @@
+package mcpwrapper
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// dependencies holds the values given to Provide, one per type.
+type dependencies struct {
+	mu     sync.RWMutex
+	values []reflect.Value
+}
+
+// Provide makes values available to handlers registered afterwards with
+// RegisterFunc, which receive them as parameters after the args:
+//
+//	wrapper.Provide(db, apiClient)
+//	wrapper.RegisterFunc("lookup", "Look up a user",
+//	    func(ctx context.Context, args *LookupArgs, db *sql.DB, api *billing.Client) (*User, error) { ... })
+//
+// A parameter is matched to the value of exactly its type or, for an
+// interface parameter, to the one value that implements it. Each type can be
+// provided once.
+func (w *Wrapper) Provide(values ...interface{}) error {
+	w.deps.mu.Lock()
+	defer w.deps.mu.Unlock()
+	for _, value := range values {
+		v := reflect.ValueOf(value)
+		if !v.IsValid() {
+			return fmt.Errorf("cannot provide nil")
+		}
+		for _, existing := range w.deps.values {
+			if existing.Type() == v.Type() {
+				return fmt.Errorf("a %s is already provided", v.Type())
+			}
+		}
+		w.deps.values = append(w.deps.values, v)
+	}
+	return nil
+}
+
+// resolve returns the provided value for a parameter type.
+func (d *dependencies) resolve(t reflect.Type) (reflect.Value, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	for _, v := range d.values {
+		if v.Type() == t {
+			return v, nil
+		}
+	}
+	if t.Kind() != reflect.Interface {
+		return reflect.Value{}, fmt.Errorf("no %s was provided", t)
+	}
+
+	var matches []reflect.Value
+	for _, v := range d.values {
+		if v.Type().Implements(t) {
+			matches = append(matches, v)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return reflect.Value{}, fmt.Errorf("no %s was provided", t)
+	case 1:
+		return matches[0], nil
+	default:
+		return reflect.Value{}, fmt.Errorf("%d provided values implement %s; take one of their concrete types instead", len(matches), t)
+	}
+}
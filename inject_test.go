@@ -0,0 +1,69 @@
+package mcpwrapper
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+type userStore struct {
+	names map[string]string
+}
+
+type clock interface {
+	Now() string
+}
+
+type fixedClock struct{}
+
+func (fixedClock) Now() string { return "noon" }
+
+func TestProvide(t *testing.T) {
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	wrapper := New(mcpServer)
+
+	store := &userStore{names: map[string]string{"Ada": "Ada Lovelace"}}
+	if err := wrapper.Provide(store, fixedClock{}); err != nil {
+		t.Fatalf("Provide failed: %v", err)
+	}
+	err := wrapper.RegisterFunc("lookup", "Look up a user",
+		func(ctx context.Context, args *TestArgs, users *userStore, c clock) (string, error) {
+			return fmt.Sprintf("%s at %s", users.names[args.Name], c.Now()), nil
+		})
+	if err != nil {
+		t.Fatalf("RegisterFunc failed: %v", err)
+	}
+
+	result := callTool(t, mcpServer, "lookup", map[string]interface{}{"name": "Ada", "age": 36, "category": "A"})
+	if text := result.Content[0].(mcp.TextContent).Text; result.IsError || text != "Ada Lovelace at noon" {
+		t.Errorf("Unexpected result: %v", result.Content)
+	}
+}
+
+func TestProvideErrors(t *testing.T) {
+	wrapper := New(server.NewMCPServer("test", "1.0.0"))
+	if err := wrapper.Provide(nil); err == nil {
+		t.Error("Expected nil to be rejected")
+	}
+	if err := wrapper.Provide(&userStore{}, &userStore{}); err == nil || !strings.Contains(err.Error(), "already provided") {
+		t.Errorf("Expected a duplicate error, got %v", err)
+	}
+
+	err := wrapper.RegisterFunc("missing", "Missing dependency",
+		func(ctx context.Context, args *TestArgs, c clock) (string, error) { return "", nil })
+	if err == nil || !strings.Contains(err.Error(), "no mcpwrapper.clock was provided") {
+		t.Errorf("Expected a missing dependency error, got %v", err)
+	}
+
+	type otherClock struct{ fixedClock }
+	wrapper.Provide(fixedClock{}, otherClock{})
+	err = wrapper.RegisterFunc("ambiguous", "Ambiguous dependency",
+		func(ctx context.Context, args *TestArgs, c clock) (string, error) { return "", nil })
+	if err == nil || !strings.Contains(err.Error(), "2 provided values implement") {
+		t.Errorf("Expected an ambiguity error, got %v", err)
+	}
+}
@@ -0,0 +1,85 @@
+// Package invopop generates tool input schemas with
+// github.com/invopop/jsonschema instead of the wrapper's built-in tag
+// parser, for the full JSON Schema keyword coverage of that library:
+//
+//	wrapper := mcpwrapper.New(mcpServer, mcpwrapper.WithSchemaGenerator(invopop.Generator(nil)))
+package invopop
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	mcpwrapper "github.com/aleksadvaisly/mcp-go-wrapper"
+	"github.com/invopop/jsonschema"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Generator returns a SchemaGenerator backed by r. A nil r uses a Reflector
+// that inlines the argument struct, puts nested types under $defs and takes
+// required fields from jsonschema:"required", as the built-in parser does.
+// Fields with validate:"required" are marked required as well.
+func Generator(r *jsonschema.Reflector) mcpwrapper.SchemaGenerator {
+	if r == nil {
+		r = &jsonschema.Reflector{
+			ExpandedStruct:             true,
+			RequiredFromJSONSchemaTags: true,
+		}
+	}
+
+	return mcpwrapper.SchemaGeneratorFunc(func(argsType interface{}) (*mcp.ToolInputSchema, error) {
+		data, err := json.Marshal(r.Reflect(argsType))
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode schema: %w", err)
+		}
+
+		var schema mcp.ToolInputSchema
+		if err := json.Unmarshal(data, &schema); err != nil {
+			return nil, fmt.Errorf("failed to decode schema: %w", err)
+		}
+		if schema.Required == nil {
+			schema.Required = make([]string, 0)
+		}
+		for _, name := range validateRequired(argsType) {
+			if !contains(schema.Required, name) {
+				schema.Required = append(schema.Required, name)
+			}
+		}
+		return &schema, nil
+	})
+}
+
+// validateRequired returns the JSON names of the fields of argsType with a
+// required validate tag.
+func validateRequired(argsType interface{}) []string {
+	t := reflect.TypeOf(argsType)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	var names []string
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+		for _, rule := range strings.Split(field.Tag.Get("validate"), ",") {
+			if rule == "required" {
+				names = append(names, name)
+				break
+			}
+		}
+	}
+	return names
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
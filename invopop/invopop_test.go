@@ -0,0 +1,70 @@
+package invopop
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	mcpwrapper "github.com/aleksadvaisly/mcp-go-wrapper"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+type Address struct {
+	City string `json:"city" jsonschema:"required"`
+}
+
+type ShipArgs struct {
+	Name    string   `json:"name" validate:"required"`
+	Tags    []string `json:"tags" jsonschema:"uniqueItems=true,minItems=1"`
+	Mode    string   `json:"mode" jsonschema:"enum=air,enum=sea,default=sea"`
+	Address Address  `json:"address" jsonschema:"required"`
+}
+
+func TestGenerator(t *testing.T) {
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	wrapper := mcpwrapper.New(mcpServer, mcpwrapper.WithSchemaGenerator(Generator(nil)))
+
+	var got ShipArgs
+	handler := func(ctx context.Context, args interface{}) (interface{}, error) {
+		got = *args.(*ShipArgs)
+		return "ok", nil
+	}
+	if err := wrapper.Register("ship", "Ship a parcel", ShipArgs{}, handler); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	schema := mcpServer.GetTool("ship").Tool.InputSchema
+	if schema.Type != "object" || !reflect.DeepEqual(schema.Required, []string{"address", "name"}) {
+		t.Errorf("Unexpected schema: %+v", schema)
+	}
+	tags := schema.Properties["tags"].(map[string]interface{})
+	if tags["uniqueItems"] != true || tags["minItems"] != float64(1) {
+		t.Errorf("Unexpected tags schema: %v", tags)
+	}
+	if mode := schema.Properties["mode"].(map[string]interface{}); mode["default"] != "sea" {
+		t.Errorf("Unexpected mode schema: %v", mode)
+	}
+	if address := schema.Properties["address"].(map[string]interface{}); address["$ref"] != "#/$defs/Address" {
+		t.Errorf("Unexpected address schema: %v", address)
+	}
+	if _, ok := schema.Defs["Address"]; !ok {
+		t.Errorf("Expected an Address definition, got %v", schema.Defs)
+	}
+
+	result, err := mcpServer.GetTool("ship").Handler(context.Background(), mcp.CallToolRequest{
+		Params: mcp.CallToolParams{Name: "ship", Arguments: map[string]interface{}{
+			"name": "box", "mode": "air", "address": map[string]interface{}{"city": "Oslo"},
+		}},
+	})
+	if err != nil || result.IsError {
+		t.Fatalf("Call failed: %v %v", err, result)
+	}
+	if got.Name != "box" || got.Address.City != "Oslo" {
+		t.Errorf("Unexpected bound args: %+v", got)
+	}
+
+	if err := wrapper.Register("bad", "Not a struct", "", handler); err == nil {
+		t.Error("Expected an error for a non-struct argsType")
+	}
+}
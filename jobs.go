@@ -0,0 +1,492 @@
+package mcpwrapper
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Job states reported by Job.State.
+const (
+	JobQueued    = "queued"
+	JobRunning   = "running"
+	JobSucceeded = "succeeded"
+	JobFailed    = "failed"
+	JobCancelled = "cancelled"
+)
+
+// DefaultJobTTL is how long finished jobs are kept by default.
+const DefaultJobTTL = time.Hour
+
+// Job is a tool call submitted to run in the background. Result and Error
+// are as in BatchResult. Session is the ID of the client session that
+// submitted the job; the job tools show a job to that session only.
+type Job struct {
+	ID          string                 `json:"job_id"`
+	Tool        string                 `json:"tool"`
+	Session     string                 `json:"session,omitempty"`
+	Arguments   map[string]interface{} `json:"arguments,omitempty"`
+	State       string                 `json:"state"`
+	SubmittedAt time.Time              `json:"submitted_at"`
+	StartedAt   *time.Time             `json:"started_at,omitempty"`
+	EndedAt     *time.Time             `json:"ended_at,omitempty"`
+	Result      interface{}            `json:"result,omitempty"`
+	Error       string                 `json:"error,omitempty"`
+}
+
+// Finished reports whether the job has stopped running for good.
+func (j *Job) Finished() bool {
+	return j.State != JobQueued && j.State != JobRunning
+}
+
+// JobStore persists jobs. Save is called with a new job and again on every
+// change of state. Load returns nil and no error when there is no job with
+// the ID. The wrapper calls the store from several goroutines.
+type JobStore interface {
+	Save(ctx context.Context, job *Job) error
+	Load(ctx context.Context, id string) (*Job, error)
+	List(ctx context.Context) ([]*Job, error)
+	Delete(ctx context.Context, id string) error
+}
+
+// memoryJobStore is the JobStore used when none is given. Jobs are lost
+// when the server stops.
+type memoryJobStore struct {
+	mu   sync.Mutex
+	jobs map[string]Job
+}
+
+// NewMemoryJobStore returns a JobStore that keeps jobs in memory.
+func NewMemoryJobStore() JobStore {
+	return &memoryJobStore{jobs: make(map[string]Job)}
+}
+
+func (s *memoryJobStore) Save(ctx context.Context, job *Job) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.jobs[job.ID] = *job
+	return nil
+}
+
+func (s *memoryJobStore) Load(ctx context.Context, id string) (*Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job, ok := s.jobs[id]
+	if !ok {
+		return nil, nil
+	}
+	return &job, nil
+}
+
+func (s *memoryJobStore) List(ctx context.Context) ([]*Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	jobs := make([]*Job, 0, len(s.jobs))
+	for _, job := range s.jobs {
+		job := job
+		jobs = append(jobs, &job)
+	}
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].SubmittedAt.Before(jobs[j].SubmittedAt) })
+	return jobs, nil
+}
+
+func (s *memoryJobStore) Delete(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.jobs, id)
+	return nil
+}
+
+// JobOption configures the job tools added with RegisterJobs.
+type JobOption func(*jobQueue)
+
+// WithJobStore sets where jobs are kept. The default is
+// NewMemoryJobStore.
+func WithJobStore(store JobStore) JobOption {
+	return func(q *jobQueue) {
+		q.store = store
+	}
+}
+
+// WithJobTTL sets how long finished jobs are kept; older ones are deleted
+// when a job is submitted. The default is DefaultJobTTL.
+func WithJobTTL(ttl time.Duration) JobOption {
+	return func(q *jobQueue) {
+		q.ttl = ttl
+	}
+}
+
+// WithJobToolOptions applies opts to each of the job tools.
+func WithJobToolOptions(opts ...ToolOption) JobOption {
+	return func(q *jobQueue) {
+		q.toolOptions = append(q.toolOptions, opts...)
+	}
+}
+
+type jobSubmitArgs struct {
+	Tool      string                 `json:"tool" jsonschema:"required,description=Tool to call" validate:"required"`
+	Arguments map[string]interface{} `json:"arguments,omitempty" jsonschema:"description=Arguments of the call"`
+}
+
+type jobArgs struct {
+	JobID string `json:"job_id" jsonschema:"required,description=Job ID returned by submit" validate:"required"`
+}
+
+type jobResultArgs struct {
+	JobID  string `json:"job_id" jsonschema:"required,description=Job ID returned by submit" validate:"required"`
+	WaitMs int    `json:"wait_ms,omitempty" jsonschema:"description=Milliseconds to wait for the job to finish (default 0)" validate:"gte=0"`
+}
+
+// jobToolNames are the tools RegisterJobs adds; they cannot be submitted.
+var jobToolNames = map[string]bool{"submit": true, "job_status": true, "job_result": true, "job_cancel": true}
+
+// jobQueue runs submitted calls in submission order, at most
+// maxConcurrency at a time.
+type jobQueue struct {
+	store          JobStore
+	ttl            time.Duration
+	toolOptions    []ToolOption
+	maxConcurrency int
+
+	mu      sync.Mutex
+	live    map[string]*liveJob
+	pending []*liveJob
+	running int
+}
+
+// liveJob is a job queued or running in this process. job is only changed
+// by the goroutine running it, or by cancel while it is pending. dropped is
+// set, under the queue's lock, once the job's session has ended.
+type liveJob struct {
+	job     *Job
+	ctx     context.Context
+	cancel  context.CancelFunc
+	done    chan struct{}
+	dropped bool
+}
+
+// RegisterJobs adds tools that run other tools' calls in the background, so
+// slow calls don't run into client timeouts:
+//
+//   - submit queues a call and returns its job at once.
+//   - job_status reports a job's state.
+//   - job_result returns the call's result once the job has finished,
+//     optionally waiting for it.
+//   - job_cancel cancels a queued or running job.
+//
+// At most maxConcurrency jobs run at a time, one if it is below 1; the
+// others stay queued until a slot frees up. Each job goes through the called tool's
+// full pipeline, as a batch call does, with the values of the submit call's
+// context, such as the caller's identity. Jobs belong to the session that
+// submitted them, and are cancelled and deleted by EndSession.
+func (w *Wrapper) RegisterJobs(maxConcurrency int, opts ...JobOption) error {
+	if maxConcurrency < 1 {
+		maxConcurrency = 1
+	}
+	q := &jobQueue{
+		store: NewMemoryJobStore(),
+		ttl:   DefaultJobTTL,
+		live:  make(map[string]*liveJob),
+
+		maxConcurrency: maxConcurrency,
+	}
+	for _, opt := range opts {
+		opt(q)
+	}
+
+	tools := []struct {
+		name        string
+		description string
+		argsType    interface{}
+		handler     Handler
+	}{
+		{"submit", "Start a tool call in the background and return its job_id at once. Use it for slow tools.", jobSubmitArgs{}, q.submitHandler(w)},
+		{"job_status", "Report the state of a job started with submit.", jobArgs{}, q.statusHandler()},
+		{"job_result", "Get the result of a job started with submit, waiting up to wait_ms for it to finish.", jobResultArgs{}, q.resultHandler()},
+		{"job_cancel", "Cancel a job started with submit.", jobArgs{}, q.cancelHandler(w)},
+	}
+	for _, tool := range tools {
+		if err := w.Register(tool.name, tool.description, tool.argsType, tool.handler, q.toolOptions...); err != nil {
+			return err
+		}
+	}
+	w.jobs = q
+	return nil
+}
+
+func (q *jobQueue) submitHandler(w *Wrapper) Handler {
+	return func(ctx context.Context, args interface{}) (interface{}, error) {
+		a := args.(*jobSubmitArgs)
+		if jobToolNames[a.Tool] {
+			return nil, InvalidInput("%s cannot be submitted as a job", a.Tool)
+		}
+		if w.server.GetTool(a.Tool) == nil {
+			return nil, NotFound("tool %s not found", a.Tool)
+		}
+		q.prune(ctx, w)
+
+		job := &Job{
+			ID:          newJobID(),
+			Tool:        a.Tool,
+			Session:     SessionIDFromContext(ctx),
+			Arguments:   a.Arguments,
+			State:       JobQueued,
+			SubmittedAt: time.Now(),
+		}
+		// The job outlives the call, so it keeps only the call's values.
+		jobCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		live := &liveJob{job: job, ctx: jobCtx, cancel: cancel, done: make(chan struct{})}
+		q.mu.Lock()
+		q.live[job.ID] = live
+		q.mu.Unlock()
+		if err := q.store.Save(ctx, job); err != nil {
+			q.mu.Lock()
+			delete(q.live, job.ID)
+			q.mu.Unlock()
+			cancel()
+			return nil, fmt.Errorf("failed to save job: %w", err)
+		}
+		status := *job
+		q.mu.Lock()
+		q.pending = append(q.pending, live)
+		q.mu.Unlock()
+		q.dispatch(w)
+		return &status, nil
+	}
+}
+
+// dispatch starts pending jobs while there are free slots.
+func (q *jobQueue) dispatch(w *Wrapper) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for q.running < q.maxConcurrency && len(q.pending) > 0 {
+		live := q.pending[0]
+		q.pending = q.pending[1:]
+		q.running++
+		go q.run(w, live)
+	}
+}
+
+func (q *jobQueue) run(w *Wrapper, live *liveJob) {
+	job := live.job
+	defer func() {
+		q.mu.Lock()
+		q.running--
+		q.mu.Unlock()
+		q.end(live)
+		q.dispatch(w)
+	}()
+
+	started := time.Now()
+	job.State = JobRunning
+	job.StartedAt = &started
+	q.saveLive(w, live)
+
+	outcome := w.runBatchCall(live.ctx, "", BatchCall{Tool: job.Tool, Arguments: job.Arguments})
+	switch {
+	case live.ctx.Err() != nil:
+		job.finish(JobCancelled, nil, outcome.Error)
+	case outcome.Error != "":
+		job.finish(JobFailed, nil, outcome.Error)
+	default:
+		job.finish(JobSucceeded, outcome.Result, "")
+	}
+	q.saveLive(w, live)
+}
+
+// end forgets a job that has finished.
+func (q *jobQueue) end(live *liveJob) {
+	q.mu.Lock()
+	delete(q.live, live.job.ID)
+	q.mu.Unlock()
+	live.cancel()
+	close(live.done)
+}
+
+func (q *jobQueue) save(w *Wrapper, job *Job) {
+	if err := q.store.Save(context.Background(), job); err != nil {
+		w.logger.Error("failed to save job", "job_id", job.ID, "tool", job.Tool, "error", err)
+	}
+}
+
+// saveLive saves a live job unless its session has ended, so that a job
+// EndSession deleted is not stored again.
+func (q *jobQueue) saveLive(w *Wrapper, live *liveJob) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if !live.dropped {
+		q.save(w, live.job)
+	}
+}
+
+// unqueue removes live from the pending jobs, reporting whether it was
+// still there.
+func (q *jobQueue) unqueue(live *liveJob) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for i, pending := range q.pending {
+		if pending == live {
+			q.pending = append(q.pending[:i], q.pending[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func (j *Job) finish(state string, result interface{}, errMsg string) {
+	ended := time.Now()
+	j.State = state
+	j.EndedAt = &ended
+	j.Result = result
+	j.Error = errMsg
+}
+
+// load returns the stored job, if the calling session submitted it. A job
+// the store has as unfinished but that is not live was interrupted by a
+// restart, and is reported as failed. Live jobs are looked up first: a job
+// stops being live only after its final state is saved, so one that
+// finishes in between is not mistaken for an interrupted one.
+func (q *jobQueue) load(ctx context.Context, id string) (*Job, *liveJob, error) {
+	q.mu.Lock()
+	live := q.live[id]
+	q.mu.Unlock()
+	job, err := q.store.Load(ctx, id)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load job %s: %w", id, err)
+	}
+	if job == nil || job.Session != SessionIDFromContext(ctx) {
+		return nil, nil, NotFound("no job %q", id)
+	}
+	if live == nil && !job.Finished() {
+		job.finish(JobFailed, nil, "the server stopped before the job finished")
+		if err := q.store.Save(ctx, job); err != nil {
+			return nil, nil, fmt.Errorf("failed to save job %s: %w", id, err)
+		}
+	}
+	return job, live, nil
+}
+
+func (q *jobQueue) statusHandler() Handler {
+	return func(ctx context.Context, args interface{}) (interface{}, error) {
+		job, _, err := q.load(ctx, args.(*jobArgs).JobID)
+		if err != nil {
+			return nil, err
+		}
+		job.Result = nil
+		return job, nil
+	}
+}
+
+func (q *jobQueue) resultHandler() Handler {
+	return func(ctx context.Context, args interface{}) (interface{}, error) {
+		a := args.(*jobResultArgs)
+		job, live, err := q.load(ctx, a.JobID)
+		if err != nil {
+			return nil, err
+		}
+		if live != nil && a.WaitMs > 0 {
+			timer := time.NewTimer(time.Duration(a.WaitMs) * time.Millisecond)
+			defer timer.Stop()
+			select {
+			case <-live.done:
+				if job, _, err = q.load(ctx, a.JobID); err != nil {
+					return nil, err
+				}
+			case <-timer.C:
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+
+		switch job.State {
+		case JobSucceeded:
+			return job.Result, nil
+		case JobFailed:
+			return nil, fmt.Errorf("job %s failed: %s", job.ID, job.Error)
+		case JobCancelled:
+			return nil, fmt.Errorf("job %s was cancelled", job.ID)
+		default:
+			return nil, Unavailable("job %s is still %s", job.ID, job.State)
+		}
+	}
+}
+
+func (q *jobQueue) cancelHandler(w *Wrapper) Handler {
+	return func(ctx context.Context, args interface{}) (interface{}, error) {
+		id := args.(*jobArgs).JobID
+		job, live, err := q.load(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if live != nil {
+			if q.unqueue(live) {
+				live.job.finish(JobCancelled, nil, "cancelled before it started")
+				q.save(w, live.job)
+				q.end(live)
+			} else {
+				live.cancel()
+				select {
+				case <-live.done:
+				case <-ctx.Done():
+					return nil, ctx.Err()
+				}
+			}
+			if job, _, err = q.load(ctx, id); err != nil {
+				return nil, err
+			}
+		}
+		job.Result = nil
+		return job, nil
+	}
+}
+
+// prune deletes the jobs that finished more than the TTL ago.
+func (q *jobQueue) prune(ctx context.Context, w *Wrapper) {
+	jobs, err := q.store.List(ctx)
+	if err != nil {
+		w.logger.Warn("failed to list jobs", "error", err)
+		return
+	}
+	cutoff := time.Now().Add(-q.ttl)
+	for _, job := range jobs {
+		if job.Finished() && job.EndedAt != nil && job.EndedAt.Before(cutoff) {
+			if err := q.store.Delete(ctx, job.ID); err != nil {
+				w.logger.Warn("failed to delete job", "job_id", job.ID, "error", err)
+			}
+		}
+	}
+}
+
+// endSession cancels the jobs a session submitted and deletes them from the
+// store, since no other session can reach them.
+func (q *jobQueue) endSession(w *Wrapper, sessionID string) {
+	ctx := context.Background()
+	jobs, err := q.store.List(ctx)
+	if err != nil {
+		w.logger.Warn("failed to list jobs", "error", err)
+		return
+	}
+	for _, job := range jobs {
+		if job.Session != sessionID {
+			continue
+		}
+		q.mu.Lock()
+		live := q.live[job.ID]
+		if live != nil {
+			live.dropped = true
+		}
+		q.mu.Unlock()
+		if live != nil {
+			if q.unqueue(live) {
+				q.end(live)
+			} else {
+				live.cancel()
+			}
+		}
+		if err := q.store.Delete(ctx, job.ID); err != nil {
+			w.logger.Warn("failed to delete job", "job_id", job.ID, "error", err)
+		}
+	}
+}
@@ -0,0 +1,203 @@
+package mcpwrapper
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+type SlowArgs struct {
+	Name string `json:"name" validate:"required"`
+}
+
+func newJobsServer(t *testing.T, release chan struct{}, opts ...JobOption) *server.MCPServer {
+	t.Helper()
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	wrapper := New(mcpServer)
+	err := wrapper.Register("slow", "A slow tool", SlowArgs{}, func(ctx context.Context, args interface{}) (interface{}, error) {
+		select {
+		case <-release:
+			return &TestResult{Message: "done " + args.(*SlowArgs).Name}, nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	})
+	if err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	if err := wrapper.RegisterJobs(1, opts...); err != nil {
+		t.Fatalf("RegisterJobs failed: %v", err)
+	}
+	return mcpServer
+}
+
+func resultText(result *mcp.CallToolResult) string {
+	return result.Content[0].(mcp.TextContent).Text
+}
+
+func TestRegisterJobs(t *testing.T) {
+	release := make(chan struct{})
+	mcpServer := newJobsServer(t, release)
+
+	var first, second Job
+	decodeResult(t, callTool(t, mcpServer, "submit", map[string]interface{}{"tool": "slow", "arguments": map[string]interface{}{"name": "a"}}), &first)
+	decodeResult(t, callTool(t, mcpServer, "submit", map[string]interface{}{"tool": "slow", "arguments": map[string]interface{}{"name": "b"}}), &second)
+	if first.ID == "" || first.State != JobQueued || first.Tool != "slow" {
+		t.Fatalf("Unexpected submitted job: %+v", first)
+	}
+
+	status := func(id string) Job {
+		var job Job
+		decodeResult(t, callTool(t, mcpServer, "job_status", map[string]interface{}{"job_id": id}), &job)
+		return job
+	}
+	waitFor(t, "the first job to run", func() bool { return status(first.ID).State == JobRunning })
+	if job := status(second.ID); job.State != JobQueued {
+		t.Errorf("Expected the second job to wait for a slot, got %s", job.State)
+	}
+
+	result := callTool(t, mcpServer, "job_result", map[string]interface{}{"job_id": first.ID, "wait_ms": 10})
+	if !result.IsError || !strings.Contains(resultText(result), "still running") {
+		t.Errorf("Expected an unavailable error for a running job, got %v", result.Content)
+	}
+
+	release <- struct{}{}
+	var output TestResult
+	decodeResult(t, callTool(t, mcpServer, "job_result", map[string]interface{}{"job_id": first.ID, "wait_ms": 5000}), &output)
+	if output.Message != "done a" {
+		t.Errorf("Expected the tool's result, got %+v", output)
+	}
+	if job := status(first.ID); job.State != JobSucceeded || job.EndedAt == nil || job.Result != nil {
+		t.Errorf("Unexpected finished status: %+v", job)
+	}
+
+	waitFor(t, "the second job to run", func() bool { return status(second.ID).State == JobRunning })
+	var third, cancelled Job
+	decodeResult(t, callTool(t, mcpServer, "submit", map[string]interface{}{"tool": "slow", "arguments": map[string]interface{}{"name": "c"}}), &third)
+	decodeResult(t, callTool(t, mcpServer, "job_cancel", map[string]interface{}{"job_id": third.ID}), &cancelled)
+	if cancelled.State != JobCancelled || cancelled.StartedAt != nil {
+		t.Errorf("Expected the queued job to be cancelled before it started, got %+v", cancelled)
+	}
+	decodeResult(t, callTool(t, mcpServer, "job_cancel", map[string]interface{}{"job_id": second.ID}), &cancelled)
+	if cancelled.State != JobCancelled {
+		t.Errorf("Expected the job to be cancelled, got %+v", cancelled)
+	}
+	result = callTool(t, mcpServer, "job_result", map[string]interface{}{"job_id": second.ID})
+	if !result.IsError || !strings.Contains(resultText(result), "was cancelled") {
+		t.Errorf("Expected an error for a cancelled job, got %v", result.Content)
+	}
+}
+
+func TestJobsBelongToSession(t *testing.T) {
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	wrapper := New(mcpServer)
+	err := wrapper.Register("slow", "A slow tool", SlowArgs{}, func(ctx context.Context, args interface{}) (interface{}, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	if err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	store := NewMemoryJobStore()
+	if err := wrapper.RegisterJobs(1, WithJobStore(store)); err != nil {
+		t.Fatalf("RegisterJobs failed: %v", err)
+	}
+
+	call := func(sessionID, name string, args map[string]interface{}) *mcp.CallToolResult {
+		t.Helper()
+		ctx := mcpServer.WithContext(context.Background(), &testSession{id: sessionID})
+		result, err := mcpServer.GetTool(name).Handler(ctx, mcp.CallToolRequest{
+			Params: mcp.CallToolParams{Name: name, Arguments: args},
+		})
+		if err != nil {
+			t.Fatalf("Handler returned error: %v", err)
+		}
+		return result
+	}
+
+	var job Job
+	decodeResult(t, call("alice", "submit", map[string]interface{}{"tool": "slow", "arguments": map[string]interface{}{"name": "a"}}), &job)
+	if job.Session != "alice" {
+		t.Errorf("Expected the job to record its session, got %+v", job)
+	}
+	for _, tool := range []string{"job_status", "job_result", "job_cancel"} {
+		result := call("bob", tool, map[string]interface{}{"job_id": job.ID})
+		if !result.IsError || !strings.Contains(resultText(result), "no job") {
+			t.Errorf("Expected %s from another session to find no job, got %v", tool, result.Content)
+		}
+	}
+	if result := call("alice", "job_status", map[string]interface{}{"job_id": job.ID}); result.IsError {
+		t.Errorf("Expected the submitting session to see its job, got %v", result.Content)
+	}
+
+	waitFor(t, "the job to run", func() bool {
+		stored, _ := store.Load(context.Background(), job.ID)
+		return stored != nil && stored.State == JobRunning
+	})
+	wrapper.EndSession("alice")
+	waitFor(t, "the job to be dropped", func() bool {
+		jobs, _ := store.List(context.Background())
+		wrapper.jobs.mu.Lock()
+		defer wrapper.jobs.mu.Unlock()
+		return len(jobs) == 0 && len(wrapper.jobs.live) == 0
+	})
+	if result := call("alice", "job_status", map[string]interface{}{"job_id": job.ID}); !result.IsError {
+		t.Errorf("Expected the job to be gone after its session ended, got %v", result.Content)
+	}
+}
+
+func TestRegisterJobsErrors(t *testing.T) {
+	mcpServer := newJobsServer(t, make(chan struct{}))
+	tests := []struct {
+		name string
+		tool string
+		args map[string]interface{}
+		want string
+	}{
+		{"unknown tool", "submit", map[string]interface{}{"tool": "missing"}, "not_found"},
+		{"job tool", "submit", map[string]interface{}{"tool": "job_status"}, "invalid_input"},
+		{"unknown job", "job_status", map[string]interface{}{"job_id": "nope"}, "not_found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := callTool(t, mcpServer, tt.tool, tt.args)
+			if !result.IsError || !strings.Contains(resultText(result), tt.want) {
+				t.Errorf("Expected a %s error, got %v", tt.want, result.Content)
+			}
+		})
+	}
+
+	var job Job
+	decodeResult(t, callTool(t, mcpServer, "submit", map[string]interface{}{"tool": "slow"}), &job)
+	result := callTool(t, mcpServer, "job_result", map[string]interface{}{"job_id": job.ID, "wait_ms": 5000})
+	if !result.IsError || !strings.Contains(resultText(result), "failed") {
+		t.Errorf("Expected the validation error as the job's error, got %v", result.Content)
+	}
+}
+
+func TestJobStoreRestartAndTTL(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryJobStore()
+	ended := time.Now().Add(-2 * time.Hour)
+	store.Save(ctx, &Job{ID: "interrupted", Tool: "slow", State: JobRunning, SubmittedAt: ended})
+	store.Save(ctx, &Job{ID: "old", Tool: "slow", State: JobSucceeded, SubmittedAt: ended, EndedAt: &ended})
+
+	release := make(chan struct{})
+	close(release)
+	mcpServer := newJobsServer(t, release, WithJobStore(store))
+
+	var job Job
+	decodeResult(t, callTool(t, mcpServer, "job_status", map[string]interface{}{"job_id": "interrupted"}), &job)
+	if job.State != JobFailed || !strings.Contains(job.Error, "server stopped") {
+		t.Errorf("Expected a job interrupted by a restart to fail, got %+v", job)
+	}
+
+	callTool(t, mcpServer, "submit", map[string]interface{}{"tool": "slow", "arguments": map[string]interface{}{"name": "a"}})
+	if old, _ := store.Load(ctx, "old"); old != nil {
+		t.Error("Expected a job past the TTL to be deleted")
+	}
+}
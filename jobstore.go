@@ -0,0 +1,149 @@
+package mcpwrapper
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// jobIDPattern matches the IDs a file store accepts, so that an ID sent by
+// a client cannot name a file outside the store's directory.
+var jobIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// fileJobStore keeps each job in its own file, <dir>/<id>.job.
+type fileJobStore struct {
+	dir       string
+	encryptor *Encryptor
+	mu        sync.Mutex
+}
+
+// NewFileJobStore returns a JobStore that keeps jobs as files in dir, so
+// they survive restarts. dir is created if needed. Files are written
+// atomically, so a crash leaves either the old or the new state of a job.
+// If encryptor is not nil, files are sealed with it, since results and
+// arguments may hold sensitive data. Only one server should use dir at a
+// time.
+func NewFileJobStore(dir string, encryptor *Encryptor) (JobStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create job store: %w", err)
+	}
+	return &fileJobStore{dir: dir, encryptor: encryptor}, nil
+}
+
+func (s *fileJobStore) path(id string) string {
+	return filepath.Join(s.dir, id+".job")
+}
+
+// encodeJob marshals a job for a store, sealing it if encryptor is not
+// nil.
+func encodeJob(job *Job, encryptor *Encryptor) ([]byte, error) {
+	data, err := json.Marshal(job)
+	if err != nil || encryptor == nil {
+		return data, err
+	}
+	return encryptor.Seal(data)
+}
+
+// decodeJob reverses encodeJob.
+func decodeJob(data []byte, encryptor *Encryptor) (*Job, error) {
+	if encryptor != nil {
+		var err error
+		if data, err = encryptor.Open(data); err != nil {
+			return nil, err
+		}
+	}
+	var job Job
+	if err := json.Unmarshal(data, &job); err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+func (s *fileJobStore) Save(ctx context.Context, job *Job) error {
+	if !jobIDPattern.MatchString(job.ID) {
+		return fmt.Errorf("invalid job ID %q", job.ID)
+	}
+	data, err := encodeJob(job, s.encryptor)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tmp, err := os.CreateTemp(s.dir, ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path(job.ID))
+}
+
+func (s *fileJobStore) Load(ctx context.Context, id string) (*Job, error) {
+	if !jobIDPattern.MatchString(id) {
+		return nil, nil
+	}
+	s.mu.Lock()
+	data, err := os.ReadFile(s.path(id))
+	s.mu.Unlock()
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return decodeJob(data, s.encryptor)
+}
+
+func (s *fileJobStore) List(ctx context.Context) ([]*Job, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+	var jobs []*Job
+	for _, entry := range entries {
+		id, ok := strings.CutSuffix(entry.Name(), ".job")
+		if !ok || entry.IsDir() {
+			continue
+		}
+		job, err := s.Load(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("job %s: %w", id, err)
+		}
+		if job != nil {
+			jobs = append(jobs, job)
+		}
+	}
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].SubmittedAt.Before(jobs[j].SubmittedAt) })
+	return jobs, nil
+}
+
+func (s *fileJobStore) Delete(ctx context.Context, id string) error {
+	if !jobIDPattern.MatchString(id) {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := os.Remove(s.path(id)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
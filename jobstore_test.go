@@ -0,0 +1,137 @@
+package mcpwrapper
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func TestJobStores(t *testing.T) {
+	encryptor := NewEncryptor(StaticSecrets("k1", map[string][]byte{"k1": bytes.Repeat([]byte{7}, 32)}))
+	stores := map[string]func(t *testing.T) JobStore{
+		"memory": func(t *testing.T) JobStore { return NewMemoryJobStore() },
+		"file": func(t *testing.T) JobStore {
+			store, err := NewFileJobStore(filepath.Join(t.TempDir(), "jobs"), nil)
+			if err != nil {
+				t.Fatalf("NewFileJobStore failed: %v", err)
+			}
+			return store
+		},
+		"encrypted file": func(t *testing.T) JobStore {
+			store, err := NewFileJobStore(t.TempDir(), encryptor)
+			if err != nil {
+				t.Fatalf("NewFileJobStore failed: %v", err)
+			}
+			return store
+		},
+	}
+	for name, newStore := range stores {
+		t.Run(name, func(t *testing.T) {
+			testJobStore(t, newStore(t))
+		})
+	}
+}
+
+// testJobStore checks the JobStore contract against an empty store.
+func testJobStore(t *testing.T, store JobStore) {
+	t.Helper()
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Second)
+	first := &Job{ID: "a1", Tool: "backup", State: JobQueued, SubmittedAt: now}
+	second := &Job{ID: "b2", Tool: "backup", State: JobQueued, SubmittedAt: now.Add(time.Second)}
+	for _, job := range []*Job{second, first} {
+		if err := store.Save(ctx, job); err != nil {
+			t.Fatalf("Save failed: %v", err)
+		}
+	}
+	first.finish(JobSucceeded, map[string]interface{}{"api_key": "secret"}, "")
+	if err := store.Save(ctx, first); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	loaded, err := store.Load(ctx, "a1")
+	if err != nil || loaded == nil || loaded.State != JobSucceeded || loaded.Result.(map[string]interface{})["api_key"] != "secret" {
+		t.Errorf("Unexpected loaded job: %+v, %v", loaded, err)
+	}
+	for _, id := range []string{"missing", "../a1"} {
+		if job, err := store.Load(ctx, id); job != nil || err != nil {
+			t.Errorf("Expected no job for %q, got %+v, %v", id, job, err)
+		}
+	}
+
+	jobs, err := store.List(ctx)
+	if err != nil || len(jobs) != 2 || jobs[0].ID != "a1" || jobs[1].ID != "b2" {
+		t.Fatalf("Expected both jobs in submission order, got %+v, %v", jobs, err)
+	}
+	if err := store.Delete(ctx, "a1"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if job, _ := store.Load(ctx, "a1"); job != nil {
+		t.Error("Expected the job to be deleted")
+	}
+}
+
+func TestFileJobStoreEncryption(t *testing.T) {
+	dir := t.TempDir()
+	encryptor := NewEncryptor(StaticSecrets("k1", map[string][]byte{"k1": bytes.Repeat([]byte{7}, 32)}))
+	store, err := NewFileJobStore(dir, encryptor)
+	if err != nil {
+		t.Fatalf("NewFileJobStore failed: %v", err)
+	}
+	store.Save(context.Background(), &Job{ID: "a1", Tool: "login", Arguments: map[string]interface{}{"password": "hunter2"}})
+	data, err := os.ReadFile(filepath.Join(dir, "a1.job"))
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+	if bytes.Contains(data, []byte("hunter2")) {
+		t.Error("Expected the job file to be encrypted")
+	}
+}
+
+func TestFileJobStoreRestart(t *testing.T) {
+	dir := t.TempDir()
+	newStore := func() JobStore {
+		store, err := NewFileJobStore(dir, nil)
+		if err != nil {
+			t.Fatalf("NewFileJobStore failed: %v", err)
+		}
+		return store
+	}
+
+	release := make(chan struct{}, 1)
+	before := newJobsServer(t, release, WithJobStore(newStore()))
+	var done, interrupted Job
+	decodeResult(t, callTool(t, before, "submit", map[string]interface{}{"tool": "slow", "arguments": map[string]interface{}{"name": "a"}}), &done)
+	release <- struct{}{}
+	decodeResult(t, callTool(t, before, "job_result", map[string]interface{}{"job_id": done.ID, "wait_ms": 5000}), &TestResult{})
+	decodeResult(t, callTool(t, before, "submit", map[string]interface{}{"tool": "slow", "arguments": map[string]interface{}{"name": "b"}}), &interrupted)
+	waitForJobState(t, before, interrupted.ID, JobRunning)
+
+	after := newJobsServer(t, make(chan struct{}), WithJobStore(newStore()))
+	var output TestResult
+	decodeResult(t, callTool(t, after, "job_result", map[string]interface{}{"job_id": done.ID}), &output)
+	if output.Message != "done a" {
+		t.Errorf("Expected the result to survive the restart, got %+v", output)
+	}
+	result := callTool(t, after, "job_result", map[string]interface{}{"job_id": interrupted.ID})
+	if !result.IsError || !strings.Contains(resultText(result), "server stopped") {
+		t.Errorf("Expected the interrupted job to have failed, got %v", result.Content)
+	}
+}
+
+// waitForJobState waits until a job has reached state, so that the server
+// no longer writes to its store while the job is blocked.
+func waitForJobState(t *testing.T, mcpServer *server.MCPServer, id, state string) {
+	t.Helper()
+	waitFor(t, "job "+id+" to be "+state, func() bool {
+		var job Job
+		decodeResult(t, callTool(t, mcpServer, "job_status", map[string]interface{}{"job_id": id}), &job)
+		return job.State == state
+	})
+}
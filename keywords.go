@@ -0,0 +1,280 @@
+package mcpwrapper
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// WithStrictSchema enforces the jsonschema tag keywords at bind time:
+// minimum, maximum, exclusiveMinimum, exclusiveMaximum, multipleOf,
+// minLength, maxLength, pattern, format, const and uniqueItems. Without it
+// they are only advertised in the schema, and the validate tags are the
+// source of truth.
+func WithStrictSchema() Option {
+	return func(w *Wrapper) {
+		w.strictSchema = true
+	}
+}
+
+// splitPattern separates a pattern= segment from the rest of a jsonschema
+// tag. The pattern takes the rest of the tag, so it may contain commas as
+// long as it comes last.
+func splitPattern(tag string) (string, string, bool) {
+	idx := -1
+	if strings.HasPrefix(strings.TrimSpace(tag), "pattern=") {
+		idx = strings.Index(tag, "pattern=")
+	} else if i := strings.Index(tag, ",pattern="); i >= 0 {
+		idx = i + 1
+	} else if i := strings.Index(tag, ", pattern="); i >= 0 {
+		idx = i + 2
+	}
+	if idx < 0 {
+		return tag, "", false
+	}
+	rest := strings.TrimRight(strings.TrimSpace(tag[:idx]), ",")
+	return rest, strings.TrimPrefix(tag[idx:], "pattern="), true
+}
+
+var patternCache sync.Map
+
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	if re, ok := patternCache.Load(pattern); ok {
+		return re.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	patternCache.Store(pattern, re)
+	return re, nil
+}
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+var hostnamePattern = regexp.MustCompile(`^([a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)(\.[a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)*$`)
+
+// validFormat reports whether s matches a JSON Schema format. Formats the
+// wrapper doesn't know are accepted.
+func validFormat(format, s string) bool {
+	switch format {
+	case "date-time":
+		_, err := time.Parse(time.RFC3339, s)
+		return err == nil
+	case "date":
+		_, err := time.Parse("2006-01-02", s)
+		return err == nil
+	case "time":
+		_, err := time.Parse("15:04:05Z07:00", s)
+		return err == nil
+	case "email":
+		addr, err := mail.ParseAddress(s)
+		return err == nil && addr.Address == s
+	case "uri":
+		u, err := url.Parse(s)
+		return err == nil && u.Scheme != ""
+	case "uuid":
+		return uuidPattern.MatchString(s)
+	case "ipv4":
+		ip := net.ParseIP(s)
+		return ip != nil && ip.To4() != nil && !strings.Contains(s, ":")
+	case "ipv6":
+		ip := net.ParseIP(s)
+		return ip != nil && strings.Contains(s, ":")
+	case "hostname":
+		return len(s) <= 253 && hostnamePattern.MatchString(s)
+	default:
+		return true
+	}
+}
+
+// validateKeywords checks every field against the keywords of its jsonschema
+// tag, including the fields of nested structs and of structs in slices.
+// Zero values of non-pointer fields are skipped, as in validateEnums.
+func validateKeywords(v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var errs ValidationErrors
+	collectKeywordViolations(rv, "", &errs)
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func collectKeywordViolations(rv reflect.Value, prefix string, errs *ValidationErrors) {
+	t := rv.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		fv := rv.Field(i)
+		if fv.Kind() == reflect.Ptr {
+			if fv.IsNil() {
+				continue
+			}
+			fv = fv.Elem()
+		} else if fv.IsZero() {
+			continue
+		}
+		name := prefix + field.Name
+
+		if tag := field.Tag.Get("jsonschema"); tag != "" {
+			for _, msg := range keywordViolations(tag, fv) {
+				*errs = append(*errs, ValidationError{Field: name, Message: msg})
+			}
+		}
+
+		switch fv.Kind() {
+		case reflect.Struct:
+			collectKeywordViolations(fv, name+".", errs)
+		case reflect.Slice, reflect.Array:
+			for j := 0; j < fv.Len(); j++ {
+				item := fv.Index(j)
+				if item.Kind() == reflect.Ptr {
+					if item.IsNil() {
+						continue
+					}
+					item = item.Elem()
+				}
+				if item.Kind() == reflect.Struct {
+					collectKeywordViolations(item, fmt.Sprintf("%s[%d].", name, j), errs)
+				}
+			}
+		}
+	}
+}
+
+func keywordViolations(tag string, v reflect.Value) []string {
+	rest, pattern, hasPattern := splitPattern(tag)
+
+	var msgs []string
+	switch v.Kind() {
+	case reflect.String:
+		s := v.String()
+		length := float64(utf8.RuneCountInString(s))
+		if n, ok := tagNumber(rest, "minLength"); ok && length < n {
+			msgs = append(msgs, fmt.Sprintf("must be at least %s characters long", formatNumber(n)))
+		}
+		if n, ok := tagNumber(rest, "maxLength"); ok && length > n {
+			msgs = append(msgs, fmt.Sprintf("must be at most %s characters long", formatNumber(n)))
+		}
+		if hasPattern {
+			if re, err := compilePattern(pattern); err == nil && !re.MatchString(s) {
+				msgs = append(msgs, fmt.Sprintf("must match pattern %s", pattern))
+			}
+		}
+		if format, ok := lastTagValue(rest, "format"); ok && !validFormat(format, s) {
+			msgs = append(msgs, fmt.Sprintf("must be a valid %s", format))
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		x := numericValue(v)
+		if n, ok := tagNumber(rest, "minimum"); ok && x < n {
+			msgs = append(msgs, fmt.Sprintf("must be greater than or equal to %s", formatNumber(n)))
+		}
+		if n, ok := tagNumber(rest, "maximum"); ok && x > n {
+			msgs = append(msgs, fmt.Sprintf("must be less than or equal to %s", formatNumber(n)))
+		}
+		if n, ok := tagNumber(rest, "exclusiveMinimum"); ok && x <= n {
+			msgs = append(msgs, fmt.Sprintf("must be greater than %s", formatNumber(n)))
+		}
+		if n, ok := tagNumber(rest, "exclusiveMaximum"); ok && x >= n {
+			msgs = append(msgs, fmt.Sprintf("must be less than %s", formatNumber(n)))
+		}
+		if n, ok := tagNumber(rest, "multipleOf"); ok && n > 0 && !isMultipleOf(x, n) {
+			msgs = append(msgs, fmt.Sprintf("must be a multiple of %s", formatNumber(n)))
+		}
+	case reflect.Slice, reflect.Array:
+		if hasTagFlag(rest, "uniqueItems") && !uniqueItems(v) {
+			msgs = append(msgs, "must not contain duplicate items")
+		}
+	}
+
+	if c, ok := lastTagValue(rest, "const"); ok && !enumContains([]string{c}, v) {
+		msgs = append(msgs, fmt.Sprintf("must be %s", c))
+	}
+	return msgs
+}
+
+func lastTagValue(tag, key string) (string, bool) {
+	values := tagValues(tag, key)
+	if len(values) == 0 {
+		return "", false
+	}
+	return values[len(values)-1], true
+}
+
+func tagNumber(tag, key string) (float64, bool) {
+	s, ok := lastTagValue(tag, key)
+	if !ok {
+		return 0, false
+	}
+	n, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, false
+	}
+	return n, true
+}
+
+func hasTagFlag(tag, key string) bool {
+	for _, part := range strings.Split(tag, ",") {
+		if strings.TrimSpace(part) == key {
+			return true
+		}
+	}
+	return false
+}
+
+func numericValue(v reflect.Value) float64 {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint())
+	default:
+		return v.Float()
+	}
+}
+
+func isMultipleOf(x, n float64) bool {
+	r := math.Abs(math.Remainder(x, n))
+	return r <= 1e-9*math.Max(1, math.Abs(x))
+}
+
+func formatNumber(n float64) string {
+	return strconv.FormatFloat(n, 'f', -1, 64)
+}
+
+func uniqueItems(v reflect.Value) bool {
+	seen := make(map[string]bool, v.Len())
+	for i := 0; i < v.Len(); i++ {
+		key, err := json.Marshal(v.Index(i).Interface())
+		if err != nil {
+			continue
+		}
+		if seen[string(key)] {
+			return false
+		}
+		seen[string(key)] = true
+	}
+	return true
+}
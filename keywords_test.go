@@ -0,0 +1,170 @@
+package mcpwrapper
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+type ReleaseArgs struct {
+	Tag      string   `json:"tag" jsonschema:"description=Release tag,pattern=^v[0-9]{1,3}\\.[0-9]+$"`
+	Owner    string   `json:"owner" jsonschema:"format=email"`
+	ID       string   `json:"id" jsonschema:"format=uuid"`
+	Channel  string   `json:"channel" jsonschema:"const=stable"`
+	Replicas int      `json:"replicas" jsonschema:"multipleOf=2,exclusiveMinimum=0,exclusiveMaximum=10"`
+	Ratio    *float64 `json:"ratio,omitempty" jsonschema:"const=0.5"`
+	Labels   []string `json:"labels" jsonschema:"uniqueItems"`
+}
+
+func TestSchemaKeywordTags(t *testing.T) {
+	schema, err := buildSchema(ReleaseArgs{})
+	if err != nil {
+		t.Fatalf("buildSchema failed: %v", err)
+	}
+
+	prop := func(name string) map[string]interface{} {
+		return schema.Properties[name].(map[string]interface{})
+	}
+	if p := prop("tag"); p["pattern"] != `^v[0-9]{1,3}\.[0-9]+$` || p["description"] != "Release tag" {
+		t.Errorf("Unexpected tag schema: %v", p)
+	}
+	if p := prop("owner"); p["format"] != "email" {
+		t.Errorf("Unexpected owner schema: %v", p)
+	}
+	if p := prop("channel"); p["const"] != "stable" {
+		t.Errorf("Unexpected channel schema: %v", p)
+	}
+	if p := prop("replicas"); p["multipleOf"] != 2 || p["exclusiveMinimum"] != 0 || p["exclusiveMaximum"] != 10 {
+		t.Errorf("Unexpected replicas schema: %v", p)
+	}
+	if p := prop("ratio"); p["const"] != 0.5 {
+		t.Errorf("Expected a typed const, got %#v", p["const"])
+	}
+	if p := prop("labels"); p["uniqueItems"] != true {
+		t.Errorf("Unexpected labels schema: %v", p)
+	}
+}
+
+func TestInvalidKeywordTags(t *testing.T) {
+	type BadPattern struct {
+		Name string `json:"name" jsonschema:"pattern=[a-z"`
+	}
+	if _, err := buildSchema(BadPattern{}); err == nil || !strings.Contains(err.Error(), "pattern") {
+		t.Errorf("Expected a pattern error, got %v", err)
+	}
+
+	type BadConst struct {
+		Port int `json:"port" jsonschema:"const=http"`
+	}
+	if _, err := buildSchema(BadConst{}); err == nil || !strings.Contains(err.Error(), "const") {
+		t.Errorf("Expected a const error, got %v", err)
+	}
+}
+
+func TestStrictSchema(t *testing.T) {
+	handler := func(ctx context.Context, args interface{}) (interface{}, error) {
+		return &TestResult{Message: "ok"}, nil
+	}
+	valid := map[string]interface{}{
+		"tag":      "v1.2",
+		"owner":    "ops@example.com",
+		"id":       "123e4567-e89b-12d3-a456-426614174000",
+		"channel":  "stable",
+		"replicas": 4,
+		"labels":   []interface{}{"a", "b"},
+	}
+
+	lenient := server.NewMCPServer("test", "1.0.0")
+	New(lenient).Register("release", "Cut a release", ReleaseArgs{}, handler)
+	result := callTool(t, lenient, "release", map[string]interface{}{"tag": "latest"})
+	if result.IsError {
+		t.Errorf("Expected keywords not to be enforced by default, got %v", result.Content)
+	}
+
+	strict := server.NewMCPServer("test", "1.0.0")
+	New(strict, WithStrictSchema()).Register("release", "Cut a release", ReleaseArgs{}, handler)
+	if result := callTool(t, strict, "release", valid); result.IsError {
+		t.Fatalf("Expected valid arguments to pass, got %v", result.Content)
+	}
+
+	tests := []struct {
+		field string
+		value interface{}
+		want  string
+	}{
+		{"tag", "v1234.0", "must match pattern"},
+		{"owner", "not-an-email", "must be a valid email"},
+		{"id", "123", "must be a valid uuid"},
+		{"channel", "beta", "must be stable"},
+		{"replicas", 3, "must be a multiple of 2"},
+		{"replicas", 10, "must be less than 10"},
+		{"ratio", 0.25, "must be 0.5"},
+		{"labels", []interface{}{"a", "a"}, "must not contain duplicate items"},
+	}
+	for _, tt := range tests {
+		args := cloneMap(valid)
+		args[tt.field] = tt.value
+		result := callTool(t, strict, "release", args)
+		if !result.IsError {
+			t.Errorf("%s=%v: expected a validation error", tt.field, tt.value)
+			continue
+		}
+		if text := result.Content[0].(mcp.TextContent).Text; !strings.Contains(text, tt.want) {
+			t.Errorf("%s=%v: expected %q, got %q", tt.field, tt.value, tt.want, text)
+		}
+	}
+}
+
+func TestValidFormat(t *testing.T) {
+	tests := []struct {
+		format, value string
+		want          bool
+	}{
+		{"date-time", "2024-05-01T10:00:00Z", true},
+		{"date-time", "2024-05-01", false},
+		{"date", "2024-05-01", true},
+		{"uri", "https://example.com/a", true},
+		{"uri", "example.com", false},
+		{"ipv4", "10.0.0.1", true},
+		{"ipv4", "::1", false},
+		{"ipv6", "::1", true},
+		{"hostname", "api.example.com", true},
+		{"hostname", "-bad-", false},
+		{"color", "anything", true},
+	}
+	for _, tt := range tests {
+		if got := validFormat(tt.format, tt.value); got != tt.want {
+			t.Errorf("validFormat(%q, %q) = %v, want %v", tt.format, tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestDecimalKeywordTags(t *testing.T) {
+	type Dosage struct {
+		Amount float64 `json:"amount" jsonschema:"multipleOf=0.5,exclusiveMinimum=2.5,exclusiveMaximum=1e3"`
+	}
+	type Prescription struct {
+		Doses []Dosage `json:"doses"`
+		First Dosage   `json:"first"`
+	}
+
+	schema, err := buildSchema(Dosage{})
+	if err != nil {
+		t.Fatalf("buildSchema failed: %v", err)
+	}
+	p := schema.Properties["amount"].(map[string]interface{})
+	if p["multipleOf"] != 0.5 || p["exclusiveMinimum"] != 2.5 || p["exclusiveMaximum"] != 1000.0 {
+		t.Errorf("Expected decimal keywords to be kept whole, got %v", p)
+	}
+
+	if err := validateKeywords(&Dosage{Amount: 3}); err != nil {
+		t.Errorf("Expected 3 to pass, got %v", err)
+	}
+	err = validateKeywords(&Prescription{First: Dosage{Amount: 2.5}, Doses: []Dosage{{Amount: 4}, {Amount: 4.2}}})
+	if err == nil || !strings.Contains(err.Error(), "First.Amount") || !strings.Contains(err.Error(), "Doses[1].Amount") {
+		t.Errorf("Expected nested fields to be checked, got %v", err)
+	}
+}
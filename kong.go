@@ -0,0 +1,332 @@
+//go:build !nokong
+
+package mcpwrapper
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alecthomas/kong"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// KongOption configures RegisterKong.
+type KongOption func(*kongOptions)
+
+type kongOptions struct {
+	kongOptions []kong.Option
+	bindings    []interface{}
+	toolOptions []ToolOption
+}
+
+// WithKongOptions passes options to kong.New, such as kong.Name or
+// kong.Vars for interpolated help.
+func WithKongOptions(opts ...kong.Option) KongOption {
+	return func(o *kongOptions) {
+		o.kongOptions = append(o.kongOptions, opts...)
+	}
+}
+
+// WithKongBindings passes values to the commands' Run methods, as
+// kong.Context.Run does.
+func WithKongBindings(bindings ...interface{}) KongOption {
+	return func(o *kongOptions) {
+		o.bindings = append(o.bindings, bindings...)
+	}
+}
+
+// WithKongToolOptions applies tool options to every registered command.
+func WithKongToolOptions(opts ...ToolOption) KongOption {
+	return func(o *kongOptions) {
+		o.toolOptions = append(o.toolOptions, opts...)
+	}
+}
+
+// RegisterKong registers each command of a kong-annotated CLI struct as a
+// tool. Schemas come from kong's own model of the struct, so its tags
+// carry over: help becomes the description, enum an enum, required and
+// default the same keywords, and arg fields named properties. Flags of
+// parent commands are included; hidden commands and flags are left out.
+// Tools are named after the command path in snake_case, e.g.
+// "users_list", or after the application if it has no commands.
+//
+// A call builds the command line from the arguments, parses it with kong,
+// which resets every field to its default first, and calls the command's
+// Run method. What it writes to kong.Context's Stdout and Stderr is
+// returned as the result's Stdout and Stderr, and an error it returns is an
+// ExitError. It returns the registered tool names.
+func (w *Wrapper) RegisterKong(cli interface{}, opts ...KongOption) ([]string, error) {
+	options := &kongOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	kongOpts := append([]kong.Option{kong.Exit(func(int) {})}, options.kongOptions...)
+	parser, err := kong.New(cli, kongOpts...)
+	if err != nil {
+		return nil, err
+	}
+
+	var commands []*kong.Node
+	for _, node := range parser.Model.Leaves(true) {
+		if node.Type == kong.CommandNode {
+			commands = append(commands, node)
+		}
+	}
+	if len(commands) == 0 {
+		commands = []*kong.Node{parser.Model.Node}
+	}
+
+	mu := &sync.Mutex{}
+	var names []string
+	for _, node := range commands {
+		name, err := w.registerKongCommand(parser, mu, node, options)
+		if err != nil {
+			return names, err
+		}
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+// kongValue is a flag or positional argument of a command.
+type kongValue struct {
+	*kong.Value
+	flag *kong.Flag
+}
+
+func (w *Wrapper) registerKongCommand(parser *kong.Kong, mu *sync.Mutex, node *kong.Node, options *kongOptions) (string, error) {
+	var path []string
+	for n := node; n != nil && n.Type != kong.ApplicationNode; n = n.Parent {
+		if n.Type == kong.CommandNode {
+			path = append([]string{n.Name}, path...)
+		}
+	}
+	name := strings.ReplaceAll(strings.Join(path, "_"), "-", "_")
+	if name == "" {
+		name = strings.ReplaceAll(parser.Model.Name, "-", "_")
+	}
+
+	values := make(map[string]kongValue)
+	var order []string
+	add := func(v kongValue) error {
+		prop := strings.ReplaceAll(v.Name, "-", "_")
+		if _, taken := values[prop]; taken {
+			return fmt.Errorf("command %s: %s is both a flag and an argument", name, prop)
+		}
+		values[prop] = v
+		order = append(order, prop)
+		return nil
+	}
+	for _, group := range node.AllFlags(true) {
+		for _, flag := range group {
+			if flag.Name == "help" {
+				continue
+			}
+			if err := add(kongValue{flag.Value, flag}); err != nil {
+				return "", err
+			}
+		}
+	}
+	var positional []string
+	for _, arg := range node.Positional {
+		if err := add(kongValue{Value: arg}); err != nil {
+			return "", err
+		}
+		positional = append(positional, order[len(order)-1])
+	}
+
+	schema := mcp.ToolInputSchema{
+		Type:       "object",
+		Properties: make(map[string]interface{}, len(values)),
+		Required:   make([]string, 0),
+	}
+	for prop, v := range values {
+		schema.Properties[prop] = kongSchema(v)
+		if v.Required {
+			schema.Required = append(schema.Required, prop)
+		}
+	}
+	sort.Strings(schema.Required)
+
+	handler := func(ctx context.Context, args interface{}) (interface{}, error) {
+		arguments := args.(Arguments)
+		argv := append([]string(nil), path...)
+		flags := append([]string(nil), order[:len(order)-len(positional)]...)
+		sort.Strings(flags)
+		for _, prop := range flags {
+			if value, ok := arguments[prop]; ok && value != nil {
+				argv = append(argv, kongFlagArgs(values[prop], value)...)
+			}
+		}
+		var rest []string
+		for _, prop := range positional {
+			value, ok := arguments[prop]
+			if !ok || value == nil {
+				break
+			}
+			if list, ok := value.([]interface{}); ok {
+				for _, item := range list {
+					rest = append(rest, argumentString(item))
+				}
+			} else {
+				rest = append(rest, argumentString(value))
+			}
+		}
+		if len(rest) > 0 {
+			argv = append(append(argv, "--"), rest...)
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		return runKong(ctx, parser, argv, options.bindings)
+	}
+
+	description := node.Help
+	if description == "" {
+		description = node.Detail
+	}
+	if description == "" {
+		description = fmt.Sprintf("Execute %s command", name)
+	}
+	return name, w.register(name, description, Arguments{}, &schema, handler, options.toolOptions)
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// kongSchema describes a kong value from its target type and tags.
+func kongSchema(v kongValue) map[string]interface{} {
+	var prop map[string]interface{}
+	switch {
+	case v.IsCounter():
+		prop = map[string]interface{}{"type": "integer", "minimum": 0}
+	case v.IsBool():
+		prop = map[string]interface{}{"type": "boolean"}
+	case v.IsSlice():
+		prop = map[string]interface{}{"type": "array", "items": kongTypeSchema(v.Target.Type().Elem())}
+	case v.IsMap():
+		prop = map[string]interface{}{"type": "object", "additionalProperties": kongTypeSchema(v.Target.Type().Elem())}
+	default:
+		prop = kongTypeSchema(v.Target.Type())
+	}
+
+	if v.Enum != "" {
+		enum := stringValues(v.EnumSlice())
+		if items, ok := prop["items"].(map[string]interface{}); ok {
+			items["enum"] = enum
+		} else {
+			prop["enum"] = enum
+		}
+	}
+	if v.Help != "" {
+		prop["description"] = v.Help
+	}
+	if v.HasDefault && v.Default != "" {
+		prop["default"] = kongDefault(v, prop)
+	}
+	return prop
+}
+
+func kongTypeSchema(t reflect.Type) map[string]interface{} {
+	if t == durationType {
+		return map[string]interface{}{"type": "string", "format": "duration", "pattern": durationPattern}
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	default:
+		return map[string]interface{}{"type": "string"}
+	}
+}
+
+// kongDefault converts a default tag to the JSON type of its schema.
+func kongDefault(v kongValue, prop map[string]interface{}) interface{} {
+	items, ok := prop["items"].(map[string]interface{})
+	if !ok {
+		return jsonFlagValue(prop["type"].(string), v.Default)
+	}
+	sep := ","
+	if v.Tag != nil && v.Tag.Sep != 0 {
+		sep = string(v.Tag.Sep)
+	}
+	var list []interface{}
+	for _, part := range strings.Split(v.Default, sep) {
+		list = append(list, jsonFlagValue(items["type"].(string), part))
+	}
+	return list
+}
+
+// kongFlagArgs renders an argument as command-line flags.
+func kongFlagArgs(v kongValue, value interface{}) []string {
+	name := "--" + v.Name
+	switch val := value.(type) {
+	case bool:
+		if v.flag != nil && v.flag.Negated {
+			// A negatable flag --x is given as --no-x to make it false.
+			if !val {
+				return []string{"--no-" + v.Name}
+			}
+			return []string{name}
+		}
+		return []string{name + "=" + strconv.FormatBool(val)}
+	case []interface{}:
+		args := make([]string, 0, len(val))
+		for _, item := range val {
+			args = append(args, name+"="+argumentString(item))
+		}
+		return args
+	case map[string]interface{}:
+		keys := make([]string, 0, len(val))
+		for k := range val {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		args := make([]string, 0, len(keys))
+		for _, k := range keys {
+			args = append(args, name+"="+k+"="+argumentString(val[k]))
+		}
+		return args
+	}
+	return []string{name + "=" + argumentString(value)}
+}
+
+// runKong parses argv and runs the selected command, capturing what it
+// writes to the context's Stdout and Stderr.
+func runKong(ctx context.Context, parser *kong.Kong, argv []string, bindings []interface{}) (*ExecResult, error) {
+	var stdoutBuf, stderrBuf bytes.Buffer
+	stdout, stderr := parser.Stdout, parser.Stderr
+	parser.Stdout, parser.Stderr = &stdoutBuf, &stderrBuf
+	defer func() {
+		parser.Stdout, parser.Stderr = stdout, stderr
+	}()
+
+	kctx, err := parser.Parse(argv)
+	if err != nil {
+		return nil, Errorf(CodeInvalidInput, "%v", err)
+	}
+	kctx.BindTo(ctx, (*context.Context)(nil))
+
+	start := time.Now()
+	err = kctx.Run(bindings...)
+	result := &ExecResult{
+		Stdout:     stdoutBuf.String(),
+		Stderr:     stderrBuf.String(),
+		DurationMs: time.Since(start).Milliseconds(),
+	}
+	if err := commandExit(result, err); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,166 @@
+//go:build !nokong
+
+package mcpwrapper
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alecthomas/kong"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+type kongUsersListCmd struct {
+	Format  string        `help:"Output format" enum:"json,text" default:"text"`
+	Limit   int           `help:"Users per page" default:"20"`
+	Tag     []string      `help:"Filter by tag"`
+	Timeout time.Duration `help:"Request timeout"`
+	Verbose int           `short:"v" type:"counter"`
+	Color   bool          `negatable:"" default:"true"`
+	Debug   bool          `hidden:""`
+	Filter  string        `arg:"" optional:"" help:"Name filter"`
+
+	ran bool
+}
+
+func (c *kongUsersListCmd) Run(kctx *kong.Context, ctx context.Context) error {
+	c.ran = true
+	if c.Filter == "fail" {
+		return errors.New("lookup failed")
+	}
+	fmt.Fprintf(kctx.Stdout, "alice %s\n", ctx.Value(testContextKey{}))
+	return nil
+}
+
+type kongCLI struct {
+	Org   string `help:"Organization" required:""`
+	Users struct {
+		List kongUsersListCmd `cmd:"" help:"List users"`
+	} `cmd:""`
+	Secret struct{} `cmd:"" hidden:""`
+}
+
+type testContextKey struct{}
+
+func TestRegisterKong(t *testing.T) {
+	var cli kongCLI
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	wrapper := New(mcpServer)
+	names, err := wrapper.RegisterKong(&cli, WithKongOptions(kong.Name("app")))
+	if err != nil {
+		t.Fatalf("RegisterKong failed: %v", err)
+	}
+	if !reflect.DeepEqual(names, []string{"users_list"}) {
+		t.Fatalf("Expected only users_list, got %v", names)
+	}
+
+	tool := mcpServer.GetTool("users_list").Tool
+	if tool.Description != "List users" {
+		t.Errorf("Expected the help tag as description, got %q", tool.Description)
+	}
+	var props []string
+	for name := range tool.InputSchema.Properties {
+		props = append(props, name)
+	}
+	sort.Strings(props)
+	if want := []string{"color", "filter", "format", "limit", "org", "tag", "timeout", "verbose"}; !reflect.DeepEqual(props, want) {
+		t.Errorf("Expected properties %v, got %v", want, props)
+	}
+	schema := func(name string) map[string]interface{} {
+		return tool.InputSchema.Properties[name].(map[string]interface{})
+	}
+	if p := schema("format"); !reflect.DeepEqual(p["enum"], []interface{}{"json", "text"}) ||
+		p["default"] != "text" || p["description"] != "Output format" {
+		t.Errorf("Unexpected format schema: %v", p)
+	}
+	if p := schema("limit"); p["type"] != "integer" || p["default"] != float64(20) {
+		t.Errorf("Unexpected limit schema: %v", p)
+	}
+	if p := schema("tag"); p["type"] != "array" {
+		t.Errorf("Unexpected tag schema: %v", p)
+	}
+	if p := schema("timeout"); p["type"] != "string" || p["format"] != "duration" {
+		t.Errorf("Unexpected timeout schema: %v", p)
+	}
+	if p := schema("verbose"); p["type"] != "integer" {
+		t.Errorf("Unexpected verbose schema: %v", p)
+	}
+	if !reflect.DeepEqual(tool.InputSchema.Required, []string{"org"}) {
+		t.Errorf("Expected org to be required, got %v", tool.InputSchema.Required)
+	}
+
+	call := func(ctx context.Context, args map[string]interface{}) *mcp.CallToolResult {
+		t.Helper()
+		request := mcp.CallToolRequest{}
+		request.Params.Name = "users_list"
+		request.Params.Arguments = args
+		result, err := mcpServer.GetTool("users_list").Handler(ctx, request)
+		if err != nil {
+			t.Fatalf("Call failed: %v", err)
+		}
+		return result
+	}
+	ctx := context.WithValue(context.Background(), testContextKey{}, "ok")
+	result := call(ctx, map[string]interface{}{
+		"org": "acme", "format": "json", "limit": 5, "timeout": "1m30s", "verbose": 2,
+		"color": false, "tag": []interface{}{"a", "b"}, "filter": "-al",
+	})
+	if result.IsError {
+		t.Fatalf("Unexpected error: %v", result.Content)
+	}
+	list := cli.Users.List
+	if cli.Org != "acme" || list.Format != "json" || list.Limit != 5 || list.Timeout != 90*time.Second ||
+		list.Verbose != 2 || list.Color || !reflect.DeepEqual(list.Tag, []string{"a", "b"}) || list.Filter != "-al" {
+		t.Errorf("Unexpected invocation: org=%q %+v", cli.Org, list)
+	}
+	var output ExecResult
+	json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &output)
+	if output.ExitCode != 0 || output.Stdout != "alice ok\n" {
+		t.Errorf("Expected the output to be captured with the call's context, got %+v", output)
+	}
+
+	result = call(ctx, map[string]interface{}{"org": "acme"})
+	if result.IsError {
+		t.Fatalf("Unexpected error: %v", result.Content)
+	}
+	list = cli.Users.List
+	if list.Format != "text" || list.Limit != 20 || len(list.Tag) != 0 || list.Verbose != 0 || !list.Color {
+		t.Errorf("Expected fields to be reset to their defaults, got %+v", list)
+	}
+
+	result = call(ctx, map[string]interface{}{"org": "acme", "format": "yaml"})
+	if !result.IsError {
+		t.Error("Expected an error for a value outside the enum")
+	}
+	result = call(ctx, map[string]interface{}{"org": "acme", "filter": "fail"})
+	if !result.IsError || !strings.Contains(result.Content[0].(mcp.TextContent).Text, "lookup failed") {
+		t.Errorf("Expected the Run error to be returned, got %v", result.Content)
+	}
+}
+
+func TestRegisterKongApplication(t *testing.T) {
+	var cli struct {
+		Name string `arg:"" help:"Who to greet"`
+	}
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	wrapper := New(mcpServer)
+	names, err := wrapper.RegisterKong(&cli, WithKongOptions(kong.Name("greet")))
+	if err != nil {
+		t.Fatalf("RegisterKong failed: %v", err)
+	}
+	if !reflect.DeepEqual(names, []string{"greet"}) {
+		t.Fatalf("Expected the application to be registered, got %v", names)
+	}
+	tool := mcpServer.GetTool("greet").Tool
+	if !reflect.DeepEqual(tool.InputSchema.Required, []string{"name"}) {
+		t.Errorf("Expected the argument to be required, got %v", tool.InputSchema.Required)
+	}
+}
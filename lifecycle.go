@@ -0,0 +1,269 @@
+package mcpwrapper
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// LifecycleHook runs when the server starts or stops, to open or close
+// resources tied to it, such as database pools.
+type LifecycleHook func(ctx context.Context) error
+
+// SessionHook runs when a client connects or disconnects.
+type SessionHook func(ctx context.Context, sessionID string)
+
+type lifecycle struct {
+	mu          sync.Mutex
+	start       []LifecycleHook
+	stop        []LifecycleHook
+	connect     []SessionHook
+	disconnect  []SessionHook
+	stopTimeout time.Duration
+}
+
+// OnStart adds a hook Run calls before serving. Hooks run in the order they
+// were added; if one fails, Run does not serve.
+func (w *Wrapper) OnStart(hook LifecycleHook) {
+	w.lifecycle.mu.Lock()
+	defer w.lifecycle.mu.Unlock()
+	w.lifecycle.start = append(w.lifecycle.start, hook)
+}
+
+// OnStop adds a hook Run calls after serving has stopped and calls have
+// drained. Hooks run in reverse order, like deferred calls, and all of them
+// run even if one fails.
+func (w *Wrapper) OnStop(hook LifecycleHook) {
+	w.lifecycle.mu.Lock()
+	defer w.lifecycle.mu.Unlock()
+	w.lifecycle.stop = append(w.lifecycle.stop, hook)
+}
+
+// OnClientConnect adds a hook called when a client session is registered.
+// It needs the server's hooks, given with WithHooks.
+func (w *Wrapper) OnClientConnect(hook SessionHook) {
+	w.lifecycle.mu.Lock()
+	defer w.lifecycle.mu.Unlock()
+	w.lifecycle.connect = append(w.lifecycle.connect, hook)
+}
+
+// OnClientDisconnect adds a hook called when a client session ends, before
+// the wrapper discards the session's state. It needs the server's hooks,
+// given with WithHooks.
+func (w *Wrapper) OnClientDisconnect(hook SessionHook) {
+	w.lifecycle.mu.Lock()
+	defer w.lifecycle.mu.Unlock()
+	w.lifecycle.disconnect = append(w.lifecycle.disconnect, hook)
+}
+
+// WithStopTimeout sets how long Run gives calls in flight, and then the
+// OnStop hooks, once it is told to stop. The default is
+// DefaultShutdownTimeout.
+func WithStopTimeout(d time.Duration) Option {
+	return func(w *Wrapper) {
+		w.lifecycle.stopTimeout = d
+	}
+}
+
+func (w *Wrapper) installLifecycle(hooks *server.Hooks) {
+	hooks.AddOnRegisterSession(func(ctx context.Context, session server.ClientSession) {
+		for _, hook := range w.sessionHooks(false) {
+			hook(ctx, session.SessionID())
+		}
+	})
+	hooks.AddOnUnregisterSession(func(ctx context.Context, session server.ClientSession) {
+		for _, hook := range w.sessionHooks(true) {
+			hook(ctx, session.SessionID())
+		}
+		w.EndSession(session.SessionID())
+	})
+}
+
+func (w *Wrapper) sessionHooks(disconnect bool) []SessionHook {
+	w.lifecycle.mu.Lock()
+	defer w.lifecycle.mu.Unlock()
+	if disconnect {
+		return append([]SessionHook(nil), w.lifecycle.disconnect...)
+	}
+	return append([]SessionHook(nil), w.lifecycle.connect...)
+}
+
+// Run runs the server's lifecycle around serve, a transport such as
+// ServeStdio or ServeStreamableHTTP:
+//
+//  1. The OnStart hooks run.
+//  2. serve runs until ctx is cancelled or it returns by itself.
+//  3. Shutdown drains the calls in flight while the transport is still up,
+//     so their results are delivered.
+//  4. serve's context is cancelled to stop the transport.
+//  5. The OnStop hooks run.
+//
+// The stop timeout bounds steps 3 to 5. Run returns serve's error, joined
+// with any error from stopping.
+func (w *Wrapper) Run(ctx context.Context, serve func(ctx context.Context) error) error {
+	w.lifecycle.mu.Lock()
+	startHooks := append([]LifecycleHook(nil), w.lifecycle.start...)
+	timeout := w.lifecycle.stopTimeout
+	w.lifecycle.mu.Unlock()
+	if timeout <= 0 {
+		timeout = DefaultShutdownTimeout
+	}
+
+	for i, hook := range startHooks {
+		if err := hook(ctx); err != nil {
+			w.logger.Error("start hook failed", "hook", i, "error", err)
+			stopCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), timeout)
+			defer cancel()
+			return errors.Join(fmt.Errorf("start: %w", err), w.runStopHooks(stopCtx))
+		}
+	}
+
+	serveCtx, stopServing := context.WithCancel(context.WithoutCancel(ctx))
+	defer stopServing()
+	served := make(chan error, 1)
+	go func() {
+		served <- serve(serveCtx)
+	}()
+
+	var serveErr error
+	stopped := false
+	select {
+	case serveErr = <-served:
+		stopped = true
+	case <-ctx.Done():
+		w.logger.Info("stopping", "reason", context.Cause(ctx))
+	}
+
+	stopCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), timeout)
+	defer cancel()
+	errs := []error{serveErr, w.Shutdown(stopCtx)}
+	if !stopped {
+		stopServing()
+		errs[0] = <-served
+	}
+	errs = append(errs, w.runStopHooks(stopCtx))
+	return errors.Join(errs...)
+}
+
+func (w *Wrapper) runStopHooks(ctx context.Context) error {
+	w.lifecycle.mu.Lock()
+	hooks := append([]LifecycleHook(nil), w.lifecycle.stop...)
+	w.lifecycle.mu.Unlock()
+
+	var errs []error
+	for i := len(hooks) - 1; i >= 0; i-- {
+		if err := hooks[i](ctx); err != nil {
+			w.logger.Error("stop hook failed", "hook", i, "error", err)
+			errs = append(errs, fmt.Errorf("stop: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// RunWithSignals is Run until the process receives SIGINT or SIGTERM. A
+// second signal kills the process without waiting.
+func (w *Wrapper) RunWithSignals(serve func(ctx context.Context) error) error {
+	ctx, cancel := context.WithCancelCause(context.Background())
+	defer cancel(nil)
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		if sig, ok := <-signals; ok {
+			cancel(fmt.Errorf("received %v", sig))
+			signal.Stop(signals)
+		}
+	}()
+	defer signal.Stop(signals)
+
+	return w.Run(ctx, serve)
+}
+
+// ServeStdio serves the wrapper's tools on stdin and stdout until ctx is
+// cancelled or stdin is closed. Unlike server.ServeStdio, it routes messages
+// through HandleMessage and leaves signal handling to the caller, typically
+// RunWithSignals.
+func (w *Wrapper) ServeStdio(ctx context.Context) error {
+	return w.serveStdio(ctx, os.Stdin, os.Stdout)
+}
+
+func (w *Wrapper) serveStdio(ctx context.Context, stdin io.Reader, stdout io.Writer) error {
+	out := &lineWriter{w: stdout}
+	sessionCtx := make(chan context.Context, 1)
+	stdio := server.NewStdioServer(w.server)
+	stdio.SetContextFunc(func(ctx context.Context) context.Context {
+		sessionCtx <- ctx
+		return ctx
+	})
+
+	in, forward := io.Pipe()
+	done := make(chan struct{})
+	go w.filterStdio(stdin, forward, out, sessionCtx, done)
+	err := stdio.Listen(ctx, in, out)
+	close(done)
+	in.Close()
+	if errors.Is(err, context.Canceled) && ctx.Err() != nil {
+		return nil
+	}
+	return err
+}
+
+// filterStdio reads messages from stdin, answers those HandleMessage serves
+// itself and forwards the rest to the stdio server. The session's context is
+// received once the server has registered the stdio session.
+func (w *Wrapper) filterStdio(stdin io.Reader, forward *io.PipeWriter, out io.Writer, sessionCtx <-chan context.Context, done <-chan struct{}) {
+	reader := bufio.NewReader(stdin)
+	var ctx context.Context
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 {
+			if !handlesMessage(line) {
+				if _, err := forward.Write(line); err != nil {
+					return
+				}
+			} else {
+				if ctx == nil {
+					select {
+					case ctx = <-sessionCtx:
+					case <-done:
+						return
+					}
+				}
+				response, _ := json.Marshal(w.HandleMessage(ctx, line))
+				if _, err := out.Write(append(response, '\n')); err != nil {
+					w.logger.Error("failed to write stdio response", "error", err)
+				}
+			}
+		}
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				err = nil
+			}
+			forward.CloseWithError(err)
+			return
+		}
+	}
+}
+
+// lineWriter serializes writes to stdout so that lines written by the stdio
+// server and by filterStdio do not interleave.
+type lineWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (l *lineWriter) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.w.Write(p)
+}
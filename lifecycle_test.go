@@ -0,0 +1,164 @@
+package mcpwrapper
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func TestRunLifecycle(t *testing.T) {
+	var mu sync.Mutex
+	var events []string
+	record := func(event string) {
+		mu.Lock()
+		defer mu.Unlock()
+		events = append(events, event)
+	}
+
+	wrapper := New(server.NewMCPServer("test", "1.0.0"),
+		WithFlusher(func(ctx context.Context) error { record("flush"); return nil }))
+	wrapper.OnStart(func(ctx context.Context) error { record("open db"); return nil })
+	wrapper.OnStart(func(ctx context.Context) error { record("open cache"); return nil })
+	wrapper.OnStop(func(ctx context.Context) error { record("close db"); return nil })
+	wrapper.OnStop(func(ctx context.Context) error { record("close cache"); return errors.New("cache busy") })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	err := wrapper.Run(ctx, func(serveCtx context.Context) error {
+		record("serve")
+		cancel()
+		<-serveCtx.Done()
+		record("transport stopped")
+		return nil
+	})
+	if err == nil || err.Error() != "stop: cache busy" {
+		t.Errorf("Expected the failing stop hook to be reported, got %v", err)
+	}
+
+	want := []string{"open db", "open cache", "serve", "flush", "transport stopped", "close cache", "close db"}
+	if !reflect.DeepEqual(events, want) {
+		t.Errorf("Unexpected lifecycle order:\n got %v\nwant %v", events, want)
+	}
+}
+
+func TestRunStartFailure(t *testing.T) {
+	wrapper := New(server.NewMCPServer("test", "1.0.0"))
+	stopped := false
+	wrapper.OnStart(func(ctx context.Context) error { return errors.New("db unreachable") })
+	wrapper.OnStop(func(ctx context.Context) error { stopped = true; return nil })
+
+	err := wrapper.Run(context.Background(), func(ctx context.Context) error {
+		t.Error("Expected serve not to run after a failed start hook")
+		return nil
+	})
+	if err == nil || err.Error() != "start: db unreachable" || !stopped {
+		t.Errorf("Expected the start error and the stop hooks to run, got %v, stopped=%v", err, stopped)
+	}
+}
+
+func TestRunWithSignals(t *testing.T) {
+	wrapper := New(server.NewMCPServer("test", "1.0.0"))
+	err := wrapper.RunWithSignals(func(ctx context.Context) error {
+		syscall.Kill(os.Getpid(), syscall.SIGTERM)
+		<-ctx.Done()
+		return nil
+	})
+	if err != nil {
+		t.Errorf("Expected a clean stop on SIGTERM, got %v", err)
+	}
+}
+
+func TestClientConnectHooks(t *testing.T) {
+	hooks := &server.Hooks{}
+	mcpServer := server.NewMCPServer("test", "1.0.0", server.WithHooks(hooks))
+	wrapper := New(mcpServer, WithHooks(hooks))
+
+	var connected, disconnected []string
+	wrapper.OnClientConnect(func(ctx context.Context, sessionID string) { connected = append(connected, sessionID) })
+	wrapper.OnClientDisconnect(func(ctx context.Context, sessionID string) {
+		disconnected = append(disconnected, sessionID)
+	})
+
+	session := &testSession{id: "session-1"}
+	if err := mcpServer.RegisterSession(context.Background(), session); err != nil {
+		t.Fatalf("RegisterSession failed: %v", err)
+	}
+	mcpServer.UnregisterSession(context.Background(), "session-1")
+
+	if !reflect.DeepEqual(connected, []string{"session-1"}) || !reflect.DeepEqual(disconnected, []string{"session-1"}) {
+		t.Errorf("Unexpected hooks: connected=%v disconnected=%v", connected, disconnected)
+	}
+}
+
+func TestServeStdioWrapperMethods(t *testing.T) {
+	wrapper := New(server.NewMCPServer("test", "1.0.0", server.WithResourceCapabilities(true, false)))
+	err := wrapper.RegisterCompletion("checkout", "branch", func(ctx context.Context, partial string) ([]string, error) {
+		return []string{partial + "in"}, nil
+	})
+	if err != nil {
+		t.Fatalf("RegisterCompletion failed: %v", err)
+	}
+	err = wrapper.RegisterResource("status://build", "Build status", "Current build status", func(ctx context.Context, uri string) (interface{}, error) {
+		return "green", nil
+	})
+	if err != nil {
+		t.Fatalf("RegisterResource failed: %v", err)
+	}
+
+	stdin, input := io.Pipe()
+	output, stdout := io.Pipe()
+	served := make(chan error, 1)
+	go func() {
+		served <- wrapper.serveStdio(context.Background(), stdin, stdout)
+	}()
+
+	go func() {
+		io.WriteString(input, `{"jsonrpc": "2.0", "id": 1, "method": "completion/complete", "params": {"ref": {"type": "ref/prompt", "name": "checkout"}, "argument": {"name": "branch", "value": "ma"}}}`+"\n")
+		io.WriteString(input, `{"jsonrpc": "2.0", "id": 2, "method": "resources/subscribe", "params": {"uri": "status://build"}}`+"\n")
+		io.WriteString(input, `{"jsonrpc": "2.0", "id": 3, "method": "ping"}`+"\n")
+	}()
+
+	responses := make(map[float64]string)
+	reader := bufio.NewReader(output)
+	for len(responses) < 3 {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("Reading stdout failed: %v", err)
+		}
+		var response struct {
+			ID float64 `json:"id"`
+		}
+		if err := json.Unmarshal([]byte(line), &response); err != nil {
+			t.Fatalf("Unexpected output %q: %v", line, err)
+		}
+		responses[response.ID] = line
+	}
+
+	if !strings.Contains(responses[1], `"values":["main"]`) {
+		t.Errorf("Expected completions over stdio, got %s", responses[1])
+	}
+	if !strings.Contains(responses[2], `"result":{}`) {
+		t.Errorf("Expected subscribe to succeed over stdio, got %s", responses[2])
+	}
+	if subscribers := wrapper.resources.subscribersOf("status://build"); len(subscribers) != 1 || subscribers[0] != "stdio" {
+		t.Errorf("Expected the stdio session to be subscribed, got %v", subscribers)
+	}
+	if !strings.Contains(responses[3], `"result":{}`) {
+		t.Errorf("Expected other methods to reach the MCP server, got %s", responses[3])
+	}
+
+	input.Close()
+	go io.Copy(io.Discard, output)
+	if err := <-served; err != nil {
+		t.Errorf("Expected serving to end cleanly when stdin closes, got %v", err)
+	}
+}
@@ -0,0 +1,16 @@
+package mcpwrapper
+
+import "log/slog"
+
+// WithLogger sets the structured logger used for registration and per-call
+// diagnostics (binding, validation, handler duration, result size). Argument
+// values are logged through Redact. Without it the wrapper is silent.
+//
+// On stdio servers the logger must write to stderr, never stdout.
+func WithLogger(logger *slog.Logger) Option {
+	return func(w *Wrapper) {
+		if logger != nil {
+			w.logger = logger
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package mcpwrapper
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func TestWithLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	wrapper := New(mcpServer, WithLogger(logger))
+
+	handler := func(ctx context.Context, args interface{}) (interface{}, error) {
+		return &TestResult{Message: "ok"}, nil
+	}
+
+	if err := wrapper.Register("login", "Login", CredentialArgs{}, handler); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	request := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name:      "login",
+			Arguments: map[string]interface{}{"service": "github", "api_key": "sk-12345"},
+		},
+	}
+
+	if _, err := mcpServer.GetTool("login").Handler(context.Background(), request); err != nil {
+		t.Fatalf("Handler invocation failed: %v", err)
+	}
+
+	logs := buf.String()
+	for _, expected := range []string{`"msg":"registered tool"`, `"msg":"calling handler"`, `"msg":"handler completed"`, `"result_bytes":16`} {
+		if !strings.Contains(logs, expected) {
+			t.Errorf("Expected logs to contain %s, got:\n%s", expected, logs)
+		}
+	}
+
+	if strings.Contains(logs, "sk-12345") {
+		t.Errorf("Logs leak redacted value:\n%s", logs)
+	}
+}
@@ -0,0 +1,149 @@
+package mcpwrapper
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Manifest describes tools declaratively, so simple servers can be assembled
+// from a YAML or JSON file instead of schema structs and handlers.
+type Manifest struct {
+	Tools []ManifestTool `json:"tools" yaml:"tools"`
+}
+
+// ManifestTool is one tool of a Manifest. InputSchema is a JSON Schema
+// object (see RegisterSchema). Exactly one backend must be set: Exec runs a
+// command, HTTP calls an endpoint, and Handler names a Go handler given to
+// the wrapper with WithHandlers.
+type ManifestTool struct {
+	Name        string                 `json:"name" yaml:"name"`
+	Description string                 `json:"description" yaml:"description"`
+	InputSchema map[string]interface{} `json:"input_schema,omitempty" yaml:"input_schema,omitempty"`
+	ReadOnly    bool                   `json:"read_only,omitempty" yaml:"read_only,omitempty"`
+
+	Exec    *ExecSpec `json:"exec,omitempty" yaml:"exec,omitempty"`
+	HTTP    *HTTPSpec `json:"http,omitempty" yaml:"http,omitempty"`
+	Handler string    `json:"handler,omitempty" yaml:"handler,omitempty"`
+}
+
+// WithHandlers names Go handlers that manifest tools can refer to.
+func WithHandlers(handlers map[string]Handler) Option {
+	return func(w *Wrapper) {
+		if w.handlers == nil {
+			w.handlers = make(map[string]Handler)
+		}
+		for name, handler := range handlers {
+			w.handlers[name] = handler
+		}
+	}
+}
+
+// LoadManifest reads a manifest file and registers its tools.
+func (w *Wrapper) LoadManifest(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read manifest %s: %w", path, err)
+	}
+
+	m, err := ParseManifest(data)
+	if err != nil {
+		return fmt.Errorf("failed to parse manifest %s: %w", path, err)
+	}
+
+	return w.RegisterManifest(m)
+}
+
+// ParseManifest accepts YAML or JSON (JSON is valid YAML).
+func ParseManifest(data []byte) (*Manifest, error) {
+	m := &Manifest{}
+	if err := yaml.Unmarshal(data, m); err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	for i, tool := range m.Tools {
+		if tool.Name == "" {
+			return nil, fmt.Errorf("tool %d: name is required", i)
+		}
+		if seen[tool.Name] {
+			return nil, fmt.Errorf("tool %s: defined twice", tool.Name)
+		}
+		seen[tool.Name] = true
+		if err := tool.validate(); err != nil {
+			return nil, fmt.Errorf("tool %s: %w", tool.Name, err)
+		}
+	}
+
+	return m, nil
+}
+
+func (t ManifestTool) validate() error {
+	backends := 0
+	if t.Exec != nil {
+		backends++
+	}
+	if t.HTTP != nil {
+		backends++
+	}
+	if t.Handler != "" {
+		backends++
+	}
+	if backends != 1 {
+		return fmt.Errorf("exactly one of exec, http and handler must be set")
+	}
+	return nil
+}
+
+// RegisterManifest registers every tool of m. It stops at the first tool
+// that fails to register.
+func (w *Wrapper) RegisterManifest(m *Manifest) error {
+	for _, tool := range m.Tools {
+		if err := w.registerManifestTool(tool); err != nil {
+			return fmt.Errorf("tool %s: %w", tool.Name, err)
+		}
+	}
+	return nil
+}
+
+func (w *Wrapper) registerManifestTool(t ManifestTool) error {
+	schema := t.InputSchema
+	if schema == nil {
+		schema = map[string]interface{}{"type": "object"}
+	}
+	inputSchema, err := parseInputSchema(schema)
+	if err != nil {
+		return fmt.Errorf("invalid input_schema: %w", err)
+	}
+	properties := schemaProperties(inputSchema)
+
+	var opts []ToolOption
+	if t.ReadOnly {
+		opts = append(opts, WithReadOnly())
+	}
+
+	var handler Handler
+	switch {
+	case t.Exec != nil:
+		var templates *execTemplates
+		if templates, err = parseExecSpec(*t.Exec, properties); err != nil {
+			break
+		}
+		if t.Exec.LongRunning {
+			return w.registerProcess(t.Name, t.Description, Arguments{}, inputSchema, templates, opts)
+		}
+		handler, err = execHandler(templates)
+	case t.HTTP != nil:
+		handler, err = httpHandler(*t.HTTP, properties)
+	default:
+		var ok bool
+		if handler, ok = w.handlers[t.Handler]; !ok {
+			err = fmt.Errorf("handler %s is not defined", t.Handler)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	return w.register(t.Name, t.Description, Arguments{}, inputSchema, handler, opts)
+}
@@ -0,0 +1,168 @@
+package mcpwrapper
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const testManifest = `
+tools:
+  - name: greet
+    description: Print a greeting
+    read_only: true
+    input_schema:
+      type: object
+      properties:
+        name: {type: string}
+        loud: {type: boolean}
+      required: [name]
+    exec:
+      command: sh
+      args:
+        - -c
+        - 'echo "$GREETING $1"; echo warn >&2; exit 3'
+        - sh
+        - '{{.name}}{{if .loud}}!{{end}}'
+      env: ['GREETING={{if .loud}}HELLO{{else}}hello{{end}}']
+  - name: get_item
+    description: Fetch an item
+    input_schema:
+      type: object
+      properties:
+        id: {type: string}
+      required: [id]
+    http:
+      url: '{{.base}}/items/{{urlquery .id}}'
+      headers:
+        X-Item: '{{.id}}'
+  - name: lookup
+    description: Look up a user
+    input_schema:
+      type: object
+      properties:
+        kind: {type: string, enum: [user, group]}
+    handler: lookup
+`
+
+func callTool(t *testing.T, mcpServer *server.MCPServer, name string, args map[string]interface{}) *mcp.CallToolResult {
+	t.Helper()
+	tool := mcpServer.GetTool(name)
+	if tool == nil {
+		t.Fatalf("tool %s not registered", name)
+	}
+	result, err := tool.Handler(context.Background(), mcp.CallToolRequest{
+		Params: mcp.CallToolParams{Name: name, Arguments: args},
+	})
+	if err != nil {
+		t.Fatalf("Handler returned error: %v", err)
+	}
+	return result
+}
+
+func TestLoadManifest(t *testing.T) {
+	var seenHeader string
+	api := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		seenHeader = r.Header.Get("X-Item")
+		rw.Header().Set("Content-Type", "application/json")
+		io.WriteString(rw, `{"path": "`+r.URL.EscapedPath()+`"}`)
+	}))
+	defer api.Close()
+
+	path := filepath.Join(t.TempDir(), "tools.yaml")
+	manifest := strings.ReplaceAll(testManifest, "{{.base}}", api.URL)
+	if err := os.WriteFile(path, []byte(manifest), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var lookupArgs Arguments
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	wrapper := New(mcpServer, WithHandlers(map[string]Handler{
+		"lookup": func(ctx context.Context, args interface{}) (interface{}, error) {
+			lookupArgs = args.(Arguments)
+			return &TestResult{Message: "found"}, nil
+		},
+	}))
+	if err := wrapper.LoadManifest(path); err != nil {
+		t.Fatalf("LoadManifest failed: %v", err)
+	}
+
+	greet := mcpServer.GetTool("greet").Tool
+	if greet.Annotations.ReadOnlyHint == nil || !*greet.Annotations.ReadOnlyHint {
+		t.Error("Expected read_only to set the readOnlyHint")
+	}
+	if greet.InputSchema.Required[0] != "name" {
+		t.Errorf("Expected the manifest schema, got %+v", greet.InputSchema)
+	}
+
+	result := callTool(t, mcpServer, "greet", map[string]interface{}{"name": "Ada", "loud": true})
+	details, _ := result.StructuredContent.(map[string]interface{})
+	if !result.IsError || details["stdout"] != "HELLO Ada!\n" || details["stderr"] != "warn\n" || details["exit_code"] != 3 {
+		t.Errorf("Expected an error result with the exit details, got %+v", result)
+	}
+
+	result = callTool(t, mcpServer, "greet", map[string]interface{}{})
+	if !result.IsError || !strings.Contains(result.Content[0].(mcp.TextContent).Text, "name: is required") {
+		t.Errorf("Expected a missing argument error, got %+v", result)
+	}
+
+	var httpResult HTTPResult
+	result = callTool(t, mcpServer, "get_item", map[string]interface{}{"id": "a b"})
+	if err := json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &httpResult); err != nil {
+		t.Fatalf("Unexpected result %+v: %v", result, err)
+	}
+	if httpResult.Status != http.StatusOK || httpResult.Body.(map[string]interface{})["path"] != "/items/a+b" {
+		t.Errorf("Unexpected HTTP result: %+v", httpResult)
+	}
+	if seenHeader != "a b" {
+		t.Errorf("Expected templated header, got %q", seenHeader)
+	}
+
+	result = callTool(t, mcpServer, "lookup", map[string]interface{}{"kind": "team"})
+	if !result.IsError || !strings.Contains(result.Content[0].(mcp.TextContent).Text, "kind: must be one of") {
+		t.Errorf("Expected an enum error, got %+v", result)
+	}
+	result = callTool(t, mcpServer, "lookup", map[string]interface{}{"kind": "group"})
+	if result.IsError || lookupArgs["kind"] != "group" {
+		t.Errorf("Expected the named handler to get the arguments, got %+v %v", result, lookupArgs)
+	}
+}
+
+func TestParseManifestErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest string
+		wantErr  string
+	}{
+		{"missing name", "tools: [{description: x, handler: h}]", "name is required"},
+		{"no backend", "tools: [{name: a}]", "exactly one of"},
+		{"two backends", "tools: [{name: a, handler: h, exec: {command: ls}}]", "exactly one of"},
+		{"duplicate", "tools: [{name: a, handler: h}, {name: a, handler: h}]", "defined twice"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseManifest([]byte(tt.manifest))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	wrapper := New(server.NewMCPServer("test", "1.0.0"))
+	m, err := ParseManifest([]byte("tools: [{name: a, handler: missing}]"))
+	if err != nil {
+		t.Fatalf("ParseManifest failed: %v", err)
+	}
+	if err := wrapper.RegisterManifest(m); err == nil || !strings.Contains(err.Error(), "handler missing is not defined") {
+		t.Errorf("Expected an undefined handler error, got %v", err)
+	}
+}
@@ -0,0 +1,126 @@
+// Package mcpclient calls MCP tools from Go with typed arguments and
+// results, giving programs that consume MCP servers the same type safety as
+// the handlers serving them.
+package mcpclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	mcpwrapper "github.com/aleksadvaisly/mcp-go-wrapper"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Caller is the part of an MCP client that Call needs. mcp-go's
+// *client.Client implements it once it has been started and initialized.
+type Caller interface {
+	CallTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error)
+}
+
+// ToolError is a call the tool answered with an error result. Code and Hint
+// are set when the server reports them in the structured content, as
+// mcpwrapper servers do.
+type ToolError struct {
+	Tool    string
+	Message string
+	Code    mcpwrapper.ErrorCode
+	Hint    string
+	Result  *mcp.CallToolResult
+}
+
+func (e *ToolError) Error() string {
+	return fmt.Sprintf("tool %s failed: %s", e.Tool, e.Message)
+}
+
+// Call calls tool with args, marshalled to a JSON object by their json
+// tags, and decodes the result into TResult. The structured content is
+// decoded when the server sends it, the text content otherwise; a string
+// TResult receives the text as is. An error result is returned as a
+// *ToolError.
+func Call[TArgs any, TResult any](ctx context.Context, c Caller, tool string, args TArgs) (TResult, error) {
+	var zero TResult
+
+	arguments, err := encodeArguments(args)
+	if err != nil {
+		return zero, fmt.Errorf("failed to encode arguments for %s: %w", tool, err)
+	}
+
+	result, err := c.CallTool(ctx, mcp.CallToolRequest{
+		Params: mcp.CallToolParams{Name: tool, Arguments: arguments},
+	})
+	if err != nil {
+		return zero, fmt.Errorf("call to %s failed: %w", tool, err)
+	}
+	if result.IsError {
+		return zero, toolError(tool, result)
+	}
+
+	var out TResult
+	if err := decodeResult(result, &out); err != nil {
+		return zero, fmt.Errorf("failed to decode result of %s: %w", tool, err)
+	}
+	return out, nil
+}
+
+func encodeArguments(args interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(args)
+	if err != nil {
+		return nil, err
+	}
+	arguments := make(map[string]interface{})
+	if string(data) == "null" {
+		return arguments, nil
+	}
+	if err := json.Unmarshal(data, &arguments); err != nil {
+		return nil, fmt.Errorf("arguments must encode to a JSON object: %w", err)
+	}
+	return arguments, nil
+}
+
+func decodeResult(result *mcp.CallToolResult, out interface{}) error {
+	if result.StructuredContent != nil {
+		data, err := json.Marshal(result.StructuredContent)
+		if err != nil {
+			return err
+		}
+		return json.Unmarshal(data, out)
+	}
+
+	text := resultText(result)
+	if s, ok := out.(*string); ok {
+		*s = text
+		return nil
+	}
+	if text == "" {
+		return nil
+	}
+	return json.Unmarshal([]byte(text), out)
+}
+
+func resultText(result *mcp.CallToolResult) string {
+	var parts []string
+	for _, content := range result.Content {
+		if text, ok := mcp.AsTextContent(content); ok {
+			parts = append(parts, text.Text)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+func toolError(tool string, result *mcp.CallToolResult) *ToolError {
+	e := &ToolError{Tool: tool, Message: resultText(result), Result: result}
+	if structured, ok := result.StructuredContent.(map[string]interface{}); ok {
+		if code, ok := structured["code"].(string); ok {
+			e.Code = mcpwrapper.ErrorCode(code)
+		}
+		if hint, ok := structured["hint"].(string); ok {
+			e.Hint = hint
+		}
+		if msg, ok := structured["error"].(string); ok {
+			e.Message = msg
+		}
+	}
+	return e
+}
@@ -0,0 +1,93 @@
+package mcpclient
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	mcpwrapper "github.com/aleksadvaisly/mcp-go-wrapper"
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+type LookupArgs struct {
+	ID    string `json:"id" jsonschema:"required" validate:"required"`
+	Limit int    `json:"limit,omitempty"`
+}
+
+type User struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+func newClient(t *testing.T) *client.Client {
+	t.Helper()
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	wrapper := mcpwrapper.New(mcpServer)
+
+	lookup := func(ctx context.Context, args interface{}) (interface{}, error) {
+		a := args.(*LookupArgs)
+		if a.ID != "42" {
+			return nil, mcpwrapper.ErrorWithHint(mcpwrapper.NotFound("user %s does not exist", a.ID), "list users first")
+		}
+		return &User{ID: a.ID, Name: "Ada"}, nil
+	}
+	wrapper.Register("get_user", "Get a user", LookupArgs{}, lookup)
+	wrapper.Register("get_user_structured", "Get a user", LookupArgs{}, lookup,
+		mcpwrapper.WithOutputSchema(map[string]interface{}{"type": "object"}))
+	wrapper.Register("greet", "Greet a user", LookupArgs{}, func(ctx context.Context, args interface{}) (interface{}, error) {
+		return mcpwrapper.TextResult("hello " + args.(*LookupArgs).ID), nil
+	})
+
+	c, err := client.NewInProcessClient(mcpServer)
+	if err != nil {
+		t.Fatalf("NewInProcessClient failed: %v", err)
+	}
+	ctx := context.Background()
+	if err := c.Start(ctx); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	initRequest := mcp.InitializeRequest{}
+	initRequest.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	initRequest.Params.ClientInfo = mcp.Implementation{Name: "test", Version: "1.0.0"}
+	if _, err := c.Initialize(ctx, initRequest); err != nil {
+		t.Fatalf("Initialize failed: %v", err)
+	}
+	t.Cleanup(func() { c.Close() })
+	return c
+}
+
+func TestCall(t *testing.T) {
+	c := newClient(t)
+	ctx := context.Background()
+
+	for _, tool := range []string{"get_user", "get_user_structured"} {
+		user, err := Call[LookupArgs, User](ctx, c, tool, LookupArgs{ID: "42"})
+		if err != nil || user != (User{ID: "42", Name: "Ada"}) {
+			t.Errorf("%s: unexpected result %+v, %v", tool, user, err)
+		}
+	}
+
+	greeting, err := Call[map[string]string, string](ctx, c, "greet", map[string]string{"id": "Ada"})
+	if err != nil || greeting != "hello Ada" {
+		t.Errorf("Unexpected text result %q, %v", greeting, err)
+	}
+
+	_, err = Call[LookupArgs, User](ctx, c, "get_user", LookupArgs{ID: "7"})
+	var toolErr *ToolError
+	if !errors.As(err, &toolErr) {
+		t.Fatalf("Expected a ToolError, got %v", err)
+	}
+	if toolErr.Code != mcpwrapper.CodeNotFound || toolErr.Hint != "list users first" || toolErr.Message != "not_found: user 7 does not exist" {
+		t.Errorf("Unexpected ToolError: %+v", toolErr)
+	}
+
+	if _, err := Call[LookupArgs, User](ctx, c, "get_user", LookupArgs{}); !errors.As(err, &toolErr) || toolErr.Code != mcpwrapper.CodeInvalidInput {
+		t.Errorf("Expected an invalid_input ToolError, got %v", err)
+	}
+
+	if _, err := Call[[]string, User](ctx, c, "get_user", []string{"42"}); err == nil {
+		t.Error("Expected arguments that are not an object to be rejected")
+	}
+}
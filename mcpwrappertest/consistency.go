@@ -0,0 +1,16 @@
+package mcpwrappertest
+
+import (
+	"testing"
+
+	mcpwrapper "github.com/aleksadvaisly/mcp-go-wrapper"
+)
+
+// AssertConsistent fails t for every field whose jsonschema and validate
+// tags disagree, as reported by Wrapper.CheckConsistency.
+func AssertConsistent(t testing.TB, w *mcpwrapper.Wrapper) {
+	t.Helper()
+	for _, issue := range w.CheckConsistency() {
+		t.Errorf("%s", issue)
+	}
+}
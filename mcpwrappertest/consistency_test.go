@@ -0,0 +1,27 @@
+package mcpwrappertest
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	mcpwrapper "github.com/aleksadvaisly/mcp-go-wrapper"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func TestAssertConsistent(t *testing.T) {
+	type Args struct {
+		Name string `json:"name" jsonschema:"required,minLength=2" validate:"required,min=2"`
+		Kind string `json:"kind" validate:"omitempty,oneof=a b"`
+	}
+	wrapper := mcpwrapper.New(server.NewMCPServer("test", "1.0.0"))
+	wrapper.Register("create", "Create", Args{}, func(ctx context.Context, args interface{}) (interface{}, error) {
+		return nil, nil
+	})
+
+	r := &recorder{TB: t}
+	AssertConsistent(r, wrapper)
+	if len(r.failures) != 1 || !strings.Contains(r.failures[0], "tool create, field kind: validate oneof=a b") {
+		t.Errorf("Expected one oneof failure, got %v", r.failures)
+	}
+}
@@ -0,0 +1,90 @@
+// Package mcpwrappertest provides helpers for testing servers built on
+// mcpwrapper.
+package mcpwrappertest
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	mcpwrapper "github.com/aleksadvaisly/mcp-go-wrapper"
+)
+
+// CoverageTracker records which tools were exercised during a test run. A
+// tool counts as covered once a call reaches its handler, i.e. passed
+// binding and validation.
+type CoverageTracker struct {
+	mu      sync.Mutex
+	calls   map[string]int
+	exclude map[string]bool
+}
+
+func NewCoverageTracker() *CoverageTracker {
+	return &CoverageTracker{calls: make(map[string]int), exclude: make(map[string]bool)}
+}
+
+// Option installs the tracker on a wrapper. Pass it to every wrapper the
+// tests create.
+func (c *CoverageTracker) Option() mcpwrapper.Option {
+	return mcpwrapper.WithMiddleware(c.Middleware())
+}
+
+func (c *CoverageTracker) Middleware() mcpwrapper.Middleware {
+	return func(next mcpwrapper.Handler) mcpwrapper.Handler {
+		return func(ctx context.Context, args interface{}) (interface{}, error) {
+			c.mu.Lock()
+			c.calls[mcpwrapper.ToolNameFromContext(ctx)]++
+			c.mu.Unlock()
+			return next(ctx, args)
+		}
+	}
+}
+
+// Exclude leaves tools out of the coverage requirement.
+func (c *CoverageTracker) Exclude(names ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, name := range names {
+		c.exclude[name] = true
+	}
+}
+
+// Calls reports how many calls reached the handler of a tool.
+func (c *CoverageTracker) Calls(name string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.calls[name]
+}
+
+// Uncovered lists the tools registered on w that no test exercised.
+func (c *CoverageTracker) Uncovered(w *mcpwrapper.Wrapper) []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var uncovered []string
+	for _, name := range w.ToolNames() {
+		if c.calls[name] == 0 && !c.exclude[name] {
+			uncovered = append(uncovered, name)
+		}
+	}
+	return uncovered
+}
+
+// Verify returns an error listing uncovered tools. Call it from TestMain
+// after m.Run, with a wrapper holding the full catalog.
+func (c *CoverageTracker) Verify(w *mcpwrapper.Wrapper) error {
+	if uncovered := c.Uncovered(w); len(uncovered) > 0 {
+		return fmt.Errorf("%d tool(s) without a test invocation: %s", len(uncovered), strings.Join(uncovered, ", "))
+	}
+	return nil
+}
+
+// AssertCovered fails t if any tool registered on w was not exercised.
+func (c *CoverageTracker) AssertCovered(t testing.TB, w *mcpwrapper.Wrapper) {
+	t.Helper()
+	if err := c.Verify(w); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,52 @@
+package mcpwrappertest
+
+import (
+	"context"
+	"testing"
+
+	mcpwrapper "github.com/aleksadvaisly/mcp-go-wrapper"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+type EchoArgs struct {
+	Text string `json:"text" validate:"required"`
+}
+
+func TestCoverageTracker(t *testing.T) {
+	tracker := NewCoverageTracker()
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	wrapper := mcpwrapper.New(mcpServer, tracker.Option())
+
+	handler := func(ctx context.Context, args interface{}) (interface{}, error) {
+		return map[string]string{"text": args.(*EchoArgs).Text}, nil
+	}
+	for _, name := range []string{"echo", "shout", "whisper"} {
+		if err := wrapper.Register(name, "Echo", EchoArgs{}, handler); err != nil {
+			t.Fatalf("Register failed: %v", err)
+		}
+	}
+
+	call := func(name string, args map[string]interface{}) {
+		request := mcp.CallToolRequest{Params: mcp.CallToolParams{Name: name, Arguments: args}}
+		if _, err := mcpServer.GetTool(name).Handler(context.Background(), request); err != nil {
+			t.Fatalf("Handler invocation failed: %v", err)
+		}
+	}
+
+	call("echo", map[string]interface{}{"text": "hi"})
+	call("shout", map[string]interface{}{})
+
+	if tracker.Calls("echo") != 1 || tracker.Calls("shout") != 0 {
+		t.Errorf("Unexpected call counts: echo=%d shout=%d", tracker.Calls("echo"), tracker.Calls("shout"))
+	}
+
+	err := tracker.Verify(wrapper)
+	if err == nil || err.Error() != "2 tool(s) without a test invocation: shout, whisper" {
+		t.Errorf("Unexpected verify result: %v", err)
+	}
+
+	tracker.Exclude("whisper")
+	call("shout", map[string]interface{}{"text": "HI"})
+	tracker.AssertCovered(t, wrapper)
+}
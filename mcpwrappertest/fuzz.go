@@ -0,0 +1,733 @@
+package mcpwrappertest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	mcpwrapper "github.com/aleksadvaisly/mcp-go-wrapper"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Defaults for FuzzOptions fields left zero.
+const (
+	DefaultFuzzIterations = 100
+	DefaultFuzzTimeout    = 5 * time.Second
+)
+
+// FuzzOptions configures FuzzTools.
+type FuzzOptions struct {
+	// Iterations is the number of payloads sent to each tool, half of them
+	// conforming to its input schema and half deliberately not. Zero means
+	// DefaultFuzzIterations.
+	Iterations int
+	// Seed makes a run reproducible. Zero picks a seed from the clock; the
+	// seed in use is part of every reported finding.
+	Seed int64
+	// Tools limits fuzzing to the named tools. Empty means every tool.
+	Tools []string
+	// Timeout bounds a single call. Zero means DefaultFuzzTimeout.
+	Timeout time.Duration
+}
+
+// FuzzFindingKind classifies a FuzzFinding.
+type FuzzFindingKind string
+
+const (
+	// FindingPanic means the handler panicked.
+	FindingPanic FuzzFindingKind = "panic"
+	// FindingTimeout means a call did not return within the timeout.
+	FindingTimeout FuzzFindingKind = "timeout"
+	// FindingRejectedValid means binding or validation rejected a payload
+	// that conforms to the advertised schema: the validation rules are
+	// stricter than the schema says.
+	FindingRejectedValid FuzzFindingKind = "rejected conforming input"
+	// FindingAcceptedInvalid means a payload that violates the advertised
+	// schema was not rejected as invalid_input: the schema promises a
+	// check that nothing enforces.
+	FindingAcceptedInvalid FuzzFindingKind = "accepted non-conforming input"
+	// FindingProtocolError means the server answered with a JSON-RPC error
+	// instead of a tool result.
+	FindingProtocolError FuzzFindingKind = "protocol error"
+)
+
+// FuzzFinding is one problem found by Fuzz.
+type FuzzFinding struct {
+	Tool string
+	Kind FuzzFindingKind
+	// Mutation describes how a non-conforming payload departs from the
+	// schema. It is empty for conforming payloads.
+	Mutation  string
+	Detail    string
+	Arguments map[string]interface{}
+	Seed      int64
+}
+
+func (f FuzzFinding) String() string {
+	args, _ := json.Marshal(f.Arguments)
+	s := fmt.Sprintf("tool %s: %s", f.Tool, f.Kind)
+	if f.Mutation != "" {
+		s += " (" + f.Mutation + ")"
+	}
+	if f.Detail != "" {
+		s += ": " + f.Detail
+	}
+	return fmt.Sprintf("%s\n  arguments: %s\n  seed: %d", s, args, f.Seed)
+}
+
+// FuzzTools runs Fuzz and fails t with the first finding of each kind per
+// tool. The handlers really run, so register them against test doubles
+// rather than anything with side effects.
+func FuzzTools(t testing.TB, w *mcpwrapper.Wrapper, opts FuzzOptions) {
+	t.Helper()
+
+	findings, err := Fuzz(w, opts)
+	if err != nil {
+		t.Fatalf("failed to fuzz tools: %v", err)
+		return
+	}
+
+	type key struct {
+		tool string
+		kind FuzzFindingKind
+	}
+	counts := make(map[key]int)
+	var first []FuzzFinding
+	for _, f := range findings {
+		k := key{f.Tool, f.Kind}
+		if counts[k] == 0 {
+			first = append(first, f)
+		}
+		counts[k]++
+	}
+	for _, f := range first {
+		if n := counts[key{f.Tool, f.Kind}]; n > 1 {
+			t.Errorf("%s\n  (%d more like this)", f, n-1)
+			continue
+		}
+		t.Errorf("%s", f)
+	}
+}
+
+// Fuzz sends generated arguments to every tool registered on w through
+// the full bind, validate and handle pipeline and returns what went wrong.
+// Conforming payloads are built from the tool's input schema: required
+// properties, types, enum, const, formats, length and range keywords.
+// Non-conforming payloads break one of those rules.
+func Fuzz(w *mcpwrapper.Wrapper, opts FuzzOptions) ([]FuzzFinding, error) {
+	if opts.Iterations <= 0 {
+		opts.Iterations = DefaultFuzzIterations
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultFuzzTimeout
+	}
+	if opts.Seed == 0 {
+		opts.Seed = time.Now().UnixNano()
+	}
+
+	schemas, err := inputSchemas(w)
+	if err != nil {
+		return nil, err
+	}
+	names := opts.Tools
+	if len(names) == 0 {
+		for name := range schemas {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+	}
+
+	var findings []FuzzFinding
+	for _, name := range names {
+		schema, ok := schemas[name]
+		if !ok {
+			return nil, fmt.Errorf("tool %s is not registered", name)
+		}
+		f := &fuzzer{
+			w:       w,
+			tool:    name,
+			seed:    opts.Seed,
+			timeout: opts.Timeout,
+			gen:     newGenerator(rand.New(rand.NewSource(opts.Seed)), schema),
+		}
+		findings = append(findings, f.run(opts.Iterations)...)
+	}
+	return findings, nil
+}
+
+func inputSchemas(w *mcpwrapper.Wrapper) (map[string]map[string]interface{}, error) {
+	data, err := w.ExportManifest(mcpwrapper.CatalogMCP)
+	if err != nil {
+		return nil, err
+	}
+	var catalog struct {
+		Tools []struct {
+			Name        string                 `json:"name"`
+			InputSchema map[string]interface{} `json:"inputSchema"`
+		} `json:"tools"`
+	}
+	if err := json.Unmarshal(data, &catalog); err != nil {
+		return nil, err
+	}
+	schemas := make(map[string]map[string]interface{}, len(catalog.Tools))
+	for _, tool := range catalog.Tools {
+		schemas[tool.Name] = tool.InputSchema
+	}
+	return schemas, nil
+}
+
+type fuzzer struct {
+	w       *mcpwrapper.Wrapper
+	tool    string
+	seed    int64
+	timeout time.Duration
+	gen     *generator
+}
+
+func (f *fuzzer) run(iterations int) []FuzzFinding {
+	var findings []FuzzFinding
+	for i := 0; i < iterations; i++ {
+		var args map[string]interface{}
+		var mutation string
+		certain := true
+		if i%2 == 0 {
+			args, certain = f.gen.valid()
+		} else {
+			var ok bool
+			args, mutation, ok = f.gen.invalid()
+			if !ok {
+				continue
+			}
+		}
+
+		finding, timedOut := f.call(args, mutation, certain)
+		if finding != nil {
+			findings = append(findings, *finding)
+		}
+		if timedOut {
+			// The stuck call still holds its goroutine; more calls would
+			// likely pile up behind it.
+			break
+		}
+	}
+	return findings
+}
+
+func (f *fuzzer) call(args map[string]interface{}, mutation string, certain bool) (*FuzzFinding, bool) {
+	finding := func(kind FuzzFindingKind, detail string) *FuzzFinding {
+		return &FuzzFinding{Tool: f.tool, Kind: kind, Mutation: mutation, Detail: detail, Arguments: args, Seed: f.seed}
+	}
+
+	message, err := json.Marshal(mcp.JSONRPCRequest{
+		JSONRPC: mcp.JSONRPC_VERSION,
+		ID:      mcp.NewRequestId(1),
+		Request: mcp.Request{Method: string(mcp.MethodToolsCall)},
+		Params:  mcp.CallToolParams{Name: f.tool, Arguments: args},
+	})
+	if err != nil {
+		return finding(FindingProtocolError, err.Error()), false
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), f.timeout)
+	defer cancel()
+	done := make(chan mcp.JSONRPCMessage, 1)
+	go func() { done <- f.w.HandleMessage(ctx, message) }()
+
+	var response mcp.JSONRPCMessage
+	select {
+	case response = <-done:
+	case <-time.After(f.timeout + time.Second):
+		return finding(FindingTimeout, fmt.Sprintf("no response after %s", f.timeout)), true
+	}
+	if ctx.Err() != nil {
+		return finding(FindingTimeout, fmt.Sprintf("call exceeded %s", f.timeout)), false
+	}
+
+	result, rpcErr := decodeCallResult(response)
+	if rpcErr != "" {
+		return finding(FindingProtocolError, rpcErr), false
+	}
+
+	text := resultText(result)
+	code := resultCode(result)
+	switch {
+	case result.IsError && strings.HasPrefix(text, "internal error: ") && result.StructuredContent == nil:
+		return finding(FindingPanic, text), false
+	case code == mcpwrapper.CodeUnavailable || code == mcpwrapper.CodeUnauthorized:
+		// Gated tools say nothing about their schema.
+		return nil, false
+	case mutation == "":
+		if certain && code == mcpwrapper.CodeInvalidInput &&
+			(strings.HasPrefix(text, "validation failed") || strings.HasPrefix(text, "failed to bind arguments")) {
+			return finding(FindingRejectedValid, text), false
+		}
+	default:
+		if code != mcpwrapper.CodeInvalidInput {
+			detail := "call succeeded"
+			if result.IsError {
+				detail = text
+			}
+			return finding(FindingAcceptedInvalid, detail), false
+		}
+	}
+	return nil, false
+}
+
+func decodeCallResult(response mcp.JSONRPCMessage) (*mcp.CallToolResult, string) {
+	data, err := json.Marshal(response)
+	if err != nil {
+		return nil, err.Error()
+	}
+	var envelope struct {
+		Result json.RawMessage `json:"result"`
+		Error  *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return nil, err.Error()
+	}
+	if envelope.Error != nil {
+		return nil, envelope.Error.Message
+	}
+	result, err := mcp.ParseCallToolResult(&envelope.Result)
+	if err != nil {
+		return nil, err.Error()
+	}
+	return result, ""
+}
+
+func resultText(result *mcp.CallToolResult) string {
+	for _, content := range result.Content {
+		if text, ok := content.(mcp.TextContent); ok {
+			return text.Text
+		}
+	}
+	return ""
+}
+
+func resultCode(result *mcp.CallToolResult) mcpwrapper.ErrorCode {
+	if !result.IsError {
+		return ""
+	}
+	if structured, ok := result.StructuredContent.(map[string]interface{}); ok {
+		if code, ok := structured["code"].(string); ok {
+			return mcpwrapper.ErrorCode(code)
+		}
+	}
+	return ""
+}
+
+// maxFuzzDepth bounds nesting, so recursive schemas terminate.
+const maxFuzzDepth = 4
+
+type generator struct {
+	rng  *rand.Rand
+	root map[string]interface{}
+	defs map[string]interface{}
+}
+
+func newGenerator(rng *rand.Rand, root map[string]interface{}) *generator {
+	g := &generator{rng: rng, root: root}
+	if defs, ok := root["$defs"].(map[string]interface{}); ok {
+		g.defs = defs
+	} else if defs, ok := root["definitions"].(map[string]interface{}); ok {
+		g.defs = defs
+	}
+	return g
+}
+
+// resolve follows local $refs.
+func (g *generator) resolve(schema map[string]interface{}) map[string]interface{} {
+	for i := 0; i < 8; i++ {
+		ref, ok := schema["$ref"].(string)
+		if !ok {
+			return schema
+		}
+		if ref == "#" {
+			schema = g.root
+			continue
+		}
+		name := ref[strings.LastIndex(ref, "/")+1:]
+		def, ok := g.defs[name].(map[string]interface{})
+		if !ok {
+			return map[string]interface{}{}
+		}
+		schema = def
+	}
+	return schema
+}
+
+// valid returns conforming arguments, and whether they are certain to
+// conform. A pattern or an unsatisfiable combination of keywords makes
+// the payload a best effort.
+func (g *generator) valid() (map[string]interface{}, bool) {
+	value, certain := g.value(g.root, 0)
+	args, ok := value.(map[string]interface{})
+	if !ok {
+		return map[string]interface{}{}, false
+	}
+	return args, certain
+}
+
+func (g *generator) value(schema map[string]interface{}, depth int) (interface{}, bool) {
+	schema = g.resolve(schema)
+	if c, ok := schema["const"]; ok {
+		return c, true
+	}
+	if enum, ok := schema["enum"].([]interface{}); ok && len(enum) > 0 {
+		return enum[g.rng.Intn(len(enum))], true
+	}
+
+	switch schemaType(schema) {
+	case "string":
+		return g.stringValue(schema)
+	case "integer":
+		return g.numberValue(schema, true)
+	case "number":
+		return g.numberValue(schema, false)
+	case "boolean":
+		return g.rng.Intn(2) == 0, true
+	case "array":
+		return g.arrayValue(schema, depth)
+	case "object":
+		return g.objectValue(schema, depth)
+	case "null":
+		return nil, true
+	}
+	return "fuzz", true
+}
+
+func schemaType(schema map[string]interface{}) string {
+	switch t := schema["type"].(type) {
+	case string:
+		return t
+	case []interface{}:
+		for _, v := range t {
+			if s, ok := v.(string); ok && s != "null" {
+				return s
+			}
+		}
+	}
+	if _, ok := schema["properties"]; ok {
+		return "object"
+	}
+	return ""
+}
+
+func keyword(schema map[string]interface{}, name string) (float64, bool) {
+	switch v := schema[name].(type) {
+	case float64:
+		return v, true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+var formatSamples = map[string][]string{
+	"date-time": {"2024-05-01T10:00:00Z", "1999-12-31T23:59:59+02:00"},
+	"date":      {"2024-05-01", "2000-02-29"},
+	"time":      {"10:00:00Z", "23:59:59+02:00"},
+	"email":     {"ops@example.com", "a.b@example.org"},
+	"uri":       {"https://example.com/a", "urn:isbn:0451450523"},
+	"uuid":      {"123e4567-e89b-12d3-a456-426614174000"},
+	"ipv4":      {"10.0.0.1", "192.168.1.254"},
+	"ipv6":      {"::1", "2001:db8::ff00:42:8329"},
+	"hostname":  {"api.example.com", "localhost"},
+}
+
+const fuzzAlphabet = "abcxyzABC019 -_.é✓"
+
+func (g *generator) stringValue(schema map[string]interface{}) (interface{}, bool) {
+	_, hasPattern := schema["pattern"]
+	certain := !hasPattern
+	if format, ok := schema["format"].(string); ok {
+		if samples, ok := formatSamples[format]; ok {
+			return samples[g.rng.Intn(len(samples))], certain
+		}
+	}
+
+	minLength, _ := keyword(schema, "minLength")
+	maxLength, ok := keyword(schema, "maxLength")
+	if !ok {
+		maxLength = minLength + 12
+	}
+	if maxLength < minLength {
+		return "", false
+	}
+	n := int(minLength) + g.rng.Intn(int(maxLength-minLength)+1)
+	alphabet := []rune(fuzzAlphabet)
+	runes := make([]rune, n)
+	for i := range runes {
+		runes[i] = alphabet[g.rng.Intn(len(alphabet))]
+	}
+	return string(runes), certain
+}
+
+// bounds returns the inclusive range allowed by the numeric keywords.
+func bounds(schema map[string]interface{}, integer bool) (float64, float64) {
+	step := math.SmallestNonzeroFloat64
+	if integer {
+		step = 1
+	}
+	lo, hasLo := keyword(schema, "minimum")
+	if ex, ok := keyword(schema, "exclusiveMinimum"); ok && (!hasLo || ex >= lo) {
+		lo, hasLo = ex+step, true
+		if integer {
+			lo = math.Floor(ex) + 1
+		}
+	}
+	hi, hasHi := keyword(schema, "maximum")
+	if ex, ok := keyword(schema, "exclusiveMaximum"); ok && (!hasHi || ex <= hi) {
+		hi, hasHi = ex-step, true
+		if integer {
+			hi = math.Ceil(ex) - 1
+		}
+	}
+	switch {
+	case !hasLo && !hasHi:
+		lo, hi = -1000, 1000
+	case !hasLo:
+		lo = hi - 1000
+	case !hasHi:
+		hi = lo + 1000
+	}
+	if integer {
+		lo, hi = math.Ceil(lo), math.Floor(hi)
+	}
+	return lo, hi
+}
+
+func (g *generator) numberValue(schema map[string]interface{}, integer bool) (interface{}, bool) {
+	lo, hi := bounds(schema, integer)
+	if hi < lo {
+		return lo, false
+	}
+
+	if m, ok := keyword(schema, "multipleOf"); ok && m > 0 {
+		first, last := math.Ceil(lo/m), math.Floor(hi/m)
+		if last < first {
+			return lo, false
+		}
+		k := first + float64(g.rng.Int63n(int64(math.Min(last-first, 1e9))+1))
+		return k * m, true
+	}
+
+	var v float64
+	switch g.rng.Intn(5) {
+	case 0:
+		v = lo
+	case 1:
+		v = hi
+	default:
+		v = lo + g.rng.Float64()*(hi-lo)
+	}
+	if integer {
+		v = math.Max(lo, math.Min(hi, math.Round(v)))
+	}
+	return v, true
+}
+
+func (g *generator) arrayValue(schema map[string]interface{}, depth int) (interface{}, bool) {
+	minItems, _ := keyword(schema, "minItems")
+	maxItems, ok := keyword(schema, "maxItems")
+	if !ok {
+		maxItems = minItems + 3
+	}
+	if depth >= maxFuzzDepth {
+		maxItems = minItems
+	}
+	if maxItems < minItems {
+		return []interface{}{}, false
+	}
+	items, _ := schema["items"].(map[string]interface{})
+	if items == nil {
+		items = map[string]interface{}{}
+	}
+	unique, _ := schema["uniqueItems"].(bool)
+
+	n := int(minItems) + g.rng.Intn(int(maxItems-minItems)+1)
+	values := make([]interface{}, 0, n)
+	seen := make(map[string]bool)
+	certain := true
+	for attempts := 0; len(values) < n && attempts < n*4; attempts++ {
+		v, ok := g.value(items, depth+1)
+		if unique {
+			key, _ := json.Marshal(v)
+			if seen[string(key)] {
+				continue
+			}
+			seen[string(key)] = true
+		}
+		certain = certain && ok
+		values = append(values, v)
+	}
+	if float64(len(values)) < minItems {
+		certain = false
+	}
+	return values, certain
+}
+
+func (g *generator) objectValue(schema map[string]interface{}, depth int) (interface{}, bool) {
+	props, _ := schema["properties"].(map[string]interface{})
+	required := requiredSet(schema)
+
+	names := make([]string, 0, len(props))
+	for name := range props {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	obj := make(map[string]interface{})
+	certain := true
+	for _, name := range names {
+		if !required[name] && (depth >= maxFuzzDepth || g.rng.Intn(2) == 0) {
+			continue
+		}
+		prop, _ := props[name].(map[string]interface{})
+		v, ok := g.value(prop, depth+1)
+		certain = certain && ok
+		obj[name] = v
+	}
+
+	if extra, ok := schema["additionalProperties"].(map[string]interface{}); ok && depth < maxFuzzDepth {
+		for i := g.rng.Intn(3); i > 0; i-- {
+			v, ok := g.value(extra, depth+1)
+			certain = certain && ok
+			obj[fmt.Sprintf("key%d", g.rng.Intn(1000))] = v
+		}
+	}
+	return obj, certain
+}
+
+func requiredSet(schema map[string]interface{}) map[string]bool {
+	set := make(map[string]bool)
+	if list, ok := schema["required"].([]interface{}); ok {
+		for _, name := range list {
+			if s, ok := name.(string); ok {
+				set[s] = true
+			}
+		}
+	}
+	return set
+}
+
+// mutation breaks one rule of a top-level property in a conforming
+// payload.
+type mutation struct {
+	describe string
+	apply    func(args map[string]interface{})
+}
+
+// invalid returns arguments that violate the schema in exactly one way,
+// and a description of the violation. ok is false when the schema has
+// nothing to violate.
+func (g *generator) invalid() (map[string]interface{}, string, bool) {
+	mutations := g.mutations()
+	if len(mutations) == 0 {
+		return nil, "", false
+	}
+	args, _ := g.valid()
+	m := mutations[g.rng.Intn(len(mutations))]
+	m.apply(args)
+	return args, m.describe, true
+}
+
+func (g *generator) mutations() []mutation {
+	root := g.resolve(g.root)
+	props, _ := root["properties"].(map[string]interface{})
+	required := requiredSet(root)
+
+	names := make([]string, 0, len(props))
+	for name := range props {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var mutations []mutation
+	set := func(name string, value interface{}) func(map[string]interface{}) {
+		return func(args map[string]interface{}) { args[name] = value }
+	}
+	for _, name := range names {
+		name := name
+		if required[name] {
+			mutations = append(mutations, mutation{
+				describe: "missing required property " + name,
+				apply:    func(args map[string]interface{}) { delete(args, name) },
+			})
+		}
+
+		prop, _ := props[name].(map[string]interface{})
+		prop = g.resolve(prop)
+		typ := schemaType(prop)
+		if wrong, ok := wrongType(typ); ok {
+			mutations = append(mutations, mutation{
+				describe: fmt.Sprintf("%s is not of type %s", name, typ),
+				apply:    set(name, wrong),
+			})
+		}
+
+		if enum, ok := prop["enum"].([]interface{}); ok && len(enum) > 0 {
+			var outside interface{} = "__not_in_enum__"
+			if typ == "integer" || typ == "number" {
+				outside = math.MaxInt32
+			}
+			mutations = append(mutations, mutation{
+				describe: name + " is not one of the enum values",
+				apply:    set(name, outside),
+			})
+			continue
+		}
+
+		switch typ {
+		case "integer", "number":
+			if v, ok := keyword(prop, "minimum"); ok {
+				mutations = append(mutations, mutation{describe: name + " is below minimum", apply: set(name, v-1)})
+			}
+			if v, ok := keyword(prop, "exclusiveMinimum"); ok {
+				mutations = append(mutations, mutation{describe: name + " equals exclusiveMinimum", apply: set(name, v)})
+			}
+			if v, ok := keyword(prop, "maximum"); ok {
+				mutations = append(mutations, mutation{describe: name + " is above maximum", apply: set(name, v+1)})
+			}
+			if v, ok := keyword(prop, "exclusiveMaximum"); ok {
+				mutations = append(mutations, mutation{describe: name + " equals exclusiveMaximum", apply: set(name, v)})
+			}
+		case "string":
+			if v, ok := keyword(prop, "minLength"); ok && v > 0 {
+				mutations = append(mutations, mutation{describe: name + " is shorter than minLength", apply: set(name, strings.Repeat("a", int(v)-1))})
+			}
+			if v, ok := keyword(prop, "maxLength"); ok {
+				mutations = append(mutations, mutation{describe: name + " is longer than maxLength", apply: set(name, strings.Repeat("a", int(v)+1))})
+			}
+		}
+	}
+	return mutations
+}
+
+// wrongType returns a value that is not of JSON type typ.
+func wrongType(typ string) (interface{}, bool) {
+	switch typ {
+	case "string":
+		return 42, true
+	case "integer":
+		return "not a number", true
+	case "number":
+		return true, true
+	case "boolean":
+		return "yes", true
+	case "array":
+		return map[string]interface{}{"not": "an array"}, true
+	case "object":
+		return []interface{}{"not", "an", "object"}, true
+	}
+	return nil, false
+}
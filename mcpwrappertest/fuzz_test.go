@@ -0,0 +1,76 @@
+package mcpwrappertest
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	mcpwrapper "github.com/aleksadvaisly/mcp-go-wrapper"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+type ScaleArgs struct {
+	Service  string   `json:"service" jsonschema:"required,minLength=2,maxLength=20" validate:"required,min=2,max=20"`
+	Replicas int      `json:"replicas" jsonschema:"required,minimum=1,maximum=10" validate:"required,min=1,max=10"`
+	Tier     string   `json:"tier,omitempty" jsonschema:"enum=free,enum=pro"`
+	Owner    string   `json:"owner,omitempty" jsonschema:"format=email"`
+	Tags     []string `json:"tags,omitempty" jsonschema:"uniqueItems"`
+}
+
+type LooseArgs struct {
+	Name string `json:"name" jsonschema:"required" validate:"omitempty,min=5"`
+}
+
+func TestFuzzTools(t *testing.T) {
+	wrapper := mcpwrapper.New(server.NewMCPServer("test", "1.0.0"), mcpwrapper.WithStrictSchema())
+	wrapper.Register("scale", "Scale a service", ScaleArgs{}, func(ctx context.Context, args interface{}) (interface{}, error) {
+		return map[string]interface{}{"ok": true}, nil
+	})
+
+	FuzzTools(t, wrapper, FuzzOptions{Iterations: 200, Seed: 1})
+}
+
+func TestFuzzFindings(t *testing.T) {
+	wrapper := mcpwrapper.New(server.NewMCPServer("test", "1.0.0"))
+	wrapper.Register("loose", "Loose validation", LooseArgs{}, func(ctx context.Context, args interface{}) (interface{}, error) {
+		return "ok", nil
+	})
+	wrapper.Register("crash", "Panics on pro", ScaleArgs{}, func(ctx context.Context, args interface{}) (interface{}, error) {
+		if args.(*ScaleArgs).Replicas > 0 {
+			var m map[string]int
+			m["boom"]++
+		}
+		return "ok", nil
+	})
+
+	findings, err := Fuzz(wrapper, FuzzOptions{Iterations: 50, Seed: 7})
+	if err != nil {
+		t.Fatalf("Fuzz failed: %v", err)
+	}
+	kinds := make(map[string]bool)
+	for _, f := range findings {
+		if f.Seed != 7 {
+			t.Errorf("Expected the seed on every finding, got %d", f.Seed)
+		}
+		kinds[f.Tool+"/"+string(f.Kind)] = true
+	}
+	for _, want := range []string{
+		"crash/" + string(FindingPanic),
+		"loose/" + string(FindingRejectedValid),
+		"loose/" + string(FindingAcceptedInvalid),
+	} {
+		if !kinds[want] {
+			t.Errorf("Expected a %s finding, got %v", want, kinds)
+		}
+	}
+
+	r := &recorder{TB: t}
+	FuzzTools(r, wrapper, FuzzOptions{Iterations: 50, Seed: 7, Tools: []string{"crash"}})
+	if len(r.failures) == 0 || !strings.Contains(r.failures[0], "seed: 7") {
+		t.Errorf("Expected reported findings with the seed, got %v", r.failures)
+	}
+
+	if _, err := Fuzz(wrapper, FuzzOptions{Tools: []string{"missing"}}); err == nil {
+		t.Error("Expected an error for an unknown tool")
+	}
+}
@@ -0,0 +1,149 @@
+package mcpwrappertest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	mcpwrapper "github.com/aleksadvaisly/mcp-go-wrapper"
+)
+
+// UpdateGoldenEnv names the environment variable that makes
+// AssertSchemaGolden rewrite the golden files instead of comparing them.
+const UpdateGoldenEnv = "MCPWRAPPER_UPDATE_GOLDEN"
+
+// AssertSchemaGolden compares the input and output schema of every tool
+// registered on w with the golden file dir/<tool>.json, and fails t on any
+// difference, missing file, or golden file without a registered tool. Run
+// the test with MCPWRAPPER_UPDATE_GOLDEN=1 to write the current schemas,
+// then review and commit them: a later change that breaks a schema shows
+// up as a failing test and a diff in review.
+func AssertSchemaGolden(t testing.TB, w *mcpwrapper.Wrapper, dir string) {
+	t.Helper()
+
+	snapshots, err := schemaSnapshots(w)
+	if err != nil {
+		t.Fatalf("failed to snapshot tool schemas: %v", err)
+		return
+	}
+	names := make([]string, 0, len(snapshots))
+	for name := range snapshots {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	update := os.Getenv(UpdateGoldenEnv) != ""
+	if update {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatalf("failed to create %s: %v", dir, err)
+			return
+		}
+	}
+
+	files := make(map[string]bool, len(names))
+	for _, name := range names {
+		file := goldenFileName(name)
+		files[file] = true
+		path := filepath.Join(dir, file)
+
+		if update {
+			if err := os.WriteFile(path, snapshots[name], 0o644); err != nil {
+				t.Errorf("failed to write %s: %v", path, err)
+			}
+			continue
+		}
+
+		want, err := os.ReadFile(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			t.Errorf("tool %s has no golden schema; run with %s=1 to create %s", name, UpdateGoldenEnv, path)
+			continue
+		}
+		if err != nil {
+			t.Errorf("failed to read %s: %v", path, err)
+			continue
+		}
+		if !bytes.Equal(want, snapshots[name]) {
+			t.Errorf("schema of tool %s differs from %s:\n%s\nrun with %s=1 to accept the change",
+				name, path, firstDifference(string(want), string(snapshots[name])), UpdateGoldenEnv)
+		}
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("failed to list %s: %v", dir, err)
+		return
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" || files[entry.Name()] {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		if update {
+			if err := os.Remove(path); err != nil {
+				t.Errorf("failed to remove %s: %v", path, err)
+			}
+			continue
+		}
+		t.Errorf("golden schema %s has no registered tool; was the tool removed or renamed?", path)
+	}
+}
+
+// schemaSnapshots returns the golden file contents of every tool, keyed by
+// tool name.
+func schemaSnapshots(w *mcpwrapper.Wrapper) (map[string][]byte, error) {
+	data, err := w.ExportManifest(mcpwrapper.CatalogMCP)
+	if err != nil {
+		return nil, err
+	}
+	var catalog struct {
+		Tools []map[string]interface{} `json:"tools"`
+	}
+	if err := json.Unmarshal(data, &catalog); err != nil {
+		return nil, err
+	}
+
+	snapshots := make(map[string][]byte, len(catalog.Tools))
+	for _, tool := range catalog.Tools {
+		name, _ := tool["name"].(string)
+		snapshot := map[string]interface{}{"inputSchema": tool["inputSchema"]}
+		if output, ok := tool["outputSchema"]; ok {
+			snapshot["outputSchema"] = output
+		}
+		encoded, err := json.MarshalIndent(snapshot, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("tool %s: %w", name, err)
+		}
+		snapshots[name] = append(encoded, '\n')
+	}
+	return snapshots, nil
+}
+
+func goldenFileName(tool string) string {
+	return strings.NewReplacer("/", "_", "\\", "_").Replace(tool) + ".json"
+}
+
+// firstDifference shows the first line where got departs from want.
+func firstDifference(want, got string) string {
+	wantLines := strings.Split(want, "\n")
+	gotLines := strings.Split(got, "\n")
+	for i := 0; i < len(wantLines) || i < len(gotLines); i++ {
+		var w, g string
+		if i < len(wantLines) {
+			w = wantLines[i]
+		}
+		if i < len(gotLines) {
+			g = gotLines[i]
+		}
+		if w != g {
+			return fmt.Sprintf("line %d:\n  golden: %s\n  actual: %s", i+1, strings.TrimSpace(w), strings.TrimSpace(g))
+		}
+	}
+	return ""
+}
@@ -0,0 +1,85 @@
+package mcpwrappertest
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	mcpwrapper "github.com/aleksadvaisly/mcp-go-wrapper"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// recorder captures failures reported through testing.TB.
+type recorder struct {
+	testing.TB
+	failures []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func (r *recorder) Fatalf(format string, args ...interface{}) {
+	r.Errorf(format, args...)
+}
+
+type SearchArgs struct {
+	Query string `json:"query" jsonschema:"required,description=Search query"`
+}
+
+type SearchArgsV2 struct {
+	Query string `json:"query" jsonschema:"required,description=Search query"`
+	Limit int    `json:"limit" jsonschema:"required"`
+}
+
+func goldenWrapper(args interface{}, tools ...string) *mcpwrapper.Wrapper {
+	wrapper := mcpwrapper.New(server.NewMCPServer("test", "1.0.0"))
+	handler := func(ctx context.Context, args interface{}) (interface{}, error) { return nil, nil }
+	for _, name := range tools {
+		wrapper.Register(name, "Search", args, handler)
+	}
+	return wrapper
+}
+
+func TestAssertSchemaGolden(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "schemas")
+
+	r := &recorder{TB: t}
+	AssertSchemaGolden(r, goldenWrapper(SearchArgs{}, "search"), dir)
+	if len(r.failures) != 1 || !strings.Contains(r.failures[0], "has no golden schema") {
+		t.Fatalf("Expected a missing golden file failure, got %v", r.failures)
+	}
+
+	t.Setenv(UpdateGoldenEnv, "1")
+	AssertSchemaGolden(t, goldenWrapper(SearchArgs{}, "search", "find"), dir)
+	golden, err := os.ReadFile(filepath.Join(dir, "search.json"))
+	if err != nil || !strings.Contains(string(golden), `"description": "Search query"`) {
+		t.Fatalf("Expected the golden file to be written, got %s, %v", golden, err)
+	}
+
+	t.Setenv(UpdateGoldenEnv, "")
+	AssertSchemaGolden(t, goldenWrapper(SearchArgs{}, "search", "find"), dir)
+
+	r = &recorder{TB: t}
+	AssertSchemaGolden(r, goldenWrapper(SearchArgsV2{}, "search"), dir)
+	if len(r.failures) != 2 {
+		t.Fatalf("Expected a schema change and a stale file, got %v", r.failures)
+	}
+	if !strings.Contains(r.failures[0], "schema of tool search differs") || !strings.Contains(r.failures[0], `actual: "limit": {`) {
+		t.Errorf("Unexpected diff report: %s", r.failures[0])
+	}
+	if !strings.Contains(r.failures[1], "find.json has no registered tool") {
+		t.Errorf("Unexpected stale file report: %s", r.failures[1])
+	}
+
+	t.Setenv(UpdateGoldenEnv, "1")
+	AssertSchemaGolden(t, goldenWrapper(SearchArgsV2{}, "search"), dir)
+	if _, err := os.Stat(filepath.Join(dir, "find.json")); !os.IsNotExist(err) {
+		t.Error("Expected update mode to remove stale golden files")
+	}
+}
@@ -0,0 +1,93 @@
+package mcpwrapper
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Methods mcp-go's server does not route itself; HandleMessage answers them.
+const (
+	methodCompletionComplete   = "completion/complete"
+	methodResourcesSubscribe   = "resources/subscribe"
+	methodResourcesUnsubscribe = "resources/unsubscribe"
+)
+
+// HandleMessage answers the requests mcp-go's server does not route
+// (completion/complete, resources/subscribe, resources/unsubscribe) and
+// passes every other message to the MCP server. ServeStdio,
+// ServeStreamableHTTP and StreamableHTTPHandler route messages through it;
+// custom transports must call it instead of the server's HandleMessage for
+// those features to reach clients.
+func (w *Wrapper) HandleMessage(ctx context.Context, message json.RawMessage) mcp.JSONRPCMessage {
+	var request struct {
+		ID     mcp.RequestId   `json:"id"`
+		Method string          `json:"method"`
+		Params json.RawMessage `json:"params"`
+	}
+	if err := json.Unmarshal(message, &request); err != nil {
+		return w.server.HandleMessage(ctx, message)
+	}
+
+	switch request.Method {
+	case methodCompletionComplete:
+		var params mcp.CompleteParams
+		if err := json.Unmarshal(request.Params, &params); err != nil {
+			return mcp.NewJSONRPCError(request.ID, mcp.INVALID_PARAMS, err.Error(), nil)
+		}
+		result, err := w.Complete(ctx, mcp.CompleteRequest{
+			Request: mcp.Request{Method: request.Method},
+			Params:  params,
+		})
+		if err != nil {
+			return mcp.NewJSONRPCError(request.ID, mcp.INTERNAL_ERROR, err.Error(), nil)
+		}
+		return mcp.NewJSONRPCResultResponse(request.ID, result)
+
+	case methodResourcesSubscribe, methodResourcesUnsubscribe:
+		var params mcp.SubscribeParams
+		if err := json.Unmarshal(request.Params, &params); err != nil {
+			return mcp.NewJSONRPCError(request.ID, mcp.INVALID_PARAMS, err.Error(), nil)
+		}
+		if !w.resources.has(params.URI) {
+			return mcp.NewJSONRPCError(request.ID, mcp.RESOURCE_NOT_FOUND, "resource not found: "+params.URI, nil)
+		}
+		sessionID := SessionIDFromContext(ctx)
+		if request.Method == methodResourcesSubscribe {
+			w.resources.subscribe(params.URI, sessionID)
+		} else {
+			w.resources.unsubscribe(params.URI, sessionID)
+		}
+		return mcp.NewJSONRPCResultResponse(request.ID, mcp.EmptyResult{})
+	}
+
+	return w.server.HandleMessage(ctx, message)
+}
+
+// handlesMessage reports whether HandleMessage answers message itself rather
+// than passing it to the MCP server.
+func handlesMessage(message []byte) bool {
+	var request struct {
+		Method string `json:"method"`
+	}
+	if err := json.Unmarshal(message, &request); err != nil {
+		return false
+	}
+	switch request.Method {
+	case methodCompletionComplete, methodResourcesSubscribe, methodResourcesUnsubscribe:
+		return true
+	}
+	return false
+}
+
+// messageSession stands in for a transport's session when the wrapper
+// answers a message before the transport sees it. Only its ID is used.
+type messageSession struct {
+	id string
+}
+
+func (s messageSession) Initialize()                                         {}
+func (s messageSession) Initialized() bool                                   { return true }
+func (s messageSession) NotificationChannel() chan<- mcp.JSONRPCNotification { return nil }
+func (s messageSession) SessionID() string                                   { return s.id }
@@ -0,0 +1,23 @@
+package mcpwrapper
+
+type Middleware func(next Handler) Handler
+
+func WithMiddleware(mw ...Middleware) Option {
+	return func(w *Wrapper) {
+		w.middleware = append(w.middleware, mw...)
+	}
+}
+
+func WithToolMiddleware(mw ...Middleware) ToolOption {
+	return func(o *toolOptions) {
+		o.middleware = append(o.middleware, mw...)
+	}
+}
+
+// chain applies middleware so that the first one listed is the outermost.
+func chain(handler Handler, mw ...Middleware) Handler {
+	for i := len(mw) - 1; i >= 0; i-- {
+		handler = mw[i](handler)
+	}
+	return handler
+}
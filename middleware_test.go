@@ -0,0 +1,84 @@
+package mcpwrapper
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+type RemoveArgs struct {
+	Path  string `json:"path" validate:"required"`
+	Force bool   `json:"force"`
+}
+
+func TestMiddlewareTypedArgs(t *testing.T) {
+	denyForce := func(next Handler) Handler {
+		return func(ctx context.Context, args interface{}) (interface{}, error) {
+			info, _ := ToolInfoFromContext(ctx)
+			if a, ok := ArgsAs[RemoveArgs](args); ok && a.Force {
+				return nil, errors.New(info.Name + ": force is not allowed")
+			}
+			if _, ok := ArgsFromContext[RemoveArgs](ctx); !ok {
+				return nil, errors.New("validated args missing from context")
+			}
+			return next(ctx, args)
+		}
+	}
+
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	wrapper := New(mcpServer, WithMiddleware(denyForce))
+
+	handler := func(ctx context.Context, args interface{}) (interface{}, error) {
+		return &TestResult{Message: "removed"}, nil
+	}
+
+	if err := wrapper.Register("rm", "Remove a file", RemoveArgs{}, handler); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		force   bool
+		isError bool
+	}{
+		{"without force", false, false},
+		{"with force", true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := mcp.CallToolRequest{
+				Params: mcp.CallToolParams{
+					Name:      "rm",
+					Arguments: map[string]interface{}{"path": "/tmp/x", "force": tt.force},
+				},
+			}
+
+			result, err := mcpServer.GetTool("rm").Handler(context.Background(), request)
+			if err != nil {
+				t.Fatalf("Handler invocation failed: %v", err)
+			}
+
+			if result.IsError != tt.isError {
+				t.Errorf("Expected IsError=%v, got %v: %v", tt.isError, result.IsError, result.Content)
+			}
+		})
+	}
+}
+
+func TestArgsAs(t *testing.T) {
+	if a, ok := ArgsAs[RemoveArgs](&RemoveArgs{Path: "x"}); !ok || a.Path != "x" {
+		t.Error("Expected pointer args to convert")
+	}
+
+	if a, ok := ArgsAs[RemoveArgs](RemoveArgs{Path: "y"}); !ok || a.Path != "y" {
+		t.Error("Expected value args to convert")
+	}
+
+	if _, ok := ArgsAs[RemoveArgs](&TestArgs{}); ok {
+		t.Error("Expected mismatched args type to fail")
+	}
+}
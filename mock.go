@@ -0,0 +1,82 @@
+package mcpwrapper
+
+import (
+	"context"
+	"os"
+	"strconv"
+)
+
+// MockEnv enables mock mode when set to a true value such as "1", without
+// a code change.
+const MockEnv = "MCPWRAPPER_MOCK"
+
+// WithMockMode makes tools that have a mock, from WithMock or WithMocks,
+// answer with it instead of calling their handler. Setting
+// MCPWRAPPER_MOCK=1 has the same effect.
+func WithMockMode() Option {
+	return func(w *Wrapper) {
+		w.mockMode = true
+	}
+}
+
+// WithMocks sets the mocks of tools by the name they are registered with,
+// including tools registered by adapters such as RegisterCobraTree. A
+// tool's WithMock takes precedence.
+func WithMocks(mocks map[string]Handler) Option {
+	return func(w *Wrapper) {
+		if w.mocks == nil {
+			w.mocks = make(map[string]Handler)
+		}
+		for name, mock := range mocks {
+			w.mocks[name] = mock
+		}
+	}
+}
+
+// WithMock sets the handler the tool runs in mock mode. It gets the bound
+// and validated arguments, and its result goes through the tool's
+// middleware as the handler's would. A tool with a mock may be registered
+// with a nil handler until the real one exists; outside mock mode its
+// calls then fail as unavailable.
+func WithMock(mock Handler) ToolOption {
+	return func(o *toolOptions) {
+		o.mock = mock
+	}
+}
+
+// MockResult returns a mock that always returns result.
+func MockResult(result interface{}) Handler {
+	return func(ctx context.Context, args interface{}) (interface{}, error) {
+		return result, nil
+	}
+}
+
+func mockFromEnv() bool {
+	enabled, _ := strconv.ParseBool(os.Getenv(MockEnv))
+	return enabled
+}
+
+func (w *Wrapper) toolMock(name string, options *toolOptions) Handler {
+	if options.mock != nil {
+		return options.mock
+	}
+	return w.mocks[name]
+}
+
+// mockedHandler returns the handler a tool runs: its mock in mock mode,
+// and otherwise its handler, or a stand-in if it has none yet. Mocked
+// results carry "mocked": true in their _meta.
+func (w *Wrapper) mockedHandler(name string, handler Handler, mock Handler) Handler {
+	if w.mockMode && mock != nil {
+		return func(ctx context.Context, args interface{}) (interface{}, error) {
+			SetResultMeta(ctx, "mocked", true)
+			return mock(ctx, args)
+		}
+	}
+	if handler == nil {
+		return func(ctx context.Context, args interface{}) (interface{}, error) {
+			return nil, Unavailable("tool %s is not implemented yet; it only has a mock", name)
+		}
+	}
+	return handler
+}
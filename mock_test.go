@@ -0,0 +1,85 @@
+package mcpwrapper
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func TestMockMode(t *testing.T) {
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	wrapper := New(mcpServer, WithMockMode(), WithMocks(map[string]Handler{
+		"lookup": MockResult(&TestResult{Message: "from the registry"}),
+	}))
+
+	real := func(ctx context.Context, args interface{}) (interface{}, error) {
+		t.Error("Expected the handler not to be called in mock mode")
+		return nil, nil
+	}
+	mock := func(ctx context.Context, args interface{}) (interface{}, error) {
+		return &TestResult{Message: "mocked " + args.(*TestArgs).Name}, nil
+	}
+	if err := wrapper.Register("greet", "Greet", TestArgs{}, real, WithMock(mock)); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	if err := wrapper.Register("lookup", "Look up", TestArgs{}, real); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	if err := wrapper.Register("unbuilt", "Not built yet", TestArgs{}, nil, WithMock(mock)); err != nil {
+		t.Fatalf("Register with only a mock failed: %v", err)
+	}
+
+	args := map[string]interface{}{"name": "Alice", "age": 30, "category": "A"}
+	result := callTool(t, mcpServer, "greet", args)
+	var out TestResult
+	decodeResult(t, result, &out)
+	if out.Message != "mocked Alice" || result.Meta == nil || result.Meta.AdditionalFields["mocked"] != true {
+		t.Errorf("Unexpected mocked result: %+v %+v", out, result.Meta)
+	}
+	decodeResult(t, callTool(t, mcpServer, "lookup", args), &out)
+	if out.Message != "from the registry" {
+		t.Errorf("Expected the registry mock, got %+v", out)
+	}
+	decodeResult(t, callTool(t, mcpServer, "unbuilt", args), &out)
+	if out.Message != "mocked Alice" {
+		t.Errorf("Expected the mock of a tool without a handler, got %+v", out)
+	}
+
+	if result := callTool(t, mcpServer, "greet", map[string]interface{}{"name": "Al"}); !result.IsError {
+		t.Error("Expected mocked calls to be validated")
+	}
+}
+
+func TestMockModeOff(t *testing.T) {
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	wrapper := New(mcpServer)
+	handler := func(ctx context.Context, args interface{}) (interface{}, error) {
+		return &TestResult{Message: "real"}, nil
+	}
+	wrapper.Register("greet", "Greet", TestArgs{}, handler, WithMock(MockResult(&TestResult{Message: "mocked"})))
+	wrapper.Register("unbuilt", "Not built yet", TestArgs{}, nil, WithMock(MockResult(nil)))
+
+	args := map[string]interface{}{"name": "Alice", "age": 30, "category": "A"}
+	var out TestResult
+	decodeResult(t, callTool(t, mcpServer, "greet", args), &out)
+	if out.Message != "real" {
+		t.Errorf("Expected the handler outside mock mode, got %+v", out)
+	}
+	result := callTool(t, mcpServer, "unbuilt", args)
+	if !result.IsError || !strings.Contains(resultText(result), "not implemented yet") {
+		t.Errorf("Expected a tool without a handler to be unavailable, got %v", result.Content)
+	}
+
+	if err := wrapper.Register("broken", "No handler", TestArgs{}, nil); err == nil || !strings.Contains(err.Error(), "must not be nil") {
+		t.Errorf("Expected a nil handler without a mock to be rejected, got %v", err)
+	}
+}
+
+func TestMockModeFromEnv(t *testing.T) {
+	t.Setenv(MockEnv, "1")
+	if wrapper := New(server.NewMCPServer("test", "1.0.0")); !wrapper.mockMode {
+		t.Error("Expected MCPWRAPPER_MOCK=1 to enable mock mode")
+	}
+}
@@ -0,0 +1,183 @@
+package mcpwrapper
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	offloadScheme = "offload://"
+	// DefaultOffloadChunkSize is the chunk size of offloaded results when
+	// WithResultOffload is given 0.
+	DefaultOffloadChunkSize = 64 * 1024
+)
+
+type resultOffload struct {
+	threshold int
+	chunkSize int
+	store     *oversizedStore
+}
+
+// WithResultOffload stores results larger than threshold bytes as
+// resources instead of returning them inline. The call returns a short
+// summary of the result and a link to offload://<id>/0, its first chunk of
+// about chunkSize bytes, cut at a character boundary; the client reads the
+// remaining chunks in order.
+// Like offloaded messages, they are kept per session, the latest 32 per
+// session.
+func WithResultOffload(threshold, chunkSize int) Option {
+	return func(w *Wrapper) {
+		if threshold <= 0 {
+			return
+		}
+		if chunkSize <= 0 {
+			chunkSize = DefaultOffloadChunkSize
+		}
+		w.offload = &resultOffload{
+			threshold: threshold,
+			chunkSize: chunkSize,
+			store:     &oversizedStore{sessions: make(map[string]*sessionOversized)},
+		}
+		w.server.AddResourceTemplate(
+			mcp.NewResourceTemplate(offloadScheme+"{id}/{chunk}", "Offloaded tool result",
+				mcp.WithTemplateDescription("Chunks of tool results too large to return inline"),
+			),
+			w.readOffloaded,
+		)
+	}
+}
+
+// WithoutResultOffload returns the tool's results inline whatever their
+// size, for tools whose callers cannot read resources.
+func WithoutResultOffload() ToolOption {
+	return func(o *toolOptions) {
+		o.noOffload = true
+	}
+}
+
+func (w *Wrapper) shouldOffload(t *registeredTool, text string) bool {
+	return w.offload != nil && !t.options.noOffload && len(text) > w.offload.threshold
+}
+
+// offloadResult stores text for the session and returns the summary result
+// pointing to its chunks.
+func (w *Wrapper) offloadResult(ctx context.Context, t *registeredTool, text string) *mcp.CallToolResult {
+	id := w.offload.store.put(SessionIDFromContext(ctx), text)
+	chunks := chunkCount(len(text), w.offload.chunkSize)
+	first := fmt.Sprintf("%s%s/0", offloadScheme, id)
+	w.logger.Info("offloaded tool result", "tool", t.name, "bytes", len(text), "chunks", chunks)
+
+	read := first
+	if chunks > 1 {
+		read = fmt.Sprintf("%s through %s%s/%d in order", first, offloadScheme, id, chunks-1)
+	}
+	summary := fmt.Sprintf("The result (%d bytes, %s) is too large to return inline. It is stored in %d chunk(s) of about %d bytes; read %s.",
+		len(text), describeText(text), chunks, w.offload.chunkSize, read)
+
+	result := mcp.NewToolResultText(summary)
+	result.Content = append(result.Content, mcp.NewResourceLink(first, t.name+" result", "First chunk of the result", "text/plain"))
+	result.Meta = mcp.NewMetaFromMap(map[string]interface{}{
+		"offloaded": map[string]interface{}{
+			"uri":    first,
+			"bytes":  len(text),
+			"chunks": chunks,
+		},
+	})
+	return result
+}
+
+// describeText summarizes the shape of a result for the offload notice.
+func describeText(text string) string {
+	var value interface{}
+	if err := json.Unmarshal([]byte(text), &value); err != nil {
+		return "text"
+	}
+	switch v := value.(type) {
+	case []interface{}:
+		return fmt.Sprintf("a JSON array of %d items", len(v))
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		if len(keys) > 10 {
+			keys = append(keys[:10], "...")
+		}
+		return fmt.Sprintf("a JSON object with keys %s", strings.Join(keys, ", "))
+	default:
+		return "a JSON value"
+	}
+}
+
+func chunkCount(size, chunkSize int) int {
+	if size == 0 {
+		return 1
+	}
+	return (size + chunkSize - 1) / chunkSize
+}
+
+// chunkBounds returns the byte range of chunk n, with both ends moved back
+// to a rune boundary so consecutive chunks join up exactly.
+func chunkBounds(text string, n, chunkSize int) (int, int) {
+	boundary := func(i int) int {
+		if i >= len(text) {
+			return len(text)
+		}
+		for i > 0 && !utf8.RuneStart(text[i]) {
+			i--
+		}
+		return i
+	}
+	return boundary(n * chunkSize), boundary((n + 1) * chunkSize)
+}
+
+func (w *Wrapper) readOffloaded(ctx context.Context, request mcp.ReadResourceRequest) (contents []mcp.ResourceContents, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			panicErr := newPanicError(r)
+			w.logger.Error("offloaded result read panicked", "uri", request.Params.URI, "panic", panicErr.Value, "stack", string(panicErr.Stack))
+			contents, err = nil, panicErr
+		}
+	}()
+
+	uri := request.Params.URI
+	path := strings.TrimPrefix(uri, offloadScheme)
+	i := strings.LastIndex(path, "/")
+	if i < 0 {
+		return nil, fmt.Errorf("invalid offloaded result URI %s", uri)
+	}
+	id := path[:i]
+	n, err := strconv.Atoi(path[i+1:])
+	if err != nil || n < 0 {
+		return nil, fmt.Errorf("invalid offloaded result URI %s", uri)
+	}
+
+	text, ok := w.offload.store.get(SessionIDFromContext(ctx), id)
+	if !ok {
+		return nil, fmt.Errorf("%s is not available in this session", uri)
+	}
+	chunks := chunkCount(len(text), w.offload.chunkSize)
+	if n >= chunks {
+		return nil, fmt.Errorf("%s is out of range: the result has %d chunk(s)", uri, chunks)
+	}
+
+	start, end := chunkBounds(text, n, w.offload.chunkSize)
+	meta := map[string]interface{}{"chunk": n, "chunks": chunks}
+	if n+1 < chunks {
+		meta["next"] = fmt.Sprintf("%s%s/%d", offloadScheme, id, n+1)
+	}
+	return []mcp.ResourceContents{mcp.TextResourceContents{
+		Meta:     meta,
+		URI:      uri,
+		MIMEType: "text/plain",
+		Text:     text[start:end],
+	}}, nil
+}
@@ -0,0 +1,68 @@
+package mcpwrapper
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestResultOffload(t *testing.T) {
+	mcpServer, ctx, result := oversizedCall(t, WithResultOffload(1000, 1001))
+
+	text := result.Content[0].(mcp.TextContent).Text
+	if !strings.Contains(text, "offload://1/0 through offload://1/8") || !strings.Contains(text, "(8014 bytes, a JSON object with keys message)") {
+		t.Errorf("Unexpected summary: %q", text)
+	}
+	link, ok := result.Content[1].(mcp.ResourceLink)
+	if !ok || link.URI != "offload://1/0" {
+		t.Fatalf("Unexpected resource link: %#v", result.Content[1])
+	}
+	if result.StructuredContent != nil || result.Meta.AdditionalFields["offloaded"] == nil {
+		t.Errorf("Unexpected offloaded result: %+v", result)
+	}
+
+	read := func(ctx context.Context, uri string) (mcp.TextResourceContents, bool) {
+		response := mcpServer.HandleMessage(ctx, json.RawMessage(`{"jsonrpc": "2.0", "id": 1, "method": "resources/read", "params": {"uri": "`+uri+`"}}`))
+		r, ok := response.(mcp.JSONRPCResponse)
+		if !ok {
+			return mcp.TextResourceContents{}, false
+		}
+		return r.Result.(mcp.ReadResourceResult).Contents[0].(mcp.TextResourceContents), true
+	}
+
+	var full strings.Builder
+	for uri := link.URI; uri != ""; {
+		chunk, ok := read(ctx, uri)
+		if !ok {
+			t.Fatalf("Failed to read %s", uri)
+		}
+		if len(chunk.Text) > 1001+utf8.UTFMax-1 {
+			t.Errorf("Chunk %s has %d bytes", uri, len(chunk.Text))
+		}
+		full.WriteString(chunk.Text)
+		uri, _ = chunk.Meta["next"].(string)
+	}
+	var decoded TestResult
+	if err := json.Unmarshal([]byte(full.String()), &decoded); err != nil || decoded.Message != strings.Repeat("é\"", 2000) {
+		t.Errorf("Expected the chunks to join into the full result: %v", err)
+	}
+
+	if _, ok := read(ctx, "offload://1/9"); ok {
+		t.Error("Expected reading past the last chunk to fail")
+	}
+	other := mcpServer.WithContext(context.Background(), &testSession{id: "session-2"})
+	if _, ok := read(other, "offload://1/0"); ok {
+		t.Error("Expected offloaded results to be scoped to the session")
+	}
+}
+
+func TestResultOffloadUnderThreshold(t *testing.T) {
+	_, _, result := oversizedCall(t, WithResultOffload(1<<20, 0))
+	if len(result.Content) != 1 || result.Meta != nil {
+		t.Errorf("Expected small results to be returned inline, got %+v", result)
+	}
+}
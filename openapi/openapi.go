@@ -0,0 +1,226 @@
+// Package openapi registers the operations of an OpenAPI 3 document as
+// mcpwrapper tools, so a whole REST API can be exposed in a few lines:
+//
+//	doc, err := openapi.Load("petstore.yaml")
+//	if err != nil {
+//		return err
+//	}
+//	err = openapi.Register(wrapper, doc, openapi.WithAuth(addToken))
+package openapi
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Document is a parsed OpenAPI 3 document with its local $refs resolved.
+type Document struct {
+	Title      string
+	Servers    []string
+	Operations []Operation
+}
+
+// Operation is one method on one path.
+type Operation struct {
+	ID          string
+	Method      string
+	Path        string
+	Summary     string
+	Description string
+	Parameters  []Parameter
+	// Body is the JSON schema of the application/json request body, if any.
+	Body         map[string]interface{}
+	BodyRequired bool
+	// Response is the JSON schema of the first 2xx application/json response,
+	// if any.
+	Response map[string]interface{}
+}
+
+// Parameter is a path, query or header parameter.
+type Parameter struct {
+	Name        string
+	In          string
+	Description string
+	Required    bool
+	Schema      map[string]interface{}
+}
+
+var methods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// Load reads and parses an OpenAPI 3 document in YAML or JSON.
+func Load(path string) (*Document, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read OpenAPI document %s: %w", path, err)
+	}
+
+	doc, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse OpenAPI document %s: %w", path, err)
+	}
+
+	return doc, nil
+}
+
+// Parse accepts YAML or JSON (JSON is valid YAML). Only local references
+// (#/...) are supported; recursive schemas are cut off at the first repeat.
+func Parse(data []byte) (*Document, error) {
+	var root map[string]interface{}
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, err
+	}
+
+	version, _ := root["openapi"].(string)
+	if !strings.HasPrefix(version, "3.") {
+		return nil, fmt.Errorf("unsupported OpenAPI version %q, want 3.x", version)
+	}
+
+	r := &resolver{root: root}
+	doc := &Document{}
+	if info, ok := root["info"].(map[string]interface{}); ok {
+		doc.Title, _ = info["title"].(string)
+	}
+	for _, server := range list(root["servers"]) {
+		if url, ok := object(server)["url"].(string); ok {
+			doc.Servers = append(doc.Servers, url)
+		}
+	}
+
+	paths := object(root["paths"])
+	names := make([]string, 0, len(paths))
+	for path := range paths {
+		names = append(names, path)
+	}
+	sort.Strings(names)
+
+	for _, path := range names {
+		item, err := r.resolveObject(paths[path])
+		if err != nil {
+			return nil, fmt.Errorf("path %s: %w", path, err)
+		}
+		for _, method := range methods {
+			op, ok := item[method].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			operation, err := r.operation(method, path, op, list(item["parameters"]))
+			if err != nil {
+				return nil, fmt.Errorf("%s %s: %w", strings.ToUpper(method), path, err)
+			}
+			doc.Operations = append(doc.Operations, operation)
+		}
+	}
+
+	return doc, nil
+}
+
+func (r *resolver) operation(method, path string, op map[string]interface{}, shared []interface{}) (Operation, error) {
+	operation := Operation{
+		Method: strings.ToUpper(method),
+		Path:   path,
+	}
+	operation.ID, _ = op["operationId"].(string)
+	if operation.ID == "" {
+		operation.ID = derivedID(method, path)
+	}
+	operation.Summary, _ = op["summary"].(string)
+	operation.Description, _ = op["description"].(string)
+
+	// Operation parameters override path-level ones with the same name and
+	// location.
+	byKey := make(map[string]int)
+	for _, raw := range append(shared, list(op["parameters"])...) {
+		p, err := r.resolveObject(raw)
+		if err != nil {
+			return operation, err
+		}
+		param := Parameter{}
+		param.Name, _ = p["name"].(string)
+		param.In, _ = p["in"].(string)
+		param.Description, _ = p["description"].(string)
+		param.Required, _ = p["required"].(bool)
+		if param.In == "cookie" {
+			continue
+		}
+		if param.Schema, err = r.schema(p["schema"]); err != nil {
+			return operation, err
+		}
+
+		key := param.In + ":" + param.Name
+		if i, ok := byKey[key]; ok {
+			operation.Parameters[i] = param
+			continue
+		}
+		byKey[key] = len(operation.Parameters)
+		operation.Parameters = append(operation.Parameters, param)
+	}
+
+	if raw, ok := op["requestBody"]; ok {
+		body, err := r.resolveObject(raw)
+		if err != nil {
+			return operation, err
+		}
+		if media, ok := jsonMedia(body); ok {
+			if operation.Body, err = r.schema(media["schema"]); err != nil {
+				return operation, err
+			}
+			operation.BodyRequired, _ = body["required"].(bool)
+		}
+	}
+
+	responses := object(op["responses"])
+	codes := make([]string, 0, len(responses))
+	for code := range responses {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	for _, code := range codes {
+		if !strings.HasPrefix(code, "2") {
+			continue
+		}
+		response, err := r.resolveObject(responses[code])
+		if err != nil {
+			return operation, err
+		}
+		if media, ok := jsonMedia(response); ok {
+			if operation.Response, err = r.schema(media["schema"]); err != nil {
+				return operation, err
+			}
+			break
+		}
+	}
+
+	return operation, nil
+}
+
+func jsonMedia(v map[string]interface{}) (map[string]interface{}, bool) {
+	content := object(v["content"])
+	for mediaType, media := range content {
+		if strings.Contains(mediaType, "json") {
+			return object(media), true
+		}
+	}
+	return nil, false
+}
+
+var nonNameChars = regexp.MustCompile(`[^a-zA-Z0-9]+`)
+
+// derivedID names an operation without operationId, e.g. get_pets_petId.
+func derivedID(method, path string) string {
+	return method + "_" + strings.Trim(nonNameChars.ReplaceAllString(path, "_"), "_")
+}
+
+func object(v interface{}) map[string]interface{} {
+	m, _ := v.(map[string]interface{})
+	return m
+}
+
+func list(v interface{}) []interface{} {
+	l, _ := v.([]interface{})
+	return l
+}
@@ -0,0 +1,238 @@
+package openapi
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	mcpwrapper "github.com/aleksadvaisly/mcp-go-wrapper"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const petstore = `
+openapi: 3.0.3
+info:
+  title: Petstore
+servers:
+  - url: https://petstore.example.com/v1
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      summary: List all pets
+      parameters:
+        - name: limit
+          in: query
+          schema: {type: integer, maximum: 100}
+        - name: tag
+          in: query
+          schema: {type: array, items: {type: string}}
+      responses:
+        '200':
+          content:
+            application/json:
+              schema:
+                type: array
+                items: {$ref: '#/components/schemas/Pet'}
+    post:
+      operationId: createPet
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema: {$ref: '#/components/schemas/NewPet'}
+      responses:
+        '201': {description: Created}
+  /pets/{petId}:
+    parameters:
+      - $ref: '#/components/parameters/PetId'
+    delete:
+      summary: Delete a pet
+      parameters:
+        - name: X-Reason
+          in: header
+          schema: {type: string}
+      responses:
+        '204': {description: Deleted}
+components:
+  parameters:
+    PetId:
+      name: petId
+      in: path
+      description: The pet's ID
+      schema: {type: string}
+  schemas:
+    NewPet:
+      type: object
+      required: [name]
+      properties:
+        name: {type: string}
+        parent: {$ref: '#/components/schemas/Pet'}
+    Pet:
+      allOf:
+        - $ref: '#/components/schemas/NewPet'
+        - type: object
+          properties:
+            id: {type: integer}
+`
+
+func TestParse(t *testing.T) {
+	doc, err := Parse([]byte(petstore))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if doc.Title != "Petstore" || doc.Servers[0] != "https://petstore.example.com/v1" {
+		t.Errorf("Unexpected document info: %+v", doc)
+	}
+
+	var ids []string
+	for _, op := range doc.Operations {
+		ids = append(ids, op.ID)
+	}
+	if strings.Join(ids, ",") != "listPets,createPet,delete_pets_petId" {
+		t.Fatalf("Unexpected operations: %v", ids)
+	}
+
+	list := doc.Operations[0]
+	if list.Response["type"] != "array" {
+		t.Errorf("Expected the 200 response schema, got %v", list.Response)
+	}
+	pet := list.Response["items"].(map[string]interface{})
+	if _, ok := pet["allOf"]; !ok {
+		t.Errorf("Expected references to be inlined, got %v", pet)
+	}
+
+	create := doc.Operations[1]
+	parent := create.Body["properties"].(map[string]interface{})["parent"].(map[string]interface{})
+	cycle := parent["allOf"].([]interface{})[0].(map[string]interface{})
+	if cycle["type"] != "object" || cycle["properties"] != nil {
+		t.Errorf("Expected the recursive reference to be cut off, got %v", cycle)
+	}
+
+	del := doc.Operations[2]
+	if len(del.Parameters) != 2 || del.Parameters[0].Name != "petId" || del.Parameters[0].Description != "The pet's ID" {
+		t.Errorf("Expected path-level parameters to be resolved, got %+v", del.Parameters)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	if _, err := Parse([]byte("swagger: '2.0'")); err == nil {
+		t.Error("Expected an error for Swagger 2.0")
+	}
+	doc := "openapi: 3.0.0\npaths:\n  /a:\n    get:\n      parameters: [{$ref: 'other.yaml#/P'}]\n"
+	if _, err := Parse([]byte(doc)); err == nil || !strings.Contains(err.Error(), "only local references") {
+		t.Errorf("Expected a remote reference error, got %v", err)
+	}
+}
+
+func TestRegister(t *testing.T) {
+	var requests []string
+	var lastBody, lastAuth, lastReason string
+	api := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, r.Method+" "+r.URL.RequestURI())
+		lastBody, lastAuth, lastReason = string(body), r.Header.Get("Authorization"), r.Header.Get("X-Reason")
+		rw.Header().Set("Content-Type", "application/json")
+		io.WriteString(rw, `[{"id": 1, "name": "Rex"}]`)
+	}))
+	defer api.Close()
+
+	doc, err := Parse([]byte(petstore))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	wrapper := mcpwrapper.New(mcpServer)
+	err = Register(wrapper, doc,
+		WithBaseURL(api.URL+"/v1/"),
+		WithAuth(func(ctx context.Context, req *http.Request) error {
+			req.Header.Set("Authorization", "Bearer token")
+			return nil
+		}))
+	if err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	list := mcpServer.GetTool("listPets").Tool
+	if list.Description != "List all pets" || list.Annotations.ReadOnlyHint == nil || !*list.Annotations.ReadOnlyHint {
+		t.Errorf("Unexpected listPets tool: %+v", list)
+	}
+	if list.OutputSchema.Properties["body"].(map[string]interface{})["type"] != "array" {
+		t.Errorf("Expected the response schema as output schema, got %+v", list.OutputSchema)
+	}
+	if del := mcpServer.GetTool("delete_pets_petId").Tool; del.InputSchema.Required[0] != "petId" {
+		t.Errorf("Expected path parameters to be required, got %+v", del.InputSchema)
+	}
+
+	call := func(name string, args map[string]interface{}) *mcp.CallToolResult {
+		t.Helper()
+		result, err := mcpServer.GetTool(name).Handler(context.Background(), mcp.CallToolRequest{
+			Params: mcp.CallToolParams{Name: name, Arguments: args},
+		})
+		if err != nil {
+			t.Fatalf("Handler returned error: %v", err)
+		}
+		return result
+	}
+
+	result := call("listPets", map[string]interface{}{"limit": 10, "tag": []interface{}{"dog", "cat"}})
+	var got mcpwrapper.HTTPResult
+	if err := json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &got); err != nil {
+		t.Fatalf("Unexpected result %+v: %v", result, err)
+	}
+	if got.Status != http.StatusOK || got.Body.([]interface{})[0].(map[string]interface{})["name"] != "Rex" {
+		t.Errorf("Unexpected result: %+v", got)
+	}
+	if result.StructuredContent == nil {
+		t.Error("Expected structured content for a tool with an output schema")
+	}
+	if lastAuth != "Bearer token" {
+		t.Errorf("Expected the auth hook to run, got %q", lastAuth)
+	}
+
+	call("createPet", map[string]interface{}{"body": map[string]interface{}{"name": "Tom"}})
+	if lastBody != `{"name":"Tom"}` {
+		t.Errorf("Unexpected request body %s", lastBody)
+	}
+
+	call("delete_pets_petId", map[string]interface{}{"petId": "a/b", "X-Reason": "adopted"})
+	if lastReason != "adopted" {
+		t.Errorf("Expected the header parameter to be sent, got %q", lastReason)
+	}
+
+	want := []string{
+		"GET /v1/pets?limit=10&tag=dog&tag=cat",
+		"POST /v1/pets",
+		"DELETE /v1/pets/a%2Fb",
+	}
+	if strings.Join(requests, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Unexpected requests:\n%s", strings.Join(requests, "\n"))
+	}
+
+	if result := call("createPet", map[string]interface{}{}); !result.IsError {
+		t.Error("Expected a required body to be enforced")
+	}
+}
+
+func TestRegisterFilter(t *testing.T) {
+	doc, err := Parse([]byte(petstore))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	wrapper := mcpwrapper.New(server.NewMCPServer("test", "1.0.0"))
+	err = Register(wrapper, doc, WithFilter(func(op Operation) bool { return op.Method == http.MethodGet }))
+	if err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	if names := wrapper.ToolNames(); len(names) != 1 || names[0] != "listPets" {
+		t.Errorf("Expected only read operations, got %v", names)
+	}
+}
@@ -0,0 +1,281 @@
+package openapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	mcpwrapper "github.com/aleksadvaisly/mcp-go-wrapper"
+)
+
+// bodyArgument is the tool argument holding the request body.
+const bodyArgument = "body"
+
+// maxResponseBytes bounds how much of a response body is read.
+const maxResponseBytes = 10 << 20
+
+// AuthFunc adds credentials to an outgoing request, e.g. a bearer token or an
+// API key header. ctx is the tool call's context, so credentials can depend
+// on the session.
+type AuthFunc func(ctx context.Context, req *http.Request) error
+
+type options struct {
+	baseURL     string
+	client      *http.Client
+	auth        AuthFunc
+	include     func(Operation) bool
+	toolOptions func(Operation) []mcpwrapper.ToolOption
+}
+
+type Option func(*options)
+
+// WithBaseURL sets the URL operation paths are appended to. It defaults to
+// the document's first server.
+func WithBaseURL(baseURL string) Option {
+	return func(o *options) {
+		o.baseURL = baseURL
+	}
+}
+
+func WithClient(client *http.Client) Option {
+	return func(o *options) {
+		o.client = client
+	}
+}
+
+// WithAuth injects credentials into every request. They never appear in the
+// tools' schemas, arguments or results.
+func WithAuth(auth AuthFunc) Option {
+	return func(o *options) {
+		o.auth = auth
+	}
+}
+
+// WithFilter registers only the operations for which include returns true.
+func WithFilter(include func(Operation) bool) Option {
+	return func(o *options) {
+		o.include = include
+	}
+}
+
+// WithToolOptions adds tool options per operation, e.g. middleware or
+// authorizers for operations that modify data.
+func WithToolOptions(fn func(Operation) []mcpwrapper.ToolOption) Option {
+	return func(o *options) {
+		o.toolOptions = fn
+	}
+}
+
+// Register registers every operation of doc as a tool named after its
+// operationId. Parameters become arguments of the same name and a JSON
+// request body becomes the "body" argument. Results are
+// mcpwrapper.HTTPResult values, with the response schema, if any, declared
+// as the output schema of its body. GET and HEAD operations are marked
+// read-only.
+func Register(w *mcpwrapper.Wrapper, doc *Document, opts ...Option) error {
+	o := &options{client: http.DefaultClient}
+	if len(doc.Servers) > 0 {
+		o.baseURL = doc.Servers[0]
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.baseURL == "" {
+		return fmt.Errorf("the document has no servers; set WithBaseURL")
+	}
+
+	for _, op := range doc.Operations {
+		if o.include != nil && !o.include(op) {
+			continue
+		}
+
+		var toolOpts []mcpwrapper.ToolOption
+		if op.Method == http.MethodGet || op.Method == http.MethodHead {
+			toolOpts = append(toolOpts, mcpwrapper.WithReadOnly())
+		}
+		toolOpts = append(toolOpts, mcpwrapper.WithOutputSchema(outputSchema(op)))
+		if o.toolOptions != nil {
+			toolOpts = append(toolOpts, o.toolOptions(op)...)
+		}
+
+		if err := w.RegisterSchema(op.ID, description(op), inputSchema(op), o.handler(op), toolOpts...); err != nil {
+			return fmt.Errorf("operation %s: %w", op.ID, err)
+		}
+	}
+	return nil
+}
+
+func description(op Operation) string {
+	switch {
+	case op.Summary != "" && op.Description != "":
+		return op.Summary + "\n\n" + op.Description
+	case op.Summary != "":
+		return op.Summary
+	case op.Description != "":
+		return op.Description
+	default:
+		return op.Method + " " + op.Path
+	}
+}
+
+func inputSchema(op Operation) map[string]interface{} {
+	properties := make(map[string]interface{})
+	required := make([]string, 0)
+
+	for _, p := range op.Parameters {
+		prop := make(map[string]interface{})
+		for k, v := range p.Schema {
+			prop[k] = v
+		}
+		if len(prop) == 0 {
+			prop["type"] = "string"
+		}
+		if p.Description != "" {
+			prop["description"] = p.Description
+		}
+		properties[p.Name] = prop
+		if p.Required || p.In == "path" {
+			required = append(required, p.Name)
+		}
+	}
+
+	if op.Body != nil {
+		properties[bodyArgument] = op.Body
+		if op.BodyRequired {
+			required = append(required, bodyArgument)
+		}
+	}
+
+	return map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+		"required":   required,
+	}
+}
+
+func outputSchema(op Operation) map[string]interface{} {
+	body := op.Response
+	if body == nil {
+		body = map[string]interface{}{}
+	}
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"status":  map[string]interface{}{"type": "integer"},
+			"headers": map[string]interface{}{"type": "object"},
+			"body":    body,
+		},
+		"required": []string{"status", "headers", "body"},
+	}
+}
+
+func (o *options) handler(op Operation) mcpwrapper.Handler {
+	return func(ctx context.Context, a interface{}) (interface{}, error) {
+		args := a.(mcpwrapper.Arguments)
+
+		path := op.Path
+		query := url.Values{}
+		header := http.Header{}
+		for _, p := range op.Parameters {
+			value, ok := args[p.Name]
+			if !ok || value == nil {
+				continue
+			}
+			switch p.In {
+			case "path":
+				path = strings.ReplaceAll(path, "{"+p.Name+"}", url.PathEscape(format(value)))
+			case "query":
+				if values, ok := value.([]interface{}); ok {
+					for _, v := range values {
+						query.Add(p.Name, format(v))
+					}
+					continue
+				}
+				query.Set(p.Name, format(value))
+			case "header":
+				header.Set(p.Name, format(value))
+			}
+		}
+
+		target := strings.TrimSuffix(o.baseURL, "/") + path
+		if len(query) > 0 {
+			target += "?" + query.Encode()
+		}
+
+		var body io.Reader
+		if value, ok := args[bodyArgument]; ok && op.Body != nil {
+			encoded, err := json.Marshal(value)
+			if err != nil {
+				return nil, fmt.Errorf("failed to encode body: %w", err)
+			}
+			body = bytes.NewReader(encoded)
+			header.Set("Content-Type", "application/json")
+		}
+
+		req, err := http.NewRequestWithContext(ctx, op.Method, target, body)
+		if err != nil {
+			return nil, fmt.Errorf("invalid request: %w", err)
+		}
+		for name, values := range header {
+			req.Header[name] = values
+		}
+		req.Header.Set("Accept", "application/json")
+		if o.auth != nil {
+			if err := o.auth(ctx, req); err != nil {
+				return nil, fmt.Errorf("failed to authenticate request: %w", err)
+			}
+		}
+
+		resp, err := o.client.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("%s %s failed: %w", op.Method, req.URL.Redacted(), err)
+		}
+		defer resp.Body.Close()
+
+		respBody, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read response: %w", err)
+		}
+
+		headers := make(map[string]string, len(resp.Header))
+		for name, values := range resp.Header {
+			headers[name] = strings.Join(values, ", ")
+		}
+		return &mcpwrapper.HTTPResult{
+			Status:  resp.StatusCode,
+			Headers: headers,
+			Body:    decodeBody(resp.Header.Get("Content-Type"), respBody),
+		}, nil
+	}
+}
+
+// format renders an argument decoded from JSON as a parameter value.
+func format(v interface{}) string {
+	switch value := v.(type) {
+	case string:
+		return value
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(value)
+	default:
+		encoded, _ := json.Marshal(value)
+		return string(encoded)
+	}
+}
+
+func decodeBody(contentType string, body []byte) interface{} {
+	if strings.Contains(contentType, "json") {
+		var decoded interface{}
+		if err := json.Unmarshal(body, &decoded); err == nil {
+			return decoded
+		}
+	}
+	return string(body)
+}
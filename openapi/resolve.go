@@ -0,0 +1,103 @@
+package openapi
+
+import (
+	"fmt"
+	"strings"
+)
+
+// resolver replaces local $refs with the objects they point to.
+type resolver struct {
+	root map[string]interface{}
+}
+
+func (r *resolver) lookup(ref string) (interface{}, error) {
+	if !strings.HasPrefix(ref, "#/") {
+		return nil, fmt.Errorf("unsupported reference %s: only local references are supported", ref)
+	}
+
+	var node interface{} = r.root
+	for _, part := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+		part = strings.ReplaceAll(strings.ReplaceAll(part, "~1", "/"), "~0", "~")
+		m, ok := node.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("reference %s not found", ref)
+		}
+		if node, ok = m[part]; !ok {
+			return nil, fmt.Errorf("reference %s not found", ref)
+		}
+	}
+	return node, nil
+}
+
+// resolveObject follows a $ref at the top of v only.
+func (r *resolver) resolveObject(v interface{}) (map[string]interface{}, error) {
+	m := object(v)
+	for seen := 0; m != nil; seen++ {
+		ref, ok := m["$ref"].(string)
+		if !ok {
+			return m, nil
+		}
+		if seen > 32 {
+			return nil, fmt.Errorf("reference %s is circular", ref)
+		}
+		target, err := r.lookup(ref)
+		if err != nil {
+			return nil, err
+		}
+		m = object(target)
+	}
+	return nil, fmt.Errorf("expected an object")
+}
+
+// schema returns a copy of a schema with every $ref inlined. A reference
+// back to a schema being inlined is replaced by an unconstrained object.
+func (r *resolver) schema(v interface{}) (map[string]interface{}, error) {
+	if v == nil {
+		return nil, nil
+	}
+	resolved, err := r.inline(v, map[string]bool{})
+	if err != nil {
+		return nil, err
+	}
+	return object(resolved), nil
+}
+
+func (r *resolver) inline(v interface{}, active map[string]bool) (interface{}, error) {
+	switch node := v.(type) {
+	case map[string]interface{}:
+		if ref, ok := node["$ref"].(string); ok {
+			if active[ref] {
+				return map[string]interface{}{"type": "object"}, nil
+			}
+			target, err := r.lookup(ref)
+			if err != nil {
+				return nil, err
+			}
+			active[ref] = true
+			defer delete(active, ref)
+			return r.inline(target, active)
+		}
+
+		out := make(map[string]interface{}, len(node))
+		for k, child := range node {
+			resolved, err := r.inline(child, active)
+			if err != nil {
+				return nil, err
+			}
+			out[k] = resolved
+		}
+		return out, nil
+	case []interface{}:
+		out := make([]interface{}, len(node))
+		for i, child := range node {
+			resolved, err := r.inline(child, active)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = resolved
+		}
+		return out, nil
+	default:
+		return v, nil
+	}
+}
@@ -0,0 +1,46 @@
+package mcpwrapper
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// WithOutputValidation checks every handler result before it is sent: a
+// struct result against its validate and jsonschema tags, and the encoded
+// result against the tool's WithOutputSchema. As with RegisterSchema, only
+// the required, type and enum keywords of top-level properties are
+// enforced; other keywords, such as minimum, pattern, items or
+// additionalProperties, are ignored. A tool producing malformed output gets
+// an internal error result instead, and the violation is logged, so
+// regressions surface where they happen rather than in the tool that
+// consumes the output.
+func WithOutputValidation() Option {
+	return func(w *Wrapper) {
+		w.validateOutput = true
+	}
+}
+
+// validateResult checks result, the value returned by the handler, and
+// resultMap, the object sent to the client (nil if the result is not an
+// object).
+func (w *Wrapper) validateResult(t *registeredTool, result interface{}, resultMap map[string]interface{}) error {
+	rv := reflect.ValueOf(result)
+	if rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if rv.Kind() == reflect.Struct {
+		if err := w.validate(result); err != nil {
+			return err
+		}
+	}
+
+	if t.options.outputSchema == nil {
+		return nil
+	}
+	if resultMap == nil {
+		return fmt.Errorf("result is not an object")
+	}
+	return validateSchemaArgs(mcp.ToolInputSchema(t.tool.OutputSchema), resultMap)
+}
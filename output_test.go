@@ -0,0 +1,105 @@
+package mcpwrapper
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+type QuoteResult struct {
+	Symbol string  `json:"symbol" validate:"required"`
+	Price  float64 `json:"price" validate:"gte=0"`
+}
+
+func TestOutputValidation(t *testing.T) {
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	var logs bytes.Buffer
+	wrapper := New(mcpServer, WithOutputValidation(), WithLogger(slog.New(slog.NewTextHandler(&logs, nil))))
+
+	var quote QuoteResult
+	wrapper.Register("quote", "Get a quote", TestArgs{}, func(ctx context.Context, args interface{}) (interface{}, error) {
+		return &quote, nil
+	})
+
+	var report interface{}
+	output := map[string]interface{}{
+		"type":       "object",
+		"properties": map[string]interface{}{"status": map[string]interface{}{"type": "string", "enum": []interface{}{"ok", "failed"}}},
+		"required":   []interface{}{"status"},
+	}
+	wrapper.Register("report", "Get a report", TestArgs{}, func(ctx context.Context, args interface{}) (interface{}, error) {
+		return report, nil
+	}, WithOutputSchema(output))
+
+	args := map[string]interface{}{"name": "Alice", "age": 30, "category": "A"}
+
+	quote = QuoteResult{Symbol: "ACME", Price: 12.5}
+	if result := callTool(t, mcpServer, "quote", args); result.IsError {
+		t.Errorf("Expected a valid result to pass, got %v", result.Content)
+	}
+
+	quote = QuoteResult{Price: -1}
+	result := callTool(t, mcpServer, "quote", args)
+	if !result.IsError || result.Content[0].(mcp.TextContent).Text != "internal error: tool produced invalid output" {
+		t.Errorf("Expected an internal error, got %v", result.Content)
+	}
+	if !strings.Contains(logs.String(), "tool produced invalid output") || !strings.Contains(logs.String(), "Symbol: is required") {
+		t.Errorf("Expected the violation to be logged, got %q", logs.String())
+	}
+
+	report = map[string]interface{}{"status": "ok"}
+	if result := callTool(t, mcpServer, "report", args); result.IsError || result.StructuredContent == nil {
+		t.Errorf("Expected a valid report to pass, got %v", result.Content)
+	}
+	for _, bad := range []interface{}{
+		map[string]interface{}{"status": "unknown"},
+		map[string]interface{}{},
+		"ok",
+	} {
+		report = bad
+		if result := callTool(t, mcpServer, "report", args); !result.IsError {
+			t.Errorf("Expected %v to fail the output schema", bad)
+		}
+	}
+}
+
+func TestOutputValidationIgnoredKeywords(t *testing.T) {
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	wrapper := New(mcpServer, WithOutputValidation())
+
+	output := map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"count": map[string]interface{}{"type": "number", "minimum": 0},
+			"code":  map[string]interface{}{"type": "string", "pattern": "^[A-Z]+$"},
+			"tags":  map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+		},
+		"additionalProperties": false,
+	}
+	wrapper.Register("report", "Get a report", TestArgs{}, func(ctx context.Context, args interface{}) (interface{}, error) {
+		return map[string]interface{}{"count": -1, "code": "abc", "tags": []interface{}{1, 2}, "extra": true}, nil
+	}, WithOutputSchema(output))
+
+	result := callTool(t, mcpServer, "report", map[string]interface{}{"name": "Alice", "age": 30, "category": "A"})
+	if result.IsError {
+		t.Errorf("Expected unsupported keywords to be ignored, got %v", result.Content)
+	}
+}
+
+func TestOutputValidationOff(t *testing.T) {
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	wrapper := New(mcpServer)
+	wrapper.Register("quote", "Get a quote", TestArgs{}, func(ctx context.Context, args interface{}) (interface{}, error) {
+		return &QuoteResult{Price: -1}, nil
+	})
+
+	result := callTool(t, mcpServer, "quote", map[string]interface{}{"name": "Alice", "age": 30, "category": "A"})
+	if result.IsError {
+		t.Errorf("Expected results not to be validated by default, got %v", result.Content)
+	}
+}
@@ -0,0 +1,221 @@
+package mcpwrapper
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	oversizedScheme = "result://"
+	// envelopeBytes is reserved for the JSON-RPC envelope around a result.
+	envelopeBytes = 128
+	// maxOversizedPerSession bounds the offloaded results kept per session.
+	maxOversizedPerSession = 32
+)
+
+type OversizeFallback int
+
+const (
+	// OversizeTruncate cuts the largest text item and marks the result
+	// "truncated" in its _meta.
+	OversizeTruncate OversizeFallback = iota
+	// OversizeResourceLink moves the largest text item to a result://<id>
+	// resource and returns a resource link to it instead.
+	OversizeResourceLink
+)
+
+type messageLimit struct {
+	limit    int
+	fallback OversizeFallback
+	store    *oversizedStore
+}
+
+// WithMaxMessageSize keeps every tool response below limit bytes once
+// serialized. Some stdio clients fail to parse very large lines, so an
+// oversized result is delivered through fallback instead.
+func WithMaxMessageSize(limit int, fallback OversizeFallback) Option {
+	return func(w *Wrapper) {
+		if limit <= 0 {
+			return
+		}
+		w.messageLimit = &messageLimit{limit: limit, fallback: fallback}
+		if fallback == OversizeResourceLink {
+			w.messageLimit.store = &oversizedStore{sessions: make(map[string]*sessionOversized)}
+			w.server.AddResourceTemplate(
+				mcp.NewResourceTemplate(oversizedScheme+"{id}", "Oversized tool result",
+					mcp.WithTemplateDescription("Tool results too large to return in a single message"),
+				),
+				w.readOversized,
+			)
+		}
+	}
+}
+
+// oversizedStore keeps texts too large for one message per session, for
+// offloaded results and streamed output. Each session keeps the latest
+// maxOversizedPerSession texts.
+type oversizedStore struct {
+	mu       sync.Mutex
+	sessions map[string]*sessionOversized
+}
+
+type sessionOversized struct {
+	next    int
+	order   []string
+	entries map[string]string
+}
+
+func (s *oversizedStore) put(sessionID, text string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	so, ok := s.sessions[sessionID]
+	if !ok {
+		so = &sessionOversized{entries: make(map[string]string)}
+		s.sessions[sessionID] = so
+	}
+
+	so.next++
+	id := fmt.Sprintf("%d", so.next)
+	so.entries[id] = text
+	so.order = append(so.order, id)
+	if len(so.order) > maxOversizedPerSession {
+		delete(so.entries, so.order[0])
+		so.order = so.order[1:]
+	}
+	return id
+}
+
+func (s *oversizedStore) get(sessionID, id string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	so, ok := s.sessions[sessionID]
+	if !ok {
+		return "", false
+	}
+	text, ok := so.entries[id]
+	return text, ok
+}
+
+func (s *oversizedStore) end(sessionID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.sessions, sessionID)
+}
+
+func (w *Wrapper) limitMessageSize(ctx context.Context, tool string, result *mcp.CallToolResult) {
+	if w.messageLimit == nil {
+		return
+	}
+	size := messageSize(result)
+	if size <= w.messageLimit.limit {
+		return
+	}
+
+	w.logger.Warn("tool result exceeds message size limit", "tool", tool, "bytes", size, "limit", w.messageLimit.limit)
+	result.StructuredContent = nil
+
+	if w.messageLimit.fallback == OversizeResourceLink {
+		if i := largestText(result); i >= 0 {
+			text := result.Content[i].(mcp.TextContent).Text
+			uri := oversizedScheme + w.messageLimit.store.put(SessionIDFromContext(ctx), text)
+			mimeType := "text/plain"
+			if json.Valid([]byte(text)) {
+				mimeType = "application/json"
+			}
+			result.Content[i] = mcp.NewTextContent(fmt.Sprintf(
+				"The result (%d bytes) is too large to return in one message; read it from %s.", len(text), uri))
+			result.Content = append(result.Content, mcp.NewResourceLink(uri, tool+" result", "Full result of the call", mimeType))
+		}
+		if messageSize(result) <= w.messageLimit.limit {
+			return
+		}
+	}
+
+	truncate(result, w.messageLimit.limit)
+}
+
+// truncate keeps the longest prefix of the largest text item that lets the
+// result fit. JSON escaping makes the encoded size differ from the text
+// length, so the prefix is found by binary search on the encoded size.
+func truncate(result *mcp.CallToolResult, limit int) {
+	if result.Meta == nil {
+		result.Meta = &mcp.Meta{}
+	}
+	if result.Meta.AdditionalFields == nil {
+		result.Meta.AdditionalFields = make(map[string]interface{})
+	}
+	result.Meta.AdditionalFields["truncated"] = true
+
+	i := largestText(result)
+	if i < 0 {
+		return
+	}
+	text := result.Content[i].(mcp.TextContent).Text
+	marker := fmt.Sprintf("\n...[truncated: result exceeded %d bytes]", limit)
+
+	cut := func(keep int) {
+		for keep > 0 && !utf8.RuneStart(text[keep]) {
+			keep--
+		}
+		result.Content[i] = mcp.NewTextContent(text[:keep] + marker)
+	}
+
+	lo, hi := 0, len(text)
+	for lo < hi {
+		mid := (lo + hi + 1) / 2
+		cut(mid)
+		if messageSize(result) <= limit {
+			lo = mid
+		} else {
+			hi = mid - 1
+		}
+	}
+	cut(lo)
+}
+
+func messageSize(result *mcp.CallToolResult) int {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return 0
+	}
+	return len(data) + envelopeBytes
+}
+
+func largestText(result *mcp.CallToolResult) int {
+	largest, size := -1, 0
+	for i, c := range result.Content {
+		if text, ok := c.(mcp.TextContent); ok && len(text.Text) > size {
+			largest, size = i, len(text.Text)
+		}
+	}
+	return largest
+}
+
+func (w *Wrapper) readOversized(ctx context.Context, request mcp.ReadResourceRequest) (contents []mcp.ResourceContents, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			panicErr := newPanicError(r)
+			w.logger.Error("oversized result read panicked", "uri", request.Params.URI, "panic", panicErr.Value, "stack", string(panicErr.Stack))
+			contents, err = nil, panicErr
+		}
+	}()
+
+	uri := request.Params.URI
+	text, ok := w.messageLimit.store.get(SessionIDFromContext(ctx), strings.TrimPrefix(uri, oversizedScheme))
+	if !ok {
+		return nil, fmt.Errorf("%s is not available in this session", uri)
+	}
+
+	mimeType := "text/plain"
+	if json.Valid([]byte(text)) {
+		mimeType = "application/json"
+	}
+	return []mcp.ResourceContents{mcp.TextResourceContents{URI: uri, MIMEType: mimeType, Text: text}}, nil
+}
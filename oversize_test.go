@@ -0,0 +1,97 @@
+package mcpwrapper
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func oversizedCall(t *testing.T, opts ...Option) (*server.MCPServer, context.Context, *mcp.CallToolResult) {
+	t.Helper()
+
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	wrapper := New(mcpServer, opts...)
+
+	handler := func(ctx context.Context, args interface{}) (interface{}, error) {
+		return &TestResult{Message: strings.Repeat("é\"", 2000)}, nil
+	}
+
+	if err := wrapper.Register("dump", "Dump tool", TestArgs{}, handler); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	ctx := mcpServer.WithContext(context.Background(), &testSession{id: "session-1"})
+	request := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name:      "dump",
+			Arguments: map[string]interface{}{"name": "Alice", "age": 30, "category": "A"},
+		},
+	}
+	result, err := mcpServer.GetTool("dump").Handler(ctx, request)
+	if err != nil || result.IsError {
+		t.Fatalf("Handler invocation failed: %v %v", err, result)
+	}
+	return mcpServer, ctx, result
+}
+
+func TestMaxMessageSizeTruncate(t *testing.T) {
+	_, _, result := oversizedCall(t, WithMaxMessageSize(1000, OversizeTruncate))
+
+	if size := messageSize(result); size > 1000 {
+		t.Errorf("Expected result within 1000 bytes, got %d", size)
+	}
+	text := result.Content[0].(mcp.TextContent).Text
+	if !strings.HasSuffix(text, "[truncated: result exceeded 1000 bytes]") {
+		t.Errorf("Expected truncation marker, got %q", text)
+	}
+	if !strings.HasPrefix(text, `{"message":"é\"`) {
+		t.Errorf("Expected the head of the result to be kept, got %q", text[:20])
+	}
+	if result.Meta.AdditionalFields["truncated"] != true {
+		t.Error("Expected truncated flag in _meta")
+	}
+}
+
+func TestMaxMessageSizeResourceLink(t *testing.T) {
+	mcpServer, ctx, result := oversizedCall(t, WithMaxMessageSize(1000, OversizeResourceLink))
+
+	if size := messageSize(result); size > 1000 {
+		t.Errorf("Expected result within 1000 bytes, got %d", size)
+	}
+	if len(result.Content) != 2 {
+		t.Fatalf("Expected notice and resource link, got %d items", len(result.Content))
+	}
+	link, ok := result.Content[1].(mcp.ResourceLink)
+	if !ok || link.URI != "result://1" || link.MIMEType != "application/json" {
+		t.Fatalf("Unexpected resource link: %#v", result.Content[1])
+	}
+
+	response := mcpServer.HandleMessage(ctx, json.RawMessage(fmt.Sprintf(
+		`{"jsonrpc": "2.0", "id": 1, "method": "resources/read", "params": {"uri": %q}}`, link.URI)))
+	contents := response.(mcp.JSONRPCResponse).Result.(mcp.ReadResourceResult).Contents
+	var full TestResult
+	if err := json.Unmarshal([]byte(contents[0].(mcp.TextResourceContents).Text), &full); err != nil {
+		t.Fatalf("Expected full JSON result in resource: %v", err)
+	}
+	if full.Message != strings.Repeat("é\"", 2000) {
+		t.Error("Expected the offloaded result to be complete")
+	}
+
+	other := mcpServer.WithContext(context.Background(), &testSession{id: "session-2"})
+	response = mcpServer.HandleMessage(other, json.RawMessage(`{"jsonrpc": "2.0", "id": 2, "method": "resources/read", "params": {"uri": "result://1"}}`))
+	if _, ok := response.(mcp.JSONRPCError); !ok {
+		t.Error("Expected offloaded results to be scoped to the session")
+	}
+}
+
+func TestMaxMessageSizeUnderLimit(t *testing.T) {
+	_, _, result := oversizedCall(t, WithMaxMessageSize(1<<20, OversizeTruncate))
+	if result.Meta != nil {
+		t.Errorf("Expected small results to be left alone, got meta %v", result.Meta)
+	}
+}
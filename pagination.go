@@ -0,0 +1,138 @@
+package mcpwrapper
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// Paginated is the result of a list tool: one page of items, the cursor of
+// the next page (empty on the last page) and, when known, the total number
+// of items. Using it for every list tool gives a server one pagination
+// convention; WithPaginatedOutput describes it in the tool's output
+// schema.
+type Paginated[T any] struct {
+	Items      []T    `json:"items"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	Total      int    `json:"total,omitempty"`
+}
+
+// PageArgs are the arguments of a list tool. Embed them in the tool's
+// argsType:
+//
+//	type ListUsersArgs struct {
+//		mcpwrapper.PageArgs
+//		Team string `json:"team"`
+//	}
+type PageArgs struct {
+	Cursor string `json:"cursor,omitempty" jsonschema:"description=Cursor from next_cursor of the previous page; omit for the first page"`
+	Limit  int    `json:"limit,omitempty" jsonschema:"description=Maximum number of items to return,minimum=1"`
+}
+
+// EncodeCursor encodes any JSON-serializable position, such as an offset
+// or the last key seen, as an opaque URL-safe cursor.
+func EncodeCursor(position interface{}) (string, error) {
+	data, err := json.Marshal(position)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// DecodeCursor decodes a cursor made by EncodeCursor into position. A
+// malformed cursor is an InvalidInput error.
+func DecodeCursor(cursor string, position interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return InvalidInput("invalid cursor")
+	}
+	if err := json.Unmarshal(data, position); err != nil {
+		return InvalidInput("invalid cursor")
+	}
+	return nil
+}
+
+type offsetCursor struct {
+	Offset int `json:"o"`
+}
+
+// Paginate returns the page of items selected by args, for tools that hold
+// the whole list in memory. defaultLimit applies when args.Limit is not
+// set; a limit above maxLimit is lowered to it (0 means no maximum).
+func Paginate[T any](items []T, args PageArgs, defaultLimit, maxLimit int) (*Paginated[T], error) {
+	var position offsetCursor
+	if args.Cursor != "" {
+		if err := DecodeCursor(args.Cursor, &position); err != nil {
+			return nil, err
+		}
+		if position.Offset < 0 || position.Offset > len(items) {
+			return nil, InvalidInput("cursor is out of range")
+		}
+	}
+
+	limit := args.Limit
+	if limit <= 0 {
+		limit = defaultLimit
+	}
+	if maxLimit > 0 && limit > maxLimit {
+		limit = maxLimit
+	}
+	if limit <= 0 {
+		limit = len(items)
+	}
+
+	end := position.Offset + limit
+	if end > len(items) {
+		end = len(items)
+	}
+	page := &Paginated[T]{Items: items[position.Offset:end], Total: len(items)}
+	if page.Items == nil {
+		page.Items = []T{}
+	}
+	if end < len(items) {
+		next, err := EncodeCursor(offsetCursor{Offset: end})
+		if err != nil {
+			return nil, err
+		}
+		page.NextCursor = next
+	}
+	return page, nil
+}
+
+// WithPaginatedOutput declares that the tool returns a Paginated result of
+// item's type, setting its output schema to PaginatedSchema(item).
+func WithPaginatedOutput(item interface{}) ToolOption {
+	return func(o *toolOptions) {
+		o.paginatedItem = item
+	}
+}
+
+// PaginatedSchema returns the output schema of a Paginated result whose
+// items have item's type. Item schemas are built like input schemas, so
+// struct tags apply.
+func PaginatedSchema(item interface{}) (map[string]interface{}, error) {
+	t := reflect.TypeOf(item)
+	if t == nil {
+		return nil, fmt.Errorf("item must not be nil")
+	}
+	b := &schemaBuilder{names: make(map[reflect.Type]string)}
+	itemSchema, err := b.typeSchema(t)
+	if err != nil {
+		return nil, err
+	}
+
+	schema := map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"items":       map[string]interface{}{"type": "array", "items": itemSchema},
+			"next_cursor": map[string]interface{}{"type": "string", "description": "Cursor of the next page; absent on the last page"},
+			"total":       map[string]interface{}{"type": "integer", "description": "Total number of items, when known"},
+		},
+		"required": []interface{}{"items"},
+	}
+	if b.defs != nil {
+		schema["$defs"] = b.defs
+	}
+	return schema, nil
+}
@@ -0,0 +1,105 @@
+package mcpwrapper
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+type Member struct {
+	Name string `json:"name" jsonschema:"required"`
+	Role string `json:"role" jsonschema:"enum=admin,enum=member"`
+}
+
+type ListMembersArgs struct {
+	PageArgs
+	Team string `json:"team" validate:"required"`
+}
+
+func TestPaginatedTool(t *testing.T) {
+	members := []Member{{"ann", "admin"}, {"bob", "member"}, {"cid", "member"}, {"dan", "member"}, {"eve", "admin"}}
+
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	wrapper := New(mcpServer, WithOutputValidation())
+	err := wrapper.Register("list_members", "List team members", ListMembersArgs{}, func(ctx context.Context, args interface{}) (interface{}, error) {
+		return Paginate(members, args.(*ListMembersArgs).PageArgs, 2, 3)
+	}, WithPaginatedOutput(Member{}))
+	if err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	tool := mcpServer.GetTool("list_members").Tool
+	if _, ok := tool.InputSchema.Properties["cursor"]; !ok || !reflect.DeepEqual(tool.InputSchema.Required, []string{"team"}) {
+		t.Errorf("Expected embedded PageArgs to be promoted, got %+v", tool.InputSchema)
+	}
+	items := tool.OutputSchema.Properties["items"].(map[string]interface{})["items"].(map[string]interface{})
+	if items["$ref"] != "#/$defs/Member" || tool.OutputSchema.Defs["Member"] == nil {
+		t.Errorf("Unexpected output schema: %+v", tool.OutputSchema)
+	}
+
+	page := func(args map[string]interface{}) Paginated[Member] {
+		t.Helper()
+		result := callTool(t, mcpServer, "list_members", args)
+		if result.IsError {
+			t.Fatalf("Call failed: %v", result.Content)
+		}
+		var p Paginated[Member]
+		json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &p)
+		return p
+	}
+
+	var names []string
+	args := map[string]interface{}{"team": "core"}
+	for {
+		p := page(args)
+		if p.Total != 5 || len(p.Items) > 2 {
+			t.Fatalf("Unexpected page: %+v", p)
+		}
+		for _, m := range p.Items {
+			names = append(names, m.Name)
+		}
+		if p.NextCursor == "" {
+			break
+		}
+		args = map[string]interface{}{"team": "core", "cursor": p.NextCursor}
+	}
+	if !reflect.DeepEqual(names, []string{"ann", "bob", "cid", "dan", "eve"}) {
+		t.Errorf("Unexpected items across pages: %v", names)
+	}
+
+	if p := page(map[string]interface{}{"team": "core", "limit": 10}); len(p.Items) != 3 {
+		t.Errorf("Expected the limit to be capped at 3, got %d items", len(p.Items))
+	}
+
+	result := callTool(t, mcpServer, "list_members", map[string]interface{}{"team": "core", "cursor": "not a cursor"})
+	if !result.IsError || result.StructuredContent.(map[string]interface{})["code"] != CodeInvalidInput {
+		t.Errorf("Expected an invalid_input error, got %+v", result)
+	}
+}
+
+func TestCursorCodec(t *testing.T) {
+	type keyset struct {
+		After string `json:"after"`
+		ID    int    `json:"id"`
+	}
+	cursor, err := EncodeCursor(keyset{After: "2024-01-01", ID: 7})
+	if err != nil {
+		t.Fatalf("EncodeCursor failed: %v", err)
+	}
+	var decoded keyset
+	if err := DecodeCursor(cursor, &decoded); err != nil || decoded.ID != 7 || decoded.After != "2024-01-01" {
+		t.Errorf("Unexpected decoded cursor %+v, %v", decoded, err)
+	}
+	if code, _ := CodeFromError(DecodeCursor("%%%", &decoded)); code != CodeInvalidInput {
+		t.Errorf("Expected an invalid_input error for a malformed cursor")
+	}
+
+	empty, err := Paginate([]int{}, PageArgs{}, 10, 0)
+	if err != nil || empty.Items == nil || empty.NextCursor != "" {
+		t.Errorf("Unexpected empty page %+v, %v", empty, err)
+	}
+}
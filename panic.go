@@ -0,0 +1,23 @@
+package mcpwrapper
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// PanicError is reported when a handler panics. No panic crosses the wrapper
+// boundary: every handler the wrapper installs on the MCP server recovers and
+// converts the panic into an error result, so one malformed request cannot
+// take down a stdio server.
+type PanicError struct {
+	Value interface{}
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("internal error: %v", e.Value)
+}
+
+func newPanicError(value interface{}) *PanicError {
+	return &PanicError{Value: value, Stack: debug.Stack()}
+}
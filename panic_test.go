@@ -0,0 +1,111 @@
+package mcpwrapper
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func TestHandlerPanicRecovered(t *testing.T) {
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	wrapper := New(mcpServer)
+
+	handler := func(ctx context.Context, args interface{}) (interface{}, error) {
+		var m map[string]int
+		m["boom"] = 1
+		return nil, nil
+	}
+
+	if err := wrapper.Register("test-tool", "Test tool", TestArgs{}, handler); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	request := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name:      "test-tool",
+			Arguments: map[string]interface{}{"name": "Alice", "age": 30, "category": "A"},
+		},
+	}
+
+	result, err := mcpServer.GetTool("test-tool").Handler(context.Background(), request)
+	if err != nil {
+		t.Fatalf("Expected panic to be converted into a result, got error: %v", err)
+	}
+
+	if result == nil || !result.IsError {
+		t.Fatal("Expected error result for panicking handler")
+	}
+}
+
+func TestRegisterInvalidInput(t *testing.T) {
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	wrapper := New(mcpServer)
+
+	handler := func(ctx context.Context, args interface{}) (interface{}, error) {
+		return nil, nil
+	}
+
+	if err := wrapper.Register("nil-args", "Nil args", nil, handler); err == nil {
+		t.Error("Expected error for nil argsType")
+	}
+
+	if err := wrapper.Register("nil-handler", "Nil handler", TestArgs{}, nil); err == nil {
+		t.Error("Expected error for nil handler")
+	}
+}
+
+// FuzzToolHandler feeds arbitrary JSON as tool arguments and checks that the
+// wrapper always answers with a result and never hits a recovered panic.
+func FuzzToolHandler(f *testing.F) {
+	for _, seed := range []string{
+		`{"name":"Alice","age":30,"category":"A"}`,
+		`{}`,
+		`null`,
+		`[]`,
+		`"input"`,
+		`{"name":1,"age":"thirty","category":["A"]}`,
+		`{"name":null,"age":1e400}`,
+		`{"name":{"nested":{"deep":[1,2,{"x":null}]}}}`,
+		`{"age":-9223372036854775809}`,
+	} {
+		f.Add(seed)
+	}
+
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	wrapper := New(mcpServer)
+
+	handler := func(ctx context.Context, args interface{}) (interface{}, error) {
+		return args, nil
+	}
+
+	if err := wrapper.Register("test-tool", "Test tool", TestArgs{}, handler); err != nil {
+		f.Fatalf("Register failed: %v", err)
+	}
+	tool := mcpServer.GetTool("test-tool")
+
+	f.Fuzz(func(t *testing.T, input string) {
+		var args interface{}
+		if err := json.Unmarshal([]byte(input), &args); err != nil {
+			args = input
+		}
+
+		request := mcp.CallToolRequest{
+			Params: mcp.CallToolParams{Name: "test-tool", Arguments: args},
+		}
+
+		result, err := tool.Handler(context.Background(), request)
+		if err != nil {
+			t.Fatalf("Expected errors to be reported in the result, got: %v", err)
+		}
+		if result == nil {
+			t.Fatal("Expected non-nil result")
+		}
+		if text, ok := result.Content[0].(mcp.TextContent); ok && strings.HasPrefix(text.Text, "internal error") {
+			t.Fatalf("Wrapper panicked on input %q: %s", input, text.Text)
+		}
+	})
+}
@@ -0,0 +1,428 @@
+package mcpwrapper
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	// maxProcessLog bounds the output kept per job; older output is
+	// dropped.
+	maxProcessLog = 1 << 20
+	// maxFinishedJobs bounds the finished jobs kept for status and logs.
+	maxFinishedJobs = 100
+	// defaultLogLimit is the output returned by one logs call by default.
+	defaultLogLimit = 64 << 10
+	// defaultStopGrace is how long stop waits after SIGTERM before killing.
+	defaultStopGrace = 5 * time.Second
+)
+
+// Process states reported by ProcessStatus.
+const (
+	ProcessRunning = "running"
+	ProcessExited  = "exited"
+	ProcessStopped = "stopped"
+)
+
+// ProcessStatus describes a job of a long-running command.
+type ProcessStatus struct {
+	JobID      string     `json:"job_id"`
+	State      string     `json:"state"`
+	PID        int        `json:"pid"`
+	ExitCode   *int       `json:"exit_code,omitempty"`
+	StartedAt  time.Time  `json:"started_at"`
+	EndedAt    *time.Time `json:"ended_at,omitempty"`
+	DurationMs int64      `json:"duration_ms"`
+}
+
+// ProcessLogs is a chunk of a job's combined stdout and stderr. Offsets
+// count bytes from the start of the output; pass NextOffset as the next
+// call's offset to follow it.
+type ProcessLogs struct {
+	JobID      string `json:"job_id"`
+	State      string `json:"state"`
+	Output     string `json:"output"`
+	Offset     int64  `json:"offset"`
+	NextOffset int64  `json:"next_offset"`
+	// Dropped is set when output before Offset was discarded to bound
+	// the memory a job uses.
+	Dropped bool `json:"dropped,omitempty"`
+}
+
+type processJobArgs struct {
+	JobID string `json:"job_id" jsonschema:"required,description=Job ID returned by the start tool" validate:"required"`
+}
+
+type processLogsArgs struct {
+	JobID  string `json:"job_id" jsonschema:"required,description=Job ID returned by the start tool" validate:"required"`
+	Offset *int64 `json:"offset,omitempty" jsonschema:"description=Byte offset to read from; the latest output if omitted" validate:"omitempty,gte=0"`
+	Limit  int    `json:"limit,omitempty" jsonschema:"description=Maximum bytes to return (default 65536)" validate:"gte=0"`
+}
+
+type processStopArgs struct {
+	JobID   string `json:"job_id" jsonschema:"required,description=Job ID returned by the start tool" validate:"required"`
+	GraceMs int    `json:"grace_ms,omitempty" jsonschema:"description=Milliseconds to wait after SIGTERM before killing (default 5000)" validate:"gte=0"`
+}
+
+// processManager keeps the jobs of long-running commands.
+type processManager struct {
+	mu   sync.Mutex
+	jobs map[string]*processJob
+}
+
+func newProcessManager() *processManager {
+	return &processManager{jobs: make(map[string]*processJob)}
+}
+
+type processJob struct {
+	id      string
+	tool    string
+	cmd     *exec.Cmd
+	cancel  context.CancelFunc
+	started time.Time
+	output  *logBuffer
+	done    chan struct{}
+
+	mu       sync.Mutex
+	ended    time.Time
+	exitCode int
+	stopped  bool
+}
+
+// registerProcess registers the companion tools of a long-running command.
+// start renders the command like RegisterExec and returns at once.
+func (w *Wrapper) registerProcess(name, description string, argsType interface{}, schema *mcp.ToolInputSchema, t *execTemplates, opts []ToolOption) error {
+	if len(t.spec.Prompts) > 0 {
+		return fmt.Errorf("invalid exec spec for tool %s: prompts are not supported for long-running commands", name)
+	}
+	start := "Start in the background: " + strings.TrimSuffix(description, ".") + ". Returns a job_id for the other " + name + " tools."
+	if err := w.register(name+"_start", start, argsType, schema, w.processStart(name, t), opts); err != nil {
+		return err
+	}
+
+	tools := []struct {
+		suffix      string
+		description string
+		argsType    interface{}
+		handler     Handler
+	}{
+		{"_status", "Report whether a " + name + " job is running and its exit code.", processJobArgs{}, w.processStatus(name)},
+		{"_logs", "Read the output of a " + name + " job.", processLogsArgs{}, w.processLogs(name)},
+		{"_stop", "Stop a " + name + " job with SIGTERM, then SIGKILL after grace_ms.", processStopArgs{}, w.processStop(name)},
+	}
+	for _, tool := range tools {
+		if err := w.Register(name+tool.suffix, tool.description, tool.argsType, tool.handler, opts...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *Wrapper) processStart(name string, t *execTemplates) Handler {
+	return func(ctx context.Context, a interface{}) (interface{}, error) {
+		// The job outlives the call, so it keeps only the call's values.
+		jobCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		if t.spec.Timeout > 0 {
+			var cancelTimeout context.CancelFunc
+			jobCtx, cancelTimeout = context.WithTimeout(jobCtx, t.spec.Timeout)
+			cancelJob := cancel
+			cancel = func() {
+				cancelTimeout()
+				cancelJob()
+			}
+		}
+		cmd, _, err := t.command(jobCtx, a)
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+		job := &processJob{
+			tool:   name,
+			cmd:    cmd,
+			cancel: cancel,
+			output: newLogBuffer(maxProcessLog),
+			done:   make(chan struct{}),
+		}
+		cmd.Stdout = job.output
+		cmd.Stderr = job.output
+		// Don't wait for children that inherited the output after a kill.
+		cmd.WaitDelay = time.Second
+
+		if err := cmd.Start(); err != nil {
+			cancel()
+			return nil, fmt.Errorf("failed to start %s: %w", t.spec.Command, err)
+		}
+		job.started = time.Now()
+		w.processes.add(job)
+		go job.wait()
+		return job.status(), nil
+	}
+}
+
+func (w *Wrapper) processStatus(name string) Handler {
+	return func(ctx context.Context, args interface{}) (interface{}, error) {
+		job, err := w.processes.get(name, args.(*processJobArgs).JobID)
+		if err != nil {
+			return nil, err
+		}
+		return job.status(), nil
+	}
+}
+
+func (w *Wrapper) processLogs(name string) Handler {
+	return func(ctx context.Context, args interface{}) (interface{}, error) {
+		a := args.(*processLogsArgs)
+		job, err := w.processes.get(name, a.JobID)
+		if err != nil {
+			return nil, err
+		}
+		limit := a.Limit
+		if limit == 0 {
+			limit = defaultLogLimit
+		}
+		logs := job.output.read(a.Offset, limit)
+		logs.JobID = job.id
+		logs.State = job.status().State
+		return logs, nil
+	}
+}
+
+func (w *Wrapper) processStop(name string) Handler {
+	return func(ctx context.Context, args interface{}) (interface{}, error) {
+		a := args.(*processStopArgs)
+		job, err := w.processes.get(name, a.JobID)
+		if err != nil {
+			return nil, err
+		}
+		grace := defaultStopGrace
+		if a.GraceMs > 0 {
+			grace = time.Duration(a.GraceMs) * time.Millisecond
+		}
+		job.stop(ctx, grace)
+		return job.status(), nil
+	}
+}
+
+func (m *processManager) add(job *processJob) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	job.id = newJobID()
+	m.jobs[job.id] = job
+	m.prune()
+}
+
+// prune drops the oldest finished jobs beyond maxFinishedJobs.
+func (m *processManager) prune() {
+	var finished []*processJob
+	for _, job := range m.jobs {
+		if !job.running() {
+			finished = append(finished, job)
+		}
+	}
+	if len(finished) <= maxFinishedJobs {
+		return
+	}
+	sort.Slice(finished, func(i, j int) bool { return finished[i].started.Before(finished[j].started) })
+	for _, job := range finished[:len(finished)-maxFinishedJobs] {
+		delete(m.jobs, job.id)
+	}
+}
+
+func (m *processManager) get(tool, id string) (*processJob, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	job, ok := m.jobs[id]
+	if !ok || job.tool != tool {
+		return nil, NotFound("no %s job %q", tool, id)
+	}
+	return job, nil
+}
+
+// stopAll stops every running job, killing those still running when ctx
+// is done.
+func (m *processManager) stopAll(ctx context.Context) {
+	m.mu.Lock()
+	jobs := make([]*processJob, 0, len(m.jobs))
+	for _, job := range m.jobs {
+		jobs = append(jobs, job)
+	}
+	m.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, job := range jobs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			job.stop(ctx, defaultStopGrace)
+		}()
+	}
+	wg.Wait()
+}
+
+func newJobID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func (j *processJob) wait() {
+	j.cmd.Wait()
+	j.mu.Lock()
+	j.ended = time.Now()
+	j.exitCode = j.cmd.ProcessState.ExitCode()
+	j.mu.Unlock()
+	j.cancel()
+	close(j.done)
+}
+
+func (j *processJob) running() bool {
+	select {
+	case <-j.done:
+		return false
+	default:
+		return true
+	}
+}
+
+// stop sends SIGTERM and kills the job if it is still running after grace
+// or when ctx is done.
+func (j *processJob) stop(ctx context.Context, grace time.Duration) {
+	if !j.running() {
+		return
+	}
+	j.mu.Lock()
+	j.stopped = true
+	j.mu.Unlock()
+
+	if err := j.cmd.Process.Signal(syscall.SIGTERM); err != nil && !errors.Is(err, os.ErrProcessDone) {
+		// Signals other than kill are not supported everywhere.
+		j.cancel()
+	}
+	timer := time.NewTimer(grace)
+	defer timer.Stop()
+	select {
+	case <-j.done:
+		return
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+	j.cancel()
+	<-j.done
+}
+
+func (j *processJob) status() *ProcessStatus {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	status := &ProcessStatus{
+		JobID:     j.id,
+		State:     ProcessRunning,
+		PID:       j.cmd.Process.Pid,
+		StartedAt: j.started,
+	}
+	end := time.Now()
+	if !j.ended.IsZero() {
+		end = j.ended
+		status.EndedAt = &end
+		status.ExitCode = &j.exitCode
+		status.State = ProcessExited
+		if j.stopped {
+			status.State = ProcessStopped
+		}
+	}
+	status.DurationMs = end.Sub(j.started).Milliseconds()
+	return status
+}
+
+// logBuffer keeps the last size bytes written to it in a ring, so that a
+// chatty job does not copy its log on every write. The byte at offset n of
+// the output is at buf[n%size]; buf grows to size before it wraps.
+type logBuffer struct {
+	mu      sync.Mutex
+	size    int
+	buf     []byte
+	written int64 // bytes written in all
+}
+
+func newLogBuffer(size int) *logBuffer {
+	return &logBuffer{size: size}
+}
+
+func (b *logBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	n := len(p)
+	if len(p) > b.size {
+		b.written += int64(len(p) - b.size)
+		p = p[len(p)-b.size:]
+	}
+	if int64(len(b.buf)) == b.written && len(b.buf)+len(p) <= b.size {
+		b.buf = append(b.buf, p...)
+		b.written += int64(len(p))
+		return n, nil
+	}
+	if len(b.buf) < b.size {
+		full := make([]byte, b.size)
+		copy(full, b.buf)
+		b.buf = full
+	}
+	for len(p) > 0 {
+		copied := copy(b.buf[b.written%int64(b.size):], p)
+		p = p[copied:]
+		b.written += int64(copied)
+	}
+	return n, nil
+}
+
+// read returns up to limit bytes from offset, or the last limit bytes if
+// offset is nil.
+func (b *logBuffer) read(offset *int64, limit int) *ProcessLogs {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	end := b.written
+	dropped := end - int64(b.size)
+	if dropped < 0 {
+		dropped = 0
+	}
+	var start int64
+	if offset != nil {
+		start = *offset
+	} else {
+		start = end - int64(limit)
+	}
+	logs := &ProcessLogs{}
+	if start < dropped {
+		logs.Dropped = offset != nil && *offset < dropped
+		start = dropped
+	}
+	if start > end {
+		start = end
+	}
+	stop := start + int64(limit)
+	if stop > end {
+		stop = end
+	}
+	logs.Offset = start
+	logs.NextOffset = stop
+
+	out := make([]byte, 0, stop-start)
+	for pos := start; pos < stop; {
+		i := pos % int64(b.size)
+		chunk := b.buf[i:min(int64(len(b.buf)), i+stop-pos)]
+		out = append(out, chunk...)
+		pos += int64(len(chunk))
+	}
+	logs.Output = string(out)
+	return logs
+}
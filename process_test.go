@@ -0,0 +1,185 @@
+package mcpwrapper
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+type WatchArgs struct {
+	Name string `json:"name" jsonschema:"required" validate:"required"`
+}
+
+func decodeResult(t *testing.T, result *mcp.CallToolResult, v interface{}) {
+	t.Helper()
+	if result.IsError {
+		t.Fatalf("Unexpected error: %v", result.Content)
+	}
+	if err := json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), v); err != nil {
+		t.Fatalf("Failed to decode result: %v", err)
+	}
+}
+
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("Timed out waiting for %s", what)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestLongRunningExec(t *testing.T) {
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	wrapper := New(mcpServer)
+	err := wrapper.RegisterExec("watch", "Watch files.", WatchArgs{}, ExecSpec{
+		Command:     "sh",
+		Args:        []string{"-c", `trap 'echo bye; exit 0' TERM; echo "watching $1"; while true; do sleep 0.01; done`, "sh", "{{.name}}"},
+		LongRunning: true,
+	})
+	if err != nil {
+		t.Fatalf("RegisterExec failed: %v", err)
+	}
+	for _, name := range []string{"watch_start", "watch_status", "watch_logs", "watch_stop"} {
+		if mcpServer.GetTool(name) == nil {
+			t.Fatalf("Expected tool %s", name)
+		}
+	}
+	if mcpServer.GetTool("watch") != nil {
+		t.Error("Expected no blocking tool for a long-running command")
+	}
+	if desc := mcpServer.GetTool("watch_start").Tool.Description; !strings.HasPrefix(desc, "Start in the background: Watch files. Returns a job_id") {
+		t.Errorf("Unexpected start description %q", desc)
+	}
+
+	var started ProcessStatus
+	decodeResult(t, callTool(t, mcpServer, "watch_start", map[string]interface{}{"name": "src"}), &started)
+	if started.JobID == "" || started.State != ProcessRunning || started.PID == 0 {
+		t.Fatalf("Unexpected start result: %+v", started)
+	}
+	job := map[string]interface{}{"job_id": started.JobID}
+
+	var logs ProcessLogs
+	waitFor(t, "output", func() bool {
+		decodeResult(t, callTool(t, mcpServer, "watch_logs", job), &logs)
+		return logs.Output == "watching src\n"
+	})
+	if logs.State != ProcessRunning || logs.NextOffset != int64(len("watching src\n")) {
+		t.Errorf("Unexpected logs: %+v", logs)
+	}
+
+	var stopped ProcessStatus
+	decodeResult(t, callTool(t, mcpServer, "watch_stop", job), &stopped)
+	if stopped.State != ProcessStopped || stopped.ExitCode == nil || *stopped.ExitCode != 0 || stopped.EndedAt == nil {
+		t.Errorf("Expected the job to stop gracefully, got %+v", stopped)
+	}
+
+	decodeResult(t, callTool(t, mcpServer, "watch_logs", map[string]interface{}{"job_id": started.JobID, "offset": logs.NextOffset}), &logs)
+	if logs.Output != "bye\n" || logs.State != ProcessStopped {
+		t.Errorf("Expected to follow the output from the offset, got %+v", logs)
+	}
+
+	result := callTool(t, mcpServer, "watch_status", map[string]interface{}{"job_id": "nope"})
+	if !result.IsError || !strings.Contains(result.Content[0].(mcp.TextContent).Text, "not_found") {
+		t.Errorf("Expected an unknown job to be not found, got %v", result.Content)
+	}
+}
+
+func TestLongRunningExecExits(t *testing.T) {
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	wrapper := New(mcpServer)
+	err := wrapper.RegisterExec("build", "Build", WatchArgs{}, ExecSpec{
+		Command:     "sh",
+		Args:        []string{"-c", "echo done; exit 3"},
+		LongRunning: true,
+	})
+	if err != nil {
+		t.Fatalf("RegisterExec failed: %v", err)
+	}
+
+	var status ProcessStatus
+	decodeResult(t, callTool(t, mcpServer, "build_start", map[string]interface{}{"name": "x"}), &status)
+	job := map[string]interface{}{"job_id": status.JobID}
+	waitFor(t, "exit", func() bool {
+		decodeResult(t, callTool(t, mcpServer, "build_status", job), &status)
+		return status.State != ProcessRunning
+	})
+	if status.State != ProcessExited || *status.ExitCode != 3 {
+		t.Errorf("Expected the exit code, got %+v", status)
+	}
+}
+
+func TestShutdownStopsJobs(t *testing.T) {
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	wrapper := New(mcpServer)
+	err := wrapper.RegisterExec("serve", "Serve", WatchArgs{}, ExecSpec{
+		Command:     "sleep",
+		Args:        []string{"30"},
+		LongRunning: true,
+	})
+	if err != nil {
+		t.Fatalf("RegisterExec failed: %v", err)
+	}
+
+	var status ProcessStatus
+	decodeResult(t, callTool(t, mcpServer, "serve_start", map[string]interface{}{"name": "x"}), &status)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	wrapper.Shutdown(ctx)
+
+	job, err := wrapper.processes.get("serve", status.JobID)
+	if err != nil {
+		t.Fatalf("Expected the job to be kept: %v", err)
+	}
+	if job.running() || job.status().State != ProcessStopped {
+		t.Errorf("Expected shutdown to stop the job, got %+v", job.status())
+	}
+}
+
+func TestLongRunningExecRejectsPrompts(t *testing.T) {
+	wrapper := New(server.NewMCPServer("test", "1.0.0"))
+	err := wrapper.RegisterExec("x", "X", WatchArgs{}, ExecSpec{
+		Command:     "true",
+		LongRunning: true,
+		Prompts:     []Prompt{{Pattern: "y/n"}},
+	})
+	if err == nil {
+		t.Error("Expected an error for prompts on a long-running command")
+	}
+}
+
+func TestLogBuffer(t *testing.T) {
+	b := newLogBuffer(8)
+	b.Write([]byte("abc"))
+	if logs := b.read(nil, 100); logs.Output != "abc" || logs.Offset != 0 || logs.NextOffset != 3 {
+		t.Fatalf("Unexpected logs before wrapping: %+v", logs)
+	}
+	b.Write([]byte("defgh"))
+	b.Write([]byte("ij"))
+	if logs := b.read(nil, 100); logs.Output != "cdefghij" || logs.Offset != 2 || logs.Dropped {
+		t.Errorf("Expected the last 8 bytes, got %+v", logs)
+	}
+	offset := int64(0)
+	if logs := b.read(&offset, 3); logs.Output != "cde" || !logs.Dropped || logs.NextOffset != 5 {
+		t.Errorf("Expected a read from a dropped offset to resume at the oldest byte, got %+v", logs)
+	}
+	offset = 6
+	if logs := b.read(&offset, 100); logs.Output != "ghij" {
+		t.Errorf("Expected a read across the wrap, got %+v", logs)
+	}
+
+	b.Write([]byte("0123456789klmnop"))
+	if logs := b.read(nil, 4); logs.Output != "mnop" || logs.NextOffset != 26 {
+		t.Errorf("Expected a long write to keep its tail, got %+v", logs)
+	}
+	if logs := b.read(nil, 100); logs.Output != "89klmnop" {
+		t.Errorf("Expected the last 8 bytes, got %+v", logs)
+	}
+}
@@ -0,0 +1,183 @@
+package mcpwrapper
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"sync"
+	"text/template"
+)
+
+// Prompt answers an interactive prompt of a wrapped command, such as a
+// confirmation or a password question, which would otherwise wait for
+// input that never comes. Pattern is a regular expression matched against
+// the command's output since the last answer. Answer is a text/template
+// rendered with the call's arguments, like ExecSpec.Args, and written to
+// the command's stdin followed by a newline. If it renders to "", for
+// example because the argument was omitted, the client is asked instead
+// with an elicitation request showing Message, or the prompt itself if
+// Message is empty.
+type Prompt struct {
+	Pattern string `json:"pattern" yaml:"pattern"`
+	Answer  string `json:"answer,omitempty" yaml:"answer,omitempty"`
+	Message string `json:"message,omitempty" yaml:"message,omitempty"`
+}
+
+type compiledPrompt struct {
+	pattern *regexp.Regexp
+	answer  *template.Template
+	message string
+}
+
+func compilePrompts(prompts []Prompt) ([]compiledPrompt, error) {
+	compiled := make([]compiledPrompt, 0, len(prompts))
+	for i, p := range prompts {
+		if p.Pattern == "" {
+			return nil, fmt.Errorf("prompt %d: pattern is required", i)
+		}
+		pattern, err := regexp.Compile(p.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("prompt %d: invalid pattern: %w", i, err)
+		}
+		answer, err := parseTemplate(fmt.Sprintf("prompts[%d].answer", i), p.Answer)
+		if err != nil {
+			return nil, err
+		}
+		compiled = append(compiled, compiledPrompt{pattern: pattern, answer: answer, message: p.Message})
+	}
+	return compiled, nil
+}
+
+// maxPendingOutput bounds the unanswered output kept for matching prompts.
+const maxPendingOutput = 4096
+
+type promptAnswer struct {
+	Answer string `json:"answer" jsonschema:"required,description=Answer to the prompt"`
+}
+
+// promptResponder watches a command's output and answers the prompts it
+// recognizes. Answers go to answers, the command's stdin, or are kept for
+// Read if it is nil, for commands run in-process.
+type promptResponder struct {
+	ctx     context.Context
+	prompts []compiledPrompt
+	data    map[string]interface{}
+	answers io.Writer
+	abort   func()
+
+	mu      sync.Mutex
+	pending []byte
+	input   bytes.Buffer
+	err     error
+}
+
+func newPromptResponder(ctx context.Context, prompts []compiledPrompt, data map[string]interface{}, answers io.Writer, abort func()) *promptResponder {
+	return &promptResponder{ctx: ctx, prompts: prompts, data: data, answers: answers, abort: abort}
+}
+
+// wrap returns a writer that writes to w and answers the prompts in what
+// is written.
+func (r *promptResponder) wrap(w io.Writer) io.Writer {
+	if len(r.prompts) == 0 {
+		return w
+	}
+	return &promptWriter{responder: r, w: w}
+}
+
+type promptWriter struct {
+	responder *promptResponder
+	w         io.Writer
+}
+
+func (pw *promptWriter) Write(p []byte) (int, error) {
+	n, err := pw.w.Write(p)
+	pw.responder.scan(p[:n])
+	return n, err
+}
+
+// Read returns the answers given so far. With none left it reports io.EOF,
+// as a command reading an unanswered prompt would see from a closed stdin.
+func (r *promptResponder) Read(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.input.Read(p)
+}
+
+// Err returns why a prompt went unanswered, if one did.
+func (r *promptResponder) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.err
+}
+
+func (r *promptResponder) scan(p []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		return
+	}
+	r.pending = append(r.pending, p...)
+	if len(r.pending) > maxPendingOutput {
+		r.pending = r.pending[len(r.pending)-maxPendingOutput:]
+	}
+
+	for {
+		prompt, loc := r.match()
+		if prompt == nil {
+			return
+		}
+		text := strings.TrimSpace(string(r.pending[loc[0]:loc[1]]))
+		r.pending = r.pending[loc[1]:]
+
+		answer, err := r.answer(prompt, text)
+		if err != nil {
+			r.err = err
+			if r.abort != nil {
+				r.abort()
+			}
+			return
+		}
+		if r.answers != nil {
+			// The command may have exited without reading the answer.
+			r.answers.Write([]byte(answer + "\n"))
+		} else {
+			r.input.WriteString(answer + "\n")
+		}
+	}
+}
+
+// match returns the prompt matching earliest in the pending output.
+func (r *promptResponder) match() (*compiledPrompt, []int) {
+	var first *compiledPrompt
+	var firstLoc []int
+	for i := range r.prompts {
+		loc := r.prompts[i].pattern.FindIndex(r.pending)
+		if loc != nil && (firstLoc == nil || loc[0] < firstLoc[0]) {
+			first, firstLoc = &r.prompts[i], loc
+		}
+	}
+	return first, firstLoc
+}
+
+func (r *promptResponder) answer(prompt *compiledPrompt, text string) (string, error) {
+	answer, err := renderTemplate(prompt.answer, r.data)
+	if err != nil || answer != "" {
+		return answer, err
+	}
+
+	message := prompt.message
+	if message == "" {
+		message = text
+	}
+	result, err := Elicit(r.ctx, promptAnswer{}, message)
+	if err != nil {
+		return "", fmt.Errorf("prompt %q needs an answer: %w", text, err)
+	}
+	if !result.Accepted() {
+		return "", Errorf(CodeInvalidInput, "prompt %q was not answered: %s", text, result.Action)
+	}
+	return result.Value.(*promptAnswer).Answer, nil
+}
@@ -0,0 +1,91 @@
+package mcpwrapper
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+type PromptArgs struct {
+	Confirm  bool   `json:"confirm,omitempty"`
+	Password string `json:"password,omitempty"`
+}
+
+func TestExecPrompts(t *testing.T) {
+	mcpServer := server.NewMCPServer("test", "1.0.0", server.WithElicitation())
+	wrapper := New(mcpServer)
+	err := wrapper.RegisterExec("deploy", "Deploy", PromptArgs{}, ExecSpec{
+		Command: "sh",
+		Args:    []string{"-c", `printf 'Continue? [y/N] '; read ok; printf 'Password: ' >&2; read pw; echo "ok=$ok pw=$pw"`},
+		Prompts: []Prompt{
+			{Pattern: `Continue\? \[y/N\]`, Answer: "{{if .confirm}}y{{else}}n{{end}}"},
+			{Pattern: `Password:`, Answer: "{{.password}}", Message: "Deployment password"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("RegisterExec failed: %v", err)
+	}
+
+	call := func(elicitor *fakeElicitor, args map[string]interface{}) *mcp.CallToolResult {
+		t.Helper()
+		session := server.NewInProcessSessionWithHandlers("session-1", nil, elicitor, nil)
+		ctx := mcpServer.WithContext(context.Background(), session)
+		result, err := mcpServer.GetTool("deploy").Handler(ctx, mcp.CallToolRequest{
+			Params: mcp.CallToolParams{Name: "deploy", Arguments: args},
+		})
+		if err != nil {
+			t.Fatalf("Handler invocation failed: %v", err)
+		}
+		return result
+	}
+	stdout := func(result *mcp.CallToolResult) string {
+		t.Helper()
+		if result.IsError {
+			t.Fatalf("Unexpected error: %v", result.Content)
+		}
+		var exec ExecResult
+		json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &exec)
+		return exec.Stdout
+	}
+
+	elicitor := &fakeElicitor{}
+	out := stdout(call(elicitor, map[string]interface{}{"confirm": true, "password": "s3cret"}))
+	if !strings.HasSuffix(out, "ok=y pw=s3cret\n") {
+		t.Errorf("Expected the prompts to be answered from the arguments, got %q", out)
+	}
+	if elicitor.request.Params.Message != "" {
+		t.Errorf("Expected no elicitation, got %+v", elicitor.request)
+	}
+
+	elicitor = &fakeElicitor{response: mcp.ElicitationResponse{
+		Action:  mcp.ElicitationResponseActionAccept,
+		Content: map[string]interface{}{"answer": "hunter2"},
+	}}
+	out = stdout(call(elicitor, map[string]interface{}{}))
+	if !strings.HasSuffix(out, "ok=n pw=hunter2\n") {
+		t.Errorf("Expected the omitted password to be elicited, got %q", out)
+	}
+	if elicitor.request.Params.Message != "Deployment password" {
+		t.Errorf("Unexpected elicitation request: %+v", elicitor.request)
+	}
+
+	elicitor = &fakeElicitor{response: mcp.ElicitationResponse{Action: mcp.ElicitationResponseActionDecline}}
+	result := call(elicitor, map[string]interface{}{})
+	if !result.IsError || !strings.Contains(result.Content[0].(mcp.TextContent).Text, "was not answered") {
+		t.Errorf("Expected a declined prompt to fail the call, got %v", result.Content)
+	}
+}
+
+func TestExecPromptsInvalid(t *testing.T) {
+	wrapper := New(server.NewMCPServer("test", "1.0.0"))
+	for _, prompt := range []Prompt{{}, {Pattern: "("}, {Pattern: "x", Answer: "{{"}} {
+		err := wrapper.RegisterExec("bad", "Bad", PromptArgs{}, ExecSpec{Command: "true", Prompts: []Prompt{prompt}})
+		if err == nil {
+			t.Errorf("Expected an error for prompt %+v", prompt)
+		}
+	}
+}
@@ -0,0 +1,252 @@
+package mcpwrapper
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// Values shorter than this are too common ("id", 1, true) to say anything
+	// about where an argument came from.
+	minFingerprintLen = 4
+	// maxProvenanceCalls, maxProvenanceValues and maxProvenanceEdges bound
+	// the history kept per session; the oldest calls stop being matched once
+	// a limit is reached.
+	maxProvenanceCalls  = 1000
+	maxProvenanceValues = 10000
+	maxProvenanceEdges  = 10000
+)
+
+type ProvenanceCall struct {
+	ID   int       `json:"id"`
+	Tool string    `json:"tool"`
+	Time time.Time `json:"time"`
+}
+
+// ProvenanceEdge records that the value at ResultPath in the result of call
+// From later appeared at ArgPath in the arguments of call To.
+type ProvenanceEdge struct {
+	From       int    `json:"from"`
+	ResultPath string `json:"result_path"`
+	To         int    `json:"to"`
+	ArgPath    string `json:"arg_path"`
+}
+
+type ProvenanceGraph struct {
+	SessionID string           `json:"session_id"`
+	Calls     []ProvenanceCall `json:"calls"`
+	Edges     []ProvenanceEdge `json:"edges"`
+}
+
+type provenanceTracker struct {
+	mu       sync.Mutex
+	sessions map[string]*sessionProvenance
+}
+
+type sessionProvenance struct {
+	graph   ProvenanceGraph
+	sources map[string][]valueSource
+	values  int
+	nextID  int
+}
+
+type valueSource struct {
+	call int
+	path string
+}
+
+// WithProvenance records, per client session, which values returned by one
+// tool call were later passed as arguments to another. The resulting graph is
+// available from Provenance and the admin API.
+func WithProvenance() Option {
+	return func(w *Wrapper) {
+		w.provenance = &provenanceTracker{sessions: make(map[string]*sessionProvenance)}
+	}
+}
+
+func (t *provenanceTracker) middleware(next Handler) Handler {
+	return func(ctx context.Context, args interface{}) (interface{}, error) {
+		callID := t.recordCall(SessionIDFromContext(ctx), ToolNameFromContext(ctx), args)
+
+		result, err := next(ctx, args)
+		if err == nil {
+			t.recordResult(SessionIDFromContext(ctx), callID, result)
+		}
+		return result, err
+	}
+}
+
+func (t *provenanceTracker) session(id string) *sessionProvenance {
+	s, ok := t.sessions[id]
+	if !ok {
+		s = &sessionProvenance{
+			graph:   ProvenanceGraph{SessionID: id},
+			sources: make(map[string][]valueSource),
+		}
+		t.sessions[id] = s
+	}
+	return s
+}
+
+func (t *provenanceTracker) recordCall(session, tool string, args interface{}) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	s := t.session(session)
+	s.nextID++
+	id := s.nextID
+
+	s.graph.Calls = append(s.graph.Calls, ProvenanceCall{ID: id, Tool: tool, Time: time.Now()})
+	for path, fp := range fingerprints(args) {
+		for _, src := range s.sources[fp] {
+			s.graph.Edges = append(s.graph.Edges, ProvenanceEdge{From: src.call, ResultPath: src.path, To: id, ArgPath: path})
+		}
+	}
+	s.trim()
+
+	return id
+}
+
+func (t *provenanceTracker) recordResult(session string, callID int, result interface{}) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	s, ok := t.sessions[session]
+	if !ok {
+		return
+	}
+	for path, fp := range fingerprints(result) {
+		s.sources[fp] = append(s.sources[fp], valueSource{call: callID, path: path})
+		s.values++
+	}
+	s.trim()
+}
+
+// trim forgets the oldest calls while the session is over a limit. The
+// latest call is kept, so a single call is bounded only by its own size.
+func (s *sessionProvenance) trim() {
+	for len(s.graph.Calls) > 1 && (len(s.graph.Calls) > maxProvenanceCalls ||
+		s.values > maxProvenanceValues || len(s.graph.Edges) > maxProvenanceEdges) {
+		s.forget(s.graph.Calls[0].ID)
+		s.graph.Calls = s.graph.Calls[1:]
+	}
+}
+
+// end discards the graph of a session that has ended.
+func (t *provenanceTracker) end(session string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.sessions, session)
+}
+
+func (s *sessionProvenance) forget(callID int) {
+	for fp, sources := range s.sources {
+		kept := sources[:0]
+		for _, src := range sources {
+			if src.call != callID {
+				kept = append(kept, src)
+			}
+		}
+		s.values -= len(sources) - len(kept)
+		if len(kept) == 0 {
+			delete(s.sources, fp)
+		} else {
+			s.sources[fp] = kept
+		}
+	}
+
+	edges := s.graph.Edges[:0]
+	for _, edge := range s.graph.Edges {
+		if edge.From != callID && edge.To != callID {
+			edges = append(edges, edge)
+		}
+	}
+	s.graph.Edges = edges
+}
+
+func (t *provenanceTracker) graph(session string) (ProvenanceGraph, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	s, ok := t.sessions[session]
+	if !ok {
+		return ProvenanceGraph{}, false
+	}
+
+	g := s.graph
+	g.Calls = append([]ProvenanceCall(nil), s.graph.Calls...)
+	g.Edges = append([]ProvenanceEdge(nil), s.graph.Edges...)
+	return g, true
+}
+
+func (t *provenanceTracker) sessionIDs() []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	ids := make([]string, 0, len(t.sessions))
+	for id := range t.sessions {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Provenance returns the provenance graph of a session. The boolean is false
+// when provenance tracking is disabled or the session made no calls.
+func (w *Wrapper) Provenance(sessionID string) (ProvenanceGraph, bool) {
+	if w.provenance == nil {
+		return ProvenanceGraph{}, false
+	}
+	return w.provenance.graph(sessionID)
+}
+
+// fingerprints maps the JSON path of every scalar leaf of v to a hash of its
+// value. Values that fail to marshal yield no fingerprints.
+func fingerprints(v interface{}) map[string]string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+
+	var decoded interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return nil
+	}
+
+	out := make(map[string]string)
+	collectFingerprints(decoded, "$", out)
+	return out
+}
+
+func collectFingerprints(v interface{}, path string, out map[string]string) {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for k, child := range val {
+			collectFingerprints(child, path+"."+k, out)
+		}
+	case []interface{}:
+		for i, child := range val {
+			collectFingerprints(child, fmt.Sprintf("%s[%d]", path, i), out)
+		}
+	case string:
+		if len(val) >= minFingerprintLen {
+			out[path] = fingerprint("s:" + val)
+		}
+	case float64:
+		text := fmt.Sprint(val)
+		if len(text) >= minFingerprintLen {
+			out[path] = fingerprint("n:" + text)
+		}
+	}
+}
+
+func fingerprint(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:8])
+}
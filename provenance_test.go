@@ -0,0 +1,130 @@
+package mcpwrapper
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+type testSession struct {
+	id string
+}
+
+func (s *testSession) Initialize()       {}
+func (s *testSession) Initialized() bool { return true }
+func (s *testSession) SessionID() string { return s.id }
+func (s *testSession) NotificationChannel() chan<- mcp.JSONRPCNotification {
+	return make(chan mcp.JSONRPCNotification, 10)
+}
+
+type LookupArgs struct {
+	Email string `json:"email" validate:"required"`
+}
+
+type LookupResult struct {
+	UserID string `json:"user_id"`
+}
+
+type DeleteArgs struct {
+	UserID string `json:"user_id" validate:"required"`
+}
+
+func TestProvenance(t *testing.T) {
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	wrapper := New(mcpServer, WithProvenance())
+
+	lookup := func(ctx context.Context, args interface{}) (interface{}, error) {
+		return &LookupResult{UserID: "usr-12345"}, nil
+	}
+	remove := func(ctx context.Context, args interface{}) (interface{}, error) {
+		return &TestResult{Message: "deleted"}, nil
+	}
+
+	if err := wrapper.Register("lookup", "Lookup user", LookupArgs{}, lookup); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	if err := wrapper.Register("delete", "Delete user", DeleteArgs{}, remove); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	ctx := mcpServer.WithContext(context.Background(), &testSession{id: "session-1"})
+
+	call := func(name string, args map[string]interface{}) {
+		request := mcp.CallToolRequest{Params: mcp.CallToolParams{Name: name, Arguments: args}}
+		result, err := mcpServer.GetTool(name).Handler(ctx, request)
+		if err != nil || result.IsError {
+			t.Fatalf("Handler invocation failed: %v %v", err, result)
+		}
+	}
+
+	call("lookup", map[string]interface{}{"email": "alice@example.com"})
+	call("delete", map[string]interface{}{"user_id": "usr-12345"})
+
+	graph, ok := wrapper.Provenance("session-1")
+	if !ok {
+		t.Fatal("Expected provenance graph for session-1")
+	}
+
+	if len(graph.Calls) != 2 {
+		t.Fatalf("Expected 2 calls, got %d", len(graph.Calls))
+	}
+
+	if len(graph.Edges) != 1 {
+		t.Fatalf("Expected 1 edge, got %d", len(graph.Edges))
+	}
+
+	edge := graph.Edges[0]
+	expected := ProvenanceEdge{From: 1, ResultPath: "$.user_id", To: 2, ArgPath: "$.user_id"}
+	if edge != expected {
+		t.Errorf("Expected edge %+v, got %+v", expected, edge)
+	}
+
+	rec := httptest.NewRecorder()
+	wrapper.AdminHandler().ServeHTTP(rec, httptest.NewRequest("GET", "/provenance/session-1", nil))
+
+	var served ProvenanceGraph
+	if err := json.Unmarshal(rec.Body.Bytes(), &served); err != nil {
+		t.Fatalf("Failed to decode admin response: %v", err)
+	}
+
+	if len(served.Edges) != 1 {
+		t.Errorf("Expected admin API to serve 1 edge, got %d", len(served.Edges))
+	}
+
+	wrapper.EndSession("session-1")
+	if _, ok := wrapper.Provenance("session-1"); ok {
+		t.Error("Expected the graph to be discarded when the session ends")
+	}
+}
+
+func TestProvenanceBounds(t *testing.T) {
+	tracker := &provenanceTracker{sessions: make(map[string]*sessionProvenance)}
+	for call := 0; call < 5; call++ {
+		result := make(map[string]string, 4000)
+		for i := 0; i < 4000; i++ {
+			result[fmt.Sprintf("k%d", i)] = fmt.Sprintf("value-%d-%d", call, i)
+		}
+		id := tracker.recordCall("session-1", "dump", nil)
+		tracker.recordResult("session-1", id, result)
+	}
+
+	s := tracker.sessions["session-1"]
+	if s.values > maxProvenanceValues {
+		t.Errorf("Expected at most %d values, got %d", maxProvenanceValues, s.values)
+	}
+	if len(s.graph.Calls) != 2 || s.graph.Calls[1].ID != 5 {
+		t.Errorf("Expected the oldest calls to be forgotten, got %+v", s.graph.Calls)
+	}
+	count := 0
+	for _, sources := range s.sources {
+		count += len(sources)
+	}
+	if count != s.values {
+		t.Errorf("Expected %d values to be kept, got %d", s.values, count)
+	}
+}
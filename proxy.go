@@ -0,0 +1,136 @@
+package mcpwrapper
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Downstream is the part of an MCP client that RegisterProxy needs. mcp-go's
+// *client.Client implements it, over any transport, once it has been
+// started and initialized.
+type Downstream interface {
+	ListTools(ctx context.Context, request mcp.ListToolsRequest) (*mcp.ListToolsResult, error)
+	CallTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error)
+}
+
+// ProxyOption configures RegisterProxy.
+type ProxyOption func(*proxyOptions)
+
+type proxyOptions struct {
+	prefix      string
+	filter      func(tool mcp.Tool) bool
+	toolOptions []ToolOption
+}
+
+// WithPrefix prepends prefix to the names of the proxied tools, such as
+// "github_", to keep the tools of several backends apart.
+func WithPrefix(prefix string) ProxyOption {
+	return func(o *proxyOptions) {
+		o.prefix = prefix
+	}
+}
+
+// WithToolFilter proxies only the downstream tools for which filter returns
+// true. It sees the tools under their downstream names.
+func WithToolFilter(filter func(tool mcp.Tool) bool) ProxyOption {
+	return func(o *proxyOptions) {
+		o.filter = filter
+	}
+}
+
+// WithProxyToolOptions applies opts to every proxied tool, for example
+// WithToolMiddleware or WithToolAuthorizer.
+func WithProxyToolOptions(opts ...ToolOption) ProxyOption {
+	return func(o *proxyOptions) {
+		o.toolOptions = append(o.toolOptions, opts...)
+	}
+}
+
+// RegisterProxy lists the tools of a downstream MCP server and registers
+// each one on the wrapper, forwarding calls to it. Several downstreams can
+// be registered on one wrapper, making it a gateway in front of them.
+// Proxied tools keep their input and output schemas, and the read-only
+// hint. Arguments are checked against the input schema like RegisterSchema
+// tools, the wrapper's middleware and authorizers apply, and the
+// downstream's result is returned unchanged. A failure to reach the
+// downstream is an unavailable error. RegisterProxy returns the names of
+// the registered tools. Tools the downstream adds later are not picked up;
+// call RegisterProxy again.
+func (w *Wrapper) RegisterProxy(ctx context.Context, downstream Downstream, opts ...ProxyOption) ([]string, error) {
+	options := &proxyOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	listed, err := downstream.ListTools(ctx, mcp.ListToolsRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list downstream tools: %w", err)
+	}
+
+	var names []string
+	for _, tool := range listed.Tools {
+		if options.filter != nil && !options.filter(tool) {
+			continue
+		}
+		name := options.prefix + tool.Name
+
+		toolOpts, err := proxiedToolOptions(tool)
+		if err != nil {
+			return names, fmt.Errorf("invalid output schema for downstream tool %s: %w", tool.Name, err)
+		}
+		toolOpts = append(toolOpts, options.toolOptions...)
+
+		schema := tool.InputSchema
+		if schema.Type == "" {
+			schema.Type = "object"
+		}
+		if schema.Properties == nil {
+			schema.Properties = make(map[string]interface{})
+		}
+		if schema.Required == nil {
+			schema.Required = make([]string, 0)
+		}
+
+		if err := w.register(name, tool.Description, Arguments{}, &schema, proxyHandler(downstream, tool.Name), toolOpts); err != nil {
+			return names, err
+		}
+		names = append(names, name)
+	}
+
+	w.logger.Info("registered downstream tools", "count", len(names), "prefix", options.prefix)
+	return names, nil
+}
+
+func proxiedToolOptions(tool mcp.Tool) ([]ToolOption, error) {
+	var opts []ToolOption
+	if hint := tool.Annotations.ReadOnlyHint; hint != nil && *hint {
+		opts = append(opts, WithReadOnly())
+	}
+	if tool.OutputSchema.Type != "" {
+		data, err := json.Marshal(tool.OutputSchema)
+		if err != nil {
+			return nil, err
+		}
+		var schema map[string]interface{}
+		if err := json.Unmarshal(data, &schema); err != nil {
+			return nil, err
+		}
+		opts = append(opts, WithOutputSchema(schema))
+	}
+	return opts, nil
+}
+
+func proxyHandler(downstream Downstream, name string) Handler {
+	return func(ctx context.Context, args interface{}) (interface{}, error) {
+		result, err := downstream.CallTool(ctx, mcp.CallToolRequest{
+			Params: mcp.CallToolParams{Name: name, Arguments: map[string]interface{}(args.(Arguments))},
+		})
+		if err != nil {
+			return nil, Unavailable("downstream call to %s failed: %v", name, err)
+		}
+		return result, nil
+	}
+}
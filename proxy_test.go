@@ -0,0 +1,111 @@
+package mcpwrapper
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func newDownstream(t *testing.T) *client.Client {
+	t.Helper()
+	backend := server.NewMCPServer("backend", "1.0.0")
+	wrapper := New(backend)
+	wrapper.Register("greet", "Greet someone", TestArgs{}, func(ctx context.Context, args interface{}) (interface{}, error) {
+		return &TestResult{Message: "hello " + args.(*TestArgs).Name}, nil
+	}, WithReadOnly(), WithOutputSchema(map[string]interface{}{
+		"type":       "object",
+		"properties": map[string]interface{}{"message": map[string]interface{}{"type": "string"}},
+	}))
+	wrapper.Register("delete_all", "Delete everything", TestArgs{}, func(ctx context.Context, args interface{}) (interface{}, error) {
+		return nil, errors.New("should not be proxied")
+	})
+
+	c, err := client.NewInProcessClient(backend)
+	if err != nil {
+		t.Fatalf("NewInProcessClient failed: %v", err)
+	}
+	ctx := context.Background()
+	if err := c.Start(ctx); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	initRequest := mcp.InitializeRequest{}
+	initRequest.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	initRequest.Params.ClientInfo = mcp.Implementation{Name: "gateway", Version: "1.0.0"}
+	if _, err := c.Initialize(ctx, initRequest); err != nil {
+		t.Fatalf("Initialize failed: %v", err)
+	}
+	t.Cleanup(func() { c.Close() })
+	return c
+}
+
+func TestRegisterProxy(t *testing.T) {
+	gateway := server.NewMCPServer("gateway", "1.0.0")
+	var proxied []string
+	wrapper := New(gateway)
+
+	names, err := wrapper.RegisterProxy(context.Background(), newDownstream(t),
+		WithPrefix("backend_"),
+		WithToolFilter(func(tool mcp.Tool) bool { return !strings.HasPrefix(tool.Name, "delete") }),
+		WithProxyToolOptions(WithToolMiddleware(func(next Handler) Handler {
+			return func(ctx context.Context, args interface{}) (interface{}, error) {
+				proxied = append(proxied, ToolNameFromContext(ctx))
+				return next(ctx, args)
+			}
+		})),
+	)
+	if err != nil {
+		t.Fatalf("RegisterProxy failed: %v", err)
+	}
+	sort.Strings(names)
+	if len(names) != 1 || names[0] != "backend_greet" || gateway.GetTool("backend_delete_all") != nil {
+		t.Fatalf("Unexpected proxied tools: %v", names)
+	}
+
+	tool := gateway.GetTool("backend_greet").Tool
+	if tool.Description != "Greet someone" || *tool.Annotations.ReadOnlyHint != true || tool.OutputSchema.Type != "object" {
+		t.Errorf("Expected the downstream tool definition to be kept, got %+v", tool)
+	}
+	if _, ok := tool.InputSchema.Properties["category"]; !ok {
+		t.Errorf("Expected the downstream input schema, got %+v", tool.InputSchema)
+	}
+
+	result := callTool(t, gateway, "backend_greet", map[string]interface{}{"name": "Alice", "age": 30, "category": "A"})
+	if result.IsError || !strings.Contains(result.Content[0].(mcp.TextContent).Text, "hello Alice") {
+		t.Errorf("Unexpected proxied result: %+v", result)
+	}
+	if len(proxied) != 1 || proxied[0] != "backend_greet" {
+		t.Errorf("Expected the proxy middleware to run, got %v", proxied)
+	}
+
+	result = callTool(t, gateway, "backend_greet", map[string]interface{}{"name": "Alice"})
+	if !result.IsError || len(proxied) != 1 {
+		t.Errorf("Expected missing required arguments to be rejected by the gateway, got %+v", result)
+	}
+}
+
+type failingDownstream struct{}
+
+func (failingDownstream) ListTools(ctx context.Context, request mcp.ListToolsRequest) (*mcp.ListToolsResult, error) {
+	return &mcp.ListToolsResult{Tools: []mcp.Tool{mcp.NewTool("ping")}}, nil
+}
+
+func (failingDownstream) CallTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return nil, errors.New("connection reset")
+}
+
+func TestProxyDownstreamFailure(t *testing.T) {
+	gateway := server.NewMCPServer("gateway", "1.0.0")
+	if _, err := New(gateway).RegisterProxy(context.Background(), failingDownstream{}); err != nil {
+		t.Fatalf("RegisterProxy failed: %v", err)
+	}
+	result := callTool(t, gateway, "ping", map[string]interface{}{})
+	if !result.IsError || result.StructuredContent.(map[string]interface{})["code"] != CodeUnavailable {
+		t.Errorf("Expected an unavailable error, got %+v", result)
+	}
+}
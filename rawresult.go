@@ -0,0 +1,38 @@
+package mcpwrapper
+
+import "github.com/mark3labs/mcp-go/mcp"
+
+// TextResult is a handler result sent as a single text content, as is.
+// Unlike other results it is not JSON-encoded, so pre-formatted output such
+// as a table or a diff reaches the client unchanged.
+type TextResult string
+
+// rawResult returns the CallToolResult of a handler result that is already
+// formatted: a *mcp.CallToolResult, one or more mcp.Content values, or a
+// TextResult. These bypass output transformations and JSON encoding. ok is
+// false for any other result.
+func rawResult(result interface{}) (*mcp.CallToolResult, bool) {
+	var raw *mcp.CallToolResult
+	switch r := result.(type) {
+	case *mcp.CallToolResult:
+		raw = r
+		if raw == nil {
+			raw = &mcp.CallToolResult{}
+		}
+	case mcp.CallToolResult:
+		raw = &r
+	case TextResult:
+		raw = mcp.NewToolResultText(string(r))
+	case []mcp.Content:
+		raw = &mcp.CallToolResult{Content: r}
+	case mcp.Content:
+		raw = &mcp.CallToolResult{Content: []mcp.Content{r}}
+	default:
+		return nil, false
+	}
+
+	if raw.Content == nil {
+		raw.Content = []mcp.Content{} // MCP requires the content array
+	}
+	return raw, true
+}
@@ -0,0 +1,54 @@
+package mcpwrapper
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func TestRawResults(t *testing.T) {
+	built := &mcp.CallToolResult{
+		Content: []mcp.Content{mcp.NewTextContent("built")},
+		IsError: true,
+	}
+	image := mcp.NewImageContent("aGVsbG8=", "image/png")
+
+	tests := []struct {
+		name   string
+		result interface{}
+		want   []mcp.Content
+	}{
+		{"call_tool_result", built, built.Content},
+		{"call_tool_result_value", *built, built.Content},
+		{"nil_call_tool_result", (*mcp.CallToolResult)(nil), []mcp.Content{}},
+		{"text_result", TextResult("a | b\n--|--\n1 | 2"), []mcp.Content{mcp.NewTextContent("a | b\n--|--\n1 | 2")}},
+		{"content", image, []mcp.Content{image}},
+		{"contents", []mcp.Content{mcp.NewTextContent("one"), image}, []mcp.Content{mcp.NewTextContent("one"), image}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mcpServer := server.NewMCPServer("test", "1.0.0")
+			wrapper := New(mcpServer, WithOutputValidation())
+			wrapper.Register("raw", "Raw result", TestArgs{}, func(ctx context.Context, args interface{}) (interface{}, error) {
+				return tt.result, nil
+			})
+
+			result := callTool(t, mcpServer, "raw", map[string]interface{}{"name": "Alice", "age": 30, "category": "A"})
+			if !reflect.DeepEqual(result.Content, tt.want) {
+				t.Errorf("Unexpected content: %#v", result.Content)
+			}
+		})
+	}
+
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	New(mcpServer).Register("built", "Built result", TestArgs{}, func(ctx context.Context, args interface{}) (interface{}, error) {
+		return built, nil
+	})
+	if result := callTool(t, mcpServer, "built", map[string]interface{}{"name": "Alice", "age": 30, "category": "A"}); !result.IsError {
+		t.Error("Expected IsError of a returned CallToolResult to be kept")
+	}
+}
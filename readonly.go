@@ -0,0 +1,29 @@
+package mcpwrapper
+
+import "fmt"
+
+// WithReadOnly marks a tool as not modifying its environment. It sets the
+// readOnlyHint annotation and keeps the tool callable in read-only mode.
+func WithReadOnly() ToolOption {
+	return func(o *toolOptions) {
+		o.readOnly = true
+	}
+}
+
+// SetReadOnly switches the wrapper into (or out of) read-only mode, in which
+// every tool not registered WithReadOnly is rejected. It can be toggled while
+// the server is running.
+func (w *Wrapper) SetReadOnly(readOnly bool) {
+	w.readOnly.Store(readOnly)
+}
+
+func (w *Wrapper) ReadOnly() bool {
+	return w.readOnly.Load()
+}
+
+func (w *Wrapper) checkReadOnly(t *registeredTool) error {
+	if w.readOnly.Load() && !t.options.readOnly {
+		return fmt.Errorf("tool %s modifies state and the server is in read-only mode", t.name)
+	}
+	return nil
+}
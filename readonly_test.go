@@ -0,0 +1,70 @@
+package mcpwrapper
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func TestReadOnlyMode(t *testing.T) {
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	wrapper := New(mcpServer)
+
+	handler := func(ctx context.Context, args interface{}) (interface{}, error) {
+		return &TestResult{Message: "ok"}, nil
+	}
+
+	if err := wrapper.Register("get", "Read tool", TestArgs{}, handler, WithReadOnly()); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	if err := wrapper.Register("set", "Write tool", TestArgs{}, handler); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	if hint := mcpServer.GetTool("get").Tool.Annotations.ReadOnlyHint; hint == nil || !*hint {
+		t.Error("Expected readOnlyHint on the read-only tool")
+	}
+
+	call := func(name string) *mcp.CallToolResult {
+		request := mcp.CallToolRequest{
+			Params: mcp.CallToolParams{
+				Name:      name,
+				Arguments: map[string]interface{}{"name": "Alice", "age": 30, "category": "A"},
+			},
+		}
+		result, err := mcpServer.GetTool(name).Handler(context.Background(), request)
+		if err != nil {
+			t.Fatalf("Handler invocation failed: %v", err)
+		}
+		return result
+	}
+
+	if call("set").IsError {
+		t.Error("Expected write tool to run outside read-only mode")
+	}
+
+	wrapper.SetReadOnly(true)
+	if !wrapper.ReadOnly() {
+		t.Error("Expected wrapper to report read-only mode")
+	}
+
+	if call("get").IsError {
+		t.Error("Expected read-only tool to run in read-only mode")
+	}
+
+	result := call("set")
+	if !result.IsError {
+		t.Fatal("Expected write tool to be rejected in read-only mode")
+	}
+	if text := result.Content[0].(mcp.TextContent).Text; !strings.Contains(text, "read-only mode") {
+		t.Errorf("Expected a read-only error, got %q", text)
+	}
+
+	wrapper.SetReadOnly(false)
+	if call("set").IsError {
+		t.Error("Expected write tool to run again after leaving read-only mode")
+	}
+}
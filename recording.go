@@ -0,0 +1,185 @@
+package mcpwrapper
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Recording is a recorded tool call: its arguments as the client sent them
+// and the result it got. Recordings are written and read as JSON lines.
+type Recording struct {
+	Tool       string                 `json:"tool"`
+	Arguments  map[string]interface{} `json:"arguments,omitempty"`
+	Result     json.RawMessage        `json:"result"`
+	RecordedAt time.Time              `json:"recorded_at"`
+	DurationMs int64                  `json:"duration_ms"`
+}
+
+// WithRecorder writes a Recording of every tool call to out, one JSON line
+// per call, such as to a file opened for appending. Recordings hold the
+// arguments and results unredacted, so they can be replayed. If encryptor
+// is not nil, each line is sealed with it and base64 encoded.
+func WithRecorder(out io.Writer, encryptor *Encryptor) Option {
+	return func(w *Wrapper) {
+		w.recorder = &recorder{out: out, encryptor: encryptor}
+	}
+}
+
+// WithReplay serves calls from recordings instead of calling the handlers.
+// Only the handler is replaced: calls still go through the tool's checks,
+// transforms, validation and middleware. A call is served by a recording
+// of the same tool with equal arguments; when several match, they are
+// served in order and the last one repeats. A call without a recording
+// fails as not found.
+func WithReplay(recordings []Recording) Option {
+	return func(w *Wrapper) {
+		w.replay = newReplayer(recordings)
+	}
+}
+
+// ReadRecordings reads the recordings written by WithRecorder, opening
+// them with encryptor if they were sealed.
+func ReadRecordings(r io.Reader, encryptor *Encryptor) ([]Recording, error) {
+	var recordings []Recording
+	reader := bufio.NewReader(r)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, err
+		}
+		if line = bytes.TrimSpace(line); len(line) > 0 {
+			rec, decodeErr := decodeRecording(line, encryptor)
+			if decodeErr != nil {
+				return nil, fmt.Errorf("invalid recording %d: %w", len(recordings)+1, decodeErr)
+			}
+			recordings = append(recordings, rec)
+		}
+		if err != nil {
+			return recordings, nil
+		}
+	}
+}
+
+func decodeRecording(line []byte, encryptor *Encryptor) (Recording, error) {
+	var rec Recording
+	if encryptor != nil {
+		sealed, err := base64.StdEncoding.DecodeString(string(line))
+		if err != nil {
+			return rec, err
+		}
+		if line, err = encryptor.Open(sealed); err != nil {
+			return rec, err
+		}
+	}
+	err := json.Unmarshal(line, &rec)
+	return rec, err
+}
+
+type recorder struct {
+	mu        sync.Mutex
+	out       io.Writer
+	encryptor *Encryptor
+}
+
+func (w *Wrapper) recordCall(tool string, args map[string]interface{}, result *mcp.CallToolResult, start time.Time) {
+	if w.recorder == nil {
+		return
+	}
+	data, err := json.Marshal(result)
+	if err == nil {
+		data, err = json.Marshal(Recording{
+			Tool:       tool,
+			Arguments:  args,
+			Result:     data,
+			RecordedAt: start,
+			DurationMs: time.Since(start).Milliseconds(),
+		})
+	}
+	r := w.recorder
+	if err == nil && r.encryptor != nil {
+		var sealed []byte
+		if sealed, err = r.encryptor.Seal(data); err == nil {
+			data = []byte(base64.StdEncoding.EncodeToString(sealed))
+		}
+	}
+	if err != nil {
+		w.logger.Warn("failed to record call", "tool", tool, "error", err)
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, err := r.out.Write(append(data, '\n')); err != nil {
+		w.logger.Warn("failed to record call", "tool", tool, "error", err)
+	}
+}
+
+// replayer serves recorded results, keyed by tool and arguments.
+type replayer struct {
+	mu      sync.Mutex
+	results map[string][]json.RawMessage
+	served  map[string]int
+}
+
+func newReplayer(recordings []Recording) *replayer {
+	r := &replayer{results: make(map[string][]json.RawMessage), served: make(map[string]int)}
+	for _, rec := range recordings {
+		key := replayKey(rec.Tool, rec.Arguments)
+		r.results[key] = append(r.results[key], rec.Result)
+	}
+	return r
+}
+
+// replayKey identifies a call by its tool and arguments. Encoding sorts
+// map keys, so equal arguments give equal keys.
+func replayKey(tool string, args map[string]interface{}) string {
+	if len(args) == 0 {
+		return tool
+	}
+	data, err := json.Marshal(args)
+	if err != nil {
+		return tool + " " + fmt.Sprint(args)
+	}
+	return tool + " " + string(data)
+}
+
+// replayHandler returns the handler that replays the calls to a tool. It
+// looks calls up by the arguments the client sent, as they are recorded.
+func (w *Wrapper) replayHandler(name string) Handler {
+	return func(ctx context.Context, args interface{}) (interface{}, error) {
+		request, _ := RequestFromContext(ctx)
+		return w.replay.result(name, request.GetArguments())
+	}
+}
+
+func (r *replayer) result(tool string, args map[string]interface{}) (*mcp.CallToolResult, error) {
+	key := replayKey(tool, args)
+	r.mu.Lock()
+	results := r.results[key]
+	n := r.served[key]
+	if n < len(results)-1 {
+		r.served[key] = n + 1
+	}
+	r.mu.Unlock()
+
+	if len(results) == 0 {
+		return nil, NotFound("no recording of this call to %s", tool)
+	}
+	// Parsed on every call, since the result is changed on its way out.
+	raw := results[n]
+	result, err := mcp.ParseCallToolResult(&raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid recording of %s: %w", tool, err)
+	}
+	return result, nil
+}
@@ -0,0 +1,124 @@
+package mcpwrapper
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	var buf bytes.Buffer
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	wrapper := New(mcpServer, WithRecorder(&buf, nil))
+	calls := 0
+	wrapper.Register("greet", "Greet", TestArgs{}, func(ctx context.Context, args interface{}) (interface{}, error) {
+		calls++
+		return &TestResult{Message: strings.Repeat("hello ", calls) + args.(*TestArgs).Name}, nil
+	})
+
+	alice := map[string]interface{}{"name": "Alice", "age": 30, "category": "A"}
+	callTool(t, mcpServer, "greet", alice)
+	callTool(t, mcpServer, "greet", alice)
+	callTool(t, mcpServer, "greet", map[string]interface{}{"name": "Al", "age": 30, "category": "A"})
+
+	recordings, err := ReadRecordings(&buf, nil)
+	if err != nil {
+		t.Fatalf("ReadRecordings failed: %v", err)
+	}
+	if len(recordings) != 3 || recordings[0].Tool != "greet" || recordings[0].Arguments["name"] != "Alice" || recordings[0].RecordedAt.IsZero() {
+		t.Fatalf("Unexpected recordings: %+v", recordings)
+	}
+	if !strings.Contains(string(recordings[2].Result), `"isError":true`) {
+		t.Errorf("Expected failed calls to be recorded too, got %s", recordings[2].Result)
+	}
+
+	mcpServer = server.NewMCPServer("test", "1.0.0")
+	wrapper = New(mcpServer, WithReplay(recordings))
+	wrapper.Register("greet", "Greet", TestArgs{}, func(ctx context.Context, args interface{}) (interface{}, error) {
+		t.Error("Expected the handler not to be called during replay")
+		return nil, nil
+	})
+
+	// Key order and number types don't matter.
+	replayed := map[string]interface{}{"category": "A", "age": float64(30), "name": "Alice"}
+	var first, second, third TestResult
+	decodeResult(t, callTool(t, mcpServer, "greet", replayed), &first)
+	decodeResult(t, callTool(t, mcpServer, "greet", replayed), &second)
+	decodeResult(t, callTool(t, mcpServer, "greet", replayed), &third)
+	if first.Message != "hello Alice" || second.Message != "hello hello Alice" || third.Message != second.Message {
+		t.Errorf("Expected recordings in order, then the last repeated, got %q %q %q", first.Message, second.Message, third.Message)
+	}
+
+	if result := callTool(t, mcpServer, "greet", map[string]interface{}{"name": "Al", "age": 30, "category": "A"}); !result.IsError {
+		t.Error("Expected a recorded failure to be replayed")
+	}
+	result := callTool(t, mcpServer, "greet", map[string]interface{}{"name": "Bob", "age": 30, "category": "A"})
+	if !result.IsError || !strings.Contains(resultText(result), "no recording of this call to greet") {
+		t.Errorf("Expected an unrecorded call to fail, got %v", result.Content)
+	}
+}
+
+func TestReadRecordingsInvalid(t *testing.T) {
+	_, err := ReadRecordings(strings.NewReader(`{"tool": "greet", "result": {}}`+"\n{oops\n"), nil)
+	if err == nil || !strings.Contains(err.Error(), "invalid recording 2") {
+		t.Errorf("Expected the bad line to be reported, got %v", err)
+	}
+}
+
+func TestReplayKeepsChecks(t *testing.T) {
+	recordings := []Recording{{
+		Tool:      "greet",
+		Arguments: map[string]interface{}{"name": "Alice", "age": 30, "category": "A"},
+		Result:    []byte(`{"content":[{"type":"text","text":"{\"message\":\"hello Alice\"}"}]}`),
+	}}
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	wrapper := New(mcpServer, WithReplay(recordings))
+	if err := wrapper.Register("greet", "Greet", TestArgs{}, nil); err != nil {
+		t.Fatalf("Expected a tool without a handler to register for replay: %v", err)
+	}
+
+	var got TestResult
+	decodeResult(t, callTool(t, mcpServer, "greet", recordings[0].Arguments), &got)
+	if got.Message != "hello Alice" {
+		t.Errorf("Expected the recorded result, got %q", got.Message)
+	}
+
+	result := callTool(t, mcpServer, "greet", map[string]interface{}{"name": "Al", "age": 30, "category": "A"})
+	if !result.IsError || !strings.Contains(resultText(result), "validation failed") {
+		t.Errorf("Expected replayed calls to be validated, got %v", result.Content)
+	}
+
+	wrapper.SetEnabled("greet", false)
+	result = callTool(t, mcpServer, "greet", recordings[0].Arguments)
+	if !result.IsError || strings.Contains(resultText(result), "hello Alice") {
+		t.Errorf("Expected a disabled tool not to replay, got %v", result.Content)
+	}
+}
+
+func TestEncryptedRecordings(t *testing.T) {
+	encryptor := NewEncryptor(StaticSecrets("k1", map[string][]byte{"k1": bytes.Repeat([]byte{7}, 32)}))
+	var buf bytes.Buffer
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	wrapper := New(mcpServer, WithRecorder(&buf, encryptor))
+	wrapper.Register("greet", "Greet", TestArgs{}, func(ctx context.Context, args interface{}) (interface{}, error) {
+		return &TestResult{Message: "hello " + args.(*TestArgs).Name}, nil
+	})
+	callTool(t, mcpServer, "greet", map[string]interface{}{"name": "Alice", "age": 30, "category": "A"})
+
+	if strings.Contains(buf.String(), "Alice") {
+		t.Fatalf("Expected the recording to be sealed, got %s", buf.String())
+	}
+	if _, err := ReadRecordings(bytes.NewReader(buf.Bytes()), nil); err == nil {
+		t.Error("Expected sealed recordings not to read without the encryptor")
+	}
+	recordings, err := ReadRecordings(&buf, encryptor)
+	if err != nil {
+		t.Fatalf("ReadRecordings failed: %v", err)
+	}
+	if len(recordings) != 1 || recordings[0].Arguments["name"] != "Alice" || !strings.Contains(string(recordings[0].Result), "hello Alice") {
+		t.Errorf("Unexpected recordings: %+v", recordings)
+	}
+}
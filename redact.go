@@ -0,0 +1,172 @@
+package mcpwrapper
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+const redactedValue = "[REDACTED]"
+
+// isSensitive reports whether a field is tagged redact:"true".
+func isSensitive(field reflect.StructField) bool {
+	return field.Tag.Get("redact") == "true"
+}
+
+// Redact returns a JSON-like copy of v (maps, slices and scalars) in which
+// every struct field tagged redact:"true" is replaced by "[REDACTED]". Use it
+// before writing arguments to logs, audit trails or telemetry.
+func Redact(v interface{}) interface{} {
+	return redactValue(reflect.ValueOf(v))
+}
+
+func redactValue(v reflect.Value) interface{} {
+	if !v.IsValid() {
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return redactValue(v.Elem())
+	case reflect.Struct:
+		t := v.Type()
+		out := make(map[string]interface{}, t.NumField())
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			name := jsonFieldName(field)
+			if name == "" {
+				continue
+			}
+			if isSensitive(field) {
+				out[name] = redactedValue
+				continue
+			}
+			out[name] = redactValue(v.Field(i))
+		}
+		return out
+	case reflect.Map:
+		out := make(map[string]interface{}, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			out[fmt.Sprint(iter.Key().Interface())] = redactValue(iter.Value())
+		}
+		return out
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil
+		}
+		out := make([]interface{}, v.Len())
+		for i := range out {
+			out[i] = redactValue(v.Index(i))
+		}
+		return out
+	default:
+		return v.Interface()
+	}
+}
+
+// redactString masks every sensitive string value found in args within msg.
+// It guards error messages that echo argument values back to the client.
+func redactString(msg string, args interface{}) string {
+	secrets := sensitiveValues(reflect.ValueOf(args), nil)
+	if len(secrets) == 0 {
+		return msg
+	}
+
+	// Replace longer values first so a secret that contains another is not
+	// left partially visible.
+	sort.Slice(secrets, func(i, j int) bool { return len(secrets[i]) > len(secrets[j]) })
+	for _, secret := range secrets {
+		msg = strings.ReplaceAll(msg, secret, redactedValue)
+	}
+	return msg
+}
+
+func sensitiveValues(v reflect.Value, out []string) []string {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return out
+		}
+		return sensitiveValues(v.Elem(), out)
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			if isSensitive(field) {
+				out = secretValues(v.Field(i), out)
+				continue
+			}
+			out = sensitiveValues(v.Field(i), out)
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			out = sensitiveValues(iter.Value(), out)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			out = sensitiveValues(v.Index(i), out)
+		}
+	}
+	return out
+}
+
+// secretValues appends the values held by a sensitive field, looking
+// through pointers and collections so that neither addresses nor the
+// formatting of a whole slice or map are taken for the secret. Byte slices
+// are taken as strings.
+func secretValues(v reflect.Value, out []string) []string {
+	var s string
+	switch v.Kind() {
+	case reflect.Invalid:
+		return out
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return out
+		}
+		return secretValues(v.Elem(), out)
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			out = secretValues(iter.Value(), out)
+		}
+		return out
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			s = fmt.Sprintf("%s", v.Interface())
+			break
+		}
+		for i := 0; i < v.Len(); i++ {
+			out = secretValues(v.Index(i), out)
+		}
+		return out
+	default:
+		s = fmt.Sprint(v.Interface())
+	}
+	if s != "" {
+		out = append(out, s)
+	}
+	return out
+}
+
+func jsonFieldName(field reflect.StructField) string {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return ""
+	}
+	if name := strings.Split(tag, ",")[0]; name != "" {
+		return name
+	}
+	return field.Name
+}
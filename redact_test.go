@@ -0,0 +1,92 @@
+package mcpwrapper
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+type CredentialArgs struct {
+	Service string `json:"service" validate:"required"`
+	APIKey  string `json:"api_key" redact:"true" validate:"required"`
+}
+
+func TestRedact(t *testing.T) {
+	redacted := Redact(&CredentialArgs{Service: "github", APIKey: "sk-12345"}).(map[string]interface{})
+
+	if redacted["service"] != "github" {
+		t.Errorf("Expected service 'github', got '%v'", redacted["service"])
+	}
+
+	if redacted["api_key"] != "[REDACTED]" {
+		t.Errorf("Expected api_key to be redacted, got '%v'", redacted["api_key"])
+	}
+}
+
+func TestHandlerErrorRedaction(t *testing.T) {
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	wrapper := New(mcpServer)
+
+	handler := func(ctx context.Context, args interface{}) (interface{}, error) {
+		a := args.(*CredentialArgs)
+		return nil, fmt.Errorf("key %s rejected by %s", a.APIKey, a.Service)
+	}
+
+	if err := wrapper.Register("login", "Login", CredentialArgs{}, handler); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	request := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name:      "login",
+			Arguments: map[string]interface{}{"service": "github", "api_key": "sk-12345"},
+		},
+	}
+
+	result, err := mcpServer.GetTool("login").Handler(context.Background(), request)
+	if err != nil {
+		t.Fatalf("Handler invocation failed: %v", err)
+	}
+
+	text := result.Content[0].(mcp.TextContent).Text
+	if strings.Contains(text, "sk-12345") {
+		t.Errorf("Error result leaks secret: %s", text)
+	}
+
+	if text != "handler error: key [REDACTED] rejected by github" {
+		t.Errorf("Unexpected error text: %s", text)
+	}
+}
+
+type vaultArgs struct {
+	Token   *string           `json:"token" redact:"true"`
+	Keys    []string          `json:"keys" redact:"true"`
+	Headers map[string]string `json:"headers" redact:"true"`
+	Cert    []byte            `json:"cert" redact:"true"`
+}
+
+func TestRedactStringPointersAndMaps(t *testing.T) {
+	token := "tok-secret"
+	args := &vaultArgs{
+		Token:   &token,
+		Keys:    []string{"key-one", "key-two"},
+		Headers: map[string]string{"Authorization": "Bearer abc"},
+		Cert:    []byte("cert-data"),
+	}
+	msg := redactString("tok-secret key-one key-two Bearer abc cert-data", args)
+	if msg != "[REDACTED] [REDACTED] [REDACTED] [REDACTED] [REDACTED]" {
+		t.Errorf("Expected every secret to be masked, got %q", msg)
+	}
+	if msg := redactString("stored at 0xc000010000", &vaultArgs{}); msg != "stored at 0xc000010000" {
+		t.Errorf("Expected unset fields to mask nothing, got %q", msg)
+	}
+
+	byName := map[string]interface{}{"prod": &CredentialArgs{Service: "github", APIKey: "sk-12345"}}
+	if msg := redactString("key sk-12345 for github", byName); msg != "key [REDACTED] for github" {
+		t.Errorf("Expected secrets in map values to be masked, got %q", msg)
+	}
+}
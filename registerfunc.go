@@ -0,0 +1,89 @@
+package mcpwrapper
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+var (
+	contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+	errorType   = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// RegisterFunc registers a typed handler without a type assertion in its
+// body. fn must have the signature
+//
+//	func(ctx context.Context, args *MyArgs, deps ...) (*MyResult, error)
+//
+// The args type is taken from the second parameter, which may also be a
+// struct value, and the result may be any type. Further parameters are
+// filled with values given to Provide. The signature and the dependencies
+// are checked once here, so a mismatch fails registration instead of a call.
+func (w *Wrapper) RegisterFunc(name, description string, fn interface{}, opts ...ToolOption) error {
+	handler, argsType, err := funcHandler(fn, w.deps)
+	if err != nil {
+		return fmt.Errorf("invalid handler for tool %s: %w", name, err)
+	}
+	return w.Register(name, description, argsType, handler, opts...)
+}
+
+// funcHandler adapts fn to a Handler and returns the zero value of its args
+// type. Parameters after the args are resolved from deps.
+func funcHandler(fn interface{}, deps *dependencies) (Handler, interface{}, error) {
+	fv := reflect.ValueOf(fn)
+	if fv.Kind() != reflect.Func || fv.IsNil() {
+		return nil, nil, fmt.Errorf("expected a func, got %T", fn)
+	}
+	ft := fv.Type()
+	if ft.IsVariadic() || ft.NumIn() < 2 || ft.In(0) != contextType ||
+		ft.NumOut() != 2 || ft.Out(1) != errorType {
+		return nil, nil, fmt.Errorf("expected func(context.Context, *Args, deps...) (Result, error), got %s", ft)
+	}
+
+	argType := ft.In(1)
+	byValue := argType.Kind() == reflect.Struct
+	if !byValue && (argType.Kind() != reflect.Ptr || argType.Elem().Kind() != reflect.Struct) {
+		return nil, nil, fmt.Errorf("args parameter must be a struct or a pointer to one, got %s", argType)
+	}
+	structType := argType
+	if !byValue {
+		structType = argType.Elem()
+	}
+
+	in := make([]reflect.Value, ft.NumIn())
+	for i := 2; i < ft.NumIn(); i++ {
+		dep, err := deps.resolve(ft.In(i))
+		if err != nil {
+			return nil, nil, fmt.Errorf("parameter %d: %w", i+1, err)
+		}
+		in[i] = dep
+	}
+
+	handler := func(ctx context.Context, args interface{}) (interface{}, error) {
+		av := reflect.ValueOf(args)
+		if !av.IsValid() || av.Type() != reflect.PtrTo(structType) {
+			return nil, fmt.Errorf("expected args of type *%s, got %T", structType, args)
+		}
+		if byValue {
+			av = av.Elem()
+		}
+
+		call := append([]reflect.Value{reflect.ValueOf(ctx), av}, in[2:]...)
+		out := fv.Call(call)
+		var err error
+		if e := out[1].Interface(); e != nil {
+			err = e.(error)
+		}
+		result := out[0]
+		switch result.Kind() {
+		case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice:
+			// A typed nil would otherwise be encoded as a non-nil result.
+			if result.IsNil() {
+				return nil, err
+			}
+		}
+		return result.Interface(), err
+	}
+	return handler, reflect.New(structType).Elem().Interface(), nil
+}
@@ -0,0 +1,90 @@
+package mcpwrapper
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func TestRegisterFunc(t *testing.T) {
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	wrapper := New(mcpServer)
+
+	err := wrapper.RegisterFunc("greet", "Greet someone", func(ctx context.Context, args *TestArgs) (*TestResult, error) {
+		return &TestResult{Message: "hello " + args.Name}, nil
+	})
+	if err != nil {
+		t.Fatalf("RegisterFunc failed: %v", err)
+	}
+	err = wrapper.RegisterFunc("echo", "Echo by value", func(ctx context.Context, args TestArgs) (string, error) {
+		return args.Category, nil
+	})
+	if err != nil {
+		t.Fatalf("RegisterFunc failed: %v", err)
+	}
+	err = wrapper.RegisterFunc("fail", "Always fails", func(ctx context.Context, args *TestArgs) (*TestResult, error) {
+		return nil, NotFound("no %s", args.Name)
+	})
+	if err != nil {
+		t.Fatalf("RegisterFunc failed: %v", err)
+	}
+
+	if tool := mcpServer.GetTool("greet"); !contains(tool.Tool.InputSchema.Required, "name") {
+		t.Errorf("Expected the schema to be built from the args type, got %v", tool.Tool.InputSchema)
+	}
+
+	args := map[string]interface{}{"name": "Ada", "age": 36, "category": "B"}
+	result := callTool(t, mcpServer, "greet", args)
+	if result.IsError || !strings.Contains(result.Content[0].(mcp.TextContent).Text, "hello Ada") {
+		t.Errorf("Unexpected greet result: %v", result.Content)
+	}
+	result = callTool(t, mcpServer, "echo", args)
+	if result.IsError || result.Content[0].(mcp.TextContent).Text != "B" {
+		t.Errorf("Unexpected echo result: %v", result.Content)
+	}
+	result = callTool(t, mcpServer, "fail", args)
+	if !result.IsError || !strings.Contains(result.Content[0].(mcp.TextContent).Text, "not_found: no Ada") {
+		t.Errorf("Unexpected fail result: %v", result.Content)
+	}
+	result = callTool(t, mcpServer, "greet", map[string]interface{}{"name": "Ada"})
+	if !result.IsError {
+		t.Error("Expected validation to run before the func")
+	}
+}
+
+func TestRegisterFuncSignature(t *testing.T) {
+	wrapper := New(server.NewMCPServer("test", "1.0.0"))
+	for _, fn := range []interface{}{
+		nil,
+		"not a func",
+		func(args *TestArgs) (*TestResult, error) { return nil, nil },
+		func(ctx context.Context, args *TestArgs) *TestResult { return nil },
+		func(ctx context.Context, args *TestArgs) (*TestResult, string) { return nil, "" },
+		func(ctx context.Context, name string) (string, error) { return name, nil },
+		func(ctx context.Context, args ...TestArgs) (string, error) { return "", nil },
+	} {
+		if err := wrapper.RegisterFunc("bad", "Bad", fn); err == nil {
+			t.Errorf("Expected %T to be rejected", fn)
+		}
+	}
+
+	var typedNil func(ctx context.Context, args *TestArgs) (*TestResult, error)
+	if err := wrapper.RegisterFunc("bad", "Bad", typedNil); err == nil {
+		t.Error("Expected a nil func to be rejected")
+	}
+
+	handler, _, err := funcHandler(func(ctx context.Context, args *TestArgs) (*TestResult, error) {
+		return nil, errors.New("boom")
+	}, &dependencies{})
+	if err != nil {
+		t.Fatalf("funcHandler failed: %v", err)
+	}
+	result, err := handler(context.Background(), &TestArgs{})
+	if result != nil || err == nil || err.Error() != "boom" {
+		t.Errorf("Expected an untyped nil result and the error, got %#v, %v", result, err)
+	}
+}
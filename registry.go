@@ -0,0 +1,126 @@
+package mcpwrapper
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Registry holds what several wrappers can share: the validator (with any
+// custom validations), Go type to JSON Schema mappings, middleware, and the
+// cache of built schemas. Serving the same tools over several transports
+// with one Registry configures all of this once and builds each schema once.
+//
+// Every Wrapper created without WithRegistry gets a private Registry.
+type Registry struct {
+	validator *validator.Validate
+
+	mu         sync.RWMutex
+	middleware []Middleware
+	types      map[reflect.Type]map[string]interface{}
+	enums      map[reflect.Type][]interface{}
+	schemas    map[reflect.Type]*mcp.ToolInputSchema
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		validator: validator.New(),
+		types:     make(map[reflect.Type]map[string]interface{}),
+		enums:     make(map[reflect.Type][]interface{}),
+		schemas:   make(map[reflect.Type]*mcp.ToolInputSchema),
+	}
+}
+
+// WithRegistry makes the wrapper use a shared Registry. Middleware added to
+// the Registry runs outside the wrapper's own WithMiddleware.
+func WithRegistry(r *Registry) Option {
+	return func(w *Wrapper) {
+		w.registry = r
+	}
+}
+
+// Validator returns the shared validator, for example to add custom
+// validations with RegisterValidation.
+func (r *Registry) Validator() *validator.Validate {
+	return r.validator
+}
+
+// Use adds middleware to every tool of every wrapper using the Registry.
+// It only affects tools registered afterwards.
+func (r *Registry) Use(mw ...Middleware) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.middleware = append(r.middleware, mw...)
+}
+
+// SchemaFragment is the JSON Schema of a single value, such as
+// {"type": "string", "format": "uuid"}.
+type SchemaFragment = map[string]interface{}
+
+// MapType sets the JSON Schema of fields whose type is that of value, such
+// as {"type": "string", "format": "uuid"} for a UUID type. value may also
+// be a reflect.Type. jsonschema tags on the field still apply on top. Since
+// it changes schemas, it clears the schema cache; call it before registering
+// tools.
+func (r *Registry) MapType(value interface{}, schema SchemaFragment) {
+	t, ok := value.(reflect.Type)
+	if !ok {
+		t = reflect.TypeOf(value)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.types[t] = schema
+	r.schemas = make(map[reflect.Type]*mcp.ToolInputSchema)
+}
+
+func (r *Registry) middlewares() []Middleware {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return append([]Middleware(nil), r.middleware...)
+}
+
+func (r *Registry) mappedType(t reflect.Type) (map[string]interface{}, bool) {
+	schema, ok := r.types[t]
+	return schema, ok
+}
+
+// schema returns the input schema for argsType, building it once per type.
+// Callers get their own copy, since tool options may modify it.
+func (r *Registry) schema(argsType interface{}) (*mcp.ToolInputSchema, error) {
+	t := reflect.TypeOf(argsType)
+
+	r.mu.RLock()
+	cached, ok := r.schemas[t]
+	r.mu.RUnlock()
+	if ok {
+		return cloneSchema(cached), nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	schema, err := buildSchemaWith(argsType, r.mappedType)
+	if err != nil {
+		return nil, err
+	}
+	r.schemas[t] = schema
+	return cloneSchema(schema), nil
+}
+
+func cloneSchema(s *mcp.ToolInputSchema) *mcp.ToolInputSchema {
+	clone := *s
+	clone.Properties = make(map[string]interface{}, len(s.Properties))
+	for name, prop := range s.Properties {
+		if m, ok := prop.(map[string]interface{}); ok {
+			prop = cloneMap(m)
+		}
+		clone.Properties[name] = prop
+	}
+	if s.Defs != nil {
+		clone.Defs = cloneMap(s.Defs)
+	}
+	clone.Required = append([]string{}, s.Required...)
+	return &clone
+}
@@ -0,0 +1,185 @@
+package mcpwrapper
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+type ScheduleArgs struct {
+	Room  string     `json:"room" validate:"required,room"`
+	At    time.Time  `json:"at" jsonschema:"description=Start time"`
+	Until *time.Time `json:"until"`
+}
+
+func TestRegistrySharedAcrossWrappers(t *testing.T) {
+	registry := NewRegistry()
+	registry.MapType(time.Time{}, map[string]interface{}{"type": "string", "format": "date-time"})
+	if err := registry.Validator().RegisterValidation("room", func(fl validator.FieldLevel) bool {
+		return strings.HasPrefix(fl.Field().String(), "room-")
+	}); err != nil {
+		t.Fatalf("RegisterValidation failed: %v", err)
+	}
+
+	var calls []string
+	registry.Use(func(next Handler) Handler {
+		return func(ctx context.Context, args interface{}) (interface{}, error) {
+			calls = append(calls, ToolNameFromContext(ctx))
+			return next(ctx, args)
+		}
+	})
+
+	handler := func(ctx context.Context, args interface{}) (interface{}, error) {
+		return &TestResult{Message: "booked"}, nil
+	}
+
+	var servers []*server.MCPServer
+	for i := 0; i < 2; i++ {
+		mcpServer := server.NewMCPServer("test", "1.0.0")
+		wrapper := New(mcpServer, WithRegistry(registry))
+		if err := wrapper.Register("book_room", "Book a room", ScheduleArgs{}, handler); err != nil {
+			t.Fatalf("Register failed: %v", err)
+		}
+		servers = append(servers, mcpServer)
+	}
+
+	if len(registry.schemas) != 1 {
+		t.Errorf("Expected one cached schema, got %d", len(registry.schemas))
+	}
+
+	for _, mcpServer := range servers {
+		tool := mcpServer.GetTool("book_room")
+		at := tool.Tool.InputSchema.Properties["at"].(map[string]interface{})
+		if at["type"] != "string" || at["format"] != "date-time" || at["description"] != "Start time" {
+			t.Errorf("Expected mapped time schema, got %v", at)
+		}
+		until := tool.Tool.InputSchema.Properties["until"].(map[string]interface{})
+		if until["format"] != "date-time" {
+			t.Errorf("Expected pointer fields to be mapped, got %v", until)
+		}
+
+		request := mcp.CallToolRequest{
+			Params: mcp.CallToolParams{
+				Name:      "book_room",
+				Arguments: map[string]interface{}{"room": "kitchen", "at": "2026-01-02T15:04:05Z"},
+			},
+		}
+		result, err := tool.Handler(context.Background(), request)
+		if err != nil {
+			t.Fatalf("Handler returned error: %v", err)
+		}
+		if !result.IsError {
+			t.Error("Expected the shared custom validation to reject the room")
+		}
+
+		request.Params.Arguments = map[string]interface{}{"room": "room-1", "at": "2026-01-02T15:04:05Z"}
+		if result, err := tool.Handler(context.Background(), request); err != nil || result.IsError {
+			t.Fatalf("Handler invocation failed: %v %v", err, result)
+		}
+	}
+
+	if len(calls) != 2 {
+		t.Errorf("Expected shared middleware to run once per wrapper, got %v", calls)
+	}
+}
+
+func TestRegistrySchemaCopies(t *testing.T) {
+	registry := NewRegistry()
+	first, err := registry.schema(TestArgs{})
+	if err != nil {
+		t.Fatalf("schema failed: %v", err)
+	}
+	first.Properties["name"].(map[string]interface{})["description"] = "changed"
+	first.Required = append(first.Required, "extra")
+
+	second, err := registry.schema(TestArgs{})
+	if err != nil {
+		t.Fatalf("schema failed: %v", err)
+	}
+	if second.Properties["name"].(map[string]interface{})["description"] != "Test name" {
+		t.Error("Expected changes to a returned schema not to reach the cache")
+	}
+	if contains(second.Required, "extra") {
+		t.Error("Expected required list to be copied")
+	}
+}
+
+// OrderID is bound from strings like "ord-42".
+type OrderID struct {
+	N int
+}
+
+func (id *OrderID) UnmarshalText(text []byte) error {
+	_, err := fmt.Sscanf(string(text), "ord-%d", &id.N)
+	return err
+}
+
+type Amount struct {
+	Cents int64
+}
+
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	var units, cents int64
+	if _, err := fmt.Sscanf(s, "%d.%d", &units, &cents); err != nil {
+		return err
+	}
+	a.Cents = units*100 + cents
+	return nil
+}
+
+type RefundArgs struct {
+	Order   OrderID         `json:"order" jsonschema:"required"`
+	Amount  *Amount         `json:"amount"`
+	Details json.RawMessage `json:"details" jsonschema:"description=Free-form details"`
+}
+
+func TestUnmarshalerFields(t *testing.T) {
+	registry := NewRegistry()
+	registry.MapType(reflect.TypeOf(OrderID{}), SchemaFragment{"type": "string", "pattern": "^ord-[0-9]+$"})
+
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	wrapper := New(mcpServer, WithRegistry(registry))
+	var got RefundArgs
+	handler := func(ctx context.Context, args interface{}) (interface{}, error) {
+		got = *args.(*RefundArgs)
+		return "ok", nil
+	}
+	if err := wrapper.Register("refund", "Refund an order", RefundArgs{}, handler); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	props := mcpServer.GetTool("refund").Tool.InputSchema.Properties
+	if order := props["order"].(map[string]interface{}); order["type"] != "string" || order["pattern"] != "^ord-[0-9]+$" {
+		t.Errorf("Unexpected order schema: %v", order)
+	}
+	if amount := props["amount"].(map[string]interface{}); amount["type"] != "string" {
+		t.Errorf("Expected a json.Unmarshaler to be a string, got %v", amount)
+	}
+	if details := props["details"].(map[string]interface{}); details["type"] != nil || details["description"] != "Free-form details" {
+		t.Errorf("Expected json.RawMessage to accept any value, got %v", details)
+	}
+
+	result := callTool(t, mcpServer, "refund", map[string]interface{}{
+		"order":   "ord-42",
+		"amount":  "12.50",
+		"details": map[string]interface{}{"reason": "damaged"},
+	})
+	if result.IsError {
+		t.Fatalf("Call failed: %v", result.Content)
+	}
+	if got.Order.N != 42 || got.Amount.Cents != 1250 || string(got.Details) != `{"reason":"damaged"}` {
+		t.Errorf("Unexpected bound args: %+v %+v %s", got.Order, got.Amount, got.Details)
+	}
+}
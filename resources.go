@@ -0,0 +1,134 @@
+package mcpwrapper
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// ResourceHandler returns the current contents of a resource. A string is
+// served as text/plain; anything else is marshalled to JSON.
+type ResourceHandler func(ctx context.Context, uri string) (interface{}, error)
+
+type resourceRegistry struct {
+	mu          sync.Mutex
+	uris        map[string]bool
+	subscribers map[string]map[string]bool
+}
+
+func newResourceRegistry() *resourceRegistry {
+	return &resourceRegistry{
+		uris:        make(map[string]bool),
+		subscribers: make(map[string]map[string]bool),
+	}
+}
+
+func (r *resourceRegistry) add(uri string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.uris[uri] = true
+}
+
+func (r *resourceRegistry) has(uri string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.uris[uri]
+}
+
+func (r *resourceRegistry) subscribe(uri, sessionID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.subscribers[uri] == nil {
+		r.subscribers[uri] = make(map[string]bool)
+	}
+	r.subscribers[uri][sessionID] = true
+}
+
+func (r *resourceRegistry) unsubscribe(uri, sessionID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.subscribers[uri], sessionID)
+}
+
+func (r *resourceRegistry) subscribersOf(uri string) []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ids := make([]string, 0, len(r.subscribers[uri]))
+	for id := range r.subscribers[uri] {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+func (r *resourceRegistry) end(sessionID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, sessions := range r.subscribers {
+		delete(sessions, sessionID)
+	}
+}
+
+// RegisterResource exposes a resource whose contents are produced by handler
+// on every read. Clients may subscribe to it (see HandleMessage) and are told
+// to re-read it whenever NotifyResourceUpdated is called.
+func (w *Wrapper) RegisterResource(uri, name, description string, handler ResourceHandler) error {
+	if handler == nil {
+		return fmt.Errorf("handler for resource %s must not be nil", uri)
+	}
+
+	resource := mcp.NewResource(uri, name, mcp.WithResourceDescription(description))
+	w.server.AddResource(resource, w.createResourceHandler(uri, handler))
+	w.resources.add(uri)
+	w.logger.Info("registered resource", "uri", uri)
+	return nil
+}
+
+func (w *Wrapper) createResourceHandler(uri string, handler ResourceHandler) func(context.Context, mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	return func(ctx context.Context, request mcp.ReadResourceRequest) (contents []mcp.ResourceContents, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				panicErr := newPanicError(r)
+				w.logger.Error("resource panicked", "uri", uri, "panic", panicErr.Value, "stack", string(panicErr.Stack))
+				contents, err = nil, panicErr
+			}
+		}()
+
+		value, err := handler(ctx, uri)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read resource %s: %w", uri, err)
+		}
+
+		if text, ok := value.(string); ok {
+			return []mcp.ResourceContents{mcp.TextResourceContents{URI: uri, MIMEType: "text/plain", Text: text}}, nil
+		}
+
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal resource %s: %w", uri, err)
+		}
+		return []mcp.ResourceContents{mcp.TextResourceContents{URI: uri, MIMEType: "application/json", Text: string(data)}}, nil
+	}
+}
+
+// NotifyResourceUpdated sends notifications/resources/updated to every
+// session subscribed to uri. Sessions that have disconnected are silently
+// unsubscribed.
+func (w *Wrapper) NotifyResourceUpdated(uri string) error {
+	var errs []error
+	for _, sessionID := range w.resources.subscribersOf(uri) {
+		err := w.server.SendNotificationToSpecificClient(sessionID, mcp.MethodNotificationResourceUpdated, map[string]any{"uri": uri})
+		if errors.Is(err, server.ErrSessionNotFound) {
+			w.resources.unsubscribe(uri, sessionID)
+			continue
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("session %s: %w", sessionID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,88 @@
+package mcpwrapper
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func TestResourceSubscriptions(t *testing.T) {
+	mcpServer := server.NewMCPServer("test", "1.0.0", server.WithResourceCapabilities(true, false))
+	wrapper := New(mcpServer)
+
+	status := "green"
+	err := wrapper.RegisterResource("status://build", "Build status", "Current build status", func(ctx context.Context, uri string) (interface{}, error) {
+		return map[string]string{"status": status}, nil
+	})
+	if err != nil {
+		t.Fatalf("RegisterResource failed: %v", err)
+	}
+
+	session := &notifyingSession{id: "session-1", notifications: make(chan mcp.JSONRPCNotification, 10)}
+	ctx := mcpServer.WithContext(context.Background(), session)
+	if err := mcpServer.RegisterSession(ctx, session); err != nil {
+		t.Fatalf("RegisterSession failed: %v", err)
+	}
+
+	response := wrapper.HandleMessage(ctx, json.RawMessage(`{"jsonrpc": "2.0", "id": 1, "method": "resources/read", "params": {"uri": "status://build"}}`))
+	contents := response.(mcp.JSONRPCResponse).Result.(mcp.ReadResourceResult).Contents
+	if text := contents[0].(mcp.TextResourceContents).Text; text != `{"status":"green"}` {
+		t.Errorf("Unexpected resource contents: %s", text)
+	}
+
+	response = wrapper.HandleMessage(ctx, json.RawMessage(`{"jsonrpc": "2.0", "id": 2, "method": "resources/subscribe", "params": {"uri": "status://build"}}`))
+	if _, ok := response.(mcp.JSONRPCResponse); !ok {
+		t.Fatalf("Expected subscribe to succeed, got %#v", response)
+	}
+
+	status = "red"
+	if err := wrapper.NotifyResourceUpdated("status://build"); err != nil {
+		t.Fatalf("NotifyResourceUpdated failed: %v", err)
+	}
+
+	select {
+	case n := <-session.notifications:
+		if n.Method != "notifications/resources/updated" || n.Params.AdditionalFields["uri"] != "status://build" {
+			t.Errorf("Unexpected notification: %+v", n)
+		}
+	default:
+		t.Fatal("Expected an update notification")
+	}
+
+	wrapper.HandleMessage(ctx, json.RawMessage(`{"jsonrpc": "2.0", "id": 3, "method": "resources/unsubscribe", "params": {"uri": "status://build"}}`))
+	wrapper.NotifyResourceUpdated("status://build")
+	if len(session.notifications) != 0 {
+		t.Error("Expected no notification after unsubscribing")
+	}
+
+	response = wrapper.HandleMessage(ctx, json.RawMessage(`{"jsonrpc": "2.0", "id": 4, "method": "resources/subscribe", "params": {"uri": "status://unknown"}}`))
+	if rpcErr, ok := response.(mcp.JSONRPCError); !ok || rpcErr.Error.Code != mcp.RESOURCE_NOT_FOUND {
+		t.Errorf("Expected resource not found, got %#v", response)
+	}
+}
+
+func TestResourceSubscriptionOfGoneSession(t *testing.T) {
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	wrapper := New(mcpServer)
+
+	wrapper.RegisterResource("status://build", "Build status", "", func(ctx context.Context, uri string) (interface{}, error) {
+		return "green", nil
+	})
+
+	ctx := mcpServer.WithContext(context.Background(), &testSession{id: "gone"})
+	wrapper.HandleMessage(ctx, json.RawMessage(`{"jsonrpc": "2.0", "id": 1, "method": "resources/subscribe", "params": {"uri": "status://build"}}`))
+
+	if err := wrapper.NotifyResourceUpdated("status://build"); err != nil {
+		t.Errorf("Expected disconnected sessions to be skipped, got %v", err)
+	}
+	if len(wrapper.resources.subscribersOf("status://build")) != 0 {
+		t.Error("Expected the disconnected session to be unsubscribed")
+	}
+
+	if err := wrapper.RegisterResource("status://other", "Other", "", nil); err == nil {
+		t.Error("Expected an error for a nil resource handler")
+	}
+}
@@ -0,0 +1,216 @@
+package mcpwrapper
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Truncation shrinks a serialized result that is larger than limit bytes.
+// It returns the text to send instead, at most limit bytes long, or an
+// error to fail the call with. TruncateHead, TruncateTail,
+// TruncateSummarize and TruncateError are the built-in strategies.
+type Truncation func(text string, limit int) (string, error)
+
+type resultLimit struct {
+	limit      int
+	truncation Truncation
+}
+
+// WithMaxResultSize limits the serialized result of every tool to limit
+// bytes, so a tool that accidentally returns megabytes of JSON doesn't fill
+// the client's context window. A larger result is passed through
+// truncation, drops its structured content, and is marked "truncated" in
+// its _meta. A nil truncation is TruncateHead.
+func WithMaxResultSize(limit int, truncation Truncation) Option {
+	return func(w *Wrapper) {
+		w.resultLimit = newResultLimit(limit, truncation)
+	}
+}
+
+// WithToolMaxResultSize overrides WithMaxResultSize for one tool. A limit of
+// 0 lifts the limit for the tool.
+func WithToolMaxResultSize(limit int, truncation Truncation) ToolOption {
+	return func(o *toolOptions) {
+		o.resultLimit = newResultLimit(limit, truncation)
+		if o.resultLimit == nil {
+			o.resultLimit = &resultLimit{}
+		}
+	}
+}
+
+func newResultLimit(limit int, truncation Truncation) *resultLimit {
+	if limit <= 0 {
+		return nil
+	}
+	if truncation == nil {
+		truncation = TruncateHead
+	}
+	return &resultLimit{limit: limit, truncation: truncation}
+}
+
+// textResult builds the result of a successful call from its serialized
+// form, offloading it or applying the result size limit.
+func (w *Wrapper) textResult(ctx context.Context, t *registeredTool, text string, structured map[string]interface{}) *mcp.CallToolResult {
+	if w.shouldOffload(t, text) {
+		return w.offloadResult(ctx, t, text)
+	}
+
+	limit := w.resultLimit
+	if t.options.resultLimit != nil {
+		limit = t.options.resultLimit
+	}
+
+	if limit == nil || limit.limit <= 0 || len(text) <= limit.limit {
+		result := mcp.NewToolResultText(text)
+		if structured != nil {
+			result.StructuredContent = structured
+		}
+		return result
+	}
+
+	w.logger.Warn("tool result exceeds result size limit", "tool", t.name, "bytes", len(text), "limit", limit.limit)
+	truncated, err := limit.truncation(text, limit.limit)
+	if err != nil {
+		if result := w.formatError(ctx, t, err); result != nil {
+			return result
+		}
+		code, ok := CodeFromError(err)
+		if !ok {
+			code = CodeTooLarge
+		}
+		return codedErrorResult(code, err.Error())
+	}
+
+	result := mcp.NewToolResultText(truncated)
+	result.Meta = mcp.NewMetaFromMap(map[string]interface{}{
+		"truncated":      true,
+		"original_bytes": len(text),
+	})
+	return result
+}
+
+// TruncateHead keeps the beginning of the result and appends a marker
+// saying how much was shown. Below the marker's length, limit leaves room
+// only for the start of the marker.
+func TruncateHead(text string, limit int) (string, error) {
+	marker := func(shown int) string {
+		return fmt.Sprintf("\n...[truncated: %d of %d bytes shown]", shown, len(text))
+	}
+	keep := limit - len(marker(len(text)))
+	if keep < 0 {
+		return fitMarker(marker(0), limit), nil
+	}
+	for keep > 0 && !utf8.RuneStart(text[keep]) {
+		keep--
+	}
+	return text[:keep] + marker(keep), nil
+}
+
+// TruncateTail keeps the end of the result, for output such as logs where
+// the latest lines matter most, behind a marker saying how much was shown.
+// Below the marker's length, limit leaves room only for the start of the
+// marker.
+func TruncateTail(text string, limit int) (string, error) {
+	marker := func(shown int) string {
+		return fmt.Sprintf("[truncated: last %d of %d bytes shown]...\n", shown, len(text))
+	}
+	keep := limit - len(marker(len(text)))
+	if keep < 0 {
+		return fitMarker(marker(0), limit), nil
+	}
+	start := len(text) - keep
+	for start < len(text) && !utf8.RuneStart(text[start]) {
+		start++
+	}
+	return marker(len(text)-start) + text[start:], nil
+}
+
+// fitMarker cuts a truncation marker, which is ASCII, to at most limit bytes.
+func fitMarker(marker string, limit int) string {
+	if limit < 0 {
+		limit = 0
+	}
+	if len(marker) > limit {
+		return marker[:limit]
+	}
+	return marker
+}
+
+// TruncateSummarize keeps a JSON result valid by shortening its largest
+// array, either the result itself or one of its top-level fields, to the
+// leading items that fit, followed by a "... N more items" entry. Results
+// that are not JSON or have no array are cut with TruncateHead.
+func TruncateSummarize(text string, limit int) (string, error) {
+	var value interface{}
+	decoder := json.NewDecoder(strings.NewReader(text))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil {
+		return TruncateHead(text, limit)
+	}
+
+	items, set := largestArray(&value)
+	if len(items) == 0 {
+		return TruncateHead(text, limit)
+	}
+
+	encode := func(shown int) (string, bool) {
+		summary := make([]interface{}, shown, shown+1)
+		copy(summary, items)
+		summary = append(summary, fmt.Sprintf("... %d more items", len(items)-shown))
+		set(summary)
+		data, err := json.Marshal(value)
+		return string(data), err == nil && len(data) <= limit
+	}
+
+	if _, ok := encode(0); !ok {
+		return TruncateHead(text, limit)
+	}
+	lo, hi := 0, len(items)-1
+	for lo < hi {
+		mid := (lo + hi + 1) / 2
+		if _, ok := encode(mid); ok {
+			lo = mid
+		} else {
+			hi = mid - 1
+		}
+	}
+	out, _ := encode(lo)
+	return out, nil
+}
+
+// largestArray returns the array *value holds, or else its top-level field
+// holding the most items, with a function that replaces it.
+func largestArray(value *interface{}) ([]interface{}, func(interface{})) {
+	switch v := (*value).(type) {
+	case []interface{}:
+		return v, func(r interface{}) { *value = r }
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		var largest []interface{}
+		var key string
+		for _, k := range keys {
+			if items, ok := v[k].([]interface{}); ok && len(items) > len(largest) {
+				largest, key = items, k
+			}
+		}
+		return largest, func(r interface{}) { v[key] = r }
+	}
+	return nil, nil
+}
+
+// TruncateError fails the call with a too_large error instead of sending a
+// partial result, so the caller narrows the request.
+func TruncateError(text string, limit int) (string, error) {
+	return "", Errorf(CodeTooLarge, "result is %d bytes, over the limit of %d bytes; narrow the request", len(text), limit)
+}
@@ -0,0 +1,112 @@
+package mcpwrapper
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func TestTruncationStrategies(t *testing.T) {
+	text := strings.Repeat("a", 500) + strings.Repeat("é", 250) + strings.Repeat("z", 500)
+
+	head, _ := TruncateHead(text, 200)
+	if len(head) > 200 || !strings.HasPrefix(head, "aaaa") || !strings.Contains(head, "of 1500 bytes shown]") {
+		t.Errorf("Unexpected head truncation: %q", head)
+	}
+
+	tail, _ := TruncateTail(strings.Repeat("é", 300), 101)
+	if len(tail) > 101 || !strings.HasSuffix(tail, "é") || !strings.Contains(tail, "of 600 bytes shown]") {
+		t.Errorf("Unexpected tail truncation: %q", tail)
+	}
+	if !json.Valid([]byte(`"` + tail[strings.Index(tail, "\n")+1:] + `"`)) {
+		t.Errorf("Expected the tail to start on a rune boundary: %q", tail)
+	}
+
+	for _, truncate := range []Truncation{TruncateHead, TruncateTail, TruncateSummarize} {
+		for _, limit := range []int{0, 5, 20} {
+			if out, _ := truncate(text, limit); len(out) > limit {
+				t.Errorf("Expected at most %d bytes, got %q", limit, out)
+			}
+		}
+	}
+
+	if _, err := TruncateError(text, 200); err == nil {
+		t.Error("Expected TruncateError to fail")
+	} else if code, _ := CodeFromError(err); code != CodeTooLarge {
+		t.Errorf("Expected code %s, got %s", CodeTooLarge, code)
+	}
+}
+
+func TestTruncateSummarize(t *testing.T) {
+	items := make([]map[string]interface{}, 100)
+	for i := range items {
+		items[i] = map[string]interface{}{"id": i, "name": "member"}
+	}
+	data, _ := json.Marshal(map[string]interface{}{"team": "core", "members": items, "tags": []string{"a"}})
+
+	out, err := TruncateSummarize(string(data), 300)
+	if err != nil {
+		t.Fatalf("TruncateSummarize failed: %v", err)
+	}
+	if len(out) > 300 {
+		t.Errorf("Expected at most 300 bytes, got %d", len(out))
+	}
+	var summarized struct {
+		Team    string        `json:"team"`
+		Members []interface{} `json:"members"`
+		Tags    []string      `json:"tags"`
+	}
+	if err := json.Unmarshal([]byte(out), &summarized); err != nil {
+		t.Fatalf("Expected valid JSON, got %q: %v", out, err)
+	}
+	shown := len(summarized.Members) - 1
+	if summarized.Team != "core" || len(summarized.Tags) != 1 || shown < 1 {
+		t.Errorf("Unexpected summary: %s", out)
+	}
+	if last, want := summarized.Members[shown], fmt.Sprintf("... %d more items", 100-shown); last != want {
+		t.Errorf("Expected %q, got %v", want, last)
+	}
+
+	plain, _ := TruncateSummarize(strings.Repeat("x", 1000), 100)
+	if !strings.Contains(plain, "[truncated:") {
+		t.Errorf("Expected non-JSON results to fall back to TruncateHead, got %q", plain)
+	}
+}
+
+func TestMaxResultSize(t *testing.T) {
+	big := func(ctx context.Context, args interface{}) (interface{}, error) {
+		return &TestResult{Message: strings.Repeat("x", 2000)}, nil
+	}
+
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	wrapper := New(mcpServer, WithMaxResultSize(500, nil))
+	wrapper.Register("dump", "Dump", TestArgs{}, big, WithOutputSchema(map[string]interface{}{"type": "object"}))
+	wrapper.Register("strict_dump", "Dump", TestArgs{}, big, WithToolMaxResultSize(500, TruncateError))
+	wrapper.Register("full_dump", "Dump", TestArgs{}, big, WithToolMaxResultSize(0, nil))
+
+	args := map[string]interface{}{"name": "dump", "age": 1, "category": "A"}
+
+	result := callTool(t, mcpServer, "dump", args)
+	text := result.Content[0].(mcp.TextContent).Text
+	if result.IsError || len(text) > 500 || result.StructuredContent != nil {
+		t.Errorf("Expected a truncated result without structured content, got %d bytes", len(text))
+	}
+	if result.Meta == nil || result.Meta.AdditionalFields["truncated"] != true || result.Meta.AdditionalFields["original_bytes"] != 2014 {
+		t.Errorf("Unexpected _meta: %+v", result.Meta)
+	}
+
+	result = callTool(t, mcpServer, "strict_dump", args)
+	if !result.IsError || result.StructuredContent.(map[string]interface{})["code"] != CodeTooLarge {
+		t.Errorf("Expected a too_large error, got %+v", result)
+	}
+
+	result = callTool(t, mcpServer, "full_dump", args)
+	if len(result.Content[0].(mcp.TextContent).Text) != 2014 {
+		t.Error("Expected the per-tool option to lift the limit")
+	}
+}
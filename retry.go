@@ -0,0 +1,93 @@
+package mcpwrapper
+
+import (
+	"context"
+	"math/rand"
+	"time"
+)
+
+// DefaultMaxBackoff caps the delay between retries when
+// RetryPolicy.MaxBackoff is not set.
+const DefaultMaxBackoff = time.Hour
+
+type RetryPolicy struct {
+	// MaxAttempts includes the first call. Values below 1 are treated as 1.
+	MaxAttempts int
+	// Backoff is the base delay; it doubles after every failed attempt and
+	// half of each delay is randomized to spread out concurrent retries.
+	Backoff time.Duration
+	// MaxBackoff caps the delay between attempts. Zero caps it at
+	// DefaultMaxBackoff.
+	MaxBackoff time.Duration
+	// RetryIf decides whether an error is transient. Nil retries every error.
+	RetryIf func(err error) bool
+}
+
+type RetryInfo struct {
+	Attempts int      `json:"attempts"`
+	Errors   []string `json:"errors,omitempty"`
+}
+
+func WithRetry(policy RetryPolicy) ToolOption {
+	return WithToolMiddleware(Retry(policy))
+}
+
+// Retry returns a middleware that re-invokes the handler on transient errors.
+// The attempt count and intermediate errors are reported under the "retry"
+// key of the result _meta.
+func Retry(policy RetryPolicy) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, args interface{}) (interface{}, error) {
+			info := RetryInfo{}
+			defer func() {
+				SetResultMeta(ctx, "retry", info)
+			}()
+
+			for {
+				info.Attempts++
+				result, err := next(ctx, args)
+				if err == nil {
+					return result, nil
+				}
+
+				if info.Attempts >= policy.MaxAttempts || (policy.RetryIf != nil && !policy.RetryIf(err)) {
+					return result, err
+				}
+				info.Errors = append(info.Errors, err.Error())
+
+				timer := time.NewTimer(policy.delay(info.Attempts))
+				select {
+				case <-ctx.Done():
+					timer.Stop()
+					return nil, ctx.Err()
+				case <-timer.C:
+				}
+			}
+		}
+	}
+}
+
+func (p RetryPolicy) delay(attempt int) time.Duration {
+	if p.Backoff <= 0 {
+		return 0
+	}
+
+	ceiling := p.MaxBackoff
+	if ceiling <= 0 {
+		ceiling = DefaultMaxBackoff
+	}
+	// Doubling stops at the ceiling, so high attempt counts cannot overflow.
+	d := p.Backoff
+	for i := 1; i < attempt && d < ceiling; i++ {
+		if d > ceiling/2 {
+			d = ceiling
+		} else {
+			d *= 2
+		}
+	}
+	if d > ceiling {
+		d = ceiling
+	}
+
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
@@ -0,0 +1,110 @@
+package mcpwrapper
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+var errTransient = errors.New("transient")
+
+func TestRetry(t *testing.T) {
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	wrapper := New(mcpServer)
+
+	calls := 0
+	handler := func(ctx context.Context, args interface{}) (interface{}, error) {
+		calls++
+		if calls < 3 {
+			return nil, errTransient
+		}
+		return &TestResult{Message: "ok"}, nil
+	}
+
+	err := wrapper.Register("test-tool", "Test tool", TestArgs{}, handler,
+		WithRetry(RetryPolicy{MaxAttempts: 5, Backoff: time.Millisecond}))
+	if err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	request := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name: "test-tool",
+			Arguments: map[string]interface{}{
+				"name":     "Alice",
+				"age":      30,
+				"category": "A",
+			},
+		},
+	}
+
+	result, err := mcpServer.GetTool("test-tool").Handler(context.Background(), request)
+	if err != nil {
+		t.Fatalf("Handler invocation failed: %v", err)
+	}
+
+	if result.IsError {
+		t.Fatalf("Expected success after retries, got %v", result.Content)
+	}
+
+	if calls != 3 {
+		t.Errorf("Expected 3 attempts, got %d", calls)
+	}
+
+	info, ok := result.Meta.AdditionalFields["retry"].(RetryInfo)
+	if !ok {
+		t.Fatal("Expected retry metadata in result")
+	}
+
+	if info.Attempts != 3 || len(info.Errors) != 2 {
+		t.Errorf("Expected 3 attempts and 2 errors, got %+v", info)
+	}
+}
+
+func TestRetryIf(t *testing.T) {
+	calls := 0
+	handler := Retry(RetryPolicy{
+		MaxAttempts: 5,
+		RetryIf:     func(err error) bool { return errors.Is(err, errTransient) },
+	})(func(ctx context.Context, args interface{}) (interface{}, error) {
+		calls++
+		return nil, errors.New("permanent")
+	})
+
+	if _, err := handler(context.Background(), nil); err == nil {
+		t.Fatal("Expected error")
+	}
+
+	if calls != 1 {
+		t.Errorf("Expected no retries for permanent error, got %d calls", calls)
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	policy := RetryPolicy{Backoff: 100 * time.Millisecond, MaxBackoff: 300 * time.Millisecond}
+
+	for attempt, max := range map[int]time.Duration{1: 100, 2: 200, 3: 300, 10: 300} {
+		d := policy.delay(attempt)
+		if d < max*time.Millisecond/2 || d > max*time.Millisecond {
+			t.Errorf("attempt %d: delay %v outside [%v, %v]", attempt, d, max*time.Millisecond/2, max*time.Millisecond)
+		}
+	}
+}
+
+func TestRetryDelayUncapped(t *testing.T) {
+	policy := RetryPolicy{Backoff: time.Second}
+
+	for _, attempt := range []int{40, 64, 65, 1000} {
+		d := policy.delay(attempt)
+		if d < DefaultMaxBackoff/2 || d > DefaultMaxBackoff {
+			t.Errorf("attempt %d: delay %v outside [%v, %v]", attempt, d, DefaultMaxBackoff/2, DefaultMaxBackoff)
+		}
+	}
+	if d := policy.delay(2); d < time.Second || d > 2*time.Second {
+		t.Errorf("attempt 2: delay %v outside [1s, 2s]", d)
+	}
+}
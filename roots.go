@@ -0,0 +1,99 @@
+package mcpwrapper
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// rootsCache keeps each session's roots until the client announces a change
+// with notifications/roots/list_changed or the session ends.
+type rootsCache struct {
+	mu       sync.Mutex
+	sessions map[string][]mcp.Root
+}
+
+func newRootsCache() *rootsCache {
+	return &rootsCache{sessions: make(map[string][]mcp.Root)}
+}
+
+func (c *rootsCache) get(sessionID string) ([]mcp.Root, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	roots, ok := c.sessions[sessionID]
+	return roots, ok
+}
+
+func (c *rootsCache) set(sessionID string, roots []mcp.Root) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sessions[sessionID] = roots
+}
+
+func (c *rootsCache) end(sessionID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.sessions, sessionID)
+}
+
+func (c *rootsCache) changed(ctx context.Context, notification mcp.JSONRPCNotification) {
+	c.end(SessionIDFromContext(ctx))
+}
+
+// Roots returns the filesystem roots declared by the client making the
+// current call. The list is requested once per session and cached until the
+// client reports a change. The server must be created with server.WithRoots()
+// and the client must support roots.
+func Roots(ctx context.Context) ([]mcp.Root, error) {
+	call := callFromContext(ctx)
+	if call == nil {
+		return nil, fmt.Errorf("roots requested outside of a tool call")
+	}
+
+	sessionID := SessionIDFromContext(ctx)
+	if roots, ok := call.wrapper.roots.get(sessionID); ok {
+		return roots, nil
+	}
+
+	result, err := call.wrapper.server.RequestRoots(ctx, mcp.ListRootsRequest{
+		Request: mcp.Request{Method: string(mcp.MethodListRoots)},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list roots: %w", err)
+	}
+
+	call.wrapper.roots.set(sessionID, result.Roots)
+	return result.Roots, nil
+}
+
+// WithinRoots reports whether path lies inside one of the client's file://
+// roots. File tools should refuse paths for which it returns false.
+func WithinRoots(ctx context.Context, path string) (bool, error) {
+	roots, err := Roots(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return false, fmt.Errorf("failed to resolve %s: %w", path, err)
+	}
+
+	for _, root := range roots {
+		u, err := url.Parse(root.URI)
+		if err != nil || u.Scheme != "file" {
+			continue
+		}
+		dir := filepath.Clean(filepath.FromSlash(u.Path))
+		rel, err := filepath.Rel(dir, abs)
+		if err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,92 @@
+package mcpwrapper
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+type fakeRoots struct {
+	roots []mcp.Root
+	calls int
+}
+
+func (r *fakeRoots) ListRoots(ctx context.Context, request mcp.ListRootsRequest) (*mcp.ListRootsResult, error) {
+	r.calls++
+	return &mcp.ListRootsResult{Roots: r.roots}, nil
+}
+
+func TestRootsCachedUntilListChanged(t *testing.T) {
+	mcpServer := server.NewMCPServer("test", "1.0.0", server.WithRoots())
+	wrapper := New(mcpServer)
+
+	var (
+		roots  []mcp.Root
+		inside bool
+	)
+	handler := func(ctx context.Context, args interface{}) (interface{}, error) {
+		var err error
+		if roots, err = Roots(ctx); err != nil {
+			return nil, err
+		}
+		if inside, err = WithinRoots(ctx, args.(*TestArgs).Name); err != nil {
+			return nil, err
+		}
+		return &TestResult{Message: "ok"}, nil
+	}
+
+	if err := wrapper.Register("read_file", "Read a file", TestArgs{}, handler); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	client := &fakeRoots{roots: []mcp.Root{{URI: "file:///srv/project", Name: "project"}}}
+	session := server.NewInProcessSessionWithHandlers("session-1", nil, nil, client)
+	ctx := mcpServer.WithContext(context.Background(), session)
+
+	call := func(path string) {
+		request := mcp.CallToolRequest{
+			Params: mcp.CallToolParams{
+				Name:      "read_file",
+				Arguments: map[string]interface{}{"name": path, "age": 30, "category": "A"},
+			},
+		}
+		result, err := mcpServer.GetTool("read_file").Handler(ctx, request)
+		if err != nil || result.IsError {
+			t.Fatalf("Handler invocation failed: %v %v", err, result)
+		}
+	}
+
+	call("/srv/project/main.go")
+	if len(roots) != 1 || roots[0].Name != "project" || !inside {
+		t.Errorf("Expected path inside the project root, got roots %+v inside=%v", roots, inside)
+	}
+
+	call("/srv/project-other/main.go")
+	if inside {
+		t.Error("Expected sibling directory to be outside the root")
+	}
+	call("/srv/project/../secret")
+	if inside {
+		t.Error("Expected path escaping the root to be outside it")
+	}
+	if client.calls != 1 {
+		t.Errorf("Expected roots to be requested once, got %d", client.calls)
+	}
+
+	client.roots = []mcp.Root{{URI: "file:///srv/other", Name: "other"}}
+	mcpServer.HandleMessage(ctx, json.RawMessage(`{"jsonrpc": "2.0", "method": "notifications/roots/list_changed"}`))
+
+	call("/srv/project/main.go")
+	if client.calls != 2 || roots[0].Name != "other" || inside {
+		t.Errorf("Expected refreshed roots after list_changed, got %+v after %d requests", roots, client.calls)
+	}
+}
+
+func TestRootsOutsideToolCall(t *testing.T) {
+	if _, err := Roots(context.Background()); err == nil {
+		t.Error("Expected an error outside of a tool call")
+	}
+}
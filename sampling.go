@@ -0,0 +1,100 @@
+package mcpwrapper
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const defaultSampleMaxTokens = 1024
+
+// SampleRequest asks the client's LLM for a completion. Prompt is shorthand
+// for a single user message and is sent after Messages.
+type SampleRequest struct {
+	Prompt        string
+	Messages      []mcp.SamplingMessage
+	SystemPrompt  string
+	MaxTokens     int
+	Temperature   float64
+	StopSequences []string
+	ModelHints    []string
+}
+
+type SampleResult struct {
+	Text       string
+	Model      string
+	StopReason string
+	Message    mcp.SamplingMessage
+}
+
+// Sample issues a sampling/createMessage request to the client that made the
+// current tool call. The server must have sampling enabled
+// (MCPServer.EnableSampling) and the client must support it.
+func Sample(ctx context.Context, req SampleRequest) (*SampleResult, error) {
+	call := callFromContext(ctx)
+	if call == nil {
+		return nil, fmt.Errorf("sample called outside of a tool call")
+	}
+
+	messages := append([]mcp.SamplingMessage(nil), req.Messages...)
+	if req.Prompt != "" {
+		messages = append(messages, mcp.SamplingMessage{
+			Role:    mcp.RoleUser,
+			Content: mcp.NewTextContent(req.Prompt),
+		})
+	}
+	if len(messages) == 0 {
+		return nil, fmt.Errorf("sample request needs a prompt or messages")
+	}
+
+	params := mcp.CreateMessageParams{
+		Messages:      messages,
+		SystemPrompt:  req.SystemPrompt,
+		MaxTokens:     req.MaxTokens,
+		Temperature:   req.Temperature,
+		StopSequences: req.StopSequences,
+	}
+	if params.MaxTokens <= 0 {
+		params.MaxTokens = defaultSampleMaxTokens
+	}
+	if len(req.ModelHints) > 0 {
+		prefs := &mcp.ModelPreferences{}
+		for _, name := range req.ModelHints {
+			prefs.Hints = append(prefs.Hints, mcp.ModelHint{Name: name})
+		}
+		params.ModelPreferences = prefs
+	}
+
+	result, err := call.wrapper.server.RequestSampling(ctx, mcp.CreateMessageRequest{
+		Request:             mcp.Request{Method: string(mcp.MethodSamplingCreateMessage)},
+		CreateMessageParams: params,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("sampling failed: %w", err)
+	}
+
+	return &SampleResult{
+		Text:       samplingText(result.Content),
+		Model:      result.Model,
+		StopReason: result.StopReason,
+		Message:    result.SamplingMessage,
+	}, nil
+}
+
+// samplingText extracts text from a sampled message. Over a transport the
+// content arrives as a decoded JSON object rather than mcp.TextContent.
+func samplingText(content interface{}) string {
+	switch c := content.(type) {
+	case mcp.TextContent:
+		return c.Text
+	case *mcp.TextContent:
+		return c.Text
+	case map[string]interface{}:
+		if c["type"] == "text" {
+			text, _ := c["text"].(string)
+			return text
+		}
+	}
+	return ""
+}
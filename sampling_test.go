@@ -0,0 +1,112 @@
+package mcpwrapper
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+type fakeSampler struct {
+	request mcp.CreateMessageRequest
+}
+
+func (s *fakeSampler) CreateMessage(ctx context.Context, request mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error) {
+	s.request = request
+	return &mcp.CreateMessageResult{
+		SamplingMessage: mcp.SamplingMessage{
+			Role:    mcp.RoleAssistant,
+			Content: map[string]interface{}{"type": "text", "text": "a short summary"},
+		},
+		Model:      "test-model",
+		StopReason: "endTurn",
+	}, nil
+}
+
+func TestSample(t *testing.T) {
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	mcpServer.EnableSampling()
+	wrapper := New(mcpServer)
+
+	var sampled *SampleResult
+	handler := func(ctx context.Context, args interface{}) (interface{}, error) {
+		var err error
+		sampled, err = Sample(ctx, SampleRequest{
+			Prompt:       "Summarize: " + args.(*TestArgs).Name,
+			SystemPrompt: "Be brief",
+			ModelHints:   []string{"claude"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		return &TestResult{Message: sampled.Text}, nil
+	}
+
+	if err := wrapper.Register("summarize", "Summarize tool", TestArgs{}, handler); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	sampler := &fakeSampler{}
+	ctx := mcpServer.WithContext(context.Background(), server.NewInProcessSession("session-1", sampler))
+	request := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name:      "summarize",
+			Arguments: map[string]interface{}{"name": "Alice", "age": 30, "category": "A"},
+		},
+	}
+
+	result, err := mcpServer.GetTool("summarize").Handler(ctx, request)
+	if err != nil || result.IsError {
+		t.Fatalf("Handler invocation failed: %v %v", err, result)
+	}
+
+	if sampled.Text != "a short summary" || sampled.Model != "test-model" || sampled.StopReason != "endTurn" {
+		t.Errorf("Unexpected sample result: %+v", sampled)
+	}
+
+	params := sampler.request.CreateMessageParams
+	if len(params.Messages) != 1 || params.Messages[0].Content.(mcp.TextContent).Text != "Summarize: Alice" {
+		t.Errorf("Unexpected messages: %+v", params.Messages)
+	}
+	if params.SystemPrompt != "Be brief" || params.MaxTokens != defaultSampleMaxTokens {
+		t.Errorf("Unexpected params: %+v", params)
+	}
+	if params.ModelPreferences == nil || params.ModelPreferences.Hints[0].Name != "claude" {
+		t.Errorf("Expected model hint, got %+v", params.ModelPreferences)
+	}
+}
+
+func TestSampleWithoutSupport(t *testing.T) {
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	wrapper := New(mcpServer)
+
+	handler := func(ctx context.Context, args interface{}) (interface{}, error) {
+		_, err := Sample(ctx, SampleRequest{Prompt: "hi"})
+		return nil, err
+	}
+
+	if err := wrapper.Register("summarize", "Summarize tool", TestArgs{}, handler); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	ctx := mcpServer.WithContext(context.Background(), &testSession{id: "session-1"})
+	request := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name:      "summarize",
+			Arguments: map[string]interface{}{"name": "Alice", "age": 30, "category": "A"},
+		},
+	}
+
+	result, err := mcpServer.GetTool("summarize").Handler(ctx, request)
+	if err != nil {
+		t.Fatalf("Handler invocation failed: %v", err)
+	}
+	if !result.IsError {
+		t.Error("Expected an error result when the session cannot sample")
+	}
+
+	if _, err := Sample(context.Background(), SampleRequest{Prompt: "hi"}); err == nil {
+		t.Error("Expected an error outside of a tool call")
+	}
+}
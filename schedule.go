@@ -0,0 +1,264 @@
+package mcpwrapper
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// ScheduleRun is the outcome of one scheduled call. Result and Error are
+// as in BatchResult.
+type ScheduleRun struct {
+	StartedAt  time.Time   `json:"started_at"`
+	DurationMs int64       `json:"duration_ms"`
+	Result     interface{} `json:"result,omitempty"`
+	Error      string      `json:"error,omitempty"`
+}
+
+// ScheduleStatus is the contents of a schedule's resource.
+type ScheduleStatus struct {
+	Name    string       `json:"name"`
+	Tool    string       `json:"tool"`
+	Spec    string       `json:"spec"`
+	Runs    int          `json:"runs"`
+	NextRun *time.Time   `json:"next_run,omitempty"`
+	Last    *ScheduleRun `json:"last,omitempty"`
+}
+
+// ScheduleOption configures a schedule added with Schedule.
+type ScheduleOption func(*scheduleEntry)
+
+// WithScheduleName names the schedule, so that one tool can be scheduled
+// more than once. The default is the tool's name.
+func WithScheduleName(name string) ScheduleOption {
+	return func(e *scheduleEntry) {
+		e.name = name
+	}
+}
+
+// WithScheduleLocation sets the time zone cron expressions are read in.
+// The default is the server's local time.
+func WithScheduleLocation(loc *time.Location) ScheduleOption {
+	return func(e *scheduleEntry) {
+		e.loc = loc
+	}
+}
+
+// WithScheduleLog also sends every run's outcome as a logging notification
+// to all connected clients: info when the call succeeded and error when it
+// failed. The logger name is the schedule's name.
+func WithScheduleLog() ScheduleOption {
+	return func(e *scheduleEntry) {
+		e.log = true
+	}
+}
+
+// scheduler keeps the schedules added with Schedule.
+type scheduler struct {
+	mu      sync.Mutex
+	entries map[string]*scheduleEntry
+	stopped bool
+}
+
+func newScheduler() *scheduler {
+	return &scheduler{entries: make(map[string]*scheduleEntry)}
+}
+
+type scheduleEntry struct {
+	name     string
+	tool     string
+	spec     string
+	args     map[string]interface{}
+	loc      *time.Location
+	log      bool
+	schedule cronSchedule
+	stop     chan struct{}
+	done     chan struct{}
+
+	mu   sync.Mutex
+	next time.Time
+	runs int
+	last *ScheduleRun
+}
+
+// Schedule calls tool with args on the cron expression spec until Shutdown.
+// spec has the five standard fields (minute, hour, day of month, month and
+// day of week, with lists, ranges, steps and names), or is one of @yearly,
+// @monthly, @weekly, @daily, @hourly or "@every <duration>". args is a map
+// or a struct that marshals to a JSON object.
+//
+// Each run goes through the tool's full pipeline, as a batch call does, and
+// a run still going when the next one is due delays it rather than
+// overlapping. The latest run is published as the resource
+// schedule://<name>, and clients subscribed to it are notified after every
+// run.
+func (w *Wrapper) Schedule(tool, spec string, args interface{}, opts ...ScheduleOption) error {
+	if w.server.GetTool(tool) == nil {
+		return fmt.Errorf("cannot schedule tool %s: not found", tool)
+	}
+	entry := &scheduleEntry{
+		name: tool,
+		tool: tool,
+		spec: spec,
+		args: map[string]interface{}{},
+		loc:  time.Local,
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(entry)
+	}
+	if args != nil {
+		entry.args = jsonFields(args)
+		if entry.args == nil {
+			return fmt.Errorf("cannot schedule tool %s: arguments must marshal to a JSON object", tool)
+		}
+	}
+	schedule, err := parseCron(spec, entry.loc)
+	if err != nil {
+		return fmt.Errorf("cannot schedule tool %s: %w", tool, err)
+	}
+	if schedule.next(time.Now()).IsZero() {
+		return fmt.Errorf("cannot schedule tool %s: %q never fires", tool, spec)
+	}
+	entry.schedule = schedule
+
+	if err := w.schedules.add(entry); err != nil {
+		return err
+	}
+	uri := scheduleURI(entry.name)
+	err = w.RegisterResource(uri, entry.name, "Latest scheduled run of "+tool+" ("+spec+")", func(ctx context.Context, uri string) (interface{}, error) {
+		return entry.status(), nil
+	})
+	if err != nil {
+		return err
+	}
+	go w.runSchedule(entry)
+	w.logger.Info("scheduled tool", "tool", tool, "name", entry.name, "spec", spec)
+	return nil
+}
+
+func scheduleURI(name string) string {
+	return "schedule://" + name
+}
+
+func (s *scheduler) add(entry *scheduleEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stopped {
+		return fmt.Errorf("cannot schedule %s: the wrapper is shutting down", entry.name)
+	}
+	if _, ok := s.entries[entry.name]; ok {
+		return fmt.Errorf("schedule %s already exists", entry.name)
+	}
+	s.entries[entry.name] = entry
+	return nil
+}
+
+// stop stops firing schedules. Runs in progress are calls like any other,
+// so Shutdown drains them.
+func (s *scheduler) stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stopped {
+		return
+	}
+	s.stopped = true
+	for _, entry := range s.entries {
+		close(entry.stop)
+	}
+}
+
+// wait waits for the schedules' goroutines to end after stop, or for ctx.
+func (s *scheduler) wait(ctx context.Context) {
+	s.mu.Lock()
+	entries := make([]*scheduleEntry, 0, len(s.entries))
+	for _, entry := range s.entries {
+		entries = append(entries, entry)
+	}
+	s.mu.Unlock()
+	for _, entry := range entries {
+		select {
+		case <-entry.done:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (w *Wrapper) runSchedule(e *scheduleEntry) {
+	defer close(e.done)
+	for {
+		next := e.schedule.next(time.Now())
+		if next.IsZero() {
+			return
+		}
+		e.mu.Lock()
+		e.next = next
+		e.mu.Unlock()
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-e.stop:
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		w.runScheduled(e)
+	}
+}
+
+func (w *Wrapper) runScheduled(e *scheduleEntry) {
+	run := &ScheduleRun{StartedAt: time.Now()}
+	outcome := w.runBatchCall(context.Background(), "", BatchCall{Tool: e.tool, Arguments: e.args})
+	run.DurationMs = time.Since(run.StartedAt).Milliseconds()
+	run.Result, run.Error = outcome.Result, outcome.Error
+
+	e.mu.Lock()
+	e.runs++
+	e.last = run
+	e.mu.Unlock()
+
+	level := mcp.LoggingLevelInfo
+	if run.Error != "" {
+		level = mcp.LoggingLevelError
+		w.logger.Warn("scheduled run failed", "name", e.name, "tool", e.tool, "error", run.Error)
+	} else {
+		w.logger.Info("scheduled run finished", "name", e.name, "tool", e.tool, "duration_ms", run.DurationMs)
+	}
+	if err := w.NotifyResourceUpdated(scheduleURI(e.name)); err != nil {
+		w.logger.Warn("failed to notify schedule subscribers", "name", e.name, "error", err)
+	}
+	if e.log {
+		// Broadcasts are best effort and not filtered by logging/setLevel.
+		w.server.SendNotificationToAllClients("notifications/message", map[string]any{
+			"level":  level,
+			"logger": e.name,
+			"data":   Redact(run),
+		})
+	}
+}
+
+func (e *scheduleEntry) status() *ScheduleStatus {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	status := &ScheduleStatus{
+		Name: e.name,
+		Tool: e.tool,
+		Spec: e.spec,
+		Runs: e.runs,
+		Last: e.last,
+	}
+	select {
+	case <-e.done:
+	default:
+		if !e.next.IsZero() {
+			next := e.next
+			status.NextRun = &next
+		}
+	}
+	return status
+}
@@ -0,0 +1,124 @@
+package mcpwrapper
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+type BackupArgs struct {
+	Database string `json:"database" validate:"required"`
+}
+
+func TestSchedule(t *testing.T) {
+	mcpServer := server.NewMCPServer("test", "1.0.0", server.WithResourceCapabilities(true, false))
+	wrapper := New(mcpServer)
+	var calls atomic.Int32
+	err := wrapper.Register("backup", "Back up a database", BackupArgs{}, func(ctx context.Context, args interface{}) (interface{}, error) {
+		if calls.Add(1) == 2 {
+			return nil, errors.New("disk full")
+		}
+		return &TestResult{Message: "saved " + args.(*BackupArgs).Database}, nil
+	})
+	if err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	session := &notifyingSession{id: "session-1", notifications: make(chan mcp.JSONRPCNotification, 100)}
+	ctx := mcpServer.WithContext(context.Background(), session)
+	if err := mcpServer.RegisterSession(ctx, session); err != nil {
+		t.Fatalf("RegisterSession failed: %v", err)
+	}
+
+	if err := wrapper.Schedule("backup", "@every 20ms", BackupArgs{Database: "orders"}, WithScheduleLog()); err != nil {
+		t.Fatalf("Schedule failed: %v", err)
+	}
+	waitFor(t, "three runs", func() bool { return calls.Load() >= 3 })
+
+	response := wrapper.HandleMessage(ctx, json.RawMessage(`{"jsonrpc": "2.0", "id": 1, "method": "resources/read", "params": {"uri": "schedule://backup"}}`))
+	contents := response.(mcp.JSONRPCResponse).Result.(mcp.ReadResourceResult).Contents
+	var status ScheduleStatus
+	if err := json.Unmarshal([]byte(contents[0].(mcp.TextResourceContents).Text), &status); err != nil {
+		t.Fatalf("Failed to decode status: %v", err)
+	}
+	if status.Tool != "backup" || status.Spec != "@every 20ms" || status.Runs < 3 || status.Last == nil || status.NextRun == nil {
+		t.Errorf("Unexpected status: %+v", status)
+	}
+
+	var levels []mcp.LoggingLevel
+	for len(levels) < 2 {
+		select {
+		case n := <-session.notifications:
+			if n.Method != "notifications/message" {
+				continue
+			}
+			if n.Params.AdditionalFields["logger"] != "backup" {
+				t.Errorf("Unexpected logger: %v", n.Params.AdditionalFields)
+			}
+			levels = append(levels, n.Params.AdditionalFields["level"].(mcp.LoggingLevel))
+		case <-time.After(5 * time.Second):
+			t.Fatal("Timed out waiting for log notifications")
+		}
+	}
+	if levels[0] != mcp.LoggingLevelInfo || levels[1] != mcp.LoggingLevelError {
+		t.Errorf("Expected an info then an error notification, got %v", levels)
+	}
+
+	if err := wrapper.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+	stopped := calls.Load()
+	time.Sleep(60 * time.Millisecond)
+	if calls.Load() != stopped {
+		t.Error("Expected the schedule to stop at shutdown")
+	}
+}
+
+func TestScheduleErrors(t *testing.T) {
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	wrapper := New(mcpServer)
+	if err := wrapper.Register("backup", "Back up a database", BackupArgs{}, func(ctx context.Context, args interface{}) (interface{}, error) {
+		return &TestResult{Message: "saved"}, nil
+	}); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	defer wrapper.Shutdown(context.Background())
+
+	tests := []struct {
+		name string
+		tool string
+		spec string
+		args interface{}
+		want string
+	}{
+		{"unknown tool", "restore", "@daily", nil, "not found"},
+		{"invalid spec", "backup", "0 25 * * *", nil, "out of range"},
+		{"never fires", "backup", "0 0 31 4 *", nil, "never fires"},
+		{"arguments", "backup", "@daily", []string{"orders"}, "JSON object"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := wrapper.Schedule(tt.tool, tt.spec, tt.args)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected an error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+
+	if err := wrapper.Schedule("backup", "@daily", nil); err != nil {
+		t.Fatalf("Schedule failed: %v", err)
+	}
+	if err := wrapper.Schedule("backup", "@hourly", nil); err == nil {
+		t.Error("Expected a second schedule with the same name to fail")
+	}
+	if err := wrapper.Schedule("backup", "@hourly", nil, WithScheduleName("backup-hourly")); err != nil {
+		t.Errorf("Expected a named schedule to succeed, got %v", err)
+	}
+}
@@ -0,0 +1,59 @@
+package mcpwrapper
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// SchemaGenerator builds the input schema of a tool from its argsType.
+type SchemaGenerator interface {
+	Schema(argsType interface{}) (*mcp.ToolInputSchema, error)
+}
+
+// SchemaGeneratorFunc adapts a function to SchemaGenerator.
+type SchemaGeneratorFunc func(argsType interface{}) (*mcp.ToolInputSchema, error)
+
+func (f SchemaGeneratorFunc) Schema(argsType interface{}) (*mcp.ToolInputSchema, error) {
+	return f(argsType)
+}
+
+// WithSchemaGenerator replaces the built-in jsonschema tag parser for the
+// wrapper's struct-typed tools, for example with the invopop subpackage.
+// The generated schema is used as is: Registry type mappings and field docs
+// only apply to the built-in parser. Binding and validation still read the
+// struct tags.
+func WithSchemaGenerator(g SchemaGenerator) Option {
+	return func(w *Wrapper) {
+		w.schemaGenerator = g
+	}
+}
+
+// schema returns the input schema of argsType from the wrapper's
+// SchemaGenerator, or from the Registry's built-in parser.
+func (w *Wrapper) schema(argsType interface{}) (*mcp.ToolInputSchema, error) {
+	if w.schemaGenerator == nil {
+		return w.registry.schema(argsType)
+	}
+
+	t := reflect.TypeOf(argsType)
+	if t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("argsType must be a struct, got %v", t)
+	}
+
+	schema, err := w.schemaGenerator.Schema(argsType)
+	if err != nil {
+		return nil, err
+	}
+	if schema.Type != "object" {
+		return nil, fmt.Errorf("generated schema must have type object, got %q", schema.Type)
+	}
+	if schema.Required == nil {
+		schema.Required = make([]string, 0) // MCP requires the 'required' field
+	}
+	return schema, nil
+}
@@ -0,0 +1,49 @@
+package mcpwrapper
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func TestWithSchemaGenerator(t *testing.T) {
+	var generated []interface{}
+	generator := SchemaGeneratorFunc(func(argsType interface{}) (*mcp.ToolInputSchema, error) {
+		generated = append(generated, argsType)
+		return &mcp.ToolInputSchema{
+			Type:       "object",
+			Properties: map[string]interface{}{"name": map[string]interface{}{"type": "string", "title": "Name"}},
+		}, nil
+	})
+
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	wrapper := New(mcpServer, WithSchemaGenerator(generator))
+	handler := func(ctx context.Context, args interface{}) (interface{}, error) {
+		return &TestResult{Message: "ok"}, nil
+	}
+	if err := wrapper.Register("greet", "Greet a user", TestArgs{}, handler); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	schema := mcpServer.GetTool("greet").Tool.InputSchema
+	if len(generated) != 1 || schema.Properties["name"].(map[string]interface{})["title"] != "Name" {
+		t.Errorf("Expected the generated schema, got %+v", schema)
+	}
+	if schema.Required == nil {
+		t.Error("Expected required to be set")
+	}
+
+	result := callTool(t, mcpServer, "greet", map[string]interface{}{"name": "Al"})
+	if !result.IsError {
+		t.Error("Expected validate tags to still apply")
+	}
+
+	bad := New(server.NewMCPServer("test", "1.0.0"), WithSchemaGenerator(SchemaGeneratorFunc(func(interface{}) (*mcp.ToolInputSchema, error) {
+		return &mcp.ToolInputSchema{Type: "string"}, nil
+	})))
+	if err := bad.Register("greet", "Greet a user", TestArgs{}, handler); err == nil {
+		t.Error("Expected an error for a non-object schema")
+	}
+}
@@ -0,0 +1,140 @@
+package mcpwrapper
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Arguments are the arguments of a tool registered with RegisterSchema,
+// decoded from JSON: numbers are float64, objects map[string]interface{}.
+type Arguments map[string]interface{}
+
+// RegisterSchema registers a tool whose input schema is given as a JSON
+// Schema object instead of a struct, for tools described at runtime. The
+// handler receives Arguments. Arguments are checked against the schema's
+// required, type and enum keywords of top-level properties; other keywords
+// are listed to clients but not enforced.
+func (w *Wrapper) RegisterSchema(name, description string, schema map[string]interface{}, handler Handler, opts ...ToolOption) error {
+	inputSchema, err := parseInputSchema(schema)
+	if err != nil {
+		return fmt.Errorf("invalid schema for tool %s: %w", name, err)
+	}
+
+	return w.register(name, description, Arguments{}, inputSchema, handler, opts)
+}
+
+// WithOutputSchema declares the JSON Schema object of the tool's result.
+// Clients get the result as structured content as well as text.
+func WithOutputSchema(schema map[string]interface{}) ToolOption {
+	return func(o *toolOptions) {
+		o.outputSchema = schema
+	}
+}
+
+func parseInputSchema(schema map[string]interface{}) (*mcp.ToolInputSchema, error) {
+	data, err := json.Marshal(schema)
+	if err != nil {
+		return nil, err
+	}
+
+	inputSchema := &mcp.ToolInputSchema{}
+	if err := json.Unmarshal(data, inputSchema); err != nil {
+		return nil, err
+	}
+	if inputSchema.Type == "" {
+		inputSchema.Type = "object"
+	}
+	if inputSchema.Type != "object" {
+		return nil, fmt.Errorf("type must be object, got %s", inputSchema.Type)
+	}
+	if inputSchema.Properties == nil {
+		inputSchema.Properties = make(map[string]interface{})
+	}
+	if inputSchema.Required == nil {
+		inputSchema.Required = make([]string, 0)
+	}
+	for _, name := range inputSchema.Required {
+		if _, ok := inputSchema.Properties[name]; !ok {
+			return nil, fmt.Errorf("required property %s is not defined", name)
+		}
+	}
+	return inputSchema, nil
+}
+
+// validateArgs validates bound arguments: struct arguments by their tags,
+// Arguments by the tool's input schema.
+func (w *Wrapper) validateArgs(t *registeredTool, args interface{}) error {
+	if a, ok := args.(*Arguments); ok {
+		return validateSchemaArgs(t.tool.InputSchema, *a)
+	}
+	return w.validate(args)
+}
+
+func validateSchemaArgs(schema mcp.ToolInputSchema, args Arguments) error {
+	var errs ValidationErrors
+	for _, name := range schema.Required {
+		if _, ok := args[name]; !ok {
+			errs = append(errs, ValidationError{Field: name, Message: "is required"})
+		}
+	}
+
+	names := make([]string, 0, len(args))
+	for name := range args {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		prop, ok := schema.Properties[name].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		value := args[name]
+		if typ, ok := prop["type"].(string); ok && !hasSchemaType(value, typ) {
+			errs = append(errs, ValidationError{Field: name, Message: "must be of type " + typ})
+			continue
+		}
+		if enum, ok := prop["enum"].([]interface{}); ok && !schemaEnumContains(enum, value) {
+			errs = append(errs, ValidationError{Field: name, Message: fmt.Sprintf("must be one of: %v", enum)})
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func hasSchemaType(value interface{}, typ string) bool {
+	switch v := value.(type) {
+	case string:
+		return typ == "string"
+	case float64:
+		return typ == "number" || (typ == "integer" && v == math.Trunc(v))
+	case bool:
+		return typ == "boolean"
+	case []interface{}:
+		return typ == "array"
+	case map[string]interface{}:
+		return typ == "object"
+	case nil:
+		return typ == "null"
+	}
+	return true
+}
+
+// schemaEnumContains compares deeply, since enum entries and arguments may
+// be arrays or objects, which == cannot compare.
+func schemaEnumContains(enum []interface{}, value interface{}) bool {
+	for _, allowed := range enum {
+		if reflect.DeepEqual(allowed, value) {
+			return true
+		}
+	}
+	return false
+}
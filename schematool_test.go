@@ -0,0 +1,143 @@
+package mcpwrapper
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func TestRegisterSchema(t *testing.T) {
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	wrapper := New(mcpServer)
+
+	schema := map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"query": map[string]interface{}{"type": "string"},
+			"limit": map[string]interface{}{"type": "integer", "minimum": 1},
+		},
+		"required": []string{"query"},
+	}
+	var got Arguments
+	err := wrapper.RegisterSchema("search", "Search", schema, func(ctx context.Context, args interface{}) (interface{}, error) {
+		got = args.(Arguments)
+		return &TestResult{Message: "ok"}, nil
+	})
+	if err != nil {
+		t.Fatalf("RegisterSchema failed: %v", err)
+	}
+
+	limit := mcpServer.GetTool("search").Tool.InputSchema.Properties["limit"].(map[string]interface{})
+	if limit["minimum"] != float64(1) {
+		t.Errorf("Expected keywords to be listed, got %v", limit)
+	}
+
+	tests := []struct {
+		name    string
+		args    map[string]interface{}
+		wantErr string
+	}{
+		{"valid", map[string]interface{}{"query": "go", "limit": 10}, ""},
+		{"missing required", map[string]interface{}{"limit": 10}, "query: is required"},
+		{"wrong type", map[string]interface{}{"query": 1}, "query: must be of type string"},
+		{"fractional integer", map[string]interface{}{"query": "go", "limit": 1.5}, "limit: must be of type integer"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := callTool(t, mcpServer, "search", tt.args)
+			if tt.wantErr == "" {
+				if result.IsError || got["limit"] != float64(10) {
+					t.Errorf("Expected success with decoded arguments, got %+v %v", result, got)
+				}
+				return
+			}
+			if !result.IsError || !strings.Contains(result.Content[0].(mcp.TextContent).Text, tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %+v", tt.wantErr, result)
+			}
+		})
+	}
+}
+
+func TestRegisterSchemaEnum(t *testing.T) {
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	wrapper := New(mcpServer)
+
+	schema := map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"mode":  map[string]interface{}{"enum": []interface{}{"fast", "safe"}},
+			"range": map[string]interface{}{"enum": []interface{}{[]interface{}{0, 10}, map[string]interface{}{"from": 0}}},
+		},
+	}
+	err := wrapper.RegisterSchema("scan", "Scan", schema, func(ctx context.Context, args interface{}) (interface{}, error) {
+		return &TestResult{Message: "ok"}, nil
+	})
+	if err != nil {
+		t.Fatalf("RegisterSchema failed: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		args    map[string]interface{}
+		wantErr bool
+	}{
+		{"array entry", map[string]interface{}{"range": []interface{}{0, 10}}, false},
+		{"object entry", map[string]interface{}{"range": map[string]interface{}{"from": 0}}, false},
+		{"other array", map[string]interface{}{"range": []interface{}{1, 2}}, true},
+		{"array for string enum", map[string]interface{}{"mode": []interface{}{"fast"}}, true},
+		{"object for string enum", map[string]interface{}{"mode": map[string]interface{}{"fast": true}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := callTool(t, mcpServer, "scan", tt.args)
+			if result.IsError != tt.wantErr {
+				t.Errorf("Expected error %v, got %+v", tt.wantErr, result)
+			}
+		})
+	}
+}
+
+func TestRegisterSchemaInvalid(t *testing.T) {
+	wrapper := New(server.NewMCPServer("test", "1.0.0"))
+	handler := func(ctx context.Context, args interface{}) (interface{}, error) { return nil, nil }
+
+	if err := wrapper.RegisterSchema("a", "", map[string]interface{}{"type": "string"}, handler); err == nil {
+		t.Error("Expected an error for a non-object schema")
+	}
+	if err := wrapper.RegisterSchema("b", "", map[string]interface{}{"required": []string{"x"}}, handler); err == nil {
+		t.Error("Expected an error for an undefined required property")
+	}
+}
+
+func TestWithOutputSchema(t *testing.T) {
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	wrapper := New(mcpServer)
+
+	handler := func(ctx context.Context, args interface{}) (interface{}, error) {
+		return &TestResult{Message: "ok"}, nil
+	}
+	output := map[string]interface{}{
+		"type":       "object",
+		"properties": map[string]interface{}{"message": map[string]interface{}{"type": "string"}},
+	}
+	if err := wrapper.Register("greet", "Greet", TestArgs{}, handler, WithOutputSchema(output)); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	if tool := mcpServer.GetTool("greet").Tool; tool.OutputSchema.Type != "object" || tool.OutputSchema.Properties["message"] == nil {
+		t.Errorf("Expected the output schema to be listed, got %+v", tool.OutputSchema)
+	}
+
+	result := callTool(t, mcpServer, "greet", map[string]interface{}{"name": "Alice", "age": 30, "category": "A"})
+	structured, ok := result.StructuredContent.(map[string]interface{})
+	if !ok || structured["message"] != "ok" {
+		t.Errorf("Expected structured content, got %+v", result.StructuredContent)
+	}
+
+	if err := wrapper.Register("bad", "Bad", TestArgs{}, handler, WithOutputSchema(map[string]interface{}{"type": "array"})); err == nil {
+		t.Error("Expected an error for a non-object output schema")
+	}
+}
@@ -0,0 +1,137 @@
+package mcpwrapper
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// ServiceDescriber gives the tools of a service passed to RegisterService
+// their descriptions, keyed by method name or tool name.
+type ServiceDescriber interface {
+	Describe() map[string]string
+}
+
+// ServiceToolOptions gives single tools of a service passed to
+// RegisterService their own options, such as WithReadOnly, keyed by method
+// name or tool name.
+type ServiceToolOptions interface {
+	ToolOptions() map[string][]ToolOption
+}
+
+// RegisterService registers every exported method of svc with a handler
+// signature as a tool:
+//
+//	func (s *Users) GetUser(ctx context.Context, args *GetUserArgs) (*User, error)
+//
+// is registered as get_user. Methods are adapted like RegisterFunc, so they
+// may take provided dependencies after the args; other methods are ignored.
+// Descriptions come from ServiceDescriber and default to the method name
+// in words. opts apply to every tool, before those from ServiceToolOptions.
+// A method with an unresolved dependency fails before any tool is
+// registered. RegisterService returns the tool names in method order.
+func (w *Wrapper) RegisterService(svc interface{}, opts ...ToolOption) ([]string, error) {
+	sv := reflect.ValueOf(svc)
+	if !sv.IsValid() {
+		return nil, fmt.Errorf("service must not be nil")
+	}
+
+	var descriptions map[string]string
+	if d, ok := svc.(ServiceDescriber); ok {
+		descriptions = d.Describe()
+	}
+	var toolOpts map[string][]ToolOption
+	if o, ok := svc.(ServiceToolOptions); ok {
+		toolOpts = o.ToolOptions()
+	}
+
+	type method struct {
+		name, description string
+		argsType          interface{}
+		handler           Handler
+		opts              []ToolOption
+	}
+	var methods []method
+	st := sv.Type()
+	for i := 0; i < st.NumMethod(); i++ {
+		m := st.Method(i)
+		fn := sv.Method(i)
+		if !isHandlerMethod(fn.Type()) {
+			continue
+		}
+
+		name := snakeCase(m.Name)
+		handler, argsType, err := funcHandler(fn.Interface(), w.deps)
+		if err != nil {
+			return nil, fmt.Errorf("invalid method %s.%s: %w", st, m.Name, err)
+		}
+
+		description := descriptions[m.Name]
+		if d, ok := descriptions[name]; ok {
+			description = d
+		}
+		if description == "" {
+			description = words(m.Name)
+		}
+
+		methodOpts := append(append([]ToolOption(nil), opts...), toolOpts[m.Name]...)
+		methodOpts = append(methodOpts, toolOpts[name]...)
+		methods = append(methods, method{name, description, argsType, handler, methodOpts})
+	}
+	if len(methods) == 0 {
+		return nil, fmt.Errorf("%s has no methods with a handler signature", st)
+	}
+
+	names := make([]string, 0, len(methods))
+	for _, m := range methods {
+		if err := w.Register(m.name, m.description, m.argsType, m.handler, m.opts...); err != nil {
+			return names, err
+		}
+		names = append(names, m.name)
+	}
+	w.logger.Info("registered service", "service", st.String(), "tools", len(names))
+	return names, nil
+}
+
+// isHandlerMethod reports whether a method looks like a tool: a context,
+// args and optional dependencies in, a result and an error out.
+func isHandlerMethod(t reflect.Type) bool {
+	if t.NumIn() < 2 || t.In(0) != contextType || t.NumOut() != 2 || t.Out(1) != errorType {
+		return false
+	}
+	args := t.In(1)
+	if args.Kind() == reflect.Ptr {
+		args = args.Elem()
+	}
+	return args.Kind() == reflect.Struct
+}
+
+// snakeCase converts a Go identifier to snake_case, keeping initialisms
+// together: GetHTTPStatus becomes get_http_status.
+func snakeCase(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) && i > 0 {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				b.WriteByte('_')
+			}
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
+
+// words turns a method name into a sentence: GetUser becomes "Get user".
+func words(name string) string {
+	s := strings.ReplaceAll(snakeCase(name), "_", " ")
+	if s == "" {
+		return s
+	}
+	r := []rune(s)
+	r[0] = unicode.ToUpper(r[0])
+	return string(r)
+}
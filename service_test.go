@@ -0,0 +1,93 @@
+package mcpwrapper
+
+import (
+	"context"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+type userService struct {
+	prefix string
+}
+
+func (s *userService) GetUser(ctx context.Context, args *TestArgs) (*TestResult, error) {
+	return &TestResult{Message: s.prefix + args.Name}, nil
+}
+
+func (s *userService) ListHTTPRoutes(ctx context.Context, args TestArgs, users *userStore) (string, error) {
+	return strconv.Itoa(len(users.names)), nil
+}
+
+func (s *userService) Describe() map[string]string {
+	return map[string]string{"GetUser": "Fetch a user by name"}
+}
+
+func (s *userService) ToolOptions() map[string][]ToolOption {
+	return map[string][]ToolOption{"get_user": {WithReadOnly()}}
+}
+
+// Helper is not a tool: its signature doesn't match.
+func (s *userService) Helper(name string) string { return name }
+
+func TestRegisterService(t *testing.T) {
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	wrapper := New(mcpServer)
+	wrapper.Provide(&userStore{names: map[string]string{"a": "A", "b": "B"}})
+
+	names, err := wrapper.RegisterService(&userService{prefix: "user "})
+	if err != nil {
+		t.Fatalf("RegisterService failed: %v", err)
+	}
+	if want := []string{"get_user", "list_http_routes"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("Expected %v, got %v", want, names)
+	}
+
+	get := mcpServer.GetTool("get_user")
+	if get.Tool.Description != "Fetch a user by name" || get.Tool.Annotations.ReadOnlyHint == nil || !*get.Tool.Annotations.ReadOnlyHint {
+		t.Errorf("Unexpected get_user tool: %+v", get.Tool)
+	}
+	if routes := mcpServer.GetTool("list_http_routes"); routes.Tool.Description != "List http routes" {
+		t.Errorf("Expected a description from the method name, got %q", routes.Tool.Description)
+	}
+
+	args := map[string]interface{}{"name": "Ada", "age": 36, "category": "A"}
+	if result := callTool(t, mcpServer, "get_user", args); !strings.Contains(result.Content[0].(mcp.TextContent).Text, "user Ada") {
+		t.Errorf("Unexpected get_user result: %v", result.Content)
+	}
+	if result := callTool(t, mcpServer, "list_http_routes", args); result.Content[0].(mcp.TextContent).Text != "2" {
+		t.Errorf("Unexpected list_http_routes result: %v", result.Content)
+	}
+}
+
+func TestRegisterServiceErrors(t *testing.T) {
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	wrapper := New(mcpServer)
+
+	if _, err := wrapper.RegisterService(&userService{}); err == nil || !strings.Contains(err.Error(), "ListHTTPRoutes") {
+		t.Errorf("Expected a missing dependency error, got %v", err)
+	}
+	if mcpServer.GetTool("get_user") != nil {
+		t.Error("Expected nothing to be registered")
+	}
+	if _, err := wrapper.RegisterService(struct{}{}); err == nil {
+		t.Error("Expected an error for a service without tools")
+	}
+}
+
+func TestSnakeCase(t *testing.T) {
+	for in, want := range map[string]string{
+		"GetUser":       "get_user",
+		"GetHTTPStatus": "get_http_status",
+		"ListV2Items":   "list_v2_items",
+		"ID":            "id",
+	} {
+		if got := snakeCase(in); got != want {
+			t.Errorf("snakeCase(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
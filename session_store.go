@@ -0,0 +1,113 @@
+package mcpwrapper
+
+import (
+	"context"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Store is a concurrency-safe key/value store scoped to one client session.
+type Store struct {
+	mu   sync.RWMutex
+	data map[string]interface{}
+}
+
+func newStore() *Store {
+	return &Store{data: make(map[string]interface{})}
+}
+
+func (s *Store) Get(key string) (interface{}, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	v, ok := s.data[key]
+	return v, ok
+}
+
+func (s *Store) Set(key string, value interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data[key] = value
+}
+
+func (s *Store) Delete(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.data, key)
+}
+
+type sessionStores struct {
+	mu     sync.Mutex
+	stores map[string]*Store
+}
+
+func (s *sessionStores) get(sessionID string) *Store {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	store, ok := s.stores[sessionID]
+	if !ok {
+		store = newStore()
+		s.stores[sessionID] = store
+	}
+	return store
+}
+
+func (s *sessionStores) end(sessionID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.stores, sessionID)
+}
+
+// SessionStore returns the key/value store of the client session making the
+// current call, so tools can keep state across a sequence of calls (login,
+// query, logout). It returns nil outside of a wrapped tool call.
+func SessionStore(ctx context.Context) *Store {
+	call := callFromContext(ctx)
+	if call == nil {
+		return nil
+	}
+	return call.wrapper.sessions.get(SessionIDFromContext(ctx))
+}
+
+// EndSession discards everything the wrapper keeps for a session: its store,
+// cached roots, result history, resource subscriptions, offloaded results,
+// streamed output, provenance graph and background jobs. It is called automatically on disconnect when the
+// wrapper was given the server's hooks via WithHooks.
+func (w *Wrapper) EndSession(sessionID string) {
+	w.sessions.end(sessionID)
+	w.roots.end(sessionID)
+	w.resources.end(sessionID)
+	if w.history != nil {
+		w.history.end(sessionID)
+	}
+	if w.messageLimit != nil && w.messageLimit.store != nil {
+		w.messageLimit.store.end(sessionID)
+	}
+	if w.streams != nil {
+		w.streams.end(sessionID)
+	}
+	if w.offload != nil {
+		w.offload.store.end(sessionID)
+	}
+	if w.provenance != nil {
+		w.provenance.end(sessionID)
+	}
+	if w.jobs != nil {
+		w.jobs.endSession(w, sessionID)
+	}
+}
+
+// WithHooks lets the wrapper observe server lifecycle events: client
+// connects and disconnects run the OnClientConnect and OnClientDisconnect
+// hooks, disconnects clean up per-session state, and tool call request IDs
+// are tracked so notifications/cancelled can cancel the running handler.
+// Pass the same Hooks given to server.WithHooks when the server was created.
+func WithHooks(hooks *server.Hooks) Option {
+	return func(w *Wrapper) {
+		if hooks == nil {
+			return
+		}
+		w.installLifecycle(hooks)
+		w.installCancellation(hooks)
+	}
+}
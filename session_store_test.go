@@ -0,0 +1,72 @@
+package mcpwrapper
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func TestSessionStore(t *testing.T) {
+	hooks := &server.Hooks{}
+	mcpServer := server.NewMCPServer("test", "1.0.0", server.WithHooks(hooks))
+	wrapper := New(mcpServer, WithHooks(hooks))
+
+	login := func(ctx context.Context, args interface{}) (interface{}, error) {
+		SessionStore(ctx).Set("user", args.(*TestArgs).Name)
+		return &TestResult{Message: "logged in"}, nil
+	}
+	whoami := func(ctx context.Context, args interface{}) (interface{}, error) {
+		user, ok := SessionStore(ctx).Get("user")
+		if !ok {
+			return &TestResult{Message: "anonymous"}, nil
+		}
+		return &TestResult{Message: user.(string)}, nil
+	}
+
+	if err := wrapper.Register("login", "Login", TestArgs{}, login); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	if err := wrapper.Register("whoami", "Who am I", TestArgs{}, whoami); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	session := &testSession{id: "session-1"}
+	if err := mcpServer.RegisterSession(context.Background(), session); err != nil {
+		t.Fatalf("RegisterSession failed: %v", err)
+	}
+
+	call := func(ctx context.Context, tool, name string) string {
+		request := mcp.CallToolRequest{
+			Params: mcp.CallToolParams{
+				Name:      tool,
+				Arguments: map[string]interface{}{"name": name, "age": 30, "category": "A"},
+			},
+		}
+		result, err := mcpServer.GetTool(tool).Handler(ctx, request)
+		if err != nil || result.IsError {
+			t.Fatalf("Handler invocation failed: %v %v", err, result)
+		}
+		return result.Content[0].(mcp.TextContent).Text
+	}
+
+	ctx := mcpServer.WithContext(context.Background(), session)
+	other := mcpServer.WithContext(context.Background(), &testSession{id: "session-2"})
+
+	call(ctx, "login", "Alice")
+
+	if got := call(ctx, "whoami", "Alice"); got != `{"message":"Alice"}` {
+		t.Errorf("Expected stored user, got %s", got)
+	}
+
+	if got := call(other, "whoami", "Alice"); got != `{"message":"anonymous"}` {
+		t.Errorf("Expected other session to be isolated, got %s", got)
+	}
+
+	mcpServer.UnregisterSession(context.Background(), "session-1")
+
+	if got := call(ctx, "whoami", "Alice"); got != `{"message":"anonymous"}` {
+		t.Errorf("Expected state to be cleared on disconnect, got %s", got)
+	}
+}
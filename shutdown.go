@@ -0,0 +1,130 @@
+package mcpwrapper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DefaultFlushTimeout bounds the flushers when Shutdown has already used up
+// its deadline draining calls.
+const DefaultFlushTimeout = 5 * time.Second
+
+// callTracker counts running tool calls so Shutdown can wait for them, and
+// keeps their cancel functions so it can stop the ones that outlive the
+// deadline.
+type callTracker struct {
+	mu      sync.Mutex
+	closed  bool
+	next    uint64
+	cancels map[uint64]context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+func newCallTracker() *callTracker {
+	return &callTracker{cancels: make(map[uint64]context.CancelFunc)}
+}
+
+// begin registers a call. It returns false once the tracker is closed. The
+// returned function must be called when the call completes.
+func (c *callTracker) begin(ctx context.Context) (context.Context, func(), bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return ctx, nil, false
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	id := c.next
+	c.next++
+	c.cancels[id] = cancel
+	c.wg.Add(1)
+
+	return ctx, func() {
+		c.mu.Lock()
+		delete(c.cancels, id)
+		c.mu.Unlock()
+		cancel()
+		c.wg.Done()
+	}, true
+}
+
+// close stops new calls and returns the number still running, and whether
+// the tracker was already closed.
+func (c *callTracker) close() (int, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	wasClosed := c.closed
+	c.closed = true
+	return len(c.cancels), wasClosed
+}
+
+func (c *callTracker) cancelAll() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, cancel := range c.cancels {
+		cancel()
+	}
+	return len(c.cancels)
+}
+
+// WithFlusher registers a function Shutdown calls after the calls have
+// drained, to flush buffered audit entries, metrics or logs. Flushers run in
+// registration order.
+func WithFlusher(flush func(ctx context.Context) error) Option {
+	return func(w *Wrapper) {
+		w.flushers = append(w.flushers, flush)
+	}
+}
+
+// Shutdown stops the wrapper: new tool calls are rejected as unavailable
+// and /readyz fails, schedules stop firing, calls in flight get until
+// ctx's deadline to finish, stragglers are cancelled, jobs of long-running
+// commands are stopped, pending webhooks are delivered, and the flushers
+// run. It does not stop the transport. It returns an error when calls had
+// to be cancelled or a flusher failed. Only the first call runs the
+// flushers.
+func (w *Wrapper) Shutdown(ctx context.Context) error {
+	w.health.stopping.Store(true)
+	w.schedules.stop()
+	running, wasClosed := w.calls.close()
+	w.logger.Info("shutting down", "calls_in_flight", running)
+
+	drained := make(chan struct{})
+	go func() {
+		w.calls.wg.Wait()
+		close(drained)
+	}()
+
+	var errs []error
+	select {
+	case <-drained:
+	case <-ctx.Done():
+		cancelled := w.calls.cancelAll()
+		w.logger.Warn("cancelled calls still running at shutdown deadline", "calls", cancelled)
+		errs = append(errs, fmt.Errorf("cancelled %d call(s) at shutdown deadline: %w", cancelled, ctx.Err()))
+	}
+
+	if wasClosed {
+		return errors.Join(errs...)
+	}
+	w.schedules.wait(ctx)
+	w.processes.stopAll(ctx)
+
+	flushCtx := ctx
+	if ctx.Err() != nil {
+		var cancel context.CancelFunc
+		flushCtx, cancel = context.WithTimeout(context.WithoutCancel(ctx), DefaultFlushTimeout)
+		defer cancel()
+	}
+	w.webhooks.wait(flushCtx)
+	for i, flush := range w.flushers {
+		if err := flush(flushCtx); err != nil {
+			w.logger.Error("flush failed at shutdown", "flusher", i, "error", err)
+			errs = append(errs, fmt.Errorf("flush: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
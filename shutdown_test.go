@@ -0,0 +1,99 @@
+package mcpwrapper
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func TestShutdownDrainsCalls(t *testing.T) {
+	var flushed []string
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	wrapper := New(mcpServer,
+		WithFlusher(func(ctx context.Context) error { flushed = append(flushed, "audit"); return nil }),
+		WithFlusher(func(ctx context.Context) error { flushed = append(flushed, "metrics"); return nil }),
+	)
+
+	started, release := make(chan struct{}), make(chan struct{})
+	wrapper.Register("slow", "Slow tool", TestArgs{}, func(ctx context.Context, args interface{}) (interface{}, error) {
+		close(started)
+		<-release
+		return &TestResult{Message: "done"}, nil
+	})
+
+	args := map[string]interface{}{"name": "slow", "age": 1, "category": "A"}
+	results := make(chan *mcp.CallToolResult, 1)
+	go func() { results <- callTool(t, mcpServer, "slow", args) }()
+	<-started
+
+	shutdown := make(chan error, 1)
+	go func() { shutdown <- wrapper.Shutdown(context.Background()) }()
+
+	time.Sleep(20 * time.Millisecond)
+	rejected := callTool(t, mcpServer, "slow", args)
+	if !rejected.IsError || rejected.StructuredContent.(map[string]interface{})["code"] != CodeUnavailable {
+		t.Errorf("Expected new calls to be rejected during shutdown, got %+v", rejected)
+	}
+	if _, ready := wrapper.Readiness(context.Background()); ready {
+		t.Error("Expected readiness to fail during shutdown")
+	}
+	select {
+	case <-shutdown:
+		t.Fatal("Shutdown returned before the in-flight call finished")
+	default:
+	}
+
+	close(release)
+	if result := <-results; result.IsError {
+		t.Errorf("Expected the in-flight call to complete, got %v", result.Content)
+	}
+	if err := <-shutdown; err != nil {
+		t.Errorf("Expected a clean shutdown, got %v", err)
+	}
+	if len(flushed) != 2 || flushed[0] != "audit" || flushed[1] != "metrics" {
+		t.Errorf("Expected flushers to run in order, got %v", flushed)
+	}
+
+	if err := wrapper.Shutdown(context.Background()); err != nil || len(flushed) != 2 {
+		t.Errorf("Expected a second Shutdown not to flush again, got %v %v", err, flushed)
+	}
+}
+
+func TestShutdownCancelsStragglers(t *testing.T) {
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	var flushErr error
+	wrapper := New(mcpServer, WithFlusher(func(ctx context.Context) error {
+		flushErr = ctx.Err()
+		return nil
+	}))
+
+	started := make(chan struct{})
+	wrapper.Register("stuck", "Stuck tool", TestArgs{}, func(ctx context.Context, args interface{}) (interface{}, error) {
+		close(started)
+		<-ctx.Done()
+		return nil, CheckCancelled(ctx)
+	})
+
+	results := make(chan *mcp.CallToolResult, 1)
+	go func() {
+		results <- callTool(t, mcpServer, "stuck", map[string]interface{}{"name": "stuck", "age": 1, "category": "A"})
+	}()
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err := wrapper.Shutdown(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected a deadline error, got %v", err)
+	}
+	if result := <-results; !result.IsError {
+		t.Error("Expected the straggler to be cancelled")
+	}
+	if flushErr != nil {
+		t.Errorf("Expected flushers to get a live context after the deadline, got %v", flushErr)
+	}
+}
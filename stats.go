@@ -0,0 +1,148 @@
+package mcpwrapper
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// latencySamples bounds the per-tool latency window; percentiles describe the
+// most recent calls rather than the whole lifetime.
+const latencySamples = 1024
+
+type Stats struct {
+	Since time.Time            `json:"since"`
+	Tools map[string]ToolStats `json:"tools"`
+}
+
+type ToolStats struct {
+	Calls        int64         `json:"calls"`
+	Errors       int64         `json:"errors"`
+	ErrorRate    float64       `json:"error_rate"`
+	LatencyP50   time.Duration `json:"latency_p50"`
+	LatencyP90   time.Duration `json:"latency_p90"`
+	LatencyP99   time.Duration `json:"latency_p99"`
+	CacheHits    int64         `json:"cache_hits"`
+	CacheMisses  int64         `json:"cache_misses"`
+	CacheHitRate float64       `json:"cache_hit_rate"`
+}
+
+type statsCollector struct {
+	mu    sync.Mutex
+	since time.Time
+	tools map[string]*toolCounters
+}
+
+type toolCounters struct {
+	calls, errors          int64
+	cacheHits, cacheMisses int64
+	latencies              []time.Duration
+	next                   int
+}
+
+func newStatsCollector() *statsCollector {
+	return &statsCollector{since: time.Now(), tools: make(map[string]*toolCounters)}
+}
+
+func (s *statsCollector) tool(name string) *toolCounters {
+	c, ok := s.tools[name]
+	if !ok {
+		c = &toolCounters{}
+		s.tools[name] = c
+	}
+	return c
+}
+
+func (s *statsCollector) recordCall(name string, duration time.Duration, failed bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c := s.tool(name)
+	c.calls++
+	if failed {
+		c.errors++
+	}
+	if len(c.latencies) < latencySamples {
+		c.latencies = append(c.latencies, duration)
+	} else {
+		c.latencies[c.next] = duration
+		c.next = (c.next + 1) % latencySamples
+	}
+}
+
+func (s *statsCollector) recordCache(name string, hit bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c := s.tool(name)
+	if hit {
+		c.cacheHits++
+	} else {
+		c.cacheMisses++
+	}
+}
+
+// recordCacheLookup lets cache middleware report to the wrapper serving the
+// call, if any.
+func recordCacheLookup(ctx context.Context, hit bool) {
+	if call := callFromContext(ctx); call != nil {
+		call.wrapper.stats.recordCache(call.tool.name, hit)
+	}
+}
+
+// Stats returns a snapshot of per-tool call counts, error rates, latency
+// percentiles over the last 1024 calls, and cache hit rates since start or
+// the last ResetStats. Calls rejected before the handler (validation,
+// authorization) count as errors.
+func (w *Wrapper) Stats() Stats {
+	w.stats.mu.Lock()
+	defer w.stats.mu.Unlock()
+
+	snapshot := Stats{Since: w.stats.since, Tools: make(map[string]ToolStats, len(w.stats.tools))}
+	for name, c := range w.stats.tools {
+		ts := ToolStats{
+			Calls:       c.calls,
+			Errors:      c.errors,
+			CacheHits:   c.cacheHits,
+			CacheMisses: c.cacheMisses,
+		}
+		if c.calls > 0 {
+			ts.ErrorRate = float64(c.errors) / float64(c.calls)
+		}
+		if lookups := c.cacheHits + c.cacheMisses; lookups > 0 {
+			ts.CacheHitRate = float64(c.cacheHits) / float64(lookups)
+		}
+
+		sorted := append([]time.Duration(nil), c.latencies...)
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+		ts.LatencyP50 = percentile(sorted, 0.50)
+		ts.LatencyP90 = percentile(sorted, 0.90)
+		ts.LatencyP99 = percentile(sorted, 0.99)
+
+		snapshot.Tools[name] = ts
+	}
+	return snapshot
+}
+
+func (w *Wrapper) ResetStats() {
+	w.stats.mu.Lock()
+	defer w.stats.mu.Unlock()
+	w.stats.since = time.Now()
+	w.stats.tools = make(map[string]*toolCounters)
+}
+
+// percentile uses the nearest-rank method on sorted samples.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(p*float64(len(sorted))+0.5) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	if rank >= len(sorted) {
+		rank = len(sorted) - 1
+	}
+	return sorted[rank]
+}
@@ -0,0 +1,98 @@
+package mcpwrapper
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func TestStats(t *testing.T) {
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	cfg := &Config{Tools: map[string]ToolConfig{"lookup": {Cache: &CacheConfig{}}}}
+	wrapper := New(mcpServer, WithConfig(cfg))
+
+	handler := func(ctx context.Context, args interface{}) (interface{}, error) {
+		if args.(*TestArgs).Name == "fail" {
+			return nil, errors.New("boom")
+		}
+		return &TestResult{Message: "ok"}, nil
+	}
+
+	if err := wrapper.Register("lookup", "Lookup tool", TestArgs{}, handler); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	call := func(name string) {
+		request := mcp.CallToolRequest{
+			Params: mcp.CallToolParams{
+				Name:      "lookup",
+				Arguments: map[string]interface{}{"name": name, "age": 30, "category": "A"},
+			},
+		}
+		if _, err := mcpServer.GetTool("lookup").Handler(context.Background(), request); err != nil {
+			t.Fatalf("Handler invocation failed: %v", err)
+		}
+	}
+
+	call("Alice")
+	call("Alice")
+	call("Bob")
+	call("fail")
+
+	stats := wrapper.Stats().Tools["lookup"]
+	if stats.Calls != 4 || stats.Errors != 1 || stats.ErrorRate != 0.25 {
+		t.Errorf("Unexpected call stats: %+v", stats)
+	}
+	if stats.CacheHits != 1 || stats.CacheMisses != 3 || stats.CacheHitRate != 0.25 {
+		t.Errorf("Unexpected cache stats: %+v", stats)
+	}
+	if stats.LatencyP99 < stats.LatencyP50 {
+		t.Errorf("Expected p99 >= p50, got %+v", stats)
+	}
+
+	rec := httptest.NewRecorder()
+	wrapper.AdminHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/stats", nil))
+	var body Stats
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || body.Tools["lookup"].Calls != 4 {
+		t.Errorf("Unexpected /stats response: %s", rec.Body.String())
+	}
+
+	before := wrapper.Stats().Since
+	wrapper.ResetStats()
+	after := wrapper.Stats()
+	if len(after.Tools) != 0 || !after.Since.After(before) {
+		t.Errorf("Expected empty stats after reset, got %+v", after)
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	var samples []time.Duration
+	for i := 1; i <= 100; i++ {
+		samples = append(samples, time.Duration(i)*time.Millisecond)
+	}
+
+	tests := []struct {
+		p        float64
+		expected time.Duration
+	}{
+		{0.50, 50 * time.Millisecond},
+		{0.90, 90 * time.Millisecond},
+		{0.99, 99 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		if got := percentile(samples, tt.p); got != tt.expected {
+			t.Errorf("p%v: expected %v, got %v", tt.p*100, tt.expected, got)
+		}
+	}
+
+	if got := percentile(nil, 0.5); got != 0 {
+		t.Errorf("Expected 0 for no samples, got %v", got)
+	}
+}
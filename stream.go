@@ -0,0 +1,204 @@
+package mcpwrapper
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// ReadMoreTool is the tool, added by the first RegisterStream, that returns
+// the next page of a streamed tool's output.
+const ReadMoreTool = "read_more"
+
+const (
+	defaultStreamPageSize = 32 * 1024
+	// maxProgressMessage bounds the output echoed in a progress notification.
+	maxProgressMessage = 200
+)
+
+// StreamHandler produces a tool's output by writing to out, for tools with
+// long outputs such as logs or file dumps. Writes fail once the call is
+// cancelled.
+type StreamHandler func(ctx context.Context, args interface{}, out io.Writer) error
+
+// WithPageSize sets how many bytes of a streamed tool's output each page
+// holds. The default is 32 KiB.
+func WithPageSize(bytes int) ToolOption {
+	return func(o *toolOptions) {
+		o.pageSize = bytes
+	}
+}
+
+// RegisterStream registers a tool whose handler writes its output instead of
+// returning it. Every write is reported as a notifications/progress message
+// (when the client asked for progress) carrying the bytes written so far and
+// the latest line. The result is the first page of the output; when there
+// is more, it ends with a continuation cursor for the read_more tool, which
+// is also in the result _meta as "next_cursor". Output is kept per session
+// like offloaded results.
+func (w *Wrapper) RegisterStream(name, description string, argsType interface{}, handler StreamHandler, opts ...ToolOption) error {
+	if handler == nil {
+		return fmt.Errorf("handler for tool %s must not be nil", name)
+	}
+	if err := w.registerReadMore(); err != nil {
+		return err
+	}
+
+	options := &toolOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	pageSize := options.pageSize
+	if pageSize <= 0 {
+		pageSize = defaultStreamPageSize
+	}
+
+	return w.Register(name, description, argsType, func(ctx context.Context, args interface{}) (interface{}, error) {
+		out := &streamWriter{ctx: ctx}
+		if err := handler(ctx, args, out); err != nil {
+			return nil, err
+		}
+
+		text := out.String()
+		if len(text) <= pageSize {
+			return mcp.NewToolResultText(text), nil
+		}
+		id := w.streams.put(SessionIDFromContext(ctx), text)
+		return streamPage(id, text, 0, pageSize), nil
+	}, opts...)
+}
+
+type readMoreArgs struct {
+	Cursor string `json:"cursor" jsonschema:"required,description=Cursor from the previous page" validate:"required"`
+}
+
+func (w *Wrapper) registerReadMore() error {
+	w.streamOnce.Do(func() {
+		w.streams = &oversizedStore{sessions: make(map[string]*sessionOversized)}
+		w.streamErr = w.Register(ReadMoreTool, "Return the next page of a tool's long output.", readMoreArgs{},
+			func(ctx context.Context, args interface{}) (interface{}, error) {
+				id, offset, pageSize, err := decodeStreamCursor(args.(*readMoreArgs).Cursor)
+				if err != nil {
+					return nil, InvalidInput("invalid cursor")
+				}
+				text, ok := w.streams.get(SessionIDFromContext(ctx), id)
+				if !ok || offset > len(text) {
+					return nil, NotFound("output for this cursor is no longer available; call the tool again")
+				}
+				return streamPage(id, text, offset, pageSize), nil
+			}, WithReadOnly())
+	})
+	return w.streamErr
+}
+
+// streamPage returns the page of text starting at offset.
+func streamPage(id, text string, offset, pageSize int) *mcp.CallToolResult {
+	end := pageEnd(text, offset, pageSize)
+	result := mcp.NewToolResultText(text[offset:end])
+	if end == len(text) {
+		return result
+	}
+
+	cursor := encodeStreamCursor(id, end, pageSize)
+	result.Content = append(result.Content, mcp.NewTextContent(fmt.Sprintf(
+		"[%d of %d bytes shown; call %s with cursor %q for more]", end, len(text), ReadMoreTool, cursor)))
+	result.Meta = mcp.NewMetaFromMap(map[string]interface{}{
+		"next_cursor": cursor,
+		"total_bytes": len(text),
+	})
+	return result
+}
+
+// pageEnd ends a page at the last line break within pageSize bytes, or at a
+// rune boundary if the page has no line break.
+func pageEnd(text string, offset, pageSize int) int {
+	end := offset + pageSize
+	if end >= len(text) {
+		return len(text)
+	}
+	if i := strings.LastIndexByte(text[offset:end], '\n'); i >= 0 {
+		return offset + i + 1
+	}
+	for end > offset && !utf8.RuneStart(text[end]) {
+		end--
+	}
+	if end == offset {
+		end = offset + pageSize
+	}
+	return end
+}
+
+func encodeStreamCursor(id string, offset, pageSize int) string {
+	raw := fmt.Sprintf("%s:%d:%d", id, offset, pageSize)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeStreamCursor(cursor string) (string, int, int, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return "", 0, 0, err
+	}
+	parts := strings.Split(string(raw), ":")
+	if len(parts) != 3 {
+		return "", 0, 0, fmt.Errorf("malformed cursor")
+	}
+	offset, err := strconv.Atoi(parts[1])
+	if err != nil || offset < 0 {
+		return "", 0, 0, fmt.Errorf("malformed cursor offset")
+	}
+	pageSize, err := strconv.Atoi(parts[2])
+	if err != nil || pageSize <= 0 {
+		return "", 0, 0, fmt.Errorf("malformed cursor page size")
+	}
+	return parts[0], offset, pageSize, nil
+}
+
+// streamWriter collects a streamed tool's output and reports each write as
+// progress.
+type streamWriter struct {
+	ctx context.Context
+
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (s *streamWriter) Write(p []byte) (int, error) {
+	if err := s.ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	s.mu.Lock()
+	s.buf.Write(p)
+	written := s.buf.Len()
+	s.mu.Unlock()
+
+	Progress(s.ctx, float64(written), 0, lastLine(p))
+	return len(p), nil
+}
+
+func (s *streamWriter) String() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.buf.String()
+}
+
+// lastLine returns the last non-empty line of a write, shortened for a
+// progress message.
+func lastLine(p []byte) string {
+	line := strings.TrimRight(string(p), "\r\n")
+	if i := strings.LastIndexByte(line, '\n'); i >= 0 {
+		line = line[i+1:]
+	}
+	if utf8.RuneCountInString(line) > maxProgressMessage {
+		line = string([]rune(line)[:maxProgressMessage]) + "..."
+	}
+	return line
+}
@@ -0,0 +1,113 @@
+package mcpwrapper
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+type DumpArgs struct {
+	Lines int `json:"lines" validate:"required"`
+}
+
+func TestRegisterStream(t *testing.T) {
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	wrapper := New(mcpServer)
+
+	err := wrapper.RegisterStream("dump_log", "Dump the build log", DumpArgs{}, func(ctx context.Context, args interface{}, out io.Writer) error {
+		for i := 1; i <= args.(*DumpArgs).Lines; i++ {
+			fmt.Fprintf(out, "line %02d\n", i)
+		}
+		return nil
+	}, WithPageSize(40))
+	if err != nil {
+		t.Fatalf("RegisterStream failed: %v", err)
+	}
+	if err := wrapper.RegisterStream("other", "Other stream", DumpArgs{}, func(ctx context.Context, args interface{}, out io.Writer) error {
+		return nil
+	}); err != nil {
+		t.Fatalf("Expected a second RegisterStream to share read_more, got %v", err)
+	}
+	if mcpServer.GetTool(ReadMoreTool) == nil {
+		t.Fatal("Expected the read_more tool")
+	}
+
+	session := &notifyingSession{id: "session-1", notifications: make(chan mcp.JSONRPCNotification, 100)}
+	ctx := mcpServer.WithContext(context.Background(), session)
+	call := func(name string, args map[string]interface{}, progress bool) *mcp.CallToolResult {
+		request := mcp.CallToolRequest{Params: mcp.CallToolParams{Name: name, Arguments: args}}
+		if progress {
+			request.Params.Meta = &mcp.Meta{ProgressToken: "p-1"}
+		}
+		result, err := mcpServer.GetTool(name).Handler(ctx, request)
+		if err != nil {
+			t.Fatalf("Handler failed: %v", err)
+		}
+		return result
+	}
+
+	// Each line is 8 bytes, so a 40 byte page holds 5 lines.
+	result := call("dump_log", map[string]interface{}{"lines": 12}, true)
+	var pages []string
+	for {
+		if result.IsError {
+			t.Fatalf("Call failed: %v", result.Content)
+		}
+		pages = append(pages, result.Content[0].(mcp.TextContent).Text)
+		if result.Meta == nil {
+			break
+		}
+		cursor := result.Meta.AdditionalFields["next_cursor"].(string)
+		if note := result.Content[1].(mcp.TextContent).Text; !strings.Contains(note, cursor) {
+			t.Errorf("Expected the cursor in the result text, got %q", note)
+		}
+		result = call(ReadMoreTool, map[string]interface{}{"cursor": cursor}, false)
+	}
+	if len(pages) != 3 || pages[0] != "line 01\nline 02\nline 03\nline 04\nline 05\n" || pages[2] != "line 11\nline 12\n" {
+		t.Errorf("Unexpected pages: %q", pages)
+	}
+
+	if len(session.notifications) != 12 {
+		t.Errorf("Expected a progress notification per write, got %d", len(session.notifications))
+	}
+	n := <-session.notifications
+	if n.Method != "notifications/progress" || n.Params.AdditionalFields["message"] != "line 01" {
+		t.Errorf("Unexpected notification: %+v", n)
+	}
+
+	short := call("dump_log", map[string]interface{}{"lines": 2}, false)
+	if short.Meta != nil || short.Content[0].(mcp.TextContent).Text != "line 01\nline 02\n" {
+		t.Errorf("Expected a single page, got %+v", short)
+	}
+
+	if result := call(ReadMoreTool, map[string]interface{}{"cursor": "bogus!"}, false); !result.IsError {
+		t.Error("Expected an invalid cursor to fail")
+	}
+	if result := call(ReadMoreTool, map[string]interface{}{"cursor": encodeStreamCursor("99", 0, 10)}, false); !result.IsError {
+		t.Error("Expected an unknown cursor to fail")
+	}
+}
+
+func TestPageEnd(t *testing.T) {
+	tests := []struct {
+		text             string
+		offset, pageSize int
+		want             int
+	}{
+		{"abcdef", 0, 10, 6},
+		{"ab\ncdef", 0, 5, 3},
+		{"abcdef", 0, 4, 4},
+		{"aé", 0, 2, 1},
+		{"ab\ncd\nef", 3, 4, 6},
+	}
+	for _, tt := range tests {
+		if got := pageEnd(tt.text, tt.offset, tt.pageSize); got != tt.want {
+			t.Errorf("pageEnd(%q, %d, %d) = %d, want %d", tt.text, tt.offset, tt.pageSize, got, tt.want)
+		}
+	}
+}
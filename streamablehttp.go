@@ -0,0 +1,306 @@
+package mcpwrapper
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// DefaultShutdownTimeout is how long ServeStreamableHTTP waits for
+// in-flight requests once its context is cancelled.
+const DefaultShutdownTimeout = 10 * time.Second
+
+// HTTPOption configures StreamableHTTPHandler and ServeStreamableHTTP.
+type HTTPOption func(*httpOptions)
+
+type httpOptions struct {
+	endpoint        string
+	tlsConfig       *tls.Config
+	auth            HTTPAuthFunc
+	middleware      []func(http.Handler) http.Handler
+	cors            *CORSOptions
+	shutdownTimeout time.Duration
+	serverOptions   []server.StreamableHTTPOption
+}
+
+// HTTPAuthFunc authenticates a request to the MCP endpoint. It returns the
+// context to serve the request with, typically carrying the caller's
+// identity for authorizers and handlers, or an error to reject the request
+// with 401 Unauthorized.
+type HTTPAuthFunc func(r *http.Request) (context.Context, error)
+
+// CORSOptions lets browser-based clients reach the MCP endpoint. Requests
+// with an Origin header that is not allowed are rejected with 403, which
+// also guards a local server against DNS rebinding.
+type CORSOptions struct {
+	// AllowedOrigins lists the allowed origins, such as
+	// "https://app.example.com". "*" allows any origin.
+	AllowedOrigins []string
+	// AllowedHeaders are allowed in addition to Content-Type,
+	// Authorization, Mcp-Session-Id, Mcp-Protocol-Version and
+	// Last-Event-ID.
+	AllowedHeaders   []string
+	AllowCredentials bool
+	// MaxAge is how long browsers may cache a preflight response.
+	MaxAge time.Duration
+}
+
+// WithEndpoint sets the path of the MCP endpoint. The default is "/mcp".
+func WithEndpoint(path string) HTTPOption {
+	return func(o *httpOptions) {
+		o.endpoint = path
+	}
+}
+
+// WithTLS serves HTTPS with config, which must provide the certificate
+// through Certificates or GetCertificate.
+func WithTLS(config *tls.Config) HTTPOption {
+	return func(o *httpOptions) {
+		o.tlsConfig = config
+	}
+}
+
+// WithHTTPAuth authenticates every request to the MCP endpoint with auth.
+// CORS preflight requests are answered before authentication.
+func WithHTTPAuth(auth HTTPAuthFunc) HTTPOption {
+	return func(o *httpOptions) {
+		o.auth = auth
+	}
+}
+
+// WithHTTPMiddleware wraps the MCP endpoint in mw, for concerns such as
+// request logging or rate limiting. The first middleware is the outermost;
+// all of them run after CORS and before authentication.
+func WithHTTPMiddleware(mw ...func(http.Handler) http.Handler) HTTPOption {
+	return func(o *httpOptions) {
+		o.middleware = append(o.middleware, mw...)
+	}
+}
+
+// WithCORS enables CORS on the MCP endpoint.
+func WithCORS(cors CORSOptions) HTTPOption {
+	return func(o *httpOptions) {
+		o.cors = &cors
+	}
+}
+
+// WithShutdownTimeout sets how long ServeStreamableHTTP waits for in-flight
+// requests to finish before closing their connections.
+func WithShutdownTimeout(d time.Duration) HTTPOption {
+	return func(o *httpOptions) {
+		o.shutdownTimeout = d
+	}
+}
+
+// WithStreamableHTTPOptions passes options through to mcp-go's
+// server.NewStreamableHTTPServer, such as server.WithStateLess or
+// server.WithHeartbeatInterval.
+func WithStreamableHTTPOptions(opts ...server.StreamableHTTPOption) HTTPOption {
+	return func(o *httpOptions) {
+		o.serverOptions = append(o.serverOptions, opts...)
+	}
+}
+
+func newHTTPOptions(opts []HTTPOption) *httpOptions {
+	o := &httpOptions{endpoint: "/mcp", shutdownTimeout: DefaultShutdownTimeout}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// StreamableHTTPHandler returns an http.Handler serving the wrapper's tools
+// over the streamable HTTP transport at the endpoint path, and the probes of
+// HealthHandler, for mounting on an existing server. Tools registered before
+// or after are served alike.
+func (w *Wrapper) StreamableHTTPHandler(opts ...HTTPOption) http.Handler {
+	return w.streamableHTTPHandler(newHTTPOptions(opts))
+}
+
+func (w *Wrapper) streamableHTTPHandler(o *httpOptions) http.Handler {
+	serverOptions := append([]server.StreamableHTTPOption{server.WithEndpointPath(o.endpoint)}, o.serverOptions...)
+	handler := w.handleMessages(server.NewStreamableHTTPServer(w.server, serverOptions...))
+
+	if o.auth != nil {
+		handler = w.authenticate(o.auth, handler)
+	}
+	for i := len(o.middleware) - 1; i >= 0; i-- {
+		handler = o.middleware[i](handler)
+	}
+	if o.cors != nil {
+		handler = o.cors.handler(handler)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(o.endpoint, handler)
+	health := w.HealthHandler()
+	mux.Handle("/healthz", health)
+	mux.Handle("/readyz", health)
+	return mux
+}
+
+// handleMessages answers the POSTed requests HandleMessage serves itself,
+// which mcp-go's handler would reject as unknown methods, and passes every
+// other request to next.
+func (w *Wrapper) handleMessages(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			next.ServeHTTP(rw, r)
+			return
+		}
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(rw, "failed to read request body", http.StatusBadRequest)
+			return
+		}
+		if !handlesMessage(body) {
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			next.ServeHTTP(rw, r)
+			return
+		}
+
+		ctx := r.Context()
+		if sessionID := r.Header.Get(server.HeaderKeySessionID); sessionID != "" {
+			ctx = w.server.WithContext(ctx, messageSession{id: sessionID})
+		}
+		rw.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(rw).Encode(w.HandleMessage(ctx, body)); err != nil {
+			w.logger.Error("failed to write HTTP response", "error", err)
+		}
+	})
+}
+
+func (w *Wrapper) authenticate(auth HTTPAuthFunc, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		ctx, err := auth(r)
+		if err != nil {
+			w.logger.Info("HTTP request rejected by authentication", "remote", r.RemoteAddr, "error", err)
+			http.Error(rw, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		if ctx != nil {
+			r = r.WithContext(ctx)
+		}
+		next.ServeHTTP(rw, r)
+	})
+}
+
+var defaultCORSHeaders = []string{"Content-Type", "Authorization", "Mcp-Session-Id", "Mcp-Protocol-Version", "Last-Event-ID"}
+
+func (c *CORSOptions) allows(origin string) bool {
+	for _, allowed := range c.AllowedOrigins {
+		if allowed == "*" || strings.EqualFold(allowed, origin) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *CORSOptions) handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			next.ServeHTTP(rw, r)
+			return
+		}
+
+		h := rw.Header()
+		h.Add("Vary", "Origin")
+		if !c.allows(origin) {
+			http.Error(rw, "origin not allowed", http.StatusForbidden)
+			return
+		}
+		h.Set("Access-Control-Allow-Origin", origin)
+		if c.AllowCredentials {
+			h.Set("Access-Control-Allow-Credentials", "true")
+		}
+
+		if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+			h.Set("Access-Control-Allow-Methods", "GET, POST, DELETE, OPTIONS")
+			h.Set("Access-Control-Allow-Headers", strings.Join(append(defaultCORSHeaders, c.AllowedHeaders...), ", "))
+			if c.MaxAge > 0 {
+				h.Set("Access-Control-Max-Age", strconv.Itoa(int(c.MaxAge.Seconds())))
+			}
+			rw.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		h.Set("Access-Control-Expose-Headers", "Mcp-Session-Id")
+		next.ServeHTTP(rw, r)
+	})
+}
+
+// ServeStreamableHTTP serves the wrapper's tools over the streamable HTTP
+// transport on addr until ctx is cancelled, then shuts down gracefully:
+// listening streams are closed at once, and requests in flight get the
+// shutdown timeout to finish before their connections are closed. It
+// returns nil after a shutdown, or the error that stopped the server.
+func (w *Wrapper) ServeStreamableHTTP(ctx context.Context, addr string, opts ...HTTPOption) error {
+	o := newHTTPOptions(opts)
+	handler := w.streamableHTTPHandler(o)
+
+	// Listening GET streams never finish on their own, so they are tied to
+	// a context that is cancelled when shutdown begins.
+	streams, closeStreams := context.WithCancel(context.Background())
+	defer closeStreams()
+
+	srv := &http.Server{
+		Addr:      addr,
+		TLSConfig: o.tlsConfig,
+		Handler: http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodGet {
+				ctx, cancel := context.WithCancel(r.Context())
+				defer cancel()
+				defer context.AfterFunc(streams, cancel)()
+				r = r.WithContext(ctx)
+			}
+			handler.ServeHTTP(rw, r)
+		}),
+	}
+	srv.RegisterOnShutdown(closeStreams)
+
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	w.logger.Info("serving streamable HTTP", "addr", ln.Addr().String(), "endpoint", o.endpoint, "tls", o.tlsConfig != nil)
+	w.MarkReady()
+
+	served := make(chan error, 1)
+	go func() {
+		if o.tlsConfig != nil {
+			served <- srv.ServeTLS(ln, "", "")
+		} else {
+			served <- srv.Serve(ln)
+		}
+	}()
+
+	select {
+	case err := <-served:
+		return err
+	case <-ctx.Done():
+	}
+
+	w.logger.Info("shutting down streamable HTTP", "timeout", o.shutdownTimeout)
+	w.health.stopping.Store(true)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), o.shutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		w.logger.Warn("requests still in flight at shutdown timeout, closing connections", "error", err)
+		srv.Close()
+	}
+	if err := <-served; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,213 @@
+package mcpwrapper
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/server"
+)
+
+type callerKey struct{}
+
+func newHTTPTestWrapper(t *testing.T) *Wrapper {
+	t.Helper()
+	wrapper := New(server.NewMCPServer("test", "1.0.0"))
+	err := wrapper.Register("whoami", "Return the caller", TestArgs{}, func(ctx context.Context, args interface{}) (interface{}, error) {
+		caller, _ := ctx.Value(callerKey{}).(string)
+		return &TestResult{Message: caller}, nil
+	})
+	if err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	return wrapper
+}
+
+func postMCP(t *testing.T, url, sessionID, body string, header http.Header) (*http.Response, string) {
+	t.Helper()
+	req, _ := http.NewRequest(http.MethodPost, url, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	if sessionID != "" {
+		req.Header.Set("Mcp-Session-Id", sessionID)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("POST failed: %v", err)
+	}
+	defer resp.Body.Close()
+	data, _ := io.ReadAll(resp.Body)
+	return resp, string(data)
+}
+
+const initializeRequest = `{"jsonrpc": "2.0", "id": 1, "method": "initialize", "params": {"protocolVersion": "2025-03-26", "clientInfo": {"name": "test", "version": "1.0.0"}, "capabilities": {}}}`
+
+func TestStreamableHTTPHandler(t *testing.T) {
+	wrapper := newHTTPTestWrapper(t)
+	handler := wrapper.StreamableHTTPHandler(
+		WithEndpoint("/rpc"),
+		WithHTTPAuth(func(r *http.Request) (context.Context, error) {
+			token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok || token == "" {
+				return nil, errors.New("missing bearer token")
+			}
+			return context.WithValue(r.Context(), callerKey{}, token), nil
+		}),
+		WithCORS(CORSOptions{AllowedOrigins: []string{"https://app.example.com"}, MaxAge: time.Hour}),
+	)
+	ts := httptest.NewServer(handler)
+	defer ts.Close()
+
+	auth := http.Header{"Authorization": {"Bearer alice"}}
+
+	if resp, _ := postMCP(t, ts.URL+"/rpc", "", initializeRequest, nil); resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("Expected 401 without credentials, got %d", resp.StatusCode)
+	}
+	if resp, _ := postMCP(t, ts.URL+"/mcp", "", initializeRequest, auth); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected 404 on the default path, got %d", resp.StatusCode)
+	}
+
+	resp, _ := postMCP(t, ts.URL+"/rpc", "", initializeRequest, auth)
+	sessionID := resp.Header.Get("Mcp-Session-Id")
+	if resp.StatusCode != http.StatusOK || sessionID == "" {
+		t.Fatalf("Initialize failed: %d", resp.StatusCode)
+	}
+	_, body := postMCP(t, ts.URL+"/rpc", sessionID,
+		`{"jsonrpc": "2.0", "id": 2, "method": "tools/call", "params": {"name": "whoami", "arguments": {"name": "who", "age": 1, "category": "A"}}}`, auth)
+	if !strings.Contains(body, `\"message\":\"alice\"`) {
+		t.Errorf("Expected the authenticated context to reach the handler, got %s", body)
+	}
+
+	preflight, _ := http.NewRequest(http.MethodOptions, ts.URL+"/rpc", nil)
+	preflight.Header.Set("Origin", "https://app.example.com")
+	preflight.Header.Set("Access-Control-Request-Method", "POST")
+	resp, err := http.DefaultClient.Do(preflight)
+	if err != nil {
+		t.Fatalf("Preflight failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent ||
+		resp.Header.Get("Access-Control-Allow-Origin") != "https://app.example.com" ||
+		!strings.Contains(resp.Header.Get("Access-Control-Allow-Headers"), "Mcp-Session-Id") ||
+		resp.Header.Get("Access-Control-Max-Age") != "3600" {
+		t.Errorf("Unexpected preflight response: %d %v", resp.StatusCode, resp.Header)
+	}
+
+	resp, _ = postMCP(t, ts.URL+"/rpc", "", initializeRequest, http.Header{"Authorization": {"Bearer alice"}, "Origin": {"https://evil.example.com"}})
+	if resp.StatusCode != http.StatusForbidden {
+		t.Errorf("Expected 403 for a disallowed origin, got %d", resp.StatusCode)
+	}
+}
+
+func TestStreamableHTTPWrapperMethods(t *testing.T) {
+	wrapper := New(server.NewMCPServer("test", "1.0.0", server.WithResourceCapabilities(true, false)))
+	err := wrapper.RegisterCompletion("checkout", "branch", func(ctx context.Context, partial string) ([]string, error) {
+		return []string{partial + "in"}, nil
+	})
+	if err != nil {
+		t.Fatalf("RegisterCompletion failed: %v", err)
+	}
+	err = wrapper.RegisterResource("status://build", "Build status", "Current build status", func(ctx context.Context, uri string) (interface{}, error) {
+		return "green", nil
+	})
+	if err != nil {
+		t.Fatalf("RegisterResource failed: %v", err)
+	}
+	ts := httptest.NewServer(wrapper.StreamableHTTPHandler())
+	defer ts.Close()
+
+	resp, _ := postMCP(t, ts.URL+"/mcp", "", initializeRequest, nil)
+	sessionID := resp.Header.Get("Mcp-Session-Id")
+	if resp.StatusCode != http.StatusOK || sessionID == "" {
+		t.Fatalf("Initialize failed: %d", resp.StatusCode)
+	}
+
+	_, body := postMCP(t, ts.URL+"/mcp", sessionID,
+		`{"jsonrpc": "2.0", "id": 2, "method": "completion/complete", "params": {"ref": {"type": "ref/prompt", "name": "checkout"}, "argument": {"name": "branch", "value": "ma"}}}`, nil)
+	if !strings.Contains(body, `"values":["main"]`) {
+		t.Errorf("Expected completions over HTTP, got %s", body)
+	}
+
+	_, body = postMCP(t, ts.URL+"/mcp", sessionID,
+		`{"jsonrpc": "2.0", "id": 3, "method": "resources/subscribe", "params": {"uri": "status://build"}}`, nil)
+	if !strings.Contains(body, `"result":{}`) {
+		t.Errorf("Expected subscribe to succeed over HTTP, got %s", body)
+	}
+	if subscribers := wrapper.resources.subscribersOf("status://build"); len(subscribers) != 1 || subscribers[0] != sessionID {
+		t.Errorf("Expected the HTTP session to be subscribed, got %v", subscribers)
+	}
+
+	postMCP(t, ts.URL+"/mcp", sessionID,
+		`{"jsonrpc": "2.0", "id": 4, "method": "resources/unsubscribe", "params": {"uri": "status://build"}}`, nil)
+	if subscribers := wrapper.resources.subscribersOf("status://build"); len(subscribers) != 0 {
+		t.Errorf("Expected no subscribers after unsubscribing, got %v", subscribers)
+	}
+
+	_, body = postMCP(t, ts.URL+"/mcp", sessionID, `{"jsonrpc": "2.0", "id": 5, "method": "ping"}`, nil)
+	if !strings.Contains(body, `"id":5,"result":{}`) {
+		t.Errorf("Expected other methods to reach the MCP server, got %s", body)
+	}
+}
+
+func TestServeStreamableHTTP(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+
+	wrapper := newHTTPTestWrapper(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+	go func() {
+		served <- wrapper.ServeStreamableHTTP(ctx, addr, WithShutdownTimeout(time.Second))
+	}()
+
+	// Without keep-alives the client leaves no spare connection open, which
+	// would hold up the shutdown until it times out.
+	client := &http.Client{Transport: &http.Transport{DisableKeepAlives: true}}
+	var resp *http.Response
+	for i := 0; i < 50; i++ {
+		if resp, err = client.Post("http://"+addr+"/mcp", "application/json", strings.NewReader(initializeRequest)); err == nil {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if err != nil {
+		t.Fatalf("Server did not come up: %v", err)
+	}
+	resp.Body.Close()
+	sessionID := resp.Header.Get("Mcp-Session-Id")
+
+	// A listening stream must not hold up the shutdown.
+	stream, _ := http.NewRequest(http.MethodGet, "http://"+addr+"/mcp", nil)
+	stream.Header.Set("Mcp-Session-Id", sessionID)
+	streamResp, err := client.Do(stream)
+	if err != nil {
+		t.Fatalf("GET stream failed: %v", err)
+	}
+	defer streamResp.Body.Close()
+
+	start := time.Now()
+	cancel()
+	select {
+	case err := <-served:
+		if err != nil {
+			t.Errorf("Expected a clean shutdown, got %v", err)
+		}
+		if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+			t.Errorf("Shutdown waited %v for a listening stream", elapsed)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("ServeStreamableHTTP did not return after cancellation")
+	}
+}
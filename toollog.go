@@ -0,0 +1,105 @@
+package mcpwrapper
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const methodNotificationProgress = "notifications/progress"
+
+// Logf appends a line to the tool's log. The line is streamed to the client
+// right away as an info-level notifications/message (subject to the client's
+// log level, see NotifyLog) and, when the client asked for progress, as a
+// notifications/progress message. The full log is also appended
+// to the final result as a "log" section, like CI output for build and deploy
+// tools. It is a no-op outside of a wrapped tool call.
+func Logf(ctx context.Context, format string, args ...interface{}) {
+	call := callFromContext(ctx)
+	if call == nil {
+		return
+	}
+
+	line := fmt.Sprintf(format, args...)
+
+	call.mu.Lock()
+	call.logs = append(call.logs, line)
+	step := float64(len(call.logs))
+	call.mu.Unlock()
+
+	call.log(ctx, mcp.LoggingLevelInfo, line)
+
+	if token := ProgressTokenFromContext(ctx); token != nil {
+		call.notify(ctx, methodNotificationProgress, map[string]interface{}{
+			"progressToken": token,
+			"progress":      step,
+			"message":       line,
+		})
+	}
+}
+
+// Progress reports how far the call has come. total may be 0 when unknown.
+// It only sends a notification when the client attached a progress token.
+func Progress(ctx context.Context, progress, total float64, message string) {
+	call := callFromContext(ctx)
+	token := ProgressTokenFromContext(ctx)
+	if call == nil || token == nil {
+		return
+	}
+
+	params := map[string]interface{}{
+		"progressToken": token,
+		"progress":      progress,
+	}
+	if total > 0 {
+		params["total"] = total
+	}
+	if message != "" {
+		params["message"] = message
+	}
+	call.notify(ctx, methodNotificationProgress, params)
+}
+
+// notify is best effort: a client that cannot receive notifications still
+// gets the log in the final result.
+func (c *callState) notify(ctx context.Context, method string, params map[string]interface{}) {
+	_ = c.wrapper.server.SendNotificationToClient(ctx, method, params)
+}
+
+// NotifyLog sends a logging notification to the client making the current
+// call, so diagnostics reach the client UI rather than only stderr. The
+// logger name is the tool name. Like every server log message, it is dropped
+// when level is below the one the client set with logging/setLevel (error
+// by default) or when the session does not support logging. data, if not
+// nil, is redacted and sent alongside message.
+func NotifyLog(ctx context.Context, level mcp.LoggingLevel, message string, data interface{}) {
+	call := callFromContext(ctx)
+	if call == nil {
+		return
+	}
+
+	if data == nil {
+		call.log(ctx, level, message)
+		return
+	}
+	call.log(ctx, level, map[string]interface{}{
+		"message": message,
+		"data":    Redact(data),
+	})
+}
+
+func (c *callState) log(ctx context.Context, level mcp.LoggingLevel, data interface{}) {
+	notification := mcp.NewLoggingMessageNotification(level, c.tool.name, data)
+	_ = c.wrapper.server.SendLogMessageToClient(ctx, notification)
+}
+
+func (c *callState) attachLog(result *mcp.CallToolResult) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.logs) == 0 {
+		return
+	}
+	result.Content = append(result.Content, mcp.NewTextContent("log:\n"+strings.Join(c.logs, "\n")))
+}
@@ -0,0 +1,158 @@
+package mcpwrapper
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+type notifyingSession struct {
+	id            string
+	notifications chan mcp.JSONRPCNotification
+	level         mcp.LoggingLevel
+}
+
+func (s *notifyingSession) Initialize()       {}
+func (s *notifyingSession) Initialized() bool { return true }
+func (s *notifyingSession) SessionID() string { return s.id }
+func (s *notifyingSession) NotificationChannel() chan<- mcp.JSONRPCNotification {
+	return s.notifications
+}
+func (s *notifyingSession) SetLogLevel(level mcp.LoggingLevel) { s.level = level }
+func (s *notifyingSession) GetLogLevel() mcp.LoggingLevel      { return s.level }
+
+func TestLogfStreamsAndAppendsLog(t *testing.T) {
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	wrapper := New(mcpServer)
+
+	handler := func(ctx context.Context, args interface{}) (interface{}, error) {
+		Logf(ctx, "building %s", args.(*TestArgs).Name)
+		Progress(ctx, 1, 2, "halfway")
+		Logf(ctx, "done")
+		return &TestResult{Message: "ok"}, nil
+	}
+
+	if err := wrapper.Register("build", "Build tool", TestArgs{}, handler); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	session := &notifyingSession{id: "session-1", notifications: make(chan mcp.JSONRPCNotification, 10), level: mcp.LoggingLevelInfo}
+	ctx := mcpServer.WithContext(context.Background(), session)
+	request := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name:      "build",
+			Arguments: map[string]interface{}{"name": "app", "age": 30, "category": "A"},
+			Meta:      &mcp.Meta{ProgressToken: "progress-1"},
+		},
+	}
+
+	result, err := mcpServer.GetTool("build").Handler(ctx, request)
+	if err != nil || result.IsError {
+		t.Fatalf("Handler invocation failed: %v %v", err, result)
+	}
+
+	if len(result.Content) != 2 {
+		t.Fatalf("Expected result and log content, got %d items", len(result.Content))
+	}
+	log := result.Content[1].(mcp.TextContent).Text
+	if log != "log:\nbuilding app\ndone" {
+		t.Errorf("Unexpected log section: %q", log)
+	}
+
+	close(session.notifications)
+	var methods []string
+	for n := range session.notifications {
+		methods = append(methods, n.Method)
+	}
+	expected := "notifications/message,notifications/progress,notifications/progress,notifications/message,notifications/progress"
+	if got := strings.Join(methods, ","); got != expected {
+		t.Errorf("Expected notifications %s, got %s", expected, got)
+	}
+}
+
+func TestLogfWithoutLinesLeavesResultAlone(t *testing.T) {
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	wrapper := New(mcpServer)
+
+	handler := func(ctx context.Context, args interface{}) (interface{}, error) {
+		return &TestResult{Message: "ok"}, nil
+	}
+
+	if err := wrapper.Register("quiet", "Quiet tool", TestArgs{}, handler); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	request := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name:      "quiet",
+			Arguments: map[string]interface{}{"name": "app", "age": 30, "category": "A"},
+		},
+	}
+
+	result, err := mcpServer.GetTool("quiet").Handler(context.Background(), request)
+	if err != nil || result.IsError {
+		t.Fatalf("Handler invocation failed: %v %v", err, result)
+	}
+	if len(result.Content) != 1 {
+		t.Errorf("Expected no log section, got %d items", len(result.Content))
+	}
+
+	Logf(context.Background(), "outside a call")
+}
+
+func TestNotifyLogRespectsClientLevel(t *testing.T) {
+	mcpServer := server.NewMCPServer("test", "1.0.0", server.WithLogging())
+	wrapper := New(mcpServer)
+
+	handler := func(ctx context.Context, args interface{}) (interface{}, error) {
+		NotifyLog(ctx, mcp.LoggingLevelDebug, "cache miss", nil)
+		NotifyLog(ctx, mcp.LoggingLevelWarning, "slow upstream", map[string]interface{}{"latency_ms": 900})
+		NotifyLog(ctx, mcp.LoggingLevelError, "retrying", CredentialArgs{Service: "billing", APIKey: "sk-123456"})
+		return &TestResult{Message: "ok"}, nil
+	}
+
+	if err := wrapper.Register("fetch", "Fetch tool", TestArgs{}, handler); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	session := &notifyingSession{id: "session-1", notifications: make(chan mcp.JSONRPCNotification, 10), level: mcp.LoggingLevelWarning}
+	ctx := mcpServer.WithContext(context.Background(), session)
+	request := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name:      "fetch",
+			Arguments: map[string]interface{}{"name": "app", "age": 30, "category": "A"},
+		},
+	}
+
+	if _, err := mcpServer.GetTool("fetch").Handler(ctx, request); err != nil {
+		t.Fatalf("Handler invocation failed: %v", err)
+	}
+
+	close(session.notifications)
+	var sent []mcp.JSONRPCNotification
+	for n := range session.notifications {
+		sent = append(sent, n)
+	}
+	if len(sent) != 2 {
+		t.Fatalf("Expected the debug message to be filtered out, got %d notifications", len(sent))
+	}
+
+	warning := sent[0].Params.AdditionalFields
+	if warning["level"] != mcp.LoggingLevelWarning || warning["logger"] != "fetch" {
+		t.Errorf("Unexpected warning notification: %v", warning)
+	}
+	data := warning["data"].(map[string]interface{})
+	if data["message"] != "slow upstream" {
+		t.Errorf("Unexpected warning data: %v", data)
+	}
+
+	redacted := sent[1].Params.AdditionalFields["data"].(map[string]interface{})["data"].(map[string]interface{})
+	if redacted["api_key"] != redactedValue {
+		t.Errorf("Expected redacted data, got %v", redacted)
+	}
+
+	NotifyLog(context.Background(), mcp.LoggingLevelError, "outside a call", nil)
+}
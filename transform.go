@@ -0,0 +1,211 @@
+package mcpwrapper
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+type TransformConfig struct {
+	Input  []TransformStep `json:"input,omitempty" yaml:"input,omitempty"`
+	Output []TransformStep `json:"output,omitempty" yaml:"output,omitempty"`
+}
+
+// TransformStep is a single declarative edit applied to a JSON object.
+// Paths use dot notation ("filter.limit") to address nested keys.
+//
+//	rename:  moves the value at From to To
+//	set:     writes Value at Path, overwriting any existing value
+//	default: writes Value at Path only when the key is absent
+//	delete:  removes Path
+//	pick:    keeps only the listed Fields at the top level
+//	jq:      replaces the object with the result of the jq expression Expr
+type TransformStep struct {
+	Op     string      `json:"op" yaml:"op"`
+	Path   string      `json:"path,omitempty" yaml:"path,omitempty"`
+	From   string      `json:"from,omitempty" yaml:"from,omitempty"`
+	To     string      `json:"to,omitempty" yaml:"to,omitempty"`
+	Value  interface{} `json:"value,omitempty" yaml:"value,omitempty"`
+	Fields []string    `json:"fields,omitempty" yaml:"fields,omitempty"`
+	Expr   string      `json:"expr,omitempty" yaml:"expr,omitempty"`
+}
+
+// compileJQ checks a jq expression, and runJQ runs one against a JSON
+// value. Both are nil in builds with the nojq tag.
+var (
+	compileJQ func(expr string) error
+	runJQ     func(expr string, v interface{}) (interface{}, error)
+)
+
+func (c *TransformConfig) validate() error {
+	for i, step := range c.Input {
+		if err := step.validate(); err != nil {
+			return fmt.Errorf("input transform %d: %w", i, err)
+		}
+	}
+	for i, step := range c.Output {
+		if err := step.validate(); err != nil {
+			return fmt.Errorf("output transform %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func (s TransformStep) validate() error {
+	switch s.Op {
+	case "rename":
+		if s.From == "" || s.To == "" {
+			return fmt.Errorf("rename requires from and to")
+		}
+	case "set", "default", "delete":
+		if s.Path == "" {
+			return fmt.Errorf("%s requires path", s.Op)
+		}
+	case "pick":
+		if len(s.Fields) == 0 {
+			return fmt.Errorf("pick requires fields")
+		}
+	case "jq":
+		if s.Expr == "" {
+			return fmt.Errorf("jq requires expr")
+		}
+		if compileJQ == nil {
+			return fmt.Errorf("jq is not available in builds with the nojq tag")
+		}
+		if err := compileJQ(s.Expr); err != nil {
+			return fmt.Errorf("invalid jq expression: %w", err)
+		}
+	default:
+		return fmt.Errorf("unknown op %q", s.Op)
+	}
+	return nil
+}
+
+func applyTransforms(obj map[string]interface{}, steps []TransformStep) (map[string]interface{}, error) {
+	for i, step := range steps {
+		switch step.Op {
+		case "rename":
+			if v, ok := getPath(obj, step.From); ok {
+				deletePath(obj, step.From)
+				setPath(obj, step.To, v)
+			}
+		case "set":
+			setPath(obj, step.Path, step.Value)
+		case "default":
+			if _, ok := getPath(obj, step.Path); !ok {
+				setPath(obj, step.Path, step.Value)
+			}
+		case "delete":
+			deletePath(obj, step.Path)
+		case "pick":
+			picked := make(map[string]interface{}, len(step.Fields))
+			for _, field := range step.Fields {
+				if v, ok := obj[field]; ok {
+					picked[field] = v
+				}
+			}
+			obj = picked
+		case "jq":
+			if runJQ == nil {
+				return nil, fmt.Errorf("step %d: jq is not available in builds with the nojq tag", i)
+			}
+			out, err := runJQ(step.Expr, obj)
+			if err != nil {
+				return nil, fmt.Errorf("step %d: %w", i, err)
+			}
+			next, ok := out.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("step %d: jq expression must produce an object, got %T", i, out)
+			}
+			obj = next
+		}
+	}
+	return obj, nil
+}
+
+func (w *Wrapper) transformInput(name string, args map[string]interface{}) (map[string]interface{}, error) {
+	transform := w.config.tool(name).Transform
+	if transform == nil || len(transform.Input) == 0 {
+		return args, nil
+	}
+	return applyTransforms(cloneMap(args), transform.Input)
+}
+
+// transformOutput only rewrites results that serialize to a JSON object;
+// anything else is returned unchanged.
+func (w *Wrapper) transformOutput(name string, result interface{}) (interface{}, error) {
+	transform := w.config.tool(name).Transform
+	if transform == nil || len(transform.Output) == 0 {
+		return result, nil
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		return nil, err
+	}
+
+	var obj map[string]interface{}
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return result, nil
+	}
+
+	return applyTransforms(obj, transform.Output)
+}
+
+func getPath(obj map[string]interface{}, path string) (interface{}, bool) {
+	keys := strings.Split(path, ".")
+	current := obj
+	for i, key := range keys {
+		v, ok := current[key]
+		if !ok {
+			return nil, false
+		}
+		if i == len(keys)-1 {
+			return v, true
+		}
+		next, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		current = next
+	}
+	return nil, false
+}
+
+func setPath(obj map[string]interface{}, path string, value interface{}) {
+	keys := strings.Split(path, ".")
+	current := obj
+	for _, key := range keys[:len(keys)-1] {
+		next, ok := current[key].(map[string]interface{})
+		if !ok {
+			next = make(map[string]interface{})
+			current[key] = next
+		}
+		current = next
+	}
+	current[keys[len(keys)-1]] = value
+}
+
+func deletePath(obj map[string]interface{}, path string) {
+	keys := strings.Split(path, ".")
+	current := obj
+	for _, key := range keys[:len(keys)-1] {
+		next, ok := current[key].(map[string]interface{})
+		if !ok {
+			return
+		}
+		current = next
+	}
+	delete(current, keys[len(keys)-1])
+}
+
+func cloneMap(src map[string]interface{}) map[string]interface{} {
+	dst := make(map[string]interface{}, len(src))
+	for k, v := range src {
+		if nested, ok := v.(map[string]interface{}); ok {
+			v = cloneMap(nested)
+		}
+		dst[k] = v
+	}
+	return dst
+}
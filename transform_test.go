@@ -0,0 +1,159 @@
+package mcpwrapper
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func TestParseConfigTransform(t *testing.T) {
+	data := []byte(`
+tools:
+  test-tool:
+    transform:
+      input:
+        - op: rename
+          from: full_name
+          to: name
+        - op: default
+          path: category
+          value: A
+      output:
+        - op: pick
+          fields: [message]
+`)
+
+	cfg, err := ParseConfig(data)
+	if err != nil {
+		t.Fatalf("ParseConfig failed: %v", err)
+	}
+
+	transform := cfg.Tools["test-tool"].Transform
+	if transform == nil {
+		t.Fatal("Expected transform config for test-tool")
+	}
+
+	if len(transform.Input) != 2 || len(transform.Output) != 1 {
+		t.Errorf("Expected 2 input and 1 output steps, got %d and %d", len(transform.Input), len(transform.Output))
+	}
+}
+
+func TestParseConfigInvalidTransform(t *testing.T) {
+	data := []byte(`{"tools": {"test-tool": {"transform": {"input": [{"op": "explode"}]}}}}`)
+
+	if _, err := ParseConfig(data); err == nil {
+		t.Error("Expected error for unknown transform op")
+	}
+}
+
+func TestApplyTransforms(t *testing.T) {
+	obj := map[string]interface{}{
+		"q":      "search",
+		"filter": map[string]interface{}{"limit": 5},
+		"debug":  true,
+	}
+
+	result, err := applyTransforms(obj, []TransformStep{
+		{Op: "rename", From: "q", To: "query"},
+		{Op: "default", Path: "filter.limit", Value: 10},
+		{Op: "set", Path: "filter.offset", Value: 0},
+		{Op: "delete", Path: "debug"},
+	})
+	if err != nil {
+		t.Fatalf("applyTransforms failed: %v", err)
+	}
+
+	if result["query"] != "search" {
+		t.Errorf("Expected query 'search', got '%v'", result["query"])
+	}
+
+	if _, ok := result["q"]; ok {
+		t.Error("Expected q to be renamed")
+	}
+
+	if _, ok := result["debug"]; ok {
+		t.Error("Expected debug to be deleted")
+	}
+
+	filter := result["filter"].(map[string]interface{})
+	if filter["limit"] != 5 {
+		t.Errorf("Expected existing limit to be kept, got '%v'", filter["limit"])
+	}
+
+	if filter["offset"] != 0 {
+		t.Errorf("Expected offset 0, got '%v'", filter["offset"])
+	}
+}
+
+func TestHandlerTransforms(t *testing.T) {
+	cfg := &Config{
+		Tools: map[string]ToolConfig{
+			"test-tool": {
+				Transform: &TransformConfig{
+					Input: []TransformStep{
+						{Op: "rename", From: "full_name", To: "name"},
+						{Op: "default", Path: "category", Value: "A"},
+					},
+					Output: []TransformStep{
+						{Op: "rename", From: "message", To: "greeting"},
+					},
+				},
+			},
+		},
+	}
+
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	wrapper := New(mcpServer, WithConfig(cfg))
+
+	var receivedArgs *TestArgs
+	handler := func(ctx context.Context, args interface{}) (interface{}, error) {
+		receivedArgs = args.(*TestArgs)
+		return &TestResult{Message: "Hello, " + receivedArgs.Name}, nil
+	}
+
+	if err := wrapper.Register("test-tool", "Test tool", TestArgs{}, handler); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	requestArgs := map[string]interface{}{
+		"full_name": "Alice",
+		"age":       30,
+	}
+
+	request := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name:      "test-tool",
+			Arguments: requestArgs,
+		},
+	}
+
+	result, err := mcpServer.GetTool("test-tool").Handler(context.Background(), request)
+	if err != nil {
+		t.Fatalf("Handler invocation failed: %v", err)
+	}
+
+	if result.IsError {
+		t.Fatalf("Expected success, got error result: %v", result.Content)
+	}
+
+	if receivedArgs.Name != "Alice" || receivedArgs.Category != "A" {
+		t.Errorf("Expected transformed args, got %+v", receivedArgs)
+	}
+
+	if _, ok := requestArgs["name"]; ok {
+		t.Error("Expected original request arguments to be left untouched")
+	}
+
+	var output map[string]interface{}
+	text := result.Content[0].(mcp.TextContent).Text
+	if err := json.Unmarshal([]byte(text), &output); err != nil {
+		t.Fatalf("Failed to unmarshal result: %v", err)
+	}
+
+	if output["greeting"] != "Hello, Alice" {
+		t.Errorf("Expected greeting 'Hello, Alice', got '%v'", output["greeting"])
+	}
+}
@@ -0,0 +1,58 @@
+//go:build !nojq
+
+package mcpwrapper
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/itchyny/gojq"
+)
+
+// jqPrograms caches compiled jq expressions by their source.
+var jqPrograms sync.Map
+
+func init() {
+	compileJQ = func(expr string) error {
+		_, err := jqProgram(expr)
+		return err
+	}
+	runJQ = runJQProgram
+}
+
+func jqProgram(expr string) (*gojq.Code, error) {
+	if code, ok := jqPrograms.Load(expr); ok {
+		return code.(*gojq.Code), nil
+	}
+	query, err := gojq.Parse(expr)
+	if err != nil {
+		return nil, err
+	}
+	code, err := gojq.Compile(query)
+	if err != nil {
+		return nil, err
+	}
+	jqPrograms.Store(expr, code)
+	return code, nil
+}
+
+// runJQProgram returns the first value expr produces for v.
+func runJQProgram(expr string, v interface{}) (interface{}, error) {
+	code, err := jqProgram(expr)
+	if err != nil {
+		return nil, err
+	}
+	out, ok := code.Run(v).Next()
+	if !ok {
+		return nil, errors.New("jq expression produced no value")
+	}
+	if err, ok := out.(error); ok {
+		var halt *gojq.HaltError
+		if errors.As(err, &halt) && halt.Value() == nil {
+			return nil, errors.New("jq expression halted")
+		}
+		return nil, fmt.Errorf("jq: %w", err)
+	}
+	return out, nil
+}
@@ -0,0 +1,83 @@
+//go:build !nojq
+
+package mcpwrapper
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/server"
+)
+
+type ListArgs struct {
+	Query string `json:"query"`
+	Limit int    `json:"limit"`
+}
+
+type ListResult struct {
+	Items []map[string]interface{} `json:"items"`
+}
+
+func TestJQTransform(t *testing.T) {
+	cfg, err := ParseConfig([]byte(`
+tools:
+  list:
+    transform:
+      input:
+        - op: jq
+          expr: '.query |= ascii_downcase | .limit //= 10'
+      output:
+        - op: jq
+          expr: '{active: [.items[] | select(.active) | .name], total: (.items | length)}'
+`))
+	if err != nil {
+		t.Fatalf("ParseConfig failed: %v", err)
+	}
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	wrapper := New(mcpServer, WithConfig(cfg))
+	var got *ListArgs
+	wrapper.Register("list", "List items", ListArgs{}, func(ctx context.Context, args interface{}) (interface{}, error) {
+		got = args.(*ListArgs)
+		return &ListResult{Items: []map[string]interface{}{
+			{"name": "a", "active": true},
+			{"name": "b", "active": false},
+		}}, nil
+	})
+
+	var output struct {
+		Active []string `json:"active"`
+		Total  int      `json:"total"`
+	}
+	decodeResult(t, callTool(t, mcpServer, "list", map[string]interface{}{"query": "Orders"}), &output)
+	if got == nil || got.Query != "orders" || got.Limit != 10 {
+		t.Errorf("Expected the arguments to be transformed, got %+v", got)
+	}
+	if len(output.Active) != 1 || output.Active[0] != "a" || output.Total != 2 {
+		t.Errorf("Expected the result to be transformed, got %+v", output)
+	}
+
+	result := callTool(t, mcpServer, "list", map[string]interface{}{"query": 5})
+	if !result.IsError || !strings.Contains(resultText(result), "failed to transform arguments") {
+		t.Errorf("Expected a failing expression to reject the call, got %v", result.Content)
+	}
+}
+
+func TestParseConfigInvalidJQ(t *testing.T) {
+	for _, step := range []string{
+		`{op: jq}`,
+		`{op: jq, expr: ".items[] |"}`,
+		`{op: jq, expr: "undefined_function(1)"}`,
+	} {
+		if _, err := ParseConfig([]byte("tools:\n  list:\n    transform:\n      input: [" + step + "]\n")); err == nil {
+			t.Errorf("Expected %s to be rejected", step)
+		}
+	}
+}
+
+func TestJQTransformMustProduceObject(t *testing.T) {
+	_, err := applyTransforms(map[string]interface{}{"items": []interface{}{}}, []TransformStep{{Op: "jq", Expr: ".items"}})
+	if err == nil || !strings.Contains(err.Error(), "must produce an object") {
+		t.Errorf("Expected a non-object result to fail, got %v", err)
+	}
+}
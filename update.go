@@ -0,0 +1,105 @@
+package mcpwrapper
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// UpdateDescription changes the description of a registered tool while the
+// server is running. name is the name the tool was registered with, with
+// its version if it has one.
+func (w *Wrapper) UpdateDescription(name, description string) error {
+	return w.updateTool(name, func(t *registeredTool) error {
+		t.description = description
+		return nil
+	})
+}
+
+// UpdateSchema changes the input schema of a registered tool while the
+// server is running, for tools whose valid values change, such as the list
+// of projects. argsType is a struct, as for Register, or a JSON Schema
+// object, as for RegisterSchema; the handler then receives a value of the
+// new type, or Arguments. The tool keeps its options and middleware.
+func (w *Wrapper) UpdateSchema(name string, argsType interface{}) error {
+	var schema *mcp.ToolInputSchema
+	var err error
+	if raw, ok := argsType.(map[string]interface{}); ok {
+		schema, err = parseInputSchema(raw)
+		argsType = Arguments{}
+	} else {
+		schema, err = w.schema(argsType)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to build schema for tool %s: %w", name, err)
+	}
+
+	return w.updateTool(name, func(t *registeredTool) error {
+		// FileArg fields need the workspace middleware, which is set up
+		// when the tool is registered.
+		if hasFileArgs(reflect.TypeOf(argsType), map[reflect.Type]bool{}) &&
+			(t.options.workspace == nil || !t.options.workspace.files) {
+			return fmt.Errorf("cannot add FileArg fields to tool %s; register it again", name)
+		}
+		t.argsType = argsType
+		t.schema = schema
+		return nil
+	})
+}
+
+// updateTool replaces a registered tool with a changed copy, so that calls
+// in progress keep the tool they started with, and lists it again. Adding
+// the tool sends clients notifications/tools/list_changed, unless the
+// server was created with server.WithToolCapabilities(false).
+func (w *Wrapper) updateTool(name string, change func(t *registeredTool) error) error {
+	w.updateMu.Lock()
+	defer w.updateMu.Unlock()
+
+	current, ok := w.lookupTool(name)
+	if !ok {
+		return fmt.Errorf("tool %s is not registered", name)
+	}
+	updated := *current
+	if err := change(&updated); err != nil {
+		return err
+	}
+	tool, err := w.buildTool(name, updated.description, updated.argsType, updated.schema, updated.options)
+	if err != nil {
+		return err
+	}
+	updated.tool = tool
+
+	w.toolsMu.Lock()
+	w.tools[name] = &updated
+	aliases := append([]string(nil), w.aliases[name]...)
+	var baseName string
+	latest := true
+	for base, versions := range w.versions {
+		for i, t := range versions {
+			if t == current {
+				versions[i] = &updated
+				baseName = base
+				latest = i == len(versions)-1
+			}
+		}
+	}
+	w.toolsMu.Unlock()
+
+	if baseName == "" {
+		w.server.AddTool(tool, w.createHandler(&updated))
+	} else {
+		if w.versionPolicy == ListAllVersions {
+			w.server.AddTool(tool, w.createHandler(&updated))
+		}
+		if latest {
+			tool.Name = baseName
+			w.server.AddTool(tool, w.createHandler(&updated))
+		}
+	}
+	for _, alias := range aliases {
+		w.addAlias(alias, &updated)
+	}
+	w.logger.Info("updated tool", "tool", name)
+	return nil
+}
@@ -0,0 +1,134 @@
+package mcpwrapper
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+type ProjectArgs struct {
+	Project string `json:"project" jsonschema:"required,enum=alpha,enum=beta"`
+}
+
+type ProjectArgsV2 struct {
+	Project string `json:"project" jsonschema:"required,enum=alpha,enum=beta,enum=gamma"`
+	Branch  string `json:"branch,omitempty"`
+}
+
+func TestUpdateTool(t *testing.T) {
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	wrapper := New(mcpServer)
+	var got interface{}
+	handler := func(ctx context.Context, args interface{}) (interface{}, error) {
+		got = args
+		return &TestResult{Message: "ok"}, nil
+	}
+	if err := wrapper.Register("open", "Open a project", ProjectArgs{}, handler, WithReadOnly()); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	session := &notifyingSession{id: "session-1", notifications: make(chan mcp.JSONRPCNotification, 10)}
+	ctx := mcpServer.WithContext(context.Background(), session)
+	if err := mcpServer.RegisterSession(ctx, session); err != nil {
+		t.Fatalf("RegisterSession failed: %v", err)
+	}
+
+	if err := wrapper.UpdateDescription("open", "Open a project by name"); err != nil {
+		t.Fatalf("UpdateDescription failed: %v", err)
+	}
+	tool := mcpServer.GetTool("open").Tool
+	if tool.Description != "Open a project by name" || tool.Annotations.ReadOnlyHint == nil || !*tool.Annotations.ReadOnlyHint {
+		t.Errorf("Expected the new description and the tool's options, got %+v", tool)
+	}
+	if len(session.notifications) != 1 || (<-session.notifications).Method != mcp.MethodNotificationToolsListChanged {
+		t.Error("Expected a list_changed notification")
+	}
+
+	if err := wrapper.UpdateSchema("open", ProjectArgsV2{}); err != nil {
+		t.Fatalf("UpdateSchema failed: %v", err)
+	}
+	tool = mcpServer.GetTool("open").Tool
+	enum := tool.InputSchema.Properties["project"].(map[string]interface{})["enum"]
+	if len(enum.([]string)) != 3 || tool.InputSchema.Properties["branch"] == nil || tool.Description != "Open a project by name" {
+		t.Errorf("Unexpected updated tool: %+v", tool)
+	}
+	result := callTool(t, mcpServer, "open", map[string]interface{}{"project": "gamma", "branch": "main"})
+	if args, ok := got.(*ProjectArgsV2); result.IsError || !ok || args.Branch != "main" {
+		t.Errorf("Expected the handler to receive the new type, got %T %v", got, result.Content)
+	}
+
+	err := wrapper.UpdateSchema("open", map[string]interface{}{
+		"type":       "object",
+		"properties": map[string]interface{}{"project": map[string]interface{}{"type": "string", "enum": []interface{}{"delta"}}},
+		"required":   []interface{}{"project"},
+	})
+	if err != nil {
+		t.Fatalf("UpdateSchema failed: %v", err)
+	}
+	if result := callTool(t, mcpServer, "open", map[string]interface{}{"project": "alpha"}); !result.IsError {
+		t.Error("Expected the new enum to be enforced")
+	}
+	callTool(t, mcpServer, "open", map[string]interface{}{"project": "delta"})
+	if args, ok := got.(Arguments); !ok || args["project"] != "delta" {
+		t.Errorf("Expected Arguments, got %#v", got)
+	}
+
+	if err := wrapper.UpdateDescription("missing", "x"); err == nil || !strings.Contains(err.Error(), "not registered") {
+		t.Errorf("Expected an error for an unknown tool, got %v", err)
+	}
+	if err := wrapper.UpdateSchema("open", "not a struct"); err == nil {
+		t.Error("Expected an error for an invalid argsType")
+	}
+}
+
+func TestUpdateVersionedTool(t *testing.T) {
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	wrapper := New(mcpServer)
+	handler := func(ctx context.Context, args interface{}) (interface{}, error) {
+		return &TestResult{Message: "ok"}, nil
+	}
+	wrapper.Register("open", "Open v1", ProjectArgs{}, handler, WithVersion("v1"))
+	wrapper.Register("open", "Open v2", ProjectArgs{}, handler, WithVersion("v2"))
+
+	if err := wrapper.UpdateDescription("open_v2", "Open a project"); err != nil {
+		t.Fatalf("UpdateDescription failed: %v", err)
+	}
+	if d := mcpServer.GetTool("open").Tool.Description; d != "Open a project" {
+		t.Errorf("Expected the latest version's update under the base name, got %q", d)
+	}
+
+	if err := wrapper.UpdateDescription("open_v1", "Open a project, old style"); err != nil {
+		t.Fatalf("UpdateDescription failed: %v", err)
+	}
+	if d := mcpServer.GetTool("open").Tool.Description; d != "Open a project" {
+		t.Errorf("Expected an older version's update to leave the base name alone, got %q", d)
+	}
+}
+
+func TestUpdateAliasedTool(t *testing.T) {
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	wrapper := New(mcpServer)
+	var got interface{}
+	wrapper.Register("open_project", "Open a project", ProjectArgs{}, func(ctx context.Context, args interface{}) (interface{}, error) {
+		got = args
+		return &TestResult{Message: "ok"}, nil
+	})
+	if err := wrapper.Alias("open", "open_project"); err != nil {
+		t.Fatalf("Alias failed: %v", err)
+	}
+
+	if err := wrapper.UpdateSchema("open_project", ProjectArgsV2{}); err != nil {
+		t.Fatalf("UpdateSchema failed: %v", err)
+	}
+	alias := mcpServer.GetTool("open").Tool
+	if alias.InputSchema.Properties["branch"] == nil || !strings.HasPrefix(alias.Description, "[DEPRECATED") {
+		t.Errorf("Expected the alias to list the new schema, got %+v", alias)
+	}
+	result := callTool(t, mcpServer, "open", map[string]interface{}{"project": "gamma", "branch": "main"})
+	if args, ok := got.(*ProjectArgsV2); result.IsError || !ok || args.Branch != "main" {
+		t.Errorf("Expected the alias to reach the updated tool, got %T %v", got, result.Content)
+	}
+}
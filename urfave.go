@@ -0,0 +1,365 @@
+//go:build !nourfave
+
+package mcpwrapper
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/urfave/cli/v3"
+)
+
+// UrfaveOption configures RegisterUrfave.
+type UrfaveOption func(*urfaveOptions)
+
+type urfaveOptions struct {
+	excluded    map[string]bool
+	toolOptions []ToolOption
+}
+
+// WithUrfaveExcludedFlags leaves flags out of the schemas, by name without
+// dashes.
+func WithUrfaveExcludedFlags(names ...string) UrfaveOption {
+	return func(o *urfaveOptions) {
+		for _, name := range names {
+			o.excluded[strings.TrimLeft(name, "-")] = true
+		}
+	}
+}
+
+// WithUrfaveToolOptions applies tool options to every registered command.
+func WithUrfaveToolOptions(opts ...ToolOption) UrfaveOption {
+	return func(o *urfaveOptions) {
+		o.toolOptions = append(o.toolOptions, opts...)
+	}
+}
+
+// RegisterUrfave registers a urfave/cli command and each of its
+// subcommands that has an Action as a tool, mirroring RegisterCobraFlags:
+//
+//   - Tools are named after the command path below cmd, joined with
+//     underscores, e.g. "users_list"; cmd itself is named after itself.
+//   - The description is the command's Usage, or else its Description.
+//   - The schema has one property per flag, named in snake_case, including
+//     the non-local flags of parent commands, and an "args" string array
+//     for positional arguments. Required flags are required; hidden flags
+//     are left out unless required.
+//
+// A call builds the command line from the arguments and runs it through
+// cmd.Run, so parsing, Before and After hooks and flag actions behave as
+// they would in a shell. What the commands write to Writer and ErrWriter is
+// returned as the result's Stdout and Stderr, and errors, including exit
+// errors, are returned as ExitErrors instead of exiting the process. It
+// returns the registered tool names.
+func (w *Wrapper) RegisterUrfave(cmd *cli.Command, opts ...UrfaveOption) ([]string, error) {
+	options := &urfaveOptions{excluded: map[string]bool{"help": true, "version": true}}
+	for _, opt := range opts {
+		opt(options)
+	}
+	if cmd.Name == "" {
+		return nil, fmt.Errorf("urfave command must have a Name")
+	}
+
+	// urfave/cli keeps parse state in its flags, so each call starts from
+	// the flags as they are now.
+	var restores []func()
+	var names []string
+	var walk func(path []*cli.Command) error
+	walk = func(path []*cli.Command) error {
+		c := path[len(path)-1]
+		for _, flag := range c.Flags {
+			restores = append(restores, snapshotUrfaveFlag(flag))
+		}
+		if c.Action != nil {
+			name, err := w.registerUrfaveCommand(cmd, path, options, &restores)
+			if err != nil {
+				return err
+			}
+			names = append(names, name)
+		}
+		for _, sub := range c.Commands {
+			if sub.Hidden || sub.Name == "help" {
+				continue
+			}
+			if err := walk(append(path[:len(path):len(path)], sub)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := walk([]*cli.Command{cmd}); err != nil {
+		return names, err
+	}
+	return names, nil
+}
+
+func (w *Wrapper) registerUrfaveCommand(root *cli.Command, path []*cli.Command, options *urfaveOptions, restores *[]func()) (string, error) {
+	cmd := path[len(path)-1]
+	name := urfaveToolName(path)
+
+	flags := urfaveFlags(path, options)
+	schema := mcp.ToolInputSchema{
+		Type:       "object",
+		Properties: make(map[string]interface{}, len(flags)+1),
+		Required:   make([]string, 0),
+	}
+	for prop, flag := range flags {
+		schema.Properties[prop] = urfaveFlagSchema(flag)
+		if required, ok := flag.(cli.RequiredFlag); ok && required.IsRequired() {
+			schema.Required = append(schema.Required, prop)
+		}
+	}
+	sort.Strings(schema.Required)
+	if _, taken := flags[argsProperty]; taken {
+		return "", fmt.Errorf("command %s: flag --%s collides with the positional arguments", name, argsProperty)
+	}
+	argsDescription := "Positional arguments"
+	if cmd.ArgsUsage != "" {
+		argsDescription += ": " + cmd.ArgsUsage
+	}
+	schema.Properties[argsProperty] = map[string]interface{}{
+		"type":        "array",
+		"items":       map[string]interface{}{"type": "string"},
+		"description": argsDescription,
+	}
+
+	handler := func(ctx context.Context, args interface{}) (interface{}, error) {
+		arguments := args.(Arguments)
+		argv := []string{root.Name}
+		for _, c := range path[1:] {
+			argv = append(argv, c.Name)
+		}
+		argv = append(argv, urfaveFlagArgs(flags, arguments)...)
+		if values, ok := arguments[argsProperty].([]interface{}); ok && len(values) > 0 {
+			argv = append(argv, "--")
+			for _, v := range values {
+				argv = append(argv, argumentString(v))
+			}
+		}
+
+		defer lockUrfave(root)()
+		for _, restore := range *restores {
+			restore()
+		}
+		return runUrfave(ctx, root, path, argv)
+	}
+
+	description := cmd.Usage
+	if description == "" {
+		description = cmd.Description
+	}
+	if description == "" {
+		description = fmt.Sprintf("Execute %s command", cmd.Name)
+	}
+	return name, w.register(name, description, Arguments{}, &schema, handler, options.toolOptions)
+}
+
+func urfaveToolName(path []*cli.Command) string {
+	if len(path) == 1 {
+		return path[0].Name
+	}
+	names := make([]string, 0, len(path)-1)
+	for _, c := range path[1:] {
+		names = append(names, c.Name)
+	}
+	return strings.Join(names, "_")
+}
+
+// urfaveFlags returns the flags a command accepts by property name: its
+// own, then the non-local flags of its ancestors, nearest first.
+func urfaveFlags(path []*cli.Command, options *urfaveOptions) map[string]cli.Flag {
+	flags := make(map[string]cli.Flag)
+	for i := len(path) - 1; i >= 0; i-- {
+		for _, flag := range path[i].Flags {
+			if i < len(path)-1 {
+				if local, ok := flag.(cli.LocalFlag); !ok || local.IsLocal() {
+					continue
+				}
+			}
+			names := flag.Names()
+			if len(names) == 0 || options.excluded[names[0]] {
+				continue
+			}
+			if visible, ok := flag.(cli.VisibleFlag); ok && !visible.IsVisible() {
+				if required, ok := flag.(cli.RequiredFlag); !ok || !required.IsRequired() {
+					continue
+				}
+			}
+			prop := strings.ReplaceAll(names[0], "-", "_")
+			if _, ok := flags[prop]; !ok {
+				flags[prop] = flag
+			}
+		}
+	}
+	return flags
+}
+
+// urfaveFlagSchema describes a flag's value from its schema type.
+func urfaveFlagSchema(flag cli.Flag) map[string]interface{} {
+	typ := "string"
+	if typer, ok := flag.(cli.SchemaTyper); ok && typer.SchemaType() != "" {
+		typ = typer.SchemaType()
+	} else if doc, ok := flag.(cli.DocGenerationFlag); ok && !doc.TakesValue() {
+		typ = "boolean"
+	}
+
+	prop := map[string]interface{}{"type": typ}
+	switch typ {
+	case "duration":
+		prop["type"] = "string"
+		prop["format"] = "duration"
+		prop["pattern"] = durationPattern
+	case "date-time":
+		prop["type"] = "string"
+		prop["format"] = "date-time"
+	case "array":
+		items := "string"
+		if typer, ok := flag.(cli.SchemaItemsTyper); ok && typer.SchemaItemsType() != "" {
+			items = typer.SchemaItemsType()
+		}
+		prop["items"] = map[string]interface{}{"type": items}
+	case "object":
+		prop["additionalProperties"] = map[string]interface{}{"type": "string"}
+	}
+
+	if doc, ok := flag.(cli.DocGenerationFlag); ok && doc.GetUsage() != "" {
+		prop["description"] = doc.GetUsage()
+	}
+	if def, ok := urfaveFlagDefault(flag); ok {
+		prop["default"] = def
+	}
+	return prop
+}
+
+// urfaveFlagDefault returns the Value of a cli.FlagBase flag if it isn't
+// the zero value.
+func urfaveFlagDefault(flag cli.Flag) (interface{}, bool) {
+	v := reflect.ValueOf(flag)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return nil, false
+	}
+	value := v.Elem().FieldByName("Value")
+	if !value.IsValid() || value.IsZero() {
+		return nil, false
+	}
+	switch d := value.Interface().(type) {
+	case fmt.Stringer:
+		return d.String(), true
+	default:
+		return d, true
+	}
+}
+
+// urfaveFlagArgs renders the arguments for flags as command-line flags,
+// in property order.
+func urfaveFlagArgs(flags map[string]cli.Flag, arguments Arguments) []string {
+	props := make([]string, 0, len(flags))
+	for prop := range flags {
+		props = append(props, prop)
+	}
+	sort.Strings(props)
+
+	var argv []string
+	for _, prop := range props {
+		value, ok := arguments[prop]
+		if !ok || value == nil {
+			continue
+		}
+		name := flags[prop].Names()[0]
+		if len(name) == 1 {
+			name = "-" + name
+		} else {
+			name = "--" + name
+		}
+		switch v := value.(type) {
+		case bool:
+			argv = append(argv, fmt.Sprintf("%s=%t", name, v))
+		case []interface{}:
+			for _, item := range v {
+				argv = append(argv, name+"="+argumentString(item))
+			}
+		case map[string]interface{}:
+			keys := make([]string, 0, len(v))
+			for k := range v {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				argv = append(argv, name+"="+k+"="+argumentString(v[k]))
+			}
+		default:
+			argv = append(argv, name+"="+argumentString(v))
+		}
+	}
+	return argv
+}
+
+// snapshotUrfaveFlag returns a function that restores a cli.FlagBase flag,
+// including the parse state it keeps between runs, to its current state.
+func snapshotUrfaveFlag(flag cli.Flag) func() {
+	v := reflect.ValueOf(flag)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return func() {}
+	}
+	saved := reflect.New(v.Elem().Type()).Elem()
+	saved.Set(v.Elem())
+	return func() { v.Elem().Set(saved) }
+}
+
+// urfaveLocks serializes calls per root command, whose flags and writers
+// every call changes.
+var urfaveLocks sync.Map // root *cli.Command -> *sync.Mutex
+
+func lockUrfave(root *cli.Command) func() {
+	mu, _ := urfaveLocks.LoadOrStore(root, &sync.Mutex{})
+	mu.(*sync.Mutex).Lock()
+	return mu.(*sync.Mutex).Unlock
+}
+
+// runUrfave runs argv through root, capturing the output of the commands
+// on path and keeping exit errors from exiting the process.
+func runUrfave(ctx context.Context, root *cli.Command, path []*cli.Command, argv []string) (*ExecResult, error) {
+	var stdoutBuf, stderrBuf bytes.Buffer
+	writers := make([][2]io.Writer, len(path))
+	for i, c := range path {
+		writers[i] = [2]io.Writer{c.Writer, c.ErrWriter}
+		c.Writer, c.ErrWriter = &stdoutBuf, &stderrBuf
+	}
+	exitHandler := root.ExitErrHandler
+	root.ExitErrHandler = func(context.Context, *cli.Command, error) {}
+	defer func() {
+		root.ExitErrHandler = exitHandler
+		// The first Run fills in missing writers once, so a nil one is
+		// restored to what it would have been given.
+		for i, c := range path {
+			c.Writer, c.ErrWriter = writers[i][0], writers[i][1]
+			if c.Writer == nil {
+				c.Writer = os.Stdout
+			}
+			if c.ErrWriter == nil {
+				c.ErrWriter = os.Stderr
+			}
+		}
+	}()
+
+	start := time.Now()
+	err := root.Run(ctx, argv)
+	result := &ExecResult{
+		Stdout:     stdoutBuf.String(),
+		Stderr:     stderrBuf.String(),
+		DurationMs: time.Since(start).Milliseconds(),
+	}
+	if err := commandExit(result, err); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
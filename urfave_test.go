@@ -0,0 +1,158 @@
+//go:build !nourfave
+
+package mcpwrapper
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/urfave/cli/v3"
+)
+
+func TestRegisterUrfave(t *testing.T) {
+	var gotOrg, gotFormat string
+	var gotLimit int
+	var gotTimeout time.Duration
+	var gotTags, gotArgs []string
+	var before []string
+	app := &cli.Command{
+		Name:  "app",
+		Flags: []cli.Flag{&cli.StringFlag{Name: "org", Usage: "Organization"}},
+		Before: func(ctx context.Context, cmd *cli.Command) (context.Context, error) {
+			before = append(before, cmd.Name)
+			return ctx, nil
+		},
+		Commands: []*cli.Command{{
+			Name:  "users",
+			Usage: "Manage users",
+			Commands: []*cli.Command{{
+				Name:      "list",
+				Usage:     "List users",
+				ArgsUsage: "[filter]",
+				Flags: []cli.Flag{
+					&cli.IntFlag{Name: "limit", Value: 20, Usage: "Users per page"},
+					&cli.StringFlag{Name: "format", Required: true, OnlyOnce: true},
+					&cli.DurationFlag{Name: "timeout"},
+					&cli.StringSliceFlag{Name: "tag"},
+					&cli.BoolFlag{Name: "debug", Hidden: true},
+				},
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					gotOrg, gotFormat = cmd.String("org"), cmd.String("format")
+					gotLimit, gotTimeout = int(cmd.Int("limit")), cmd.Duration("timeout")
+					gotTags, gotArgs = cmd.StringSlice("tag"), cmd.Args().Slice()
+					if gotFormat == "fail" {
+						return cli.Exit("bad format", 3)
+					}
+					cmd.Writer.Write([]byte("alice\n"))
+					return nil
+				},
+			}},
+		}, {
+			Name:   "secret",
+			Hidden: true,
+			Action: func(ctx context.Context, cmd *cli.Command) error { return nil },
+		}},
+	}
+
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	wrapper := New(mcpServer)
+	names, err := wrapper.RegisterUrfave(app)
+	if err != nil {
+		t.Fatalf("RegisterUrfave failed: %v", err)
+	}
+	if !reflect.DeepEqual(names, []string{"users_list"}) {
+		t.Fatalf("Expected only users_list, got %v", names)
+	}
+
+	tool := mcpServer.GetTool("users_list").Tool
+	if tool.Description != "List users" {
+		t.Errorf("Expected the Usage as description, got %q", tool.Description)
+	}
+	var props []string
+	for name := range tool.InputSchema.Properties {
+		props = append(props, name)
+	}
+	sort.Strings(props)
+	if want := []string{"args", "format", "limit", "org", "tag", "timeout"}; !reflect.DeepEqual(props, want) {
+		t.Errorf("Expected properties %v, got %v", want, props)
+	}
+	schema := func(name string) map[string]interface{} {
+		return tool.InputSchema.Properties[name].(map[string]interface{})
+	}
+	if p := schema("limit"); p["type"] != "integer" || p["default"] != 20 {
+		t.Errorf("Unexpected limit schema: %v", p)
+	}
+	if p := schema("timeout"); p["type"] != "string" || p["format"] != "duration" {
+		t.Errorf("Unexpected timeout schema: %v", p)
+	}
+	if p := schema("tag"); p["type"] != "array" {
+		t.Errorf("Unexpected tag schema: %v", p)
+	}
+	if !reflect.DeepEqual(tool.InputSchema.Required, []string{"format"}) {
+		t.Errorf("Expected format to be required, got %v", tool.InputSchema.Required)
+	}
+
+	call := func(args map[string]interface{}) *mcp.CallToolResult {
+		return callTool(t, mcpServer, "users_list", args)
+	}
+	result := call(map[string]interface{}{
+		"org": "acme", "format": "json", "limit": 5, "timeout": "1m30s",
+		"tag": []interface{}{"a", "b"}, "args": []interface{}{"-x"},
+	})
+	if result.IsError {
+		t.Fatalf("Unexpected error: %v", result.Content)
+	}
+	if gotOrg != "acme" || gotFormat != "json" || gotLimit != 5 || gotTimeout != 90*time.Second ||
+		!reflect.DeepEqual(gotTags, []string{"a", "b"}) || !reflect.DeepEqual(gotArgs, []string{"-x"}) {
+		t.Errorf("Unexpected invocation: org=%q format=%q limit=%d timeout=%v tags=%v args=%v",
+			gotOrg, gotFormat, gotLimit, gotTimeout, gotTags, gotArgs)
+	}
+	var output ExecResult
+	json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &output)
+	if output.ExitCode != 0 || output.Stdout != "alice\n" {
+		t.Errorf("Expected the output to be captured, got %+v", output)
+	}
+	if !reflect.DeepEqual(before, []string{"app"}) {
+		t.Errorf("Expected the parent's Before hook to run, got %v", before)
+	}
+
+	result = call(map[string]interface{}{"format": "text"})
+	if result.IsError {
+		t.Fatalf("Expected a second call to succeed despite OnlyOnce, got %v", result.Content)
+	}
+	if gotOrg != "" || gotLimit != 20 || len(gotTags) != 0 {
+		t.Errorf("Expected flags to be reset between calls, got org=%q limit=%d tags=%v", gotOrg, gotLimit, gotTags)
+	}
+
+	result = call(map[string]interface{}{"format": "fail"})
+	if !result.IsError || !strings.Contains(result.Content[0].(mcp.TextContent).Text, "bad format") {
+		t.Errorf("Expected the exit error to be returned, got %v", result.Content)
+	}
+	if details := result.StructuredContent.(map[string]interface{}); details["exit_code"] != 3 {
+		t.Errorf("Expected the exit code of cli.Exit, got %v", details)
+	}
+}
+
+func TestRegisterUrfaveErrors(t *testing.T) {
+	wrapper := New(server.NewMCPServer("test", "1.0.0"))
+	if _, err := wrapper.RegisterUrfave(&cli.Command{}); err == nil {
+		t.Error("Expected an error for a command without a name")
+	}
+
+	cmd := &cli.Command{
+		Name:   "tidy",
+		Flags:  []cli.Flag{&cli.StringFlag{Name: "args"}},
+		Action: func(ctx context.Context, cmd *cli.Command) error { return errors.New("unreachable") },
+	}
+	if _, err := wrapper.RegisterUrfave(cmd); err == nil {
+		t.Error("Expected an error for a flag named args")
+	}
+}
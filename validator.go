@@ -26,6 +26,24 @@ func (e ValidationErrors) Error() string {
 	return fmt.Sprintf("validation failed: %s", strings.Join(messages, "; "))
 }
 
+// validate runs the validate tags, the jsonschema and RegisterEnum enum
+// constraints and, with WithStrictSchema, the remaining jsonschema keywords.
+func (w *Wrapper) validate(v interface{}) error {
+	if err := w.validator.Struct(v); err != nil {
+		return formatValidationErrors(err)
+	}
+	if err := validateEnums(v); err != nil {
+		return err
+	}
+	if err := w.registry.validateEnumTypes(v); err != nil {
+		return err
+	}
+	if w.strictSchema {
+		return validateKeywords(v)
+	}
+	return nil
+}
+
 func formatValidationErrors(err error) error {
 	validationErrs, ok := err.(validator.ValidationErrors)
 	if !ok {
@@ -0,0 +1,115 @@
+package mcpwrapper
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type VersionPolicy int
+
+const (
+	// ListLatestVersion lists only the latest version, under the plain name.
+	ListLatestVersion VersionPolicy = iota
+	// ListAllVersions also lists every version under a suffixed name
+	// (search_v1, search_v2); the plain name serves the latest.
+	ListAllVersions
+)
+
+func WithVersionPolicy(policy VersionPolicy) Option {
+	return func(w *Wrapper) {
+		w.versionPolicy = policy
+	}
+}
+
+// WithVersion registers the tool as one version of a tool family. The
+// version is advertised in the tool's _meta as "version".
+func WithVersion(version string) ToolOption {
+	return func(o *toolOptions) {
+		o.version = version
+		WithToolMeta("version", version)(o)
+	}
+}
+
+func versionedName(name, version string) string {
+	return name + "_" + strings.ReplaceAll(version, ".", "_")
+}
+
+// checkVersionConflict keeps a name from being both a plain tool and a
+// versioned family, and the same version from being registered twice.
+func (w *Wrapper) checkVersionConflict(name, version string) error {
+	w.toolsMu.RLock()
+	defer w.toolsMu.RUnlock()
+
+	versions, versioned := w.versions[name]
+	if version == "" {
+		if versioned {
+			return fmt.Errorf("tool %s is registered with versions; register it WithVersion", name)
+		}
+		return nil
+	}
+
+	if _, plain := w.tools[name]; plain {
+		return fmt.Errorf("tool %s is registered without a version", name)
+	}
+	for _, t := range versions {
+		if t.options.version == version {
+			return fmt.Errorf("tool %s version %s is already registered", name, version)
+		}
+	}
+	return nil
+}
+
+func (w *Wrapper) registerVersion(name string, t *registeredTool) {
+	w.toolsMu.Lock()
+	versions := append(w.versions[name], t)
+	sort.SliceStable(versions, func(i, j int) bool {
+		return compareVersions(versions[i].options.version, versions[j].options.version) < 0
+	})
+	w.versions[name] = versions
+	latest := versions[len(versions)-1]
+	w.toolsMu.Unlock()
+
+	if w.versionPolicy == ListAllVersions {
+		w.server.AddTool(t.tool, w.createHandler(t))
+	}
+	if latest == t {
+		tool := t.tool
+		tool.Name = name
+		w.server.AddTool(tool, w.createHandler(t))
+	}
+}
+
+// compareVersions orders versions such as "v1", "v2", "v10" or "1.2.3"
+// numerically per dot-separated part, falling back to string order for
+// non-numeric parts.
+func compareVersions(a, b string) int {
+	pa := strings.Split(strings.TrimPrefix(strings.TrimPrefix(a, "v"), "V"), ".")
+	pb := strings.Split(strings.TrimPrefix(strings.TrimPrefix(b, "v"), "V"), ".")
+
+	for i := 0; i < len(pa) || i < len(pb); i++ {
+		var x, y string
+		if i < len(pa) {
+			x = pa[i]
+		}
+		if i < len(pb) {
+			y = pb[i]
+		}
+
+		nx, errx := strconv.Atoi(x)
+		ny, erry := strconv.Atoi(y)
+		switch {
+		case errx == nil && erry == nil:
+			if nx != ny {
+				if nx < ny {
+					return -1
+				}
+				return 1
+			}
+		case x != y:
+			return strings.Compare(x, y)
+		}
+	}
+	return 0
+}
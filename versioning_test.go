@@ -0,0 +1,111 @@
+package mcpwrapper
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func TestWithVersion(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   VersionPolicy
+		suffixed bool
+	}{
+		{"latest only", ListLatestVersion, false},
+		{"all versions", ListAllVersions, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mcpServer := server.NewMCPServer("test", "1.0.0")
+			wrapper := New(mcpServer, WithVersionPolicy(tt.policy))
+
+			handler := func(version string) Handler {
+				return func(ctx context.Context, args interface{}) (interface{}, error) {
+					return &TestResult{Message: version}, nil
+				}
+			}
+
+			// Register out of order: v10 must win over v2 and v9.
+			for _, version := range []string{"v2", "v10", "v9"} {
+				if err := wrapper.Register("search", "Search", TestArgs{}, handler(version), WithVersion(version)); err != nil {
+					t.Fatalf("Register %s failed: %v", version, err)
+				}
+			}
+
+			latest := mcpServer.GetTool("search")
+			if latest == nil {
+				t.Fatal("Expected the plain name to be listed")
+			}
+			if latest.Tool.Meta.AdditionalFields["version"] != "v10" {
+				t.Errorf("Expected plain name to serve v10, got meta %v", latest.Tool.Meta.AdditionalFields)
+			}
+
+			request := mcp.CallToolRequest{
+				Params: mcp.CallToolParams{
+					Name:      "search",
+					Arguments: map[string]interface{}{"name": "Alice", "age": 30, "category": "A"},
+				},
+			}
+			result, err := latest.Handler(context.Background(), request)
+			if err != nil || result.IsError {
+				t.Fatalf("Handler invocation failed: %v %v", err, result)
+			}
+			if text := result.Content[0].(mcp.TextContent).Text; text != `{"message":"v10"}` {
+				t.Errorf("Expected v10 result, got %s", text)
+			}
+
+			for _, suffixed := range []string{"search_v2", "search_v9", "search_v10"} {
+				if listed := mcpServer.GetTool(suffixed) != nil; listed != tt.suffixed {
+					t.Errorf("%s listed = %v, want %v", suffixed, listed, tt.suffixed)
+				}
+			}
+		})
+	}
+}
+
+func TestWithVersionConflicts(t *testing.T) {
+	wrapper := New(server.NewMCPServer("test", "1.0.0"))
+	handler := func(ctx context.Context, args interface{}) (interface{}, error) {
+		return &TestResult{}, nil
+	}
+
+	if err := wrapper.Register("search", "Search", TestArgs{}, handler, WithVersion("v1")); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	if err := wrapper.Register("search", "Search", TestArgs{}, handler, WithVersion("v1")); err == nil {
+		t.Error("Expected an error for a duplicate version")
+	}
+	if err := wrapper.Register("search", "Search", TestArgs{}, handler); err == nil {
+		t.Error("Expected an error for an unversioned tool with a versioned name")
+	}
+
+	if err := wrapper.Register("fetch", "Fetch", TestArgs{}, handler); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	if err := wrapper.Register("fetch", "Fetch", TestArgs{}, handler, WithVersion("v2")); err == nil {
+		t.Error("Expected an error for a versioned tool with an unversioned name")
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"v1", "v2", -1},
+		{"v10", "v9", 1},
+		{"1.10", "1.9", 1},
+		{"v1.2", "v1.2.0", -1},
+		{"v2", "v2", 0},
+		{"v1-beta", "v1-alpha", 1},
+	}
+	for _, tt := range tests {
+		if got := compareVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("compareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
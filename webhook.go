@@ -0,0 +1,281 @@
+package mcpwrapper
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"text/template"
+	"time"
+)
+
+// Webhook events, as in WebhookEvent.Status and Webhook.On.
+const (
+	WebhookCompleted = "completed"
+	WebhookFailed    = "failed"
+)
+
+const (
+	defaultWebhookAttempts   = 3
+	defaultWebhookRetryDelay = time.Second
+	defaultWebhookTimeout    = 10 * time.Second
+)
+
+// Webhook is an HTTP endpoint told about a tool's calls. It can be given
+// with WithWebhook or in a tool's config.
+type Webhook struct {
+	URL string `json:"url" yaml:"url"`
+	// On lists the events that fire the webhook, WebhookCompleted and
+	// WebhookFailed; the default is both.
+	On []string `json:"on,omitempty" yaml:"on,omitempty"`
+	// Template is a text/template rendered with the WebhookEvent as the
+	// request body, with the json function to quote values. The default
+	// is the event as JSON.
+	Template    string            `json:"template,omitempty" yaml:"template,omitempty"`
+	ContentType string            `json:"content_type,omitempty" yaml:"content_type,omitempty"`
+	Headers     map[string]string `json:"headers,omitempty" yaml:"headers,omitempty"`
+	// Secret, if set, signs the body with HMAC-SHA256 in the
+	// X-Signature-256 header as "sha256=<hex>".
+	Secret string `json:"secret,omitempty" yaml:"secret,omitempty"`
+	// MaxAttempts bounds the deliveries tried when the endpoint fails;
+	// the default is 3.
+	MaxAttempts int `json:"max_attempts,omitempty" yaml:"max_attempts,omitempty"`
+	// Timeout bounds each delivery attempt; the default is 10 seconds.
+	Timeout time.Duration `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+}
+
+// WebhookEvent describes a finished call. Arguments and Result are passed
+// through Redact, and Error has the redacted argument values masked.
+type WebhookEvent struct {
+	Tool       string      `json:"tool"`
+	Status     string      `json:"status"`
+	Arguments  interface{} `json:"arguments,omitempty"`
+	Result     interface{} `json:"result,omitempty"`
+	Error      string      `json:"error,omitempty"`
+	SessionID  string      `json:"session_id,omitempty"`
+	StartedAt  time.Time   `json:"started_at"`
+	DurationMs int64       `json:"duration_ms"`
+}
+
+// WithWebhook sends hook a request when a call to the tool completes or
+// fails, without delaying the call. Failed deliveries, from network errors
+// or 429 and 5xx responses, are retried with exponential backoff.
+func WithWebhook(hook Webhook) ToolOption {
+	return func(o *toolOptions) {
+		o.webhooks = append(o.webhooks, hook)
+	}
+}
+
+// WithWebhookClient sets the HTTP client webhooks are sent with. Each
+// attempt is bounded by the webhook's Timeout as well as by the client's.
+func WithWebhookClient(client *http.Client) Option {
+	return func(w *Wrapper) {
+		w.webhooks.client = client
+	}
+}
+
+type compiledWebhook struct {
+	Webhook
+	template *template.Template
+}
+
+func (h Webhook) compile() (*compiledWebhook, error) {
+	u, err := url.Parse(h.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("invalid webhook URL %q", h.URL)
+	}
+	for _, event := range h.On {
+		if event != WebhookCompleted && event != WebhookFailed {
+			return nil, fmt.Errorf("webhook %s: unknown event %q", h.URL, event)
+		}
+	}
+	if h.MaxAttempts < 0 {
+		return nil, fmt.Errorf("webhook %s: max_attempts must not be negative", h.URL)
+	}
+	if h.Timeout < 0 {
+		return nil, fmt.Errorf("webhook %s: timeout must not be negative", h.URL)
+	}
+	compiled := &compiledWebhook{Webhook: h}
+	if h.Template != "" {
+		if compiled.template, err = parseTemplate("webhook "+h.URL, h.Template); err != nil {
+			return nil, err
+		}
+	}
+	return compiled, nil
+}
+
+func (h *compiledWebhook) fires(status string) bool {
+	if len(h.On) == 0 {
+		return true
+	}
+	for _, event := range h.On {
+		if event == status {
+			return true
+		}
+	}
+	return false
+}
+
+func (h *compiledWebhook) body(event *WebhookEvent) ([]byte, error) {
+	if h.template == nil {
+		return json.Marshal(event)
+	}
+	var buf bytes.Buffer
+	if err := h.template.Execute(&buf, event); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// webhookSender delivers webhooks in the background. Shutdown waits for
+// the deliveries in progress.
+type webhookSender struct {
+	client     *http.Client
+	retryDelay time.Duration
+	ctx        context.Context
+	cancel     context.CancelFunc
+	wg         sync.WaitGroup
+}
+
+func newWebhookSender() *webhookSender {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &webhookSender{
+		client:     &http.Client{},
+		retryDelay: defaultWebhookRetryDelay,
+		ctx:        ctx,
+		cancel:     cancel,
+	}
+}
+
+func (w *Wrapper) webhookMiddleware(name string, hooks []*compiledWebhook) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, args interface{}) (interface{}, error) {
+			start := time.Now()
+			result, err := next(ctx, args)
+
+			event := &WebhookEvent{
+				Tool:       name,
+				Status:     WebhookCompleted,
+				Arguments:  Redact(args),
+				SessionID:  SessionIDFromContext(ctx),
+				StartedAt:  start,
+				DurationMs: time.Since(start).Milliseconds(),
+			}
+			if err != nil {
+				event.Status = WebhookFailed
+				event.Error = redactString(err.Error(), args)
+			} else {
+				event.Result = Redact(result)
+			}
+			for _, hook := range hooks {
+				if hook.fires(event.Status) {
+					w.sendWebhook(hook, event)
+				}
+			}
+			return result, err
+		}
+	}
+}
+
+func (w *Wrapper) sendWebhook(hook *compiledWebhook, event *WebhookEvent) {
+	body, err := hook.body(event)
+	if err != nil {
+		w.logger.Error("failed to render webhook", "tool", event.Tool, "url", hook.URL, "error", err)
+		return
+	}
+	s := w.webhooks
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		if err := s.deliver(hook, body); err != nil {
+			w.logger.Warn("webhook delivery failed", "tool", event.Tool, "url", hook.URL, "error", err)
+		}
+	}()
+}
+
+// deliver posts body, retrying failures until MaxAttempts is used up or
+// the sender is stopped.
+func (s *webhookSender) deliver(hook *compiledWebhook, body []byte) error {
+	attempts := hook.MaxAttempts
+	if attempts == 0 {
+		attempts = defaultWebhookAttempts
+	}
+	delivery := newJobID()
+	delay := s.retryDelay
+	var err error
+	for attempt := 1; ; attempt++ {
+		var retry bool
+		retry, err = s.post(hook, body, delivery)
+		if err == nil || !retry || attempt >= attempts {
+			return err
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-s.ctx.Done():
+			timer.Stop()
+			return err
+		}
+		delay *= 2
+	}
+}
+
+// post sends one delivery, reporting whether a failure is worth retrying.
+func (s *webhookSender) post(hook *compiledWebhook, body []byte, delivery string) (bool, error) {
+	timeout := hook.Timeout
+	if timeout == 0 {
+		timeout = defaultWebhookTimeout
+	}
+	ctx, cancel := context.WithTimeout(s.ctx, timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	contentType := hook.ContentType
+	if contentType == "" {
+		contentType = "application/json"
+	}
+	req.Header.Set("Content-Type", contentType)
+	for k, v := range hook.Headers {
+		req.Header.Set(k, v)
+	}
+	req.Header.Set("X-Webhook-Delivery", delivery)
+	if hook.Secret != "" {
+		mac := hmac.New(sha256.New, []byte(hook.Secret))
+		mac.Write(body)
+		req.Header.Set("X-Signature-256", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	retry := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	return retry, fmt.Errorf("webhook %s returned %s", hook.URL, resp.Status)
+}
+
+// wait waits for the deliveries in progress, and abandons them when ctx is
+// done.
+func (s *webhookSender) wait(ctx context.Context) {
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		s.cancel()
+	}
+}
@@ -0,0 +1,163 @@
+package mcpwrapper
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/server"
+)
+
+type webhookRequest struct {
+	path      string
+	body      string
+	signature string
+	delivery  string
+}
+
+func newWebhookServer(t *testing.T, failFirst int) (*httptest.Server, func() []webhookRequest) {
+	t.Helper()
+	var mu sync.Mutex
+	var requests []webhookRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		defer mu.Unlock()
+		requests = append(requests, webhookRequest{r.URL.Path, string(body), r.Header.Get("X-Signature-256"), r.Header.Get("X-Webhook-Delivery")})
+		if len(requests) <= failFirst {
+			rw.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv, func() []webhookRequest {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]webhookRequest(nil), requests...)
+	}
+}
+
+func TestWebhook(t *testing.T) {
+	srv, requests := newWebhookServer(t, 1)
+	cfg, err := ParseConfig([]byte(`
+tools:
+  greet:
+    webhooks:
+      - url: ` + srv.URL + `/slack
+        on: [failed]
+        template: '{"text": {{json (printf "%s failed: %s" .Tool .Error)}}}'
+`))
+	if err != nil {
+		t.Fatalf("ParseConfig failed: %v", err)
+	}
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	wrapper := New(mcpServer, WithConfig(cfg))
+	wrapper.webhooks.retryDelay = time.Millisecond
+	err = wrapper.Register("greet", "Greet a user", TestArgs{}, func(ctx context.Context, args interface{}) (interface{}, error) {
+		if args.(*TestArgs).Name == "nobody" {
+			return nil, errors.New("no such user")
+		}
+		return &TestResult{Message: "hello"}, nil
+	}, WithWebhook(Webhook{URL: srv.URL + "/events", Secret: "s3cret"}))
+	if err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	callTool(t, mcpServer, "greet", map[string]interface{}{"name": "alice", "age": 30, "category": "A"})
+	waitFor(t, "the retried delivery", func() bool { return len(requests()) == 2 })
+	callTool(t, mcpServer, "greet", map[string]interface{}{"name": "nobody", "age": 30, "category": "A"})
+	if err := wrapper.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+
+	got := requests()
+	if len(got) != 4 {
+		t.Fatalf("Expected 4 requests, got %+v", got)
+	}
+	if got[0].path != "/events" || got[0].delivery == "" || got[1].delivery != got[0].delivery {
+		t.Errorf("Expected the failed delivery to be retried with the same ID, got %+v", got[:2])
+	}
+	var event WebhookEvent
+	if err := json.Unmarshal([]byte(got[1].body), &event); err != nil {
+		t.Fatalf("Failed to decode event: %v", err)
+	}
+	if event.Tool != "greet" || event.Status != WebhookCompleted || event.Result.(map[string]interface{})["message"] != "hello" {
+		t.Errorf("Unexpected event: %+v", event)
+	}
+	mac := hmac.New(sha256.New, []byte("s3cret"))
+	mac.Write([]byte(got[1].body))
+	if want := "sha256=" + hex.EncodeToString(mac.Sum(nil)); got[1].signature != want {
+		t.Errorf("Expected signature %s, got %s", want, got[1].signature)
+	}
+
+	var slack *webhookRequest
+	for i := range got[2:] {
+		if got[2+i].path == "/slack" {
+			slack = &got[2+i]
+		}
+	}
+	if slack == nil || slack.body != `{"text": "greet failed: no such user"}` || slack.signature != "" {
+		t.Errorf("Expected one templated failure webhook, got %+v", got[2:])
+	}
+}
+
+type tokenArgs struct {
+	Token string `json:"token" redact:"true"`
+}
+
+func TestWebhookRedactsErrors(t *testing.T) {
+	srv, requests := newWebhookServer(t, 0)
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	wrapper := New(mcpServer)
+	wrapper.Register("login", "Log in", tokenArgs{}, func(ctx context.Context, args interface{}) (interface{}, error) {
+		return nil, fmt.Errorf("token %s was rejected", args.(*tokenArgs).Token)
+	}, WithWebhook(Webhook{URL: srv.URL}))
+
+	callTool(t, mcpServer, "login", map[string]interface{}{"token": "hunter2"})
+	if err := wrapper.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+	got := requests()
+	if len(got) != 1 || strings.Contains(got[0].body, "hunter2") || !strings.Contains(got[0].body, "token [REDACTED] was rejected") {
+		t.Errorf("Expected the error to be redacted, got %+v", got)
+	}
+}
+
+func TestWebhookTimeout(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	t.Cleanup(srv.Close)
+	t.Cleanup(func() { close(release) })
+
+	s := newWebhookSender()
+	start := time.Now()
+	_, err := s.post(&compiledWebhook{Webhook: Webhook{URL: srv.URL, Timeout: 20 * time.Millisecond}}, nil, "d1")
+	if !errors.Is(err, context.DeadlineExceeded) || time.Since(start) > time.Second {
+		t.Errorf("Expected the delivery to time out, got %v after %v", err, time.Since(start))
+	}
+}
+
+func TestParseConfigWebhookErrors(t *testing.T) {
+	for _, hook := range []string{
+		`{url: "ftp://example.com"}`,
+		`{url: "https://example.com", on: [started]}`,
+		`{url: "https://example.com", template: "{{.Tool"}`,
+		`{url: "https://example.com", timeout: -1s}`,
+	} {
+		if _, err := ParseConfig([]byte("tools:\n  greet:\n    webhooks: [" + hook + "]\n")); err == nil {
+			t.Errorf("Expected %s to be rejected", hook)
+		}
+	}
+}
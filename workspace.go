@@ -0,0 +1,180 @@
+package mcpwrapper
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// workspace confines where a tool's calls run and which paths their
+// arguments may name.
+type workspace struct {
+	dir      string
+	sandbox  bool
+	roots    []string
+	pathArgs []string
+
+	// files is set when the arguments have FileArg fields.
+	files       bool
+	maxFileSize int64
+}
+
+func (o *toolOptions) ensureWorkspace() *workspace {
+	if o.workspace == nil {
+		o.workspace = &workspace{}
+	}
+	return o.workspace
+}
+
+// WithWorkDir sets the working directory of the tool's calls. Commands run
+// by RegisterExec start in it; in-process commands and handlers read it
+// with WorkDir, since changing the server's directory would affect every
+// call.
+func WithWorkDir(dir string) ToolOption {
+	return func(o *toolOptions) {
+		o.ensureWorkspace().dir = dir
+	}
+}
+
+// WithSandbox gives every call a new empty temporary directory as its
+// working directory, removed when the call returns. Path arguments may
+// always name files inside it.
+func WithSandbox() ToolOption {
+	return func(o *toolOptions) {
+		o.ensureWorkspace().sandbox = true
+	}
+}
+
+// WithAllowedPaths sets the directories the tool's path arguments, see
+// WithPathArgs, must be inside; relative roots are relative to the working
+// directory. Without it they must be inside the working directory, or the
+// sandbox with WithSandbox.
+func WithAllowedPaths(roots ...string) ToolOption {
+	return func(o *toolOptions) {
+		ws := o.ensureWorkspace()
+		ws.roots = append(ws.roots, roots...)
+	}
+}
+
+// WithPathArgs names the arguments, as in the schema, that hold a path or
+// a list of paths. Before each call they are resolved against the working
+// directory, with symbolic links followed, and the call is rejected as
+// invalid input if one is outside the allowed directories.
+func WithPathArgs(names ...string) ToolOption {
+	return func(o *toolOptions) {
+		ws := o.ensureWorkspace()
+		ws.pathArgs = append(ws.pathArgs, names...)
+	}
+}
+
+type workDirKey struct{}
+
+// WorkDir returns the working directory set for the call by WithWorkDir or
+// WithSandbox, or "" if there is none.
+func WorkDir(ctx context.Context) string {
+	dir, _ := ctx.Value(workDirKey{}).(string)
+	return dir
+}
+
+func (ws *workspace) middleware(next Handler) Handler {
+	return func(ctx context.Context, args interface{}) (interface{}, error) {
+		dir := ws.dir
+		roots := ws.roots
+		if ws.sandbox {
+			tmp, err := os.MkdirTemp("", "mcpwrapper-sandbox-")
+			if err != nil {
+				return nil, fmt.Errorf("failed to create sandbox: %w", err)
+			}
+			defer os.RemoveAll(tmp)
+			dir = tmp
+			roots = append(roots[:len(roots):len(roots)], tmp)
+		}
+
+		base := dir
+		if base == "" {
+			wd, err := os.Getwd()
+			if err != nil {
+				return nil, err
+			}
+			base = wd
+		}
+		if len(roots) == 0 {
+			roots = []string{base}
+		}
+		if err := checkPathArgs(args, ws.pathArgs, base, roots); err != nil {
+			return nil, err
+		}
+		if ws.files {
+			cleanup, err := ws.materializeFiles(args, base, roots)
+			if err != nil {
+				return nil, err
+			}
+			defer cleanup()
+		}
+
+		if dir != "" {
+			ctx = context.WithValue(ctx, workDirKey{}, dir)
+		}
+		return next(ctx, args)
+	}
+}
+
+func checkPathArgs(args interface{}, names []string, base string, roots []string) error {
+	if len(names) == 0 {
+		return nil
+	}
+	fields := jsonFields(args)
+	for _, name := range names {
+		var paths []string
+		switch v := fields[name].(type) {
+		case string:
+			paths = []string{v}
+		case []interface{}:
+			for _, item := range v {
+				if s, ok := item.(string); ok {
+					paths = append(paths, s)
+				}
+			}
+		}
+		for _, p := range paths {
+			if p == "" {
+				continue
+			}
+			if !insideRoots(resolvePath(base, p), base, roots) {
+				return Errorf(CodeInvalidInput, "%s: path %q is outside the allowed directories", name, p)
+			}
+		}
+	}
+	return nil
+}
+
+// resolvePath makes p absolute against base and follows the symbolic links
+// of the part of it that exists.
+func resolvePath(base, p string) string {
+	if !filepath.IsAbs(p) {
+		p = filepath.Join(base, p)
+	}
+	p = filepath.Clean(p)
+	var rest []string
+	for dir := p; ; dir = filepath.Dir(dir) {
+		if resolved, err := filepath.EvalSymlinks(dir); err == nil {
+			return filepath.Join(append([]string{resolved}, rest...)...)
+		}
+		if parent := filepath.Dir(dir); parent == dir {
+			return p
+		}
+		rest = append([]string{filepath.Base(dir)}, rest...)
+	}
+}
+
+func insideRoots(p, base string, roots []string) bool {
+	for _, root := range roots {
+		rel, err := filepath.Rel(resolvePath(base, root), p)
+		if err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
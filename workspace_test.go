@@ -0,0 +1,120 @@
+package mcpwrapper
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+type CatArgs struct {
+	File  string   `json:"file"`
+	Extra []string `json:"extra,omitempty"`
+}
+
+func TestExecWorkDir(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("hello"), 0o644)
+	os.Mkdir(filepath.Join(dir, "sub"), 0o755)
+	os.Symlink("/etc", filepath.Join(dir, "escape"))
+
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	wrapper := New(mcpServer)
+	err := wrapper.RegisterExec("cat", "Print a file", CatArgs{}, ExecSpec{
+		Command: "cat",
+		Args:    []string{"--", "{{.file}}"},
+	}, WithWorkDir(dir), WithPathArgs("file", "extra"))
+	if err != nil {
+		t.Fatalf("RegisterExec failed: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		args    map[string]interface{}
+		allowed bool
+	}{
+		{"relative", map[string]interface{}{"file": "notes.txt"}, true},
+		{"absolute inside", map[string]interface{}{"file": filepath.Join(dir, "notes.txt")}, true},
+		{"not yet existing", map[string]interface{}{"file": "sub/new/file.txt"}, true},
+		{"parent", map[string]interface{}{"file": "../notes.txt"}, false},
+		{"absolute outside", map[string]interface{}{"file": "/etc/passwd"}, false},
+		{"symlink out", map[string]interface{}{"file": "escape/passwd"}, false},
+		{"list", map[string]interface{}{"file": "notes.txt", "extra": []interface{}{"sub", "/tmp"}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := callTool(t, mcpServer, "cat", tt.args)
+			rejected := strings.Contains(result.Content[0].(mcp.TextContent).Text, "outside the allowed directories")
+			if rejected == tt.allowed {
+				t.Errorf("Expected allowed=%v, got %v", tt.allowed, result.Content)
+			}
+		})
+	}
+
+	var out ExecResult
+	text := callTool(t, mcpServer, "cat", map[string]interface{}{"file": "notes.txt"}).Content[0].(mcp.TextContent).Text
+	json.Unmarshal([]byte(text), &out)
+	if out.Stdout != "hello" {
+		t.Errorf("Expected the command to run in the working directory, got %+v", out)
+	}
+}
+
+func TestSandbox(t *testing.T) {
+	allowed := t.TempDir()
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	wrapper := New(mcpServer)
+	err := wrapper.RegisterExec("touch", "Create a file", CatArgs{}, ExecSpec{
+		Command: "sh",
+		Args:    []string{"-c", `touch -- "$1" && pwd`, "sh", "{{.file}}"},
+	}, WithSandbox(), WithAllowedPaths(allowed), WithPathArgs("file"))
+	if err != nil {
+		t.Fatalf("RegisterExec failed: %v", err)
+	}
+
+	var dirs []string
+	for _, file := range []string{"a.txt", filepath.Join(allowed, "b.txt")} {
+		var out ExecResult
+		result := callTool(t, mcpServer, "touch", map[string]interface{}{"file": file})
+		if result.IsError {
+			t.Fatalf("Unexpected error for %s: %v", file, result.Content)
+		}
+		json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &out)
+		dirs = append(dirs, strings.TrimSpace(out.Stdout))
+	}
+	if dirs[0] == dirs[1] {
+		t.Errorf("Expected a new sandbox per call, got %v", dirs)
+	}
+	for _, dir := range dirs {
+		if _, err := os.Stat(dir); !os.IsNotExist(err) {
+			t.Errorf("Expected sandbox %s to be removed", dir)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(allowed, "b.txt")); err != nil {
+		t.Errorf("Expected a file in the allowed directory: %v", err)
+	}
+
+	if result := callTool(t, mcpServer, "touch", map[string]interface{}{"file": "/tmp/x"}); !result.IsError {
+		t.Error("Expected a path outside the sandbox and allowed paths to be rejected")
+	}
+}
+
+func TestWorkDirFromContext(t *testing.T) {
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	var dir string
+	err := New(mcpServer).Register("where", "Report the working directory", TestArgs{}, func(ctx context.Context, args interface{}) (interface{}, error) {
+		dir = WorkDir(ctx)
+		return &TestResult{Message: "ok"}, nil
+	}, WithWorkDir("/srv/data"))
+	if err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	callTool(t, mcpServer, "where", map[string]interface{}{"name": "Alice", "age": 30, "category": "A"})
+	if dir != "/srv/data" {
+		t.Errorf("Expected the working directory in the context, got %q", dir)
+	}
+}
@@ -2,10 +2,17 @@ package mcpwrapper
 
 import (
 	"context"
+	"encoding"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"reflect"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/mark3labs/mcp-go/mcp"
@@ -13,25 +20,251 @@ import (
 )
 
 type Wrapper struct {
-	server    *server.MCPServer
-	validator *validator.Validate
+	server       *server.MCPServer
+	registry     *Registry
+	validator    *validator.Validate
+	config       *Config
+	environment  string
+	middleware   []Middleware
+	provenance   *provenanceTracker
+	authorizers  []Authorizer
+	logger       *slog.Logger
+	sessions     *sessionStores
+	inflight     *inflightCalls
+	audit        AuditSink
+	readOnly     atomic.Bool
+	features     *features
+	roots        *rootsCache
+	completions  *completions
+	stats        *statsCollector
+	history      *resultHistory
+	resources    *resourceRegistry
+	messageLimit *messageLimit
+	handlers     map[string]Handler
+	strictSchema bool
+
+	schemaGenerator SchemaGenerator
+	validateOutput  bool
+	errorFormatter  ErrorFormatter
+	debug           bool
+	mockMode        bool
+	mocks           map[string]Handler
+	descriptionData map[string]interface{}
+
+	streamOnce sync.Once
+	streams    *oversizedStore
+	streamErr  error
+
+	resultLimit *resultLimit
+	offload     *resultOffload
+	jobs        *jobQueue
+	health      *health
+	calls       *callTracker
+	flushers    []func(ctx context.Context) error
+	lifecycle   *lifecycle
+	deps        *dependencies
+	processes   *processManager
+	schedules   *scheduler
+	webhooks    *webhookSender
+	files       *fileHost
+	recorder    *recorder
+	replay      *replayer
+
+	toolsMu       sync.RWMutex
+	updateMu      sync.Mutex
+	tools         map[string]*registeredTool
+	versions      map[string][]*registeredTool
+	aliases       map[string][]string // old names by the tool they alias
+	versionPolicy VersionPolicy
 }
 
 type Handler func(ctx context.Context, args interface{}) (interface{}, error)
 
-func New(mcpServer *server.MCPServer) *Wrapper {
-	return &Wrapper{
-		server:    mcpServer,
-		validator: validator.New(),
+type Option func(*Wrapper)
+
+type ToolOption func(*toolOptions)
+
+// registeredTool is everything the wrapper knows about a tool after Register.
+// handler is the fully chained handler, middleware included.
+type registeredTool struct {
+	tool        mcp.Tool
+	name        string
+	description string
+	argsType    interface{}
+	schema      *mcp.ToolInputSchema
+	handler     Handler
+	options     *toolOptions
+}
+
+type toolOptions struct {
+	middleware   []Middleware
+	environments []string
+	authorizers  []Authorizer
+	meta         map[string]interface{}
+	currentState StateFetcher
+	readOnly     bool
+	deprecated   *deprecation
+	version      string
+	outputSchema map[string]interface{}
+	examples     []interface{}
+	pageSize     int
+
+	paginatedItem interface{}
+	resultLimit   *resultLimit
+	noOffload     bool
+	workspace     *workspace
+	webhooks      []Webhook
+	mock          Handler
+}
+
+// WithToolMeta sets a key in the tool's _meta object as listed to clients.
+func WithToolMeta(key string, value interface{}) ToolOption {
+	return func(o *toolOptions) {
+		if o.meta == nil {
+			o.meta = make(map[string]interface{})
+		}
+		o.meta[key] = value
+	}
+}
+
+func WithConfig(cfg *Config) Option {
+	return func(w *Wrapper) {
+		w.config = cfg
+	}
+}
+
+func New(mcpServer *server.MCPServer, opts ...Option) *Wrapper {
+	w := &Wrapper{
+		server:      mcpServer,
+		logger:      slog.New(slog.DiscardHandler),
+		sessions:    &sessionStores{stores: make(map[string]*Store)},
+		inflight:    newInflightCalls(),
+		roots:       newRootsCache(),
+		completions: newCompletions(),
+		stats:       newStatsCollector(),
+		resources:   newResourceRegistry(),
+		features:    newFeatures(),
+		health:      newHealth(),
+		calls:       newCallTracker(),
+		processes:   newProcessManager(),
+		schedules:   newScheduler(),
+		webhooks:    newWebhookSender(),
+		files:       newFileHost(),
+		lifecycle:   &lifecycle{},
+		deps:        &dependencies{},
+		tools:       make(map[string]*registeredTool),
+		versions:    make(map[string][]*registeredTool),
+		aliases:     make(map[string][]string),
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+	if w.registry == nil {
+		w.registry = NewRegistry()
+	}
+	w.validator = w.registry.validator
+	if debugFromEnv() {
+		w.debug = true
 	}
+	if mockFromEnv() {
+		w.mockMode = true
+	}
+	if w.environment == "" && w.config != nil {
+		w.environment = w.config.Environment
+	}
+	w.installFeatures()
+	mcpServer.AddNotificationHandler(methodNotificationCancelled, w.inflight.cancel)
+	mcpServer.AddNotificationHandler(mcp.MethodNotificationRootsListChanged, w.roots.changed)
+	return w
 }
 
-func (w *Wrapper) Register(name, description string, argsType interface{}, handler Handler) error {
-	schema, err := buildSchema(argsType)
+func (w *Wrapper) Register(name, description string, argsType interface{}, handler Handler, opts ...ToolOption) error {
+	schema, err := w.schema(argsType)
 	if err != nil {
 		return fmt.Errorf("failed to build schema for tool %s: %w", name, err)
 	}
 
+	return w.register(name, description, argsType, schema, handler, opts)
+}
+
+// register lists a tool with the given input schema. Arguments are bound to
+// a new value of argsType's type before the handler is called.
+func (w *Wrapper) register(name, description string, argsType interface{}, schema *mcp.ToolInputSchema, handler Handler, opts []ToolOption) error {
+	options := &toolOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	baseName := name
+	if options.version != "" {
+		name = versionedName(name, options.version)
+	}
+	mock := w.toolMock(name, options)
+	if handler == nil && mock == nil && w.replay == nil {
+		return fmt.Errorf("handler for tool %s must not be nil", baseName)
+	}
+	if err := w.checkVersionConflict(baseName, options.version); err != nil {
+		return err
+	}
+
+	if options.paginatedItem != nil {
+		paginated, err := PaginatedSchema(options.paginatedItem)
+		if err != nil {
+			return fmt.Errorf("invalid output schema for tool %s: %w", name, err)
+		}
+		options.outputSchema = paginated
+	}
+
+	tool, err := w.buildTool(name, description, argsType, schema, options)
+	if err != nil {
+		return err
+	}
+
+	if !w.environmentAllowed(options.environments) {
+		w.logger.Info("skipped tool outside its environments", "tool", name,
+			"environment", w.environment, "allowed", options.environments)
+		return nil
+	}
+
+	if hasFileArgs(reflect.TypeOf(argsType), map[reflect.Type]bool{}) {
+		options.ensureWorkspace().files = true
+	}
+
+	if w.replay != nil {
+		handler = w.replayHandler(name)
+	} else {
+		handler = w.mockedHandler(name, handler, mock)
+	}
+	chained, err := w.buildChain(name, handler, options)
+	if err != nil {
+		return fmt.Errorf("invalid config for tool %s: %w", name, err)
+	}
+
+	registered := &registeredTool{
+		tool:        tool,
+		name:        name,
+		description: description,
+		argsType:    argsType,
+		schema:      schema,
+		handler:     chained,
+		options:     options,
+	}
+	w.toolsMu.Lock()
+	w.tools[name] = registered
+	w.toolsMu.Unlock()
+
+	if options.version != "" {
+		w.registerVersion(baseName, registered)
+	} else {
+		w.server.AddTool(tool, w.createHandler(registered))
+	}
+	w.logger.Info("registered tool", "tool", name)
+	return nil
+}
+
+// buildTool builds the tool clients list from its description, input
+// schema and options.
+func (w *Wrapper) buildTool(name, description string, argsType interface{}, schema *mcp.ToolInputSchema, options *toolOptions) (mcp.Tool, error) {
 	tool := mcp.NewTool(name,
 		mcp.WithDescription(description),
 		mcp.WithString("input", mcp.Required(), mcp.Description("JSON-encoded input matching the schema")),
@@ -41,48 +274,303 @@ func (w *Wrapper) Register(name, description string, argsType interface{}, handl
 		tool.InputSchema = *schema
 	}
 
-	w.server.AddTool(tool, w.createHandler(argsType, handler))
-	return nil
+	if options.outputSchema != nil {
+		outputSchema, err := parseInputSchema(options.outputSchema)
+		if err != nil {
+			return mcp.Tool{}, fmt.Errorf("invalid output schema for tool %s: %w", name, err)
+		}
+		tool.OutputSchema = mcp.ToolOutputSchema(*outputSchema)
+	}
+
+	if err := w.renderToolDescriptions(&tool); err != nil {
+		return mcp.Tool{}, fmt.Errorf("invalid description for tool %s: %w", name, err)
+	}
+
+	if options.readOnly {
+		tool.Annotations.ReadOnlyHint = mcp.ToBoolPtr(true)
+	}
+
+	if options.deprecated != nil {
+		tool.Description = options.deprecated.notice() + " " + tool.Description
+	}
+
+	// Copied, since updates rebuild the tool while clients may list it.
+	meta := make(map[string]interface{}, len(options.meta)+1)
+	for k, v := range options.meta {
+		meta[k] = v
+	}
+	if len(options.examples) > 0 {
+		examples, err := w.encodeExamples(&registeredTool{tool: tool, argsType: argsType}, options.examples)
+		if err != nil {
+			return mcp.Tool{}, fmt.Errorf("invalid examples for tool %s: %w", name, err)
+		}
+		tool.Description = withExamplesText(tool.Description, examples)
+		meta["examples"] = examples
+	}
+
+	if len(meta) > 0 {
+		tool.Meta = &mcp.Meta{AdditionalFields: meta}
+	}
+
+	return tool, nil
+}
+
+// ToolNames lists the registered tools, sorted. Aliases and tools skipped by
+// environment guards are not included.
+func (w *Wrapper) ToolNames() []string {
+	w.toolsMu.RLock()
+	defer w.toolsMu.RUnlock()
+	names := make([]string, 0, len(w.tools))
+	for name := range w.tools {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
 }
 
-func (w *Wrapper) createHandler(argsType interface{}, handler Handler) server.ToolHandlerFunc {
-	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		argsValue := reflect.New(reflect.TypeOf(argsType)).Interface()
+func (w *Wrapper) lookupTool(name string) (*registeredTool, bool) {
+	w.toolsMu.RLock()
+	defer w.toolsMu.RUnlock()
+	t, ok := w.tools[name]
+	return t, ok
+}
 
-		if err := request.BindArguments(argsValue); err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("failed to bind arguments: %v", err)), nil
+func (w *Wrapper) buildChain(name string, handler Handler, options *toolOptions) (Handler, error) {
+	var mw []Middleware
+	if w.provenance != nil {
+		mw = append(mw, w.provenance.middleware)
+	}
+	if w.audit != nil {
+		mw = append(mw, auditMiddleware(w.audit, options.currentState, w.logger))
+	}
+	mw = append(mw, w.registry.middlewares()...)
+	mw = append(mw, w.middleware...)
+
+	toolCfg := w.config.tool(name)
+	if hooks := append(append([]Webhook(nil), toolCfg.Webhooks...), options.webhooks...); len(hooks) > 0 {
+		compiled := make([]*compiledWebhook, 0, len(hooks))
+		for _, hook := range hooks {
+			c, err := hook.compile()
+			if err != nil {
+				return nil, err
+			}
+			compiled = append(compiled, c)
 		}
+		mw = append(mw, w.webhookMiddleware(name, compiled))
+	}
+	if toolCfg.Availability != nil {
+		s, err := toolCfg.Availability.compile()
+		if err != nil {
+			return nil, fmt.Errorf("availability: %w", err)
+		}
+		mw = append(mw, availabilityMiddleware(s))
+	}
+	if toolCfg.CircuitBreaker != nil {
+		mw = append(mw, CircuitBreaker(*toolCfg.CircuitBreaker))
+	}
+	if toolCfg.Cache != nil {
+		mw = append(mw, Cache(*toolCfg.Cache))
+	}
 
-		if err := w.validator.Struct(argsValue); err != nil {
-			validationErr := formatValidationErrors(err)
-			return mcp.NewToolResultError(validationErr.Error()), nil
+	if options.workspace != nil {
+		mw = append(mw, options.workspace.middleware)
+	}
+	mw = append(mw, options.middleware...)
+	return chain(handler, mw...), nil
+}
+
+func (w *Wrapper) createHandler(t *registeredTool) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (result *mcp.CallToolResult, err error) {
+		start := time.Now()
+		defer func() {
+			if r := recover(); r != nil {
+				panicErr := newPanicError(r)
+				w.logger.Error("tool panicked", "tool", t.name, "panic", panicErr.Value, "stack", string(panicErr.Stack))
+				result, err = mcp.NewToolResultError(panicErr.Error()), nil
+			}
+			w.stats.recordCall(t.name, time.Since(start), result.IsError)
+		}()
+
+		if t.options.deprecated != nil {
+			w.logger.Warn("deprecated tool called", "tool", t.name, "replacement", t.options.deprecated.Replacement)
 		}
+		w.warnDeprecatedArgs(t, request)
 
-		result, err := handler(ctx, argsValue)
-		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("handler error: %v", err)), nil
+		ctx, end, ok := w.calls.begin(ctx)
+		if !ok {
+			w.logger.Info("call rejected during shutdown", "tool", t.name)
+			return codedErrorResult(CodeUnavailable, "server is shutting down"), nil
 		}
+		defer end()
+
+		ctx, done := w.inflight.start(ctx, request)
+		defer done()
+
+		ctx, call := newCallContext(ctx, w, t, request)
+		result = w.invoke(ctx, t, request)
+		w.recordCall(t.name, request.GetArguments(), result, start)
+		w.recordHistory(ctx, call, result)
+		call.attachLog(result)
+		call.attachMeta(result)
+		w.limitMessageSize(ctx, t.name, result)
+		return result, nil
+	}
+}
+
+func (w *Wrapper) invoke(ctx context.Context, t *registeredTool, request mcp.CallToolRequest) *mcp.CallToolResult {
+	argsValue := reflect.New(reflect.TypeOf(t.argsType)).Interface()
+
+	logger := w.logger.With("tool", t.name)
 
-		resultJSON, err := json.Marshal(result)
+	if args := request.GetArguments(); args != nil {
+		transformed, err := w.transformInput(t.name, args)
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("failed to marshal result: %v", err)), nil
+			logger.Info("call rejected by input transform", "error", err)
+			return codedErrorResult(CodeInvalidInput, fmt.Sprintf("failed to transform arguments: %v", err))
 		}
+		request.Params.Arguments = transformed
+	}
+
+	if err := w.checkEnabled(ctx, t, request); err != nil {
+		logger.Info("call rejected for disabled tool")
+		return codedErrorResult(CodeUnavailable, err.Error())
+	}
+
+	if err := w.checkReadOnly(t); err != nil {
+		logger.Info("call rejected in read-only mode")
+		return codedErrorResult(CodeUnavailable, err.Error())
+	}
+
+	if err := authorize(ctx, t.name, request.Params.Arguments, w.authorizers, t.options.authorizers); err != nil {
+		logger.Info("call rejected by authorizer", "error", err)
+		return codedErrorResult(CodeUnauthorized, fmt.Sprintf("unauthorized: %v", err))
+	}
 
-		var resultMap map[string]interface{}
-		if err := json.Unmarshal(resultJSON, &resultMap); err != nil {
-			resultStr, ok := result.(string)
-			if !ok {
-				return mcp.NewToolResultError(fmt.Sprintf("failed to format result: %v", err)), nil
+	if args := request.GetArguments(); args != nil {
+		request.Params.Arguments = coerceEnumStrings(t.argsType, args)
+	}
+
+	if args := request.GetArguments(); args != nil {
+		decoded, err := decodeArguments(t.argsType, args)
+		if err != nil {
+			logger.Debug("failed to decode arguments", "error", err)
+			if result := w.formatError(ctx, t, Errorf(CodeInvalidInput, "failed to decode arguments: %w", err)); result != nil {
+				return result
 			}
-			return mcp.NewToolResultText(resultStr), nil
+			return codedErrorResult(CodeInvalidInput, fmt.Sprintf("failed to decode arguments: %v", err))
+		}
+		request.Params.Arguments = decoded
+	}
+
+	dbg := w.newDebug()
+	if dbg != nil {
+		defer SetResultMeta(ctx, "_debug", dbg)
+	}
+
+	err := request.BindArguments(argsValue)
+	dbg.mark("bind")
+	if err != nil {
+		logger.Debug("failed to bind arguments", "error", err)
+		if result := w.formatError(ctx, t, Errorf(CodeInvalidInput, "failed to bind arguments: %w", err)); result != nil {
+			return result
+		}
+		return codedErrorResult(CodeInvalidInput, fmt.Sprintf("failed to bind arguments: %v", err))
+	}
+
+	err = w.validateArgs(t, argsValue)
+	dbg.mark("validate")
+	dbg.setArguments(argsValue)
+	if err != nil {
+		logger.Debug("validation failed", "args", Redact(argsValue), "error", err)
+		if result := w.formatError(ctx, t, &CodedError{Code: CodeInvalidInput, Err: err}); result != nil {
+			return result
+		}
+		return codedErrorResult(CodeInvalidInput, err.Error())
+	}
+
+	callFromContext(ctx).args = argsValue
+	logger.Debug("calling handler", "args", Redact(argsValue))
+
+	handlerArgs := argsValue
+	if a, ok := argsValue.(*Arguments); ok {
+		handlerArgs = *a
+	}
+
+	start := time.Now()
+	result, err := t.handler(ctx, handlerArgs)
+	duration := time.Since(start)
+	dbg.mark("handle")
+	if err != nil {
+		prefix := "handler error"
+		if code, ok := CodeFromError(err); ok {
+			prefix = string(code)
+		}
+		msg := redactString(fmt.Sprintf("%s: %v", prefix, err), argsValue)
+		logger.Info("handler failed", "duration", duration, "error", msg)
+		if result := w.formatError(ctx, t, err); result != nil {
+			return result
+		}
+		return newErrorResult(msg, err)
+	}
+
+	if file, ok := fileResultOf(result); ok {
+		logger.Info("handler completed", "duration", duration, "file", true)
+		return w.fileResult(ctx, t, file)
+	}
+
+	if raw, ok := rawResult(result); ok {
+		logger.Info("handler completed", "duration", duration, "raw", true)
+		return raw
+	}
+
+	output, err := w.transformOutput(t.name, result)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to transform result: %v", err))
+	}
+
+	resultJSON, err := json.Marshal(output)
+	dbg.mark("marshal")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to marshal result: %v", err))
+	}
+
+	logger.Info("handler completed", "duration", duration, "result_bytes", len(resultJSON))
+
+	var resultMap map[string]interface{}
+	unmarshalErr := json.Unmarshal(resultJSON, &resultMap)
+
+	if w.validateOutput {
+		if err := w.validateResult(t, result, resultMap); err != nil {
+			logger.Error("tool produced invalid output", "error", err)
+			return codedErrorResult(CodeInternal, "internal error: tool produced invalid output")
+		}
+	}
+
+	if unmarshalErr != nil {
+		resultStr, ok := output.(string)
+		if !ok {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to format result: %v", unmarshalErr))
 		}
+		return w.textResult(ctx, t, resultStr, nil)
+	}
 
-		return mcp.NewToolResultText(string(resultJSON)), nil
+	if t.options.outputSchema == nil {
+		resultMap = nil
 	}
+	return w.textResult(ctx, t, string(resultJSON), resultMap)
 }
 
 func buildSchema(argsType interface{}) (*mcp.ToolInputSchema, error) {
+	return buildSchemaWith(argsType, nil)
+}
+
+// buildSchemaWith builds the schema, taking a field's base schema from
+// mapType when it knows the field's type.
+func buildSchemaWith(argsType interface{}, mapType func(reflect.Type) (map[string]interface{}, bool)) (*mcp.ToolInputSchema, error) {
 	t := reflect.TypeOf(argsType)
+	if t == nil {
+		return nil, fmt.Errorf("argsType must be a struct, got nil")
+	}
 	if t.Kind() == reflect.Ptr {
 		t = t.Elem()
 	}
@@ -91,6 +579,34 @@ func buildSchema(argsType interface{}) (*mcp.ToolInputSchema, error) {
 		return nil, fmt.Errorf("argsType must be a struct, got %s", t.Kind())
 	}
 
+	b := &schemaBuilder{mapType: mapType, root: t, names: make(map[reflect.Type]string)}
+	properties, required, err := b.structProperties(t)
+	if err != nil {
+		return nil, err
+	}
+
+	schema := &mcp.ToolInputSchema{
+		Defs:       b.defs,
+		Type:       "object",
+		Properties: properties,
+		Required:   required, // Always set, even if empty (MCP protocol requirement)
+	}
+
+	return schema, nil
+}
+
+// schemaBuilder builds the schema of one argsType. Named struct types below
+// the top level are emitted once under $defs and referenced with $ref, so a
+// type shared by several fields is described once and a recursive type
+// terminates. A reference back to the argsType itself is "#".
+type schemaBuilder struct {
+	mapType func(reflect.Type) (map[string]interface{}, bool)
+	root    reflect.Type
+	defs    map[string]interface{}
+	names   map[reflect.Type]string
+}
+
+func (b *schemaBuilder) structProperties(t reflect.Type) (map[string]interface{}, []string, error) {
 	properties := make(map[string]interface{})
 	required := make([]string, 0) // Initialize as empty slice, not nil (MCP requires 'required' field)
 
@@ -98,20 +614,82 @@ func buildSchema(argsType interface{}) (*mcp.ToolInputSchema, error) {
 		field := t.Field(i)
 
 		jsonTag := field.Tag.Get("json")
+		if embedded, ok := embeddedStruct(field); ok {
+			// Fields of an untagged embedded struct are promoted, as in
+			// encoding/json.
+			embeddedProps, embeddedRequired, err := b.structProperties(embedded)
+			if err != nil {
+				return nil, nil, err
+			}
+			for name, prop := range embeddedProps {
+				if _, ok := properties[name]; !ok {
+					properties[name] = prop
+				}
+			}
+			for _, name := range embeddedRequired {
+				if !contains(required, name) {
+					required = append(required, name)
+				}
+			}
+			continue
+		}
 		if jsonTag == "" || jsonTag == "-" {
 			continue
 		}
 
 		jsonName := strings.Split(jsonTag, ",")[0]
 
-		prop := make(map[string]interface{})
-		prop["type"] = inferType(field.Type)
+		var prop map[string]interface{}
+		var err error
+		if decoder := field.Tag.Get("decode"); decoder != "" {
+			// The argument is the payload, not the decoded value.
+			prop, err = decoderSchema(decoder)
+			if err != nil {
+				return nil, nil, fmt.Errorf("field %s: %w", field.Name, err)
+			}
+		} else if prop, err = b.typeSchema(field.Type); err != nil {
+			return nil, nil, err
+		}
 
 		jsonSchemaTag := field.Tag.Get("jsonschema")
 		if jsonSchemaTag != "" {
 			parseJSONSchemaTag(jsonSchemaTag, prop, &required, jsonName)
 		}
 
+		if _, ok := prop["description"]; !ok {
+			if doc, ok := fieldDoc(t, field.Name); ok {
+				prop["description"] = doc
+			}
+		}
+
+		if values, ok := prop["enum"].([]string); ok {
+			enum, err := typedValues(values, field.Type)
+			if err != nil {
+				return nil, nil, fmt.Errorf("field %s: enum %w", field.Name, err)
+			}
+			prop["enum"] = enum
+		}
+
+		if c, ok := prop["const"].(string); ok {
+			value, err := typedValues([]string{c}, field.Type)
+			if err != nil {
+				return nil, nil, fmt.Errorf("field %s: const %w", field.Name, err)
+			}
+			if typed, ok := value.([]interface{}); ok {
+				prop["const"] = typed[0]
+			}
+		}
+
+		if pattern, ok := prop["pattern"].(string); ok {
+			if _, err := compilePattern(pattern); err != nil {
+				return nil, nil, fmt.Errorf("field %s: pattern %w", field.Name, err)
+			}
+		}
+
+		if err := addExamples(prop, field); err != nil {
+			return nil, nil, fmt.Errorf("field %s: example %w", field.Name, err)
+		}
+
 		validateTag := field.Tag.Get("validate")
 		if validateTag != "" {
 			if strings.Contains(validateTag, "required") {
@@ -124,13 +702,145 @@ func buildSchema(argsType interface{}) (*mcp.ToolInputSchema, error) {
 		properties[jsonName] = prop
 	}
 
-	schema := &mcp.ToolInputSchema{
-		Type:       "object",
-		Properties: properties,
-		Required:   required, // Always set, even if empty (MCP protocol requirement)
+	return properties, required, nil
+}
+
+// embeddedStruct returns the struct type of an embedded field without a
+// json tag, whose fields encoding/json promotes.
+func embeddedStruct(field reflect.StructField) (reflect.Type, bool) {
+	if !field.Anonymous || field.Tag.Get("json") != "" {
+		return nil, false
 	}
+	t := field.Type
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t, t.Kind() == reflect.Struct
+}
 
-	return schema, nil
+var (
+	timeType            = reflect.TypeOf(time.Time{})
+	rawMessageType      = reflect.TypeOf(json.RawMessage{})
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// isUnmarshaler reports whether t decodes itself. Such types (UUIDs,
+// decimals, custom IDs) are bound from their string form, so they are
+// described as strings; MapType adds a format.
+func isUnmarshaler(t reflect.Type) bool {
+	pt := reflect.PtrTo(t)
+	return t.Implements(jsonUnmarshalerType) || pt.Implements(jsonUnmarshalerType) ||
+		t.Implements(textUnmarshalerType) || pt.Implements(textUnmarshalerType)
+}
+
+// typeSchema returns the base schema of a field type, before its tags are
+// applied.
+func (b *schemaBuilder) typeSchema(t reflect.Type) (map[string]interface{}, error) {
+	if mapped, ok := mapSchemaType(b.mapType, t); ok {
+		return cloneMap(mapped), nil
+	}
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == timeType:
+		return map[string]interface{}{"type": "string", "format": "date-time"}, nil
+	case t == rawMessageType:
+		return map[string]interface{}{}, nil
+	case t == fileArgType:
+		return fileArgSchema(), nil
+	case isUnmarshaler(t):
+		return map[string]interface{}{"type": "string"}, nil
+	case t.Kind() == reflect.Struct:
+		if t == b.root {
+			return map[string]interface{}{"$ref": "#"}, nil
+		}
+		if t.Name() == "" {
+			properties, required, err := b.structProperties(t)
+			if err != nil {
+				return nil, err
+			}
+			return objectSchema(properties, required), nil
+		}
+		name, err := b.define(t)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"$ref": "#/$defs/" + name}, nil
+	case t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8:
+		return map[string]interface{}{"type": "string", "contentEncoding": "base64"}, nil
+	case t.Kind() == reflect.Slice || t.Kind() == reflect.Array:
+		prop := map[string]interface{}{"type": "array"}
+		if t.Elem().Kind() != reflect.Interface {
+			items, err := b.typeSchema(t.Elem())
+			if err != nil {
+				return nil, err
+			}
+			prop["items"] = items
+		}
+		return prop, nil
+	case t.Kind() == reflect.Map:
+		prop := map[string]interface{}{"type": "object"}
+		if t.Key().Kind() == reflect.String && t.Elem().Kind() != reflect.Interface {
+			values, err := b.typeSchema(t.Elem())
+			if err != nil {
+				return nil, err
+			}
+			prop["additionalProperties"] = values
+		}
+		return prop, nil
+	default:
+		return map[string]interface{}{"type": inferType(t)}, nil
+	}
+}
+
+// define adds a named struct type to $defs and returns its name there. The
+// name is reserved before the properties are built, so a type that refers to
+// itself gets a $ref instead of recursing.
+func (b *schemaBuilder) define(t reflect.Type) (string, error) {
+	if name, ok := b.names[t]; ok {
+		return name, nil
+	}
+	if b.defs == nil {
+		b.defs = make(map[string]interface{})
+	}
+
+	name := strings.ReplaceAll(t.Name(), "/", ".")
+	if _, taken := b.defs[name]; taken {
+		name = strings.ReplaceAll(t.PkgPath()+"."+t.Name(), "/", ".")
+	}
+	b.names[t] = name
+	b.defs[name] = nil
+
+	properties, required, err := b.structProperties(t)
+	if err != nil {
+		return "", err
+	}
+	b.defs[name] = objectSchema(properties, required)
+	return name, nil
+}
+
+func objectSchema(properties map[string]interface{}, required []string) map[string]interface{} {
+	schema := map[string]interface{}{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+func mapSchemaType(mapType func(reflect.Type) (map[string]interface{}, bool), t reflect.Type) (map[string]interface{}, bool) {
+	if mapType == nil {
+		return nil, false
+	}
+	if mapped, ok := mapType(t); ok {
+		return mapped, true
+	}
+	if t.Kind() == reflect.Ptr {
+		return mapType(t.Elem())
+	}
+	return nil, false
 }
 
 func inferType(t reflect.Type) string {
@@ -157,6 +867,11 @@ func inferType(t reflect.Type) string {
 }
 
 func parseJSONSchemaTag(tag string, prop map[string]interface{}, required *[]string, fieldName string) {
+	tag, pattern, ok := splitPattern(tag)
+	if ok {
+		prop["pattern"] = pattern
+	}
+
 	parts := strings.Split(tag, ",")
 	var enumValues []string
 
@@ -176,6 +891,16 @@ func parseJSONSchemaTag(tag string, prop map[string]interface{}, required *[]str
 			continue
 		}
 
+		if strings.HasPrefix(part, "title=") {
+			prop["title"] = strings.TrimPrefix(part, "title=")
+			continue
+		}
+
+		if part == "deprecated" {
+			prop["deprecated"] = true
+			continue
+		}
+
 		if strings.HasPrefix(part, "enum=") {
 			enumValue := strings.TrimPrefix(part, "enum=")
 			enumValues = append(enumValues, enumValue)
@@ -205,6 +930,36 @@ func parseJSONSchemaTag(tag string, prop map[string]interface{}, required *[]str
 			prop["maxLength"] = parseNumber(maxLen)
 			continue
 		}
+
+		if strings.HasPrefix(part, "exclusiveMinimum=") {
+			prop["exclusiveMinimum"] = parseNumber(strings.TrimPrefix(part, "exclusiveMinimum="))
+			continue
+		}
+
+		if strings.HasPrefix(part, "exclusiveMaximum=") {
+			prop["exclusiveMaximum"] = parseNumber(strings.TrimPrefix(part, "exclusiveMaximum="))
+			continue
+		}
+
+		if strings.HasPrefix(part, "multipleOf=") {
+			prop["multipleOf"] = parseNumber(strings.TrimPrefix(part, "multipleOf="))
+			continue
+		}
+
+		if strings.HasPrefix(part, "format=") {
+			prop["format"] = strings.TrimPrefix(part, "format=")
+			continue
+		}
+
+		if strings.HasPrefix(part, "const=") {
+			prop["const"] = strings.TrimPrefix(part, "const=")
+			continue
+		}
+
+		if part == "uniqueItems" {
+			prop["uniqueItems"] = true
+			continue
+		}
 	}
 
 	if len(enumValues) > 0 {
@@ -213,12 +968,10 @@ func parseJSONSchemaTag(tag string, prop map[string]interface{}, required *[]str
 }
 
 func parseNumber(s string) interface{} {
-	var i int
-	if _, err := fmt.Sscanf(s, "%d", &i); err == nil {
-		return i
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return int(i)
 	}
-	var f float64
-	if _, err := fmt.Sscanf(s, "%f", &f); err == nil {
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
 		return f
 	}
 	return s
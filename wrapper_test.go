@@ -9,7 +9,6 @@ import (
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
-	"github.com/spf13/cobra"
 )
 
 type TestArgs struct {
@@ -78,6 +77,85 @@ func TestBuildSchema(t *testing.T) {
 	}
 }
 
+type Address struct {
+	Street string `json:"street" jsonschema:"required"`
+	City   string `json:"city"`
+}
+
+type TreeNode struct {
+	Name     string      `json:"name"`
+	Children []*TreeNode `json:"children"`
+	Home     *Address    `json:"home"`
+}
+
+type ShipmentArgs struct {
+	From    Address           `json:"from" jsonschema:"description=Sender address"`
+	To      Address           `json:"to"`
+	Stops   []Address         `json:"stops"`
+	Tree    TreeNode          `json:"tree"`
+	Labels  map[string]string `json:"labels"`
+	Extra   map[string]any    `json:"extra"`
+	Parent  *ShipmentArgs     `json:"parent,omitempty"`
+	Options struct {
+		Fast bool `json:"fast"`
+	} `json:"options"`
+}
+
+func TestBuildSchemaDefs(t *testing.T) {
+	schema, err := buildSchema(ShipmentArgs{})
+	if err != nil {
+		t.Fatalf("buildSchema failed: %v", err)
+	}
+
+	from := schema.Properties["from"].(map[string]interface{})
+	if from["$ref"] != "#/$defs/Address" || from["description"] != "Sender address" {
+		t.Errorf("Unexpected from schema: %v", from)
+	}
+	if to := schema.Properties["to"].(map[string]interface{}); to["$ref"] != "#/$defs/Address" {
+		t.Errorf("Expected to to share the Address definition, got %v", to)
+	}
+	stops := schema.Properties["stops"].(map[string]interface{})
+	if items := stops["items"].(map[string]interface{}); items["$ref"] != "#/$defs/Address" {
+		t.Errorf("Unexpected stops items: %v", items)
+	}
+	if parent := schema.Properties["parent"].(map[string]interface{}); parent["$ref"] != "#" {
+		t.Errorf("Expected a root reference, got %v", parent)
+	}
+	if labels := schema.Properties["labels"].(map[string]interface{}); !reflect.DeepEqual(labels["additionalProperties"], map[string]interface{}{"type": "string"}) {
+		t.Errorf("Unexpected labels schema: %v", labels)
+	}
+	if extra := schema.Properties["extra"].(map[string]interface{}); extra["additionalProperties"] != nil {
+		t.Errorf("Expected no additionalProperties for any values, got %v", extra)
+	}
+	options := schema.Properties["options"].(map[string]interface{})
+	if options["type"] != "object" || options["properties"].(map[string]interface{})["fast"] == nil {
+		t.Errorf("Expected an anonymous struct inline, got %v", options)
+	}
+
+	if len(schema.Defs) != 2 {
+		t.Fatalf("Expected Address and TreeNode definitions, got %v", schema.Defs)
+	}
+	address := schema.Defs["Address"].(map[string]interface{})
+	if !reflect.DeepEqual(address["required"], []string{"street"}) {
+		t.Errorf("Unexpected Address definition: %v", address)
+	}
+	tree := schema.Defs["TreeNode"].(map[string]interface{})
+	children := tree["properties"].(map[string]interface{})["children"].(map[string]interface{})
+	if items := children["items"].(map[string]interface{}); items["$ref"] != "#/$defs/TreeNode" {
+		t.Errorf("Expected a recursive reference, got %v", items)
+	}
+
+	data, err := json.Marshal(schema)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	var decoded map[string]interface{}
+	json.Unmarshal(data, &decoded)
+	if _, ok := decoded["$defs"]; !ok {
+		t.Errorf("Expected $defs in the JSON schema, got %s", data)
+	}
+}
+
 func TestInferType(t *testing.T) {
 	tests := []struct {
 		value    interface{}
@@ -256,48 +334,6 @@ func TestRegister(t *testing.T) {
 	}
 }
 
-func TestRegisterCobra(t *testing.T) {
-	mcpServer := server.NewMCPServer("test", "1.0.0")
-	wrapper := New(mcpServer)
-
-	cmd := &cobra.Command{
-		Use:   "test-cmd",
-		Short: "Test command description",
-	}
-
-	handler := func(ctx context.Context, args interface{}) (interface{}, error) {
-		return &TestResult{Message: "Success"}, nil
-	}
-
-	err := wrapper.RegisterCobra(cmd, TestArgs{}, handler)
-	if err != nil {
-		t.Fatalf("RegisterCobra failed: %v", err)
-	}
-
-	tools := mcpServer.ListTools()
-	if len(tools) != 1 {
-		t.Errorf("Expected 1 tool handler, got %d", len(tools))
-	}
-}
-
-func TestRegisterCobraNoUse(t *testing.T) {
-	mcpServer := server.NewMCPServer("test", "1.0.0")
-	wrapper := New(mcpServer)
-
-	cmd := &cobra.Command{
-		Short: "Missing Use field",
-	}
-
-	handler := func(ctx context.Context, args interface{}) (interface{}, error) {
-		return &TestResult{Message: "Success"}, nil
-	}
-
-	err := wrapper.RegisterCobra(cmd, TestArgs{}, handler)
-	if err == nil {
-		t.Error("Expected error for command without Use field")
-	}
-}
-
 func TestHandlerInvocation(t *testing.T) {
 	mcpServer := server.NewMCPServer("test", "1.0.0")
 	wrapper := New(mcpServer)